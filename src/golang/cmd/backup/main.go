@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/server"
+	"github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/backup"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/dropbox/godropbox/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// passphraseEnvVar can be used instead of the passphrase flag, to keep the passphrase out of the shell history.
+const passphraseEnvVar = "AQUEDUCT_BACKUP_PASSPHRASE"
+
+var (
+	confPath = flag.String(
+		"config",
+		filepath.Join(os.Getenv("HOME"), ".aqueduct", "server", "config", "config.yml"),
+		"The path to the server config file.",
+	)
+	archivePath = flag.String("archive", "aqueduct-backup.tar.gz", "The path of the archive to export to or import from.")
+	passphrase  = flag.String("passphrase", "", fmt.Sprintf("The passphrase that integration credentials are encrypted with. Defaults to $%s.", passphraseEnvVar))
+)
+
+const (
+	exportUsage = `export                 Exports the server's metadata and storage content to the archive.`
+	importUsage = `import                 Imports the archive into the server.`
+
+	exportCmd = "export"
+	importCmd = "import"
+)
+
+func printUsage() {
+	fmt.Printf(`Usage: backup OPTIONS COMMAND
+		OPTIONS:
+			- help 		Print usage
+			- config 	The path to the server config file
+			- archive 	The path of the archive
+			- passphrase 	The passphrase that integration credentials are encrypted with
+
+		COMMANDS:
+			%s
+			%s
+	`, exportUsage, importUsage)
+}
+
+func main() {
+	flag.Usage = printUsage
+	flag.Parse()
+	log.SetFormatter(&log.TextFormatter{DisableQuote: true})
+
+	args := flag.Args()
+	if len(args) != 1 {
+		printUsage()
+		log.Fatal("Command was not specified.")
+	}
+
+	if *passphrase == "" {
+		*passphrase = os.Getenv(passphraseEnvVar)
+	}
+
+	if err := config.Init(*confPath); err != nil {
+		log.Fatalf("Unable to initialize config: %v", err)
+	}
+
+	var err error
+	switch args[0] {
+	case exportCmd:
+		err = handleExport(context.Background())
+	case importCmd:
+		err = handleImport(context.Background())
+	default:
+		printUsage()
+		log.Fatal("Unknown command specified.")
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func handleExport(ctx context.Context) (err error) {
+	f, err := os.Create(*archivePath)
+	if err != nil {
+		return errors.Wrap(err, "Unable to create the archive.")
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(*archivePath)
+		}
+	}()
+
+	return run(ctx, func(env *environment) error {
+		summary, err := backup.Export(ctx, f, *passphrase, env.user, env.vault, env.repos, env.DB)
+		if err != nil {
+			return err
+		}
+
+		log.Infof(
+			"Exported %d workflows, %d dags, %d runs, %d integrations and %d storage objects to %s.",
+			summary.Workflows,
+			summary.DAGs,
+			summary.DAGResults,
+			summary.Integrations,
+			summary.Objects,
+			*archivePath,
+		)
+		return nil
+	})
+}
+
+func handleImport(ctx context.Context) error {
+	f, err := os.Open(*archivePath)
+	if err != nil {
+		return errors.Wrap(err, "Unable to open the archive.")
+	}
+	defer f.Close()
+
+	return run(ctx, func(env *environment) error {
+		storageConfig := config.Storage()
+		summary, err := backup.Import(ctx, f, *passphrase, env.user, &storageConfig, env.vault, env.repos, env.DB)
+		if err != nil {
+			return err
+		}
+
+		log.Infof(
+			"Imported %d workflows, %d dags, %d runs, %d integrations and %d storage objects from %s.",
+			summary.Workflows,
+			summary.DAGs,
+			summary.DAGResults,
+			summary.Integrations,
+			summary.Objects,
+			*archivePath,
+		)
+		log.Info("Restart the server for the schedules of the imported workflows to take effect.")
+		return nil
+	})
+}
+
+// environment is what the commands need to access the server's metadata and secrets.
+type environment struct {
+	DB    database.Database
+	repos *backup.Repos
+	user  *models.User
+	vault vault.Vault
+}
+
+// run calls fn with the environment of the server that is configured by the config file.
+func run(ctx context.Context, fn func(env *environment) error) error {
+	DB, err := database.NewDatabase(config.Database())
+	if err != nil {
+		return errors.Wrap(err, "Unable to connect to the database.")
+	}
+	defer DB.Close()
+
+	serverRepos := server.CreateRepos(DB.Type())
+
+	currentVersion, err := serverRepos.SchemaVersionRepo.GetCurrent(ctx, DB)
+	if err != nil {
+		return errors.Wrap(err, "Unable to retrieve the database schema version.")
+	}
+
+	if currentVersion.Version != models.CurrentSchemaVersion {
+		return errors.Newf(
+			"The database schema version is %d, but %d is required. Start the server once to migrate the database.",
+			currentVersion.Version,
+			models.CurrentSchemaVersion,
+		)
+	}
+
+	// Everything is exported and imported on behalf of the user the server was set up with.
+	user, err := serverRepos.UserRepo.GetByAPIKey(ctx, config.APIKey(), DB)
+	if err != nil {
+		return errors.Wrap(err, "Unable to retrieve the server's user.")
+	}
+
+	storageConfig := config.Storage()
+	vaultObj, err := vault.NewVault(config.Vault(), &storageConfig, config.EncryptionKeyring())
+	if err != nil {
+		return errors.Wrap(err, "Unable to access the vault.")
+	}
+
+	return fn(&environment{
+		DB:    DB,
+		repos: backupRepos(serverRepos),
+		user:  user,
+		vault: vaultObj,
+	})
+}
+
+// backupRepos returns the repos that are used for backups out of the server's repos.
+func backupRepos(serverRepos *server.Repos) *backup.Repos {
+	return &backup.Repos{
+		ArtifactRepo:             serverRepos.ArtifactRepo,
+		ArtifactResultRepo:       serverRepos.ArtifactResultRepo,
+		ContentBlobRepo:          serverRepos.ContentBlobRepo,
+		DAGRepo:                  serverRepos.DAGRepo,
+		DAGEdgeRepo:              serverRepos.DAGEdgeRepo,
+		DAGResultRepo:            serverRepos.DAGResultRepo,
+		ExecutionEnvironmentRepo: serverRepos.ExecutionEnvironmentRepo,
+		IntegrationRepo:          serverRepos.IntegrationRepo,
+		OperatorRepo:             serverRepos.OperatorRepo,
+		OperatorResultRepo:       serverRepos.OperatorResultRepo,
+		WorkflowRepo:             serverRepos.WorkflowRepo,
+	}
+}
@@ -51,7 +51,7 @@ func NewBaseExecutor(conf *job.ExecutorConfiguration) (*BaseExecutor, error) {
 	}
 
 	storageConfig := config.Storage()
-	vault, err := vault.NewVault(&storageConfig, config.EncryptionKey())
+	vault, err := vault.NewVault(config.Vault(), &storageConfig, config.EncryptionKeyring())
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +60,7 @@ func NewBaseExecutor(conf *job.ExecutorConfiguration) (*BaseExecutor, error) {
 		JobManager: jobManager,
 		Vault:      vault,
 		Database:   DB,
-		Repos:      createRepos(),
+		Repos:      createRepos(DB.Type()),
 	}, nil
 }
 
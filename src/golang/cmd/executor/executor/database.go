@@ -1,14 +1,18 @@
 package executor
 
 import (
+	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/engine"
 	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/repos/postgres"
 	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
 )
 
 type Repos struct {
 	ArtifactRepo             repos.Artifact
 	ArtifactResultRepo       repos.ArtifactResult
+	BackfillRepo             repos.Backfill
+	ContentBlobRepo          repos.ContentBlob
 	DAGRepo                  repos.DAG
 	DAGEdgeRepo              repos.DAGEdge
 	DAGResultRepo            repos.DAGResult
@@ -17,14 +21,38 @@ type Repos struct {
 	NotificationRepo         repos.Notification
 	OperatorRepo             repos.Operator
 	OperatorResultRepo       repos.OperatorResult
+	PreviewCacheEntryRepo    repos.PreviewCacheEntry
 	WatcherRepo              repos.Watcher
 	WorkflowRepo             repos.Workflow
 }
 
-func createRepos() *Repos {
+// createRepos returns the repos for the database of type dbType.
+func createRepos(dbType database.Type) *Repos {
+	if dbType == database.PostgresType {
+		return &Repos{
+			ArtifactRepo:             postgres.NewArtifactRepo(),
+			ArtifactResultRepo:       postgres.NewArtifactResultRepo(),
+			BackfillRepo:             postgres.NewBackfillRepo(),
+			ContentBlobRepo:          postgres.NewContentBlobRepo(),
+			DAGRepo:                  postgres.NewDAGRepo(),
+			DAGEdgeRepo:              postgres.NewDAGEdgeRepo(),
+			DAGResultRepo:            postgres.NewDAGResultRepo(),
+			ExecutionEnvironmentRepo: postgres.NewExecutionEnvironmentRepo(),
+			IntegrationRepo:          postgres.NewIntegrationRepo(),
+			NotificationRepo:         postgres.NewNotificationRepo(),
+			OperatorRepo:             postgres.NewOperatorRepo(),
+			OperatorResultRepo:       postgres.NewOperatorResultRepo(),
+			PreviewCacheEntryRepo:    postgres.NewPreviewCacheEntryRepo(),
+			WatcherRepo:              postgres.NewWatcherRepo(),
+			WorkflowRepo:             postgres.NewWorkflowRepo(),
+		}
+	}
+
 	return &Repos{
 		ArtifactRepo:             sqlite.NewArtifactRepo(),
 		ArtifactResultRepo:       sqlite.NewArtifactResultRepo(),
+		BackfillRepo:             sqlite.NewBackfillRepo(),
+		ContentBlobRepo:          sqlite.NewContentBlobRepo(),
 		DAGRepo:                  sqlite.NewDAGRepo(),
 		DAGEdgeRepo:              sqlite.NewDAGEdgeRepo(),
 		DAGResultRepo:            sqlite.NewDAGResultRepo(),
@@ -33,6 +61,7 @@ func createRepos() *Repos {
 		NotificationRepo:         sqlite.NewNotificationRepo(),
 		OperatorRepo:             sqlite.NewOperatorRepo(),
 		OperatorResultRepo:       sqlite.NewOperatorResultRepo(),
+		PreviewCacheEntryRepo:    sqlite.NewPreviewCacheEntryRepo(),
 		WatcherRepo:              sqlite.NewWatcherRepo(),
 		WorkflowRepo:             sqlite.NewWorklowRepo(),
 	}
@@ -42,6 +71,8 @@ func getEngineRepos(repos *Repos) *engine.Repos {
 	return &engine.Repos{
 		ArtifactRepo:             repos.ArtifactRepo,
 		ArtifactResultRepo:       repos.ArtifactResultRepo,
+		BackfillRepo:             repos.BackfillRepo,
+		ContentBlobRepo:          repos.ContentBlobRepo,
 		DAGRepo:                  repos.DAGRepo,
 		DAGEdgeRepo:              repos.DAGEdgeRepo,
 		DAGResultRepo:            repos.DAGResultRepo,
@@ -49,6 +49,17 @@ func NewExecutor(spec job.Spec) (Executor, error) {
 		}
 
 		return NewDynamicTeardownExecutor(base), nil
+	case job.StorageGCType:
+		storageGCSpec, ok := spec.(*job.StorageGCSpec)
+		if !ok {
+			return nil, job.ErrInvalidJobSpec
+		}
+		base, err := NewBaseExecutor(storageGCSpec.ExecutorConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewStorageGCExecutor(storageGCSpec, base), nil
 	default:
 		return nil, errors.New("Unsupported JobType")
 	}
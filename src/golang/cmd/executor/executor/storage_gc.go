@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/aqueducthq/aqueduct/lib/storage_gc"
+	"github.com/dropbox/godropbox/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+type StorageGCExecutor struct {
+	*BaseExecutor
+	spec *job.StorageGCSpec
+}
+
+func NewStorageGCExecutor(spec *job.StorageGCSpec, base *BaseExecutor) *StorageGCExecutor {
+	return &StorageGCExecutor{BaseExecutor: base, spec: spec}
+}
+
+func (ex *StorageGCExecutor) Run(ctx context.Context) error {
+	log.Info("Starting storage garbage collection.")
+
+	previewEntries, err := ex.PreviewCacheEntryRepo.List(ctx, ex.Database)
+	if err != nil {
+		return errors.Wrap(err, "Unable to list preview cache entries.")
+	}
+
+	previewPaths := make([]string, 0, 3*len(previewEntries))
+	for _, entry := range previewEntries {
+		previewPaths = append(previewPaths, entry.ArtifactContentPath, entry.ArtifactMetadataPath, entry.OpMetadataPath)
+	}
+
+	storageConfig := config.Storage()
+	report, err := storage_gc.Collect(
+		ctx,
+		&storageConfig,
+		&storage_gc.Options{
+			GracePeriod:  ex.spec.GracePeriod,
+			DryRun:       ex.spec.DryRun,
+			PreviewPaths: previewPaths,
+		},
+		ex.DAGRepo,
+		ex.ArtifactRepo,
+		ex.ArtifactResultRepo,
+		ex.OperatorRepo,
+		ex.IntegrationRepo,
+		ex.ContentBlobRepo,
+		ex.Database,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Unable to collect storage garbage.")
+	}
+
+	if report.DryRun {
+		for _, orphan := range report.Orphans {
+			if orphan.StorageIntegrationID != nil {
+				log.Infof(
+					"Found orphaned object %s of %d bytes in the storage layer of integration %s, last modified at %v.",
+					orphan.Key,
+					orphan.Size,
+					*orphan.StorageIntegrationID,
+					orphan.LastModified,
+				)
+				continue
+			}
+			log.Infof("Found orphaned object %s of %d bytes, last modified at %v.", orphan.Key, orphan.Size, orphan.LastModified)
+		}
+	}
+
+	log.Infof(
+		"Executed storage garbage collection. Found %d orphaned objects out of %d, and deleted %d of them (%d bytes).",
+		len(report.Orphans),
+		report.NumObjects,
+		report.NumDeleted,
+		report.ReclaimedBytes,
+	)
+	return nil
+}
@@ -7,9 +7,11 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/engine"
 	"github.com/aqueducthq/aqueduct/lib/job"
 	"github.com/aqueducthq/aqueduct/lib/lib_utils"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/param"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/github"
 	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 )
@@ -26,6 +28,12 @@ type WorkflowExecutor struct {
 	// The parameters to execute this workflow job with. If nil, then only default parameters
 	// will be used. These values not persisted to the db.
 	Parameters map[string]param.Param
+
+	// If set, this is the failed or canceled DAG result that the workflow run resumes from.
+	SourceDAGResultID uuid.UUID
+
+	// If set, this workflow run is part of a backfill.
+	BackfillRun *engine.BackfillRun
 }
 
 func NewWorkflowExecutor(spec *job.WorkflowSpec, base *BaseExecutor) (*WorkflowExecutor, error) {
@@ -34,6 +42,31 @@ func NewWorkflowExecutor(spec *job.WorkflowSpec, base *BaseExecutor) (*WorkflowE
 		return nil, err
 	}
 
+	sourceDAGResultID := uuid.Nil
+	if spec.SourceDagResultId != "" {
+		sourceDAGResultID, err = uuid.Parse(spec.SourceDagResultId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var backfillRun *engine.BackfillRun
+	if spec.BackfillId != "" {
+		backfillID, err := uuid.Parse(spec.BackfillId)
+		if err != nil {
+			return nil, err
+		}
+
+		if spec.ExecutionTime == nil {
+			return nil, errors.New("A backfill run must have an execution time.")
+		}
+
+		backfillRun = &engine.BackfillRun{
+			BackfillID:    backfillID,
+			ExecutionTime: *spec.ExecutionTime,
+		}
+	}
+
 	githubManager, err := github.NewManager(spec.GithubManager)
 	if err != nil {
 		return nil, err
@@ -47,6 +80,8 @@ func NewWorkflowExecutor(spec *job.WorkflowSpec, base *BaseExecutor) (*WorkflowE
 		nil, /* PreviewCacheManager */
 		spec.AqPath,
 		spec.DisplayIP,
+		spec.MaxConcurrentOperators,
+		nil, /* ScheduleJobManager */
 		engineRepos,
 	)
 	if err != nil {
@@ -59,6 +94,9 @@ func NewWorkflowExecutor(spec *job.WorkflowSpec, base *BaseExecutor) (*WorkflowE
 		GithubManager: githubManager,
 		Engine:        eng,
 		Parameters:    spec.Parameters,
+
+		SourceDAGResultID: sourceDAGResultID,
+		BackfillRun:       backfillRun,
 	}, nil
 }
 
@@ -76,16 +114,36 @@ func (ex *WorkflowExecutor) Run(ctx context.Context) error {
 		}
 	}()
 
-	status, err := ex.Engine.ExecuteWorkflow(
-		ctx,
-		ex.WorkflowID,
-		&engine.AqueductTimeConfig{
-			OperatorPollInterval: pollingIntervalMS,
-			ExecTimeout:          engine.DefaultExecutionTimeout,
-			CleanupTimeout:       engine.DefaultCleanupTimeout,
-		},
-		ex.Parameters,
-	)
+	timeConfig := &engine.AqueductTimeConfig{
+		OperatorPollInterval: pollingIntervalMS,
+		ExecTimeout:          engine.DefaultExecutionTimeout,
+		CleanupTimeout:       engine.DefaultCleanupTimeout,
+	}
+
+	var status shared.ExecutionStatus
+	var err error
+	if ex.SourceDAGResultID != uuid.Nil {
+		status, err = ex.Engine.ExecuteResumedWorkflowRun(
+			ctx,
+			ex.WorkflowID,
+			ex.SourceDAGResultID,
+			timeConfig,
+		)
+	} else if ex.BackfillRun != nil {
+		status, err = ex.Engine.ExecuteBackfillRun(
+			ctx,
+			ex.WorkflowID,
+			ex.BackfillRun,
+			timeConfig,
+		)
+	} else {
+		status, err = ex.Engine.ExecuteWorkflow(
+			ctx,
+			ex.WorkflowID,
+			timeConfig,
+			ex.Parameters,
+		)
+	}
 	if err != nil {
 		return err
 	}
@@ -95,6 +153,11 @@ func (ex *WorkflowExecutor) Run(ctx context.Context) error {
 		"Parameters": ex.Parameters,
 	}).Infof("Workflow run completed with status: %v", status)
 
+	// Backfill runs execute for a time in the past, so they do not trigger downstream workflows.
+	if ex.BackfillRun != nil {
+		return nil
+	}
+
 	if err := ex.TriggerCascadingFlows(ctx); err != nil {
 		log.WithFields(log.Fields{
 			"WorkflowId": ex.WorkflowID,
@@ -4,6 +4,9 @@ import (
 	"context"
 
 	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
@@ -96,8 +99,71 @@ func (ex *WorkflowRetentionExecutor) cleanupOldWorkflows(
 	}
 
 	artifactResultIDs := make([]uuid.UUID, 0, len(artifactResultsToDelete))
+	contentPathsByDAGResult := make(map[uuid.UUID][]string, len(dagResults))
 	for _, artifactResult := range artifactResultsToDelete {
 		artifactResultIDs = append(artifactResultIDs, artifactResult.ID)
+		contentPathsByDAGResult[artifactResult.DAGResultID] = append(
+			contentPathsByDAGResult[artifactResult.DAGResultID],
+			artifactResult.ContentPath,
+		)
+	}
+
+	// Content that is stored by its hash can be shared with the runs that are kept,
+	// so it is only deleted once nothing references it anymore. All of the content
+	// is deleted from the storage layer of each run's dag once the results are deleted.
+	storageConfigByDAG := make(map[uuid.UUID]*shared.StorageConfig, len(dagResults))
+	contentPathsByDAG := make(map[uuid.UUID][]string, len(dagResults))
+	releasedPathsByDAG := make(map[uuid.UUID][]string, len(dagResults))
+	for _, dagResult := range dagResults {
+		dag, err := ex.DAGRepo.Get(ctx, dagResult.DagID, txn)
+		if err != nil {
+			return errors.Wrap(err, "Unexpected error occurred while retrieving workflow dag.")
+		}
+
+		otherPaths, releasedPaths, err := utils.ReleaseContent(
+			ctx,
+			contentPathsByDAGResult[dagResult.ID],
+			ex.ContentBlobRepo,
+			txn,
+		)
+		if err != nil {
+			return errors.Wrap(err, "Unexpected error occurred while releasing artifact content.")
+		}
+
+		storageConfigByDAG[dag.ID] = &dag.StorageConfig
+		contentPathsByDAG[dag.ID] = append(contentPathsByDAG[dag.ID], otherPaths...)
+		releasedPathsByDAG[dag.ID] = append(releasedPathsByDAG[dag.ID], releasedPaths...)
+	}
+
+	// Resumed runs reuse the content of the runs they resumed, so content that is
+	// still referenced by a run that is kept must not be deleted.
+	allDAGResults, err := ex.DAGResultRepo.GetByWorkflow(ctx, workflowObjectID, txn)
+	if err != nil {
+		return errors.Wrap(err, "Unexpected error occurred while retrieving workflow dag results.")
+	}
+
+	deletedDAGResultIDs := make(map[uuid.UUID]bool, len(dagResultIDs))
+	for _, dagResultID := range dagResultIDs {
+		deletedDAGResultIDs[dagResultID] = true
+	}
+
+	keptDAGResultIDs := make([]uuid.UUID, 0, len(allDAGResults))
+	for _, dagResult := range allDAGResults {
+		if !deletedDAGResultIDs[dagResult.ID] {
+			keptDAGResultIDs = append(keptDAGResultIDs, dagResult.ID)
+		}
+	}
+
+	keptContentPaths := map[string]bool{}
+	if len(keptDAGResultIDs) > 0 {
+		keptArtifactResults, err := ex.ArtifactResultRepo.GetByDAGResults(ctx, keptDAGResultIDs, txn)
+		if err != nil {
+			return errors.Wrap(err, "Unexpected error occurred while retrieving artifact results.")
+		}
+
+		for _, artifactResult := range keptArtifactResults {
+			keptContentPaths[artifactResult.ContentPath] = true
+		}
 	}
 
 	// Do the deleting
@@ -120,5 +186,23 @@ func (ex *WorkflowRetentionExecutor) cleanupOldWorkflows(
 		return errors.Wrap(err, "Failed to commit retention transaction.")
 	}
 
+	// Deletion is best-effort, and the content of failed runs may not exist.
+	for dagID, contentPaths := range contentPathsByDAG {
+		store := storage.NewStorage(storageConfigByDAG[dagID])
+		for _, contentPath := range contentPaths {
+			if keptContentPaths[contentPath] || !store.Exists(ctx, contentPath) {
+				continue
+			}
+
+			if err := store.Delete(ctx, contentPath); err != nil {
+				log.Errorf("Unable to delete artifact content %s: %v", contentPath, err)
+			}
+		}
+	}
+
+	for dagID, releasedPaths := range releasedPathsByDAG {
+		utils.DeleteReleasedContent(ctx, storageConfigByDAG[dagID], releasedPaths, ex.ContentBlobRepo, ex.Database)
+	}
+
 	return nil
 }
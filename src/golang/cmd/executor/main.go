@@ -17,6 +17,9 @@ import (
 const (
 	jobSpecFlagKey      = "spec"
 	logsFilePathFlagKey = "logs-path"
+
+	// When the executor is run by a k8s cron job, the spec is passed in this environment variable instead.
+	jobSpecEnvVarKey = "JOB_SPEC"
 )
 
 var specSerialized = flag.String(
@@ -48,6 +51,10 @@ func init() {
 		}
 	}
 
+	if path := os.Getenv(config.PathEnvVar); path != "" {
+		confPath = path
+	}
+
 	// Initialize config, the process should exit if this fails
 	if err := config.Init(confPath); err != nil {
 		log.Fatalf("Unable to initialize config: %v", err)
@@ -73,7 +80,13 @@ func main() {
 		defer logFile.Close()
 	}
 
-	spec, err := job.DecodeSpec(*specSerialized, job.GobSerializationType)
+	var spec job.Spec
+	var err error
+	if *specSerialized == "" && os.Getenv(jobSpecEnvVarKey) != "" {
+		spec, err = job.DecodeSpec(os.Getenv(jobSpecEnvVarKey), job.JsonSerializationType)
+	} else {
+		spec, err = job.DecodeSpec(*specSerialized, job.GobSerializationType)
+	}
 	if err != nil {
 		log.Errorf("Unable to decode spec. %v", err)
 		return
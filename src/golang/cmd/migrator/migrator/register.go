@@ -29,6 +29,13 @@ import (
 	_000024 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000024_migrate_exec_env_to_conda_engine"
 	_000025 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000025_add_storage_migration_table"
 	_000026 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000026_drop_integration_validated_column"
+	_000027 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000027_add_dag_result_source_column"
+	_000028 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000028_add_workflow_backfill_table"
+	_000029 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000029_add_workflow_max_concurrent_operators_column"
+	_000030 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000030_add_content_blob_table"
+	_000031 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000031_add_preview_cache_entry_table"
+	_000032 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000032_add_dag_storage_integration_column"
+	_000033 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000033_add_workflow_backfill_timezone_column"
 	"github.com/aqueducthq/aqueduct/lib/database"
 )
 
@@ -192,4 +199,46 @@ func init() {
 		downPostgres: _000026.DownPostgres,
 		name:         "remove validated column from integration table",
 	}
+
+	registeredMigrations[27] = &migration{
+		upPostgres: _000027.UpPostgres, upSqlite: _000027.UpSqlite,
+		downPostgres: _000027.DownPostgres,
+		name:         "add source_dag_result_id column to workflow_dag_result table",
+	}
+
+	registeredMigrations[28] = &migration{
+		upPostgres: _000028.UpPostgres, upSqlite: _000028.UpSqlite,
+		downPostgres: _000028.DownPostgres,
+		name:         "add workflow_backfill table",
+	}
+
+	registeredMigrations[29] = &migration{
+		upPostgres: _000029.UpPostgres, upSqlite: _000029.UpSqlite,
+		downPostgres: _000029.DownPostgres,
+		name:         "add max_concurrent_operators column to workflow table",
+	}
+
+	registeredMigrations[30] = &migration{
+		upPostgres: _000030.UpPostgres, upSqlite: _000030.UpSqlite,
+		downPostgres: _000030.DownPostgres,
+		name:         "add content_blob table",
+	}
+
+	registeredMigrations[31] = &migration{
+		upPostgres: _000031.UpPostgres, upSqlite: _000031.UpSqlite,
+		downPostgres: _000031.DownPostgres,
+		name:         "add preview_cache_entry table",
+	}
+
+	registeredMigrations[32] = &migration{
+		upPostgres: _000032.UpPostgres, upSqlite: _000032.UpSqlite,
+		downPostgres: _000032.DownPostgres,
+		name:         "add storage_integration_id column to workflow_dag table",
+	}
+
+	registeredMigrations[33] = &migration{
+		upPostgres: _000033.UpPostgres, upSqlite: _000033.UpSqlite,
+		downPostgres: _000033.DownPostgres,
+		name:         "add timezone column to workflow_backfill table",
+	}
 }
@@ -0,0 +1,5 @@
+package _000027_add_dag_result_source_column
+
+const downPostgresScript = `
+ALTER TABLE workflow_dag_result DROP COLUMN IF EXISTS source_dag_result_id;
+`
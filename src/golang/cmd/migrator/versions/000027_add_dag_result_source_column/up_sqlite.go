@@ -0,0 +1,6 @@
+package _000027_add_dag_result_source_column
+
+const upSqliteScript = `
+ALTER TABLE workflow_dag_result 
+ADD COLUMN source_dag_result_id BLOB;
+`
@@ -0,0 +1,7 @@
+package _000028_add_workflow_backfill_table
+
+const downPostgresScript = `
+ALTER TABLE workflow_dag_result DROP COLUMN IF EXISTS execution_time;
+ALTER TABLE workflow_dag_result DROP COLUMN IF EXISTS backfill_id;
+DROP TABLE IF EXISTS workflow_backfill;
+`
@@ -0,0 +1,17 @@
+package _000028_add_workflow_backfill_table
+
+const upPostgresScript = `
+CREATE TABLE IF NOT EXISTS workflow_backfill (
+	id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+	workflow_id UUID NOT NULL REFERENCES workflow (id),
+	cron_schedule VARCHAR NOT NULL,
+	start_time TIMESTAMPTZ NOT NULL,
+	end_time TIMESTAMPTZ NOT NULL,
+	max_concurrency INTEGER NOT NULL,
+	status VARCHAR NOT NULL,
+	execution_state JSONB NOT NULL
+);
+
+ALTER TABLE workflow_dag_result ADD COLUMN backfill_id UUID REFERENCES workflow_backfill (id);
+ALTER TABLE workflow_dag_result ADD COLUMN execution_time TIMESTAMPTZ;
+`
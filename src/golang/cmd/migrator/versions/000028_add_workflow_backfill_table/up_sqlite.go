@@ -0,0 +1,17 @@
+package _000028_add_workflow_backfill_table
+
+const upSqliteScript = `
+CREATE TABLE IF NOT EXISTS workflow_backfill (
+	id BLOB NOT NULL PRIMARY KEY,
+	workflow_id BLOB NOT NULL REFERENCES workflow (id),
+	cron_schedule TEXT NOT NULL,
+	start_time DATETIME NOT NULL,
+	end_time DATETIME NOT NULL,
+	max_concurrency INTEGER NOT NULL,
+	status TEXT NOT NULL,
+	execution_state BLOB NOT NULL
+);
+
+ALTER TABLE workflow_dag_result ADD COLUMN backfill_id BLOB REFERENCES workflow_backfill (id);
+ALTER TABLE workflow_dag_result ADD COLUMN execution_time DATETIME;
+`
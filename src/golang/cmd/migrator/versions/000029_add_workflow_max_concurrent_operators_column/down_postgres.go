@@ -0,0 +1,5 @@
+package _000029_add_workflow_max_concurrent_operators_column
+
+const downPostgresScript = `
+ALTER TABLE workflow DROP COLUMN IF EXISTS max_concurrent_operators;
+`
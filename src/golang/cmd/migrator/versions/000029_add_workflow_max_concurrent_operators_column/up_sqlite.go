@@ -0,0 +1,6 @@
+package _000029_add_workflow_max_concurrent_operators_column
+
+const upSqliteScript = `
+ALTER TABLE workflow 
+ADD COLUMN max_concurrent_operators INTEGER NOT NULL DEFAULT 0;
+`
@@ -0,0 +1,5 @@
+package _000030_add_content_blob_table
+
+const downPostgresScript = `
+DROP TABLE IF EXISTS content_blob;
+`
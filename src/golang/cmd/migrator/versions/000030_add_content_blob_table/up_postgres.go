@@ -0,0 +1,9 @@
+package _000030_add_content_blob_table
+
+const upPostgresScript = `
+CREATE TABLE IF NOT EXISTS content_blob (
+	hash VARCHAR PRIMARY KEY,
+	ref_count INTEGER NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL
+);
+`
@@ -0,0 +1,9 @@
+package _000030_add_content_blob_table
+
+const upSqliteScript = `
+CREATE TABLE IF NOT EXISTS content_blob (
+	hash TEXT NOT NULL PRIMARY KEY,
+	ref_count INTEGER NOT NULL,
+	created_at DATETIME NOT NULL
+);
+`
@@ -0,0 +1,5 @@
+package _000031_add_preview_cache_entry_table
+
+const downPostgresScript = `
+DROP TABLE IF EXISTS preview_cache_entry;
+`
@@ -0,0 +1,13 @@
+package _000031_add_preview_cache_entry_table
+
+const upPostgresScript = `
+CREATE TABLE IF NOT EXISTS preview_cache_entry (
+	signature UUID PRIMARY KEY,
+	artifact_content_path VARCHAR NOT NULL,
+	artifact_metadata_path VARCHAR NOT NULL,
+	op_metadata_path VARCHAR NOT NULL,
+	size BIGINT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	last_accessed_at TIMESTAMPTZ NOT NULL
+);
+`
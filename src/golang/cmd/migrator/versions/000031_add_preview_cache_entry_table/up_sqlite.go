@@ -0,0 +1,13 @@
+package _000031_add_preview_cache_entry_table
+
+const upSqliteScript = `
+CREATE TABLE IF NOT EXISTS preview_cache_entry (
+	signature BLOB NOT NULL PRIMARY KEY,
+	artifact_content_path TEXT NOT NULL,
+	artifact_metadata_path TEXT NOT NULL,
+	op_metadata_path TEXT NOT NULL,
+	size INTEGER NOT NULL,
+	created_at DATETIME NOT NULL,
+	last_accessed_at DATETIME NOT NULL
+);
+`
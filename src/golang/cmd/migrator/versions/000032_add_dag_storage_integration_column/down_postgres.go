@@ -0,0 +1,5 @@
+package _000032_add_dag_storage_integration_column
+
+const downPostgresScript = `
+ALTER TABLE workflow_dag DROP COLUMN IF EXISTS storage_integration_id;
+`
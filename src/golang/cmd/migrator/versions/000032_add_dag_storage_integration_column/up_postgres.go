@@ -0,0 +1,6 @@
+package _000032_add_dag_storage_integration_column
+
+const upPostgresScript = `
+ALTER TABLE workflow_dag
+ADD COLUMN storage_integration_id UUID REFERENCES integration (id);
+`
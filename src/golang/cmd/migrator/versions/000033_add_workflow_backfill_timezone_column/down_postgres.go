@@ -0,0 +1,5 @@
+package _000033_add_workflow_backfill_timezone_column
+
+const downPostgresScript = `
+ALTER TABLE workflow_backfill DROP COLUMN IF EXISTS timezone;
+`
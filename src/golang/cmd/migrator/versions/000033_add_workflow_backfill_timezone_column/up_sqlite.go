@@ -0,0 +1,6 @@
+package _000033_add_workflow_backfill_timezone_column
+
+const upSqliteScript = `
+ALTER TABLE workflow_backfill
+ADD COLUMN timezone TEXT NOT NULL DEFAULT '';
+`
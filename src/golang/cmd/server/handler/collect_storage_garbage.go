@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/routes"
+	"github.com/aqueducthq/aqueduct/config"
+	aq_context "github.com/aqueducthq/aqueduct/lib/context"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage_gc"
+	"github.com/aqueducthq/aqueduct/lib/workflow/preview_cache"
+	"github.com/dropbox/godropbox/errors"
+)
+
+// Route: /api/storage/gc
+// Method: POST
+// Request:
+//
+//	Headers:
+//		`api-key`: user's API Key
+//		`dry-run`: (optional) if true, the orphaned objects are only reported, and nothing is deleted.
+//
+// Deletes the objects in the storage layer that are not referenced by any operator, artifact result,
+// vault entry or preview cache entry, and that are older than the configured grace period.
+//
+// Response: serialized `storage_gc.Report` object.
+type CollectStorageGarbageHandler struct {
+	PostHandler
+
+	Database database.Database
+
+	ArtifactRepo       repos.Artifact
+	ArtifactResultRepo repos.ArtifactResult
+	ContentBlobRepo    repos.ContentBlob
+	DAGRepo            repos.DAG
+	IntegrationRepo    repos.Integration
+	OperatorRepo       repos.Operator
+
+	// The preview cache is replaced when the server restarts, so it is fetched for each request.
+	PreviewCacheManagerFn func() preview_cache.CacheManager
+}
+
+type collectStorageGarbageArgs struct {
+	*aq_context.AqContext
+	dryRun bool
+}
+
+func (*CollectStorageGarbageHandler) Name() string {
+	return "CollectStorageGarbage"
+}
+
+func (*CollectStorageGarbageHandler) Headers() []string {
+	return []string{
+		routes.DryRunHeader,
+	}
+}
+
+func (*CollectStorageGarbageHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	aqContext, statusCode, err := aq_context.ParseAqContext(r.Context())
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "Unable to collect storage garbage.")
+	}
+
+	dryRun := false
+	if dryRunStr := r.Header.Get(routes.DryRunHeader); dryRunStr != "" {
+		dryRun, err = strconv.ParseBool(dryRunStr)
+		if err != nil {
+			return nil, http.StatusBadRequest, errors.Wrap(err, "Invalid value for the dry-run header.")
+		}
+	}
+
+	return &collectStorageGarbageArgs{
+		AqContext: aqContext,
+		dryRun:    dryRun,
+	}, http.StatusOK, nil
+}
+
+func (h *CollectStorageGarbageHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*collectStorageGarbageArgs)
+
+	gracePeriod, err := config.StorageGCGracePeriod()
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if gracePeriod == 0 {
+		gracePeriod = storage_gc.DefaultGracePeriod
+	}
+
+	previewEntries, err := h.PreviewCacheManagerFn().List(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to list preview cache entries.")
+	}
+
+	previewPaths := make([]string, 0, 3*len(previewEntries))
+	for _, entry := range previewEntries {
+		previewPaths = append(previewPaths, entry.ArtifactContentPath, entry.ArtifactMetadataPath, entry.OpMetadataPath)
+	}
+
+	storageConfig := config.Storage()
+	report, err := storage_gc.Collect(
+		ctx,
+		&storageConfig,
+		&storage_gc.Options{
+			GracePeriod:  gracePeriod,
+			DryRun:       args.dryRun,
+			PreviewPaths: previewPaths,
+		},
+		h.DAGRepo,
+		h.ArtifactRepo,
+		h.ArtifactResultRepo,
+		h.OperatorRepo,
+		h.IntegrationRepo,
+		h.ContentBlobRepo,
+		h.Database,
+	)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to collect storage garbage.")
+	}
+
+	return report, http.StatusOK, nil
+}
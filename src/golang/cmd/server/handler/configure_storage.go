@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"path"
+	"strconv"
 
 	"github.com/aqueducthq/aqueduct/cmd/server/routes"
 	"github.com/aqueducthq/aqueduct/config"
@@ -11,6 +12,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage"
 	"github.com/aqueducthq/aqueduct/lib/storage_migration"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/go-chi/chi/v5"
@@ -23,6 +25,13 @@ import (
 //
 //	Headers:
 //		`api-key`: user's API Key
+//		`compression`: (optional) the algorithm to compress stored objects with, either `gzip`, `zstd` or `none`.
+//			Defaults to the current setting.
+//		`encrypt`: (optional) whether to encrypt stored objects with the server's encryption key.
+//			Defaults to the current setting.
+//
+// If the storage layer is already the local filesystem and only the compression or encryption changes,
+// all existing objects are re-encoded in place.
 //
 // Response: none
 type ConfigureStorageHandler struct {
@@ -47,6 +56,9 @@ type configureStorageArgs struct {
 	// It should only be set if configureLocalStorage is false.
 	storageIntegrationID  uuid.UUID
 	configureLocalStorage bool
+
+	compression shared.CompressionType
+	encrypt     bool
 }
 
 func (*ConfigureStorageHandler) Name() string {
@@ -65,11 +77,34 @@ func (h *ConfigureStorageHandler) Prepare(r *http.Request) (interface{}, int, er
 		return nil, http.StatusBadRequest, errors.Wrap(err, "We currently only support changing the storage layer to the local filesystem from this route.")
 	}
 
+	currentStorageConfig := config.Storage()
+
+	compression := currentStorageConfig.Compression
+	switch compressionStr := r.Header.Get(routes.StorageCompressionHeader); compressionStr {
+	case "":
+	case "none":
+		compression = ""
+	case string(shared.GzipCompressionType), string(shared.ZstdCompressionType):
+		compression = shared.CompressionType(compressionStr)
+	default:
+		return nil, http.StatusBadRequest, errors.Newf("Unsupported compression %s.", compressionStr)
+	}
+
+	encrypt := currentStorageConfig.Encrypt
+	if encryptStr := r.Header.Get(routes.StorageEncryptHeader); encryptStr != "" {
+		encrypt, err = strconv.ParseBool(encryptStr)
+		if err != nil {
+			return nil, http.StatusBadRequest, errors.Wrap(err, "Invalid value for the encrypt header.")
+		}
+	}
+
 	return &configureStorageArgs{
 		AqContext: aqContext,
 		// TODO ENG-2574: Add support for switching to non-local storage
 		storageIntegrationID:  uuid.Nil,
 		configureLocalStorage: true,
+		compression:           compression,
+		encrypt:               encrypt,
 	}, http.StatusOK, nil
 }
 
@@ -83,15 +118,28 @@ func (h *ConfigureStorageHandler) Perform(ctx context.Context, interfaceArgs int
 
 	currentStorageConfig := config.Storage()
 
+	var newStorageConfig shared.StorageConfig
 	if currentStorageConfig.Type == shared.FileStorageType {
-		return nil, http.StatusBadRequest, errors.New("The storage layer is already set to the local filesystem.")
+		if currentStorageConfig.Compression == args.compression && currentStorageConfig.Encrypt == args.encrypt {
+			return nil, http.StatusBadRequest, errors.New("The storage layer is already set to the local filesystem.")
+		}
+
+		// The existing objects are re-encoded in place.
+		newStorageConfig = currentStorageConfig
+	} else {
+		newStorageConfig = shared.StorageConfig{
+			Type: shared.FileStorageType,
+			FileConfig: &shared.FileConfig{
+				Directory: path.Join(config.AqueductPath(), "storage"),
+			},
+		}
 	}
+	newStorageConfig.Compression = args.compression
+	newStorageConfig.Encrypt = args.encrypt
 
-	newStorageConfig := shared.StorageConfig{
-		Type: shared.FileStorageType,
-		FileConfig: &shared.FileConfig{
-			Directory: path.Join(config.AqueductPath(), "storage"),
-		},
+	// Make sure that the new storage layer is usable before the server is paused for the migration.
+	if err := storage.Validate(ctx, &newStorageConfig); err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "Unable to connect to the new storage layer.")
 	}
 
 	err := storage_migration.Perform(
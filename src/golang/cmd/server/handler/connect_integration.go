@@ -190,24 +190,38 @@ func (h *ConnectIntegrationHandler) Perform(ctx context.Context, interfaceArgs i
 		return emptyResp, statusCode, err
 	}
 
-	// Assumption: we are always ADDING a new integration, so `integrationObj` must be a freshly created integration entry.
-	// Note that the config of this returned `integrationObj` may be outdated.
-	integrationObj, statusCode, err := ConnectIntegration(ctx, h, args, h.IntegrationRepo, h.Database)
-	if err != nil {
-		return emptyResp, statusCode, err
-	}
-
+	var newStorageConfig *shared.StorageConfig
 	if args.SetAsStorage {
 		confData, err := args.Config.Marshal()
 		if err != nil {
 			return nil, http.StatusInternalServerError, err
 		}
 
-		newStorageConfig, err := storage.ConvertIntegrationConfigToStorageConfig(args.Service, confData)
+		newStorageConfig, err = storage.ConvertIntegrationConfigToStorageConfig(args.Service, confData)
 		if err != nil {
 			return emptyResp, http.StatusBadRequest, errors.Wrap(err, "Integration config is malformed.")
 		}
 
+		// Objects keep being compressed and encrypted the same way in the new storage layer.
+		currentStorageConfig := config.Storage()
+		newStorageConfig.Compression = currentStorageConfig.Compression
+		newStorageConfig.Encrypt = currentStorageConfig.Encrypt
+
+		// Make sure that the new storage layer is usable before the integration is connected
+		// and the server is paused for the migration.
+		if err := storage.Validate(ctx, newStorageConfig); err != nil {
+			return emptyResp, http.StatusBadRequest, errors.Wrap(err, "Unable to connect to the new storage layer.")
+		}
+	}
+
+	// Assumption: we are always ADDING a new integration, so `integrationObj` must be a freshly created integration entry.
+	// Note that the config of this returned `integrationObj` may be outdated.
+	integrationObj, statusCode, err := ConnectIntegration(ctx, h, args, h.IntegrationRepo, h.Database)
+	if err != nil {
+		return emptyResp, statusCode, err
+	}
+
+	if args.SetAsStorage {
 		err = storage_migration.Perform(
 			ctx,
 			args.OrgID,
@@ -319,7 +333,7 @@ func ConnectIntegration(
 	}()
 
 	storageConfig := config.Storage()
-	vaultObject, err := vault.NewVault(&storageConfig, config.EncryptionKey())
+	vaultObject, err := vault.NewVault(config.Vault(), &storageConfig, config.EncryptionKeyring())
 	if err != nil {
 		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to initialize vault.")
 	}
@@ -122,7 +122,7 @@ func (h *CreateTableHandler) Perform(ctx context.Context, interfaceArgs interfac
 	emptyResp := CreateTableResponse{}
 
 	storageConfig := config.Storage()
-	vaultObject, err := vault.NewVault(&storageConfig, config.EncryptionKey())
+	vaultObject, err := vault.NewVault(config.Vault(), &storageConfig, config.EncryptionKeyring())
 	if err != nil {
 		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to initialize vault.")
 	}
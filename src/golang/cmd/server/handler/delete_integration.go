@@ -100,6 +100,19 @@ func (h *DeleteIntegrationHandler) Prepare(r *http.Request) (interface{}, int, e
 		return nil, http.StatusBadRequest, errors.New("Cannot delete an integration that is being used as artifact storage.")
 	}
 
+	dags, err := h.DAGRepo.List(r.Context(), h.Database)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error occurred while retrieving workflow dags.")
+	}
+	for _, dag := range dags {
+		if !dag.StorageIntegrationID.IsNull && dag.StorageIntegrationID.UUID == integrationObject.ID {
+			return nil, http.StatusBadRequest, errors.Newf(
+				"Cannot delete an integration that is being used as the artifact storage of workflow %v.",
+				dag.WorkflowID,
+			)
+		}
+	}
+
 	return &deleteIntegrationArgs{
 		AqContext:                    aqContext,
 		integrationObject:            integrationObject,
@@ -145,7 +158,7 @@ func (h *DeleteIntegrationHandler) Perform(ctx context.Context, interfaceArgs in
 	}
 
 	storageConfig := config.Storage()
-	vaultObject, err := vault.NewVault(&storageConfig, config.EncryptionKey())
+	vaultObject, err := vault.NewVault(config.Vault(), &storageConfig, config.EncryptionKeyring())
 	if err != nil {
 		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to initialize vault.")
 	}
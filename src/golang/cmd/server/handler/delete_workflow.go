@@ -214,7 +214,7 @@ func (h *DeleteWorkflowHandler) Perform(ctx context.Context, interfaceArgs inter
 	}
 
 	storageConfig := config.Storage()
-	vaultObject, err := vault.NewVault(&storageConfig, config.EncryptionKey())
+	vaultObject, err := vault.NewVault(config.Vault(), &storageConfig, config.EncryptionKeyring())
 	if err != nil {
 		return resp, http.StatusInternalServerError, errors.Wrap(err, "Unable to initialize vault.")
 	}
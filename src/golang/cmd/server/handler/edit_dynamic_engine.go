@@ -140,7 +140,7 @@ func (h *EditDynamicEngineHandler) Perform(ctx context.Context, interfaceArgs in
 	}
 
 	storageConfig := config.Storage()
-	vaultObject, err := vault.NewVault(&storageConfig, config.EncryptionKey())
+	vaultObject, err := vault.NewVault(config.Vault(), &storageConfig, config.EncryptionKeyring())
 	if err != nil {
 		return emptyResponse, http.StatusInternalServerError, errors.Wrap(err, "Unable to initialize vault.")
 	}
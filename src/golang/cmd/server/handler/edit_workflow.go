@@ -48,15 +48,18 @@ type editWorkflowInput struct {
 	Schedule             *shared.Schedule             `json:"schedule"`
 	RetentionPolicy      *shared.RetentionPolicy      `json:"retention_policy"`
 	NotificationSettings *shared.NotificationSettings `json:"notification_settings"`
+	// If 0, the workflow uses the server-wide default.
+	MaxConcurrentOperators *int `json:"max_concurrent_operators"`
 }
 
 type editWorkflowArgs struct {
-	workflowId           uuid.UUID
-	workflowName         string
-	workflowDescription  string
-	schedule             *shared.Schedule
-	retentionPolicy      *shared.RetentionPolicy
-	notificationSettings *shared.NotificationSettings
+	workflowId             uuid.UUID
+	workflowName           string
+	workflowDescription    string
+	schedule               *shared.Schedule
+	retentionPolicy        *shared.RetentionPolicy
+	notificationSettings   *shared.NotificationSettings
+	maxConcurrentOperators *int
 }
 
 func (*EditWorkflowHandler) Name() string {
@@ -112,18 +115,23 @@ func (h *EditWorkflowHandler) Prepare(r *http.Request) (interface{}, int, error)
 		return nil, http.StatusBadRequest, errors.New("Cannot pause a manually updated workflow.")
 	}
 
+	if input.MaxConcurrentOperators != nil && *input.MaxConcurrentOperators < 0 {
+		return nil, http.StatusBadRequest, errors.New("The maximum number of concurrent operators cannot be negative.")
+	}
+
 	// Finally, we check if there are an updates at all.
-	if input.WorkflowName == "" && input.WorkflowDescription == "" && input.Schedule.Trigger == "" {
+	if input.WorkflowName == "" && input.WorkflowDescription == "" && input.Schedule.Trigger == "" && input.MaxConcurrentOperators == nil {
 		return nil, http.StatusBadRequest, errors.New("Edit request issued without any updates specified.")
 	}
 
 	return &editWorkflowArgs{
-		workflowId:           workflowID,
-		workflowName:         input.WorkflowName,
-		workflowDescription:  input.WorkflowDescription,
-		schedule:             input.Schedule,
-		retentionPolicy:      input.RetentionPolicy,
-		notificationSettings: input.NotificationSettings,
+		workflowId:             workflowID,
+		workflowName:           input.WorkflowName,
+		workflowDescription:    input.WorkflowDescription,
+		schedule:               input.Schedule,
+		retentionPolicy:        input.RetentionPolicy,
+		notificationSettings:   input.NotificationSettings,
+		maxConcurrentOperators: input.MaxConcurrentOperators,
 	}, http.StatusOK, nil
 }
 
@@ -177,6 +185,7 @@ func (h *EditWorkflowHandler) Perform(ctx context.Context, interfaceArgs interfa
 		args.schedule,
 		args.retentionPolicy,
 		args.notificationSettings,
+		args.maxConcurrentOperators,
 	)
 	if err != nil {
 		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to update workflow.")
@@ -133,11 +133,10 @@ func (h *ExportFunctionHandlerDeprecated) Perform(ctx context.Context, interface
 		return emptyResp, http.StatusInternalServerError, errors.New("Could not find workflow that contains this operator.")
 	}
 
-	// Note: for now we assume all workflow dags have the same storage config.
-	// This assumption will stay true until we allow users to configure custom storage config to store stuff.
+	// All of the dags of a workflow store their content in the workflow's storage layer.
 	storageConfig := dags[0].StorageConfig
 	for _, workflowDag := range dags {
-		if workflowDag.StorageConfig != storageConfig {
+		if !storage.SameLocation(&workflowDag.StorageConfig, &storageConfig) {
 			return emptyResp, http.StatusInternalServerError, errors.New("Workflow Dags have mismatching storage config.")
 		}
 	}
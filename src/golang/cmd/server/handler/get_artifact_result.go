@@ -269,6 +269,8 @@ func (h *GetArtifactResultHandlerDeprecated) Perform(ctx context.Context, interf
 	if err == nil {
 		response.Data = data
 		metadata.IsDownsampled = isDownsampled
+	} else if errors.Is(err, storage.ErrContentCorrupted()) {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "The data of the artifact result is corrupted.")
 	} else if !errors.Is(err, storage.ErrObjectDoesNotExist()) {
 		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Failed to retrieve data for the artifact result.")
 	}
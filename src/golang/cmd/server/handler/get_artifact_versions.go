@@ -344,6 +344,9 @@ func (h *GetArtifactVersionsHandler) updateVersionsWithChecksAndMetrics(
 						storageObj := storage.NewStorage(&dag.StorageConfig)
 						path := metricResult.ContentPath
 						contentBytes, err := storageObj.Get(ctx, path)
+						if err == nil {
+							err = storage.VerifyChecksum(path, contentBytes, metricResult.Metadata.Checksum)
+						}
 						if err == nil {
 							content := string(contentBytes)
 							contentPtr = &content
@@ -133,6 +133,7 @@ func (h *GetOperatorResultHandlerDeprecated) Perform(ctx context.Context, interf
 		executionState.Error = dbOperatorResult.ExecState.Error
 		executionState.UserLogs = dbOperatorResult.ExecState.UserLogs
 		executionState.Status = dbOperatorResult.ExecState.Status
+		executionState.Attempts = dbOperatorResult.ExecState.Attempts
 	}
 
 	response := GetOperatorResultResponse{
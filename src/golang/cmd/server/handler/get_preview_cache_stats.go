@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	aq_context "github.com/aqueducthq/aqueduct/lib/context"
+	"github.com/aqueducthq/aqueduct/lib/workflow/preview_cache"
+	"github.com/dropbox/godropbox/errors"
+)
+
+// Route: /api/preview/cache/stats
+// Method: GET
+// Request:
+//
+//	Headers:
+//		`api-key`: user's API Key
+//
+// Response: serialized `preview_cache.Stats` object, with the hits, misses and evictions
+// of the preview cache since the server started, and its current size.
+type GetPreviewCacheStatsHandler struct {
+	GetHandler
+
+	// The preview cache is replaced when the server restarts, so it is fetched for each request.
+	PreviewCacheManagerFn func() preview_cache.CacheManager
+}
+
+type getPreviewCacheStatsArgs struct {
+	*aq_context.AqContext
+}
+
+func (*GetPreviewCacheStatsHandler) Name() string {
+	return "GetPreviewCacheStats"
+}
+
+func (*GetPreviewCacheStatsHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	aqContext, statusCode, err := aq_context.ParseAqContext(r.Context())
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "Unable to get preview cache stats.")
+	}
+
+	return &getPreviewCacheStatsArgs{
+		AqContext: aqContext,
+	}, http.StatusOK, nil
+}
+
+func (h *GetPreviewCacheStatsHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	stats, err := h.PreviewCacheManagerFn().Stats(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to get preview cache stats.")
+	}
+
+	return stats, http.StatusOK, nil
+}
@@ -179,6 +179,9 @@ func getArtifactContents(
 					// If the data does not exist, skip the fetch.
 					continue
 				}
+				if err == nil {
+					err = storage.VerifyChecksum(path, contentBytes, artfResult.Metadata.Checksum)
+				}
 				if err != nil {
 					return nil, errors.Wrap(err, "Unable to get artifact content from storage")
 				}
@@ -141,6 +141,9 @@ func (h *ListArtifactResultsHandlerDeprecated) Perform(ctx context.Context, inte
 					exists := storageObj.Exists(ctx, artfResult.ContentPath)
 					if exists {
 						contentBytes, err := storageObj.Get(ctx, artfResult.ContentPath)
+						if err == nil {
+							err = storage.VerifyChecksum(artfResult.ContentPath, contentBytes, artfResult.Metadata.Checksum)
+						}
 						if err != nil {
 							return emptyResponse, http.StatusInternalServerError, errors.Wrap(err, fmt.Sprintf("Error retrieving artifact content for result %s", artfResult.ID))
 						}
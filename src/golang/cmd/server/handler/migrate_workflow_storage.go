@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/routes"
+	"github.com/aqueducthq/aqueduct/config"
+	aq_context "github.com/aqueducthq/aqueduct/lib/context"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage_migration"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Route: /workflow/{workflowId}/storage/{integrationId}
+// Method: POST
+// Params:
+//
+//	`workflowId`: ID of the workflow whose content is migrated.
+//	`integrationId`: ID of the storage integration to migrate the workflow's content to,
+//		or `local` to migrate it back to the server's storage layer.
+//
+// Request:
+//
+//	Headers:
+//		`api-key`: user's API Key
+//
+// Moves the artifact results and operator code of all versions of the workflow to the new
+// storage layer. The migration waits until no workflow is running.
+//
+// Response: none
+type MigrateWorkflowStorageHandler struct {
+	PostHandler
+
+	Database database.Database
+
+	ArtifactResultRepo repos.ArtifactResult
+	ContentBlobRepo    repos.ContentBlob
+	DAGRepo            repos.DAG
+	DAGResultRepo      repos.DAGResult
+	IntegrationRepo    repos.Integration
+	OperatorRepo       repos.Operator
+	WorkflowRepo       repos.Workflow
+}
+
+type migrateWorkflowStorageArgs struct {
+	*aq_context.AqContext
+	workflowID uuid.UUID
+	// This is nil if the workflow is migrated to the server's storage layer.
+	storageIntegrationID *uuid.UUID
+}
+
+func (*MigrateWorkflowStorageHandler) Name() string {
+	return "MigrateWorkflowStorage"
+}
+
+func (h *MigrateWorkflowStorageHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	aqContext, statusCode, err := aq_context.ParseAqContext(r.Context())
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "Unable to migrate workflow storage.")
+	}
+
+	workflowID, err := uuid.Parse(chi.URLParam(r, routes.WorkflowIdUrlParam))
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "Malformed workflow ID.")
+	}
+
+	ok, err := h.WorkflowRepo.ValidateOrg(
+		r.Context(),
+		workflowID,
+		aqContext.OrgID,
+		h.Database,
+	)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during workflow ownership validation.")
+	}
+	if !ok {
+		return nil, http.StatusBadRequest, errors.New("The organization does not own this workflow.")
+	}
+
+	var storageIntegrationID *uuid.UUID
+	if integrationIDStr := chi.URLParam(r, routes.IntegrationIdUrlParam); integrationIDStr != "local" {
+		integrationID, err := uuid.Parse(integrationIDStr)
+		if err != nil {
+			return nil, http.StatusBadRequest, errors.Wrap(err, "Malformed integration ID.")
+		}
+
+		ok, err := h.IntegrationRepo.ValidateOwnership(
+			r.Context(),
+			integrationID,
+			aqContext.OrgID,
+			aqContext.ID,
+			h.Database,
+		)
+		if err != nil {
+			return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during integration ownership validation.")
+		}
+		if !ok {
+			return nil, http.StatusBadRequest, errors.New("The organization does not own the storage integration.")
+		}
+
+		storageIntegrationID = &integrationID
+	}
+
+	return &migrateWorkflowStorageArgs{
+		AqContext:            aqContext,
+		workflowID:           workflowID,
+		storageIntegrationID: storageIntegrationID,
+	}, http.StatusOK, nil
+}
+
+func (h *MigrateWorkflowStorageHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*migrateWorkflowStorageArgs)
+
+	latestDAG, err := h.DAGRepo.GetLatestByWorkflow(ctx, args.workflowID, h.Database)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to retrieve the workflow's storage layer.")
+	}
+
+	if latestDAG.StorageIntegrationID.IsNull && args.storageIntegrationID == nil ||
+		!latestDAG.StorageIntegrationID.IsNull && args.storageIntegrationID != nil &&
+			latestDAG.StorageIntegrationID.UUID == *args.storageIntegrationID {
+		return nil, http.StatusBadRequest, errors.New("The workflow already uses this storage layer.")
+	}
+
+	var newStorageConfig *shared.StorageConfig
+	if args.storageIntegrationID == nil {
+		serverStorageConfig := config.Storage()
+		newStorageConfig = &serverStorageConfig
+	} else {
+		integrationObj, err := h.IntegrationRepo.Get(ctx, *args.storageIntegrationID, h.Database)
+		if err != nil {
+			return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to retrieve storage integration.")
+		}
+
+		vaultObject, err := vault.NewVault(config.Vault(), args.StorageConfig, config.EncryptionKeyring())
+		if err != nil {
+			return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to initialize vault.")
+		}
+
+		newStorageConfig, err = storage_migration.NewWorkflowStorageConfig(ctx, integrationObj, vaultObject)
+		if err != nil {
+			return nil, http.StatusBadRequest, errors.Wrap(err, "Unable to use the integration as the workflow's storage layer.")
+		}
+	}
+
+	if err := storage_migration.MigrateWorkflow(
+		ctx,
+		args.workflowID,
+		args.storageIntegrationID,
+		newStorageConfig,
+		h.ArtifactResultRepo,
+		h.ContentBlobRepo,
+		h.DAGRepo,
+		h.DAGResultRepo,
+		h.OperatorRepo,
+		h.Database,
+	); err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to migrate workflow storage.")
+	}
+
+	return nil, http.StatusOK, nil
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/aqueducthq/aqueduct/cmd/server/request"
+	"github.com/aqueducthq/aqueduct/config"
 	aq_context "github.com/aqueducthq/aqueduct/lib/context"
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/engine"
@@ -16,6 +17,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/repos"
 	"github.com/aqueducthq/aqueduct/lib/spark"
+	"github.com/aqueducthq/aqueduct/lib/vault"
 	dag_utils "github.com/aqueducthq/aqueduct/lib/workflow/dag"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/github"
@@ -169,6 +171,19 @@ func (h *PreviewHandler) Prepare(r *http.Request) (interface{}, int, error) {
 		}
 	}
 
+	vaultObject, err := vault.NewVault(config.Vault(), aqContext.StorageConfig, config.EncryptionKeyring())
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to initialize vault.")
+	}
+
+	if err := dag_utils.ValidateK8sScheduling(r.Context(), dagSummary.Dag, vaultObject); err != nil {
+		if _, ok := dag_utils.ValidationErrors[err]; !ok {
+			return nil, http.StatusInternalServerError, errors.Wrap(err, "Internal system error occurred while validating the DAG.")
+		} else {
+			return nil, http.StatusBadRequest, err
+		}
+	}
+
 	return &previewArgs{
 		AqContext:  aqContext,
 		DagSummary: dagSummary,
@@ -81,6 +81,10 @@ func (h *RefreshWorkflowHandler) Prepare(r *http.Request) (interface{}, int, err
 		return nil, http.StatusBadRequest, errors.Wrap(err, "The user-defined parameters could not be extracted in current format.")
 	}
 
+	if _, ok := parameters[param.ExecutionTimeParamName]; ok {
+		return nil, http.StatusBadRequest, errors.Newf("The parameter name %s is reserved for backfill runs.", param.ExecutionTimeParamName)
+	}
+
 	return &RefreshWorkflowArgs{
 		WorkflowId: workflowID,
 		Parameters: parameters,
@@ -9,6 +9,7 @@ import (
 
 	"github.com/aqueducthq/aqueduct/cmd/server/request"
 	"github.com/aqueducthq/aqueduct/cmd/server/routes"
+	"github.com/aqueducthq/aqueduct/config"
 	aq_context "github.com/aqueducthq/aqueduct/lib/context"
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/engine"
@@ -19,6 +20,8 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	mdl_utils "github.com/aqueducthq/aqueduct/lib/models/utils"
 	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage_migration"
+	"github.com/aqueducthq/aqueduct/lib/vault"
 	"github.com/aqueducthq/aqueduct/lib/workflow"
 	dag_utils "github.com/aqueducthq/aqueduct/lib/workflow/dag"
 	operator_utils "github.com/aqueducthq/aqueduct/lib/workflow/operator"
@@ -34,6 +37,9 @@ import (
 // Request
 //	Headers:
 //		`api-key`: user's API Key
+//		`storage-integration-id`: (optional) the ID of the storage integration that the workflow's
+//			content is stored in. Defaults to the server's storage layer. It can only be set when the
+//			workflow is created.
 //	Body:
 //		`dag`: a serialized `workflow_dag` object
 //		`<operator_id>`: zip file associated with operator for the `operator_id`.
@@ -62,6 +68,9 @@ type registerWorkflowArgs struct {
 	*aq_context.AqContext
 	dagSummary *request.DagSummary
 
+	// The storage integration that the workflow's content is stored in, if any.
+	storageIntegrationID *uuid.UUID
+
 	// Whether this is a registering a new workflow or updating an existing one.
 	isUpdate bool
 	runNow   bool
@@ -97,6 +106,30 @@ func (h *RegisterWorkflowHandler) Prepare(r *http.Request) (interface{}, int, er
 		}
 	}
 
+	var storageIntegrationID *uuid.UUID
+	if storageIntegrationIDStr := r.Header.Get(routes.StorageIntegrationIDHeader); storageIntegrationIDStr != "" {
+		integrationID, err := uuid.Parse(storageIntegrationIDStr)
+		if err != nil {
+			return nil, http.StatusBadRequest, errors.Wrap(err, "Malformed storage integration ID.")
+		}
+
+		ok, err := h.IntegrationRepo.ValidateOwnership(
+			r.Context(),
+			integrationID,
+			aqContext.OrgID,
+			aqContext.ID,
+			h.Database,
+		)
+		if err != nil {
+			return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during integration ownership validation.")
+		}
+		if !ok {
+			return nil, http.StatusBadRequest, errors.New("The organization does not own the storage integration.")
+		}
+
+		storageIntegrationID = &integrationID
+	}
+
 	dagSummary, statusCode, err := request.ParseDagSummaryFromRequest(
 		r,
 		aqContext.ID,
@@ -154,11 +187,25 @@ func (h *RegisterWorkflowHandler) Prepare(r *http.Request) (interface{}, int, er
 		}
 	}
 
+	vaultObject, err := vault.NewVault(config.Vault(), aqContext.StorageConfig, config.EncryptionKeyring())
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to initialize vault.")
+	}
+
+	if err := dag_utils.ValidateK8sScheduling(r.Context(), dagSummary.Dag, vaultObject); err != nil {
+		if _, ok := dag_utils.ValidationErrors[err]; !ok {
+			return nil, http.StatusInternalServerError, errors.Wrap(err, "Internal system error occurred while validating the DAG.")
+		} else {
+			return nil, http.StatusBadRequest, err
+		}
+	}
+
 	return &registerWorkflowArgs{
-		AqContext:  aqContext,
-		dagSummary: dagSummary,
-		isUpdate:   isUpdate,
-		runNow:     runNow,
+		AqContext:            aqContext,
+		dagSummary:           dagSummary,
+		storageIntegrationID: storageIntegrationID,
+		isUpdate:             isUpdate,
+		runNow:               runNow,
 	}, http.StatusOK, nil
 }
 
@@ -169,6 +216,11 @@ func (h *RegisterWorkflowHandler) Perform(ctx context.Context, interfaceArgs int
 
 	emptyResp := registerWorkflowResponse{}
 
+	// The operator files are uploaded to the workflow's storage layer, so it is set up first.
+	if status, err := h.setWorkflowStorage(ctx, args); err != nil {
+		return emptyResp, status, err
+	}
+
 	if _, err := operator_utils.UploadOperatorFiles(
 		ctx,
 		dbWorkflowDag,
@@ -267,6 +319,12 @@ func (h *RegisterWorkflowHandler) Perform(ctx context.Context, interfaceArgs int
 	args.dagSummary.Dag.Metadata.ID = workflowId
 
 	if args.isUpdate {
+		// The concurrency limit is left as is unless the new version of the workflow sets one.
+		var maxConcurrentOperators *int
+		if dbWorkflowDag.Metadata.MaxConcurrentOperators > 0 {
+			maxConcurrentOperators = &dbWorkflowDag.Metadata.MaxConcurrentOperators
+		}
+
 		// If we're updating an existing workflow, first update the metadata.
 		err := h.Engine.EditWorkflow(
 			ctx,
@@ -277,6 +335,7 @@ func (h *RegisterWorkflowHandler) Perform(ctx context.Context, interfaceArgs int
 			&dbWorkflowDag.Metadata.Schedule,
 			&dbWorkflowDag.Metadata.RetentionPolicy,
 			&dbWorkflowDag.Metadata.NotificationSettings,
+			maxConcurrentOperators,
 		)
 		if err != nil {
 			return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to update workflow.")
@@ -290,7 +349,7 @@ func (h *RegisterWorkflowHandler) Perform(ctx context.Context, interfaceArgs int
 				ctx,
 				workflowId,
 				shared_utils.AppendPrefix(dbWorkflowDag.Metadata.ID.String()),
-				string(dbWorkflowDag.Metadata.Schedule.CronSchedule),
+				&dbWorkflowDag.Metadata.Schedule,
 			)
 
 			if err != nil {
@@ -378,3 +437,54 @@ func (h *RegisterWorkflowHandler) Perform(ctx context.Context, interfaceArgs int
 		PythonVersion: version.String(),
 	}, http.StatusOK, nil
 }
+
+// setWorkflowStorage sets the storage layer of the dag being registered. A new workflow uses the
+// requested storage integration, if any. An updated workflow keeps its current storage layer,
+// since the code of its existing operators is stored there.
+func (h *RegisterWorkflowHandler) setWorkflowStorage(ctx context.Context, args *registerWorkflowArgs) (int, error) {
+	dag := args.dagSummary.Dag
+
+	if args.isUpdate {
+		latestDAG, err := h.DAGRepo.GetLatestByWorkflow(ctx, dag.WorkflowID, h.Database)
+		if err != nil {
+			return http.StatusInternalServerError, errors.Wrap(err, "Unable to retrieve the workflow's storage layer.")
+		}
+
+		if args.storageIntegrationID != nil &&
+			(latestDAG.StorageIntegrationID.IsNull || latestDAG.StorageIntegrationID.UUID != *args.storageIntegrationID) {
+			return http.StatusBadRequest, errors.Newf(
+				"The storage layer of an existing workflow can only be changed with %s.",
+				routes.MigrateWorkflowStorageRoute,
+			)
+		}
+
+		if !latestDAG.StorageIntegrationID.IsNull {
+			dag.StorageConfig = latestDAG.StorageConfig
+			dag.StorageIntegrationID = latestDAG.StorageIntegrationID
+		}
+		return http.StatusOK, nil
+	}
+
+	if args.storageIntegrationID == nil {
+		return http.StatusOK, nil
+	}
+
+	integrationObj, err := h.IntegrationRepo.Get(ctx, *args.storageIntegrationID, h.Database)
+	if err != nil {
+		return http.StatusInternalServerError, errors.Wrap(err, "Unable to retrieve storage integration.")
+	}
+
+	vaultObject, err := vault.NewVault(config.Vault(), args.StorageConfig, config.EncryptionKeyring())
+	if err != nil {
+		return http.StatusInternalServerError, errors.Wrap(err, "Unable to initialize vault.")
+	}
+
+	storageConfig, err := storage_migration.NewWorkflowStorageConfig(ctx, integrationObj, vaultObject)
+	if err != nil {
+		return http.StatusBadRequest, errors.Wrap(err, "Unable to use the integration as the workflow's storage layer.")
+	}
+
+	dag.StorageConfig = *storageConfig
+	dag.StorageIntegrationID = mdl_utils.NullUUID{UUID: integrationObj.ID, IsNull: false}
+	return http.StatusOK, nil
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/routes"
+	"github.com/aqueducthq/aqueduct/config"
+	aq_context "github.com/aqueducthq/aqueduct/lib/context"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+)
+
+// Route: /api/vault/retire-key
+// Method: POST
+// Request:
+//
+//	Headers:
+//		`api-key`: user's API Key
+//		`key-id`: the ID of the key to retire. The original key of the keyring has the empty ID.
+//
+// The vault entries and the stored objects that are still encrypted with the key are re-encrypted with
+// the current key, both in the server's storage layer and in the storage layers of workflows, after which
+// the key is removed from the server's keyring. The key is kept if any storage layer cannot be re-encrypted.
+// The current key cannot be retired. If this is interrupted, it can be safely retried.
+//
+// Response: serialized `retireEncryptionKeyResponse` object.
+type RetireEncryptionKeyHandler struct {
+	PostHandler
+
+	Database        database.Database
+	DAGRepo         repos.DAG
+	IntegrationRepo repos.Integration
+
+	PauseServerFn   func()
+	RestartServerFn func()
+}
+
+type retireEncryptionKeyArgs struct {
+	*aq_context.AqContext
+	keyID string
+}
+
+type retireEncryptionKeyResponse struct {
+	KeyID                 string `json:"key_id"`
+	NumReencryptedEntries int    `json:"num_reencrypted_entries"`
+	NumReencryptedObjects int    `json:"num_reencrypted_objects"`
+}
+
+func (*RetireEncryptionKeyHandler) Name() string {
+	return "RetireEncryptionKey"
+}
+
+func (*RetireEncryptionKeyHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	aqContext, statusCode, err := aq_context.ParseAqContext(r.Context())
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "Unable to retire encryption key.")
+	}
+
+	return &retireEncryptionKeyArgs{
+		AqContext: aqContext,
+		keyID:     r.Header.Get(routes.KeyIDHeader),
+	}, http.StatusOK, nil
+}
+
+func (h *RetireEncryptionKeyHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*retireEncryptionKeyArgs)
+	emptyResp := retireEncryptionKeyResponse{}
+
+	keyring := config.EncryptionKeyring()
+	if _, ok := keyring.Keys[args.keyID]; !ok {
+		return emptyResp, http.StatusBadRequest, errors.Newf("Encryption key %s is not in the keyring.", args.keyID)
+	}
+
+	if args.keyID == keyring.CurrentKeyID {
+		return emptyResp, http.StatusBadRequest, errors.Newf("Encryption key %s is the current key, so it cannot be retired.", args.keyID)
+	}
+
+	// No other requests can use the vault or the storage layer while their content is re-encrypted.
+	// Restarting the server also makes sure that it, and the jobs it launches, stop using the key.
+	h.PauseServerFn()
+	defer h.RestartServerFn()
+
+	numReencryptedEntries := 0
+	storageConfig := config.Storage()
+	if !config.Vault().IsExternal() {
+		vaultObject, err := vault.NewVault(nil /* vaultConf */, &storageConfig, keyring)
+		if err != nil {
+			return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to initialize vault.")
+		}
+
+		numReencryptedEntries, err = utils.ReencryptVault(ctx, vaultObject, args.OrgID, h.IntegrationRepo, h.Database)
+		if err != nil {
+			return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to re-encrypt vault.")
+		}
+	}
+
+	numReencryptedObjects, err := utils.ReencryptStorage(ctx, &storageConfig, args.keyID, h.DAGRepo, h.Database)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to re-encrypt stored objects.")
+	}
+
+	// The key is only removed once nothing is encrypted with it anymore.
+	if err := config.RetireEncryptionKey(args.keyID); err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to retire encryption key.")
+	}
+
+	return retireEncryptionKeyResponse{
+		KeyID:                 args.keyID,
+		NumReencryptedEntries: numReencryptedEntries,
+		NumReencryptedObjects: numReencryptedObjects,
+	}, http.StatusOK, nil
+}
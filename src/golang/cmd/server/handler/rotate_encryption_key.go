@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/routes"
+	"github.com/aqueducthq/aqueduct/config"
+	aq_context "github.com/aqueducthq/aqueduct/lib/context"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+// The length of a generated vault encryption key, which makes it a valid AES-256 key.
+const encryptionKeyLength = 32
+
+// Route: /api/vault/rotate-key
+// Method: POST
+// Request:
+//
+//	Headers:
+//		`api-key`: user's API Key
+//		`resume`: (optional) if true, no new key is generated, and the vault entries that are
+//			not yet encrypted with the current key are re-encrypted. This is used to finish a
+//			rotation that was interrupted.
+//
+// A new key is added to the server's keyring and becomes the key that new vault entries and stored objects
+// are encrypted with. All existing vault entries are then re-encrypted with it. The previous keys stay in the
+// keyring, so that entries and objects that have not been re-encrypted yet can still be read, until they are
+// retired (see `RetireEncryptionKeyHandler`). This is not supported if secrets are kept in an external vault.
+//
+// Response: serialized `rotateEncryptionKeyResponse` object.
+type RotateEncryptionKeyHandler struct {
+	PostHandler
+
+	Database        database.Database
+	IntegrationRepo repos.Integration
+
+	PauseServerFn   func()
+	RestartServerFn func()
+}
+
+type rotateEncryptionKeyArgs struct {
+	*aq_context.AqContext
+	resume bool
+}
+
+type rotateEncryptionKeyResponse struct {
+	KeyID          string `json:"key_id"`
+	NumReencrypted int    `json:"num_reencrypted"`
+}
+
+func (*RotateEncryptionKeyHandler) Name() string {
+	return "RotateEncryptionKey"
+}
+
+func (*RotateEncryptionKeyHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	aqContext, statusCode, err := aq_context.ParseAqContext(r.Context())
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "Unable to rotate encryption key.")
+	}
+
+	resume := false
+	if resumeStr := r.Header.Get(routes.ResumeHeader); resumeStr != "" {
+		resume, err = strconv.ParseBool(resumeStr)
+		if err != nil {
+			return nil, http.StatusBadRequest, errors.Wrap(err, "Invalid value for the resume header.")
+		}
+	}
+
+	return &rotateEncryptionKeyArgs{
+		AqContext: aqContext,
+		resume:    resume,
+	}, http.StatusOK, nil
+}
+
+func (h *RotateEncryptionKeyHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*rotateEncryptionKeyArgs)
+	emptyResp := rotateEncryptionKeyResponse{}
+
+	if config.Vault().IsExternal() {
+		return emptyResp, http.StatusBadRequest, errors.New("Secrets are kept in an external vault, which manages their encryption.")
+	}
+
+	// No other requests can use the vault while the keyring changes and the entries are re-encrypted.
+	// Restarting the server also makes sure that it picks up the new keyring.
+	h.PauseServerFn()
+	defer h.RestartServerFn()
+
+	if !args.resume {
+		key, err := generateEncryptionKey()
+		if err != nil {
+			return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to generate encryption key.")
+		}
+
+		// The new key is persisted before any entry is encrypted with it, so no entry
+		// becomes unreadable if the server crashes during the rotation.
+		if err := config.AddEncryptionKey(uuid.New().String(), key); err != nil {
+			return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to add encryption key.")
+		}
+	}
+
+	keyring := config.EncryptionKeyring()
+	storageConfig := config.Storage()
+	vaultObject, err := vault.NewVault(nil /* vaultConf */, &storageConfig, keyring)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to initialize vault.")
+	}
+
+	numReencrypted, err := utils.ReencryptVault(ctx, vaultObject, args.OrgID, h.IntegrationRepo, h.Database)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(
+			err,
+			"Unable to re-encrypt vault. The rotation can be finished by retrying with the resume header set.",
+		)
+	}
+
+	return rotateEncryptionKeyResponse{
+		KeyID:          keyring.CurrentKeyID,
+		NumReencrypted: numReencrypted,
+	}, http.StatusOK, nil
+}
+
+// generateEncryptionKey generates a random hex-encoded key.
+func generateEncryptionKey() (string, error) {
+	b := make([]byte, encryptionKeyLength/2)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", b), nil
+}
@@ -0,0 +1,173 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/handler"
+	"github.com/aqueducthq/aqueduct/cmd/server/request/parser"
+	"github.com/aqueducthq/aqueduct/lib/backfill"
+	aq_context "github.com/aqueducthq/aqueduct/lib/context"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/engine"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/response"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+// This file should map directly to
+// src/ui/common/src/handlers/v2/BackfillCreate.tsx
+//
+// Route: /v2/workflow/{workflowId}/backfill
+// Method: POST
+// Params:
+//	`workflowId`: ID for `workflow` object
+// Request:
+//	Headers:
+//		`api-key`: user's API Key
+//	Body:
+//		serialized `backfillCreateInput` object.
+// Response:
+//	Body:
+//		serialized `response.Backfill`
+//
+// Starts a backfill of a periodic workflow, which runs the workflow once for every tick of its
+// cron schedule within [start_time, end_time]. Each run is passed the tick it executes for as
+// the reserved `aqueduct_execution_time` parameter. At most `max_concurrency` runs are in progress at a time.
+
+type backfillCreateInput struct {
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+	MaxConcurrency int       `json:"max_concurrency"`
+}
+
+type backfillCreateArgs struct {
+	*aq_context.AqContext
+	workflowID uuid.UUID
+	input      *backfillCreateInput
+}
+
+type BackfillCreateHandler struct {
+	handler.PostHandler
+
+	Database database.Database
+	Engine   engine.Engine
+
+	BackfillRepo  repos.Backfill
+	DAGRepo       repos.DAG
+	DAGResultRepo repos.DAGResult
+	WorkflowRepo  repos.Workflow
+}
+
+func (*BackfillCreateHandler) Name() string {
+	return "BackfillCreate"
+}
+
+func (h *BackfillCreateHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	aqContext, statusCode, err := aq_context.ParseAqContext(r.Context())
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	workflowID, err := (parser.WorkflowIDParser{}).Parse(r)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	var input backfillCreateInput
+	err = json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.New("Unable to parse JSON input.")
+	}
+
+	if !input.StartTime.Before(input.EndTime) {
+		return nil, http.StatusBadRequest, errors.New("The start time of a backfill must be before its end time.")
+	}
+
+	if input.EndTime.After(time.Now()) {
+		return nil, http.StatusBadRequest, errors.New("The end time of a backfill cannot be in the future.")
+	}
+
+	if input.MaxConcurrency < 1 {
+		return nil, http.StatusBadRequest, errors.New("The max concurrency of a backfill must be at least 1.")
+	}
+
+	return &backfillCreateArgs{
+		AqContext:  aqContext,
+		workflowID: workflowID,
+		input:      &input,
+	}, http.StatusOK, nil
+}
+
+func (h *BackfillCreateHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*backfillCreateArgs)
+
+	ok, err := h.WorkflowRepo.ValidateOrg(
+		ctx,
+		args.workflowID,
+		args.OrgID,
+		h.Database,
+	)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during workflow ownership validation.")
+	}
+
+	if !ok {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "The organization does not own this workflow.")
+	}
+
+	dbWorkflow, err := h.WorkflowRepo.Get(ctx, args.workflowID, h.Database)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error reading workflow.")
+	}
+
+	cronSchedule := string(dbWorkflow.Schedule.CronSchedule)
+	if cronSchedule == "" {
+		return nil, http.StatusBadRequest, errors.New("Only workflows with a cron schedule can be backfilled.")
+	}
+
+	dbDAG, err := h.DAGRepo.GetLatestByWorkflow(ctx, args.workflowID, h.Database)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error reading workflow dag.")
+	}
+
+	if dbDAG.EngineConfig.Type == shared.AirflowEngineType {
+		return nil, http.StatusBadRequest, errors.New("Backfilling is not supported for workflows running on Airflow.")
+	}
+
+	ticks, err := backfill.Ticks(cronSchedule, dbWorkflow.Schedule.Timezone, args.input.StartTime, args.input.EndTime)
+	if err != nil {
+		if aq_errors.Is(err, backfill.ErrTooManyTicks) {
+			return nil, http.StatusBadRequest, err
+		}
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to compute the backfill runs.")
+	}
+
+	if len(ticks) == 0 {
+		return nil, http.StatusBadRequest, errors.New("The workflow is not scheduled to run at any time in the given range.")
+	}
+
+	dbBackfill, err := backfill.Perform(
+		ctx,
+		args.workflowID,
+		cronSchedule,
+		dbWorkflow.Schedule.Timezone,
+		args.input.StartTime,
+		args.input.EndTime,
+		args.input.MaxConcurrency,
+		h.Engine,
+		h.BackfillRepo,
+		h.DAGResultRepo,
+		h.Database,
+	)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to start backfill.")
+	}
+
+	return response.NewBackfillFromDBObject(dbBackfill, ticks, nil /* dbDAGResults */), http.StatusOK, nil
+}
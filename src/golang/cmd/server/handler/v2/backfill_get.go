@@ -0,0 +1,113 @@
+package v2
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/handler"
+	"github.com/aqueducthq/aqueduct/cmd/server/request/parser"
+	"github.com/aqueducthq/aqueduct/lib/backfill"
+	aq_context "github.com/aqueducthq/aqueduct/lib/context"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/response"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+// This file should map directly to
+// src/ui/common/src/handlers/v2/BackfillGet.tsx
+//
+// Route: /v2/workflow/{workflowId}/backfill/{backfillID}
+// Method: GET
+// Params:
+//	`workflowId`: ID for `workflow` object
+//  `backfillID`: ID for `workflow_backfill` object
+// Request:
+//	Headers:
+//		`api-key`: user's API Key
+// Response:
+//	Body:
+//		serialized `response.Backfill`
+
+type backfillGetArgs struct {
+	*aq_context.AqContext
+	workflowID uuid.UUID
+	backfillID uuid.UUID
+}
+
+type BackfillGetHandler struct {
+	handler.GetHandler
+
+	Database database.Database
+
+	BackfillRepo  repos.Backfill
+	DAGResultRepo repos.DAGResult
+	WorkflowRepo  repos.Workflow
+}
+
+func (*BackfillGetHandler) Name() string {
+	return "BackfillGet"
+}
+
+func (h *BackfillGetHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	aqContext, statusCode, err := aq_context.ParseAqContext(r.Context())
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	workflowID, err := (parser.WorkflowIDParser{}).Parse(r)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	backfillID, err := (parser.BackfillIDParser{}).Parse(r)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	return &backfillGetArgs{
+		AqContext:  aqContext,
+		workflowID: workflowID,
+		backfillID: backfillID,
+	}, http.StatusOK, nil
+}
+
+func (h *BackfillGetHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*backfillGetArgs)
+
+	ok, err := h.WorkflowRepo.ValidateOrg(
+		ctx,
+		args.workflowID,
+		args.OrgID,
+		h.Database,
+	)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during workflow ownership validation.")
+	}
+
+	if !ok {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "The organization does not own this workflow.")
+	}
+
+	dbBackfill, err := h.BackfillRepo.Get(ctx, args.backfillID, h.Database)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error reading backfill.")
+	}
+
+	if dbBackfill.WorkflowID != args.workflowID {
+		return nil, http.StatusBadRequest, errors.New("The backfill does not belong to this workflow.")
+	}
+
+	ticks, err := backfill.Ticks(dbBackfill.CronSchedule, dbBackfill.Timezone, dbBackfill.StartTime, dbBackfill.EndTime)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to compute the backfill runs.")
+	}
+
+	dbDAGResults, err := h.DAGResultRepo.GetByBackfill(ctx, dbBackfill.ID, h.Database)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error reading backfill runs.")
+	}
+
+	return response.NewBackfillFromDBObject(dbBackfill, ticks, dbDAGResults), http.StatusOK, nil
+}
@@ -0,0 +1,119 @@
+package v2
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/handler"
+	"github.com/aqueducthq/aqueduct/cmd/server/request/parser"
+	aq_context "github.com/aqueducthq/aqueduct/lib/context"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/engine"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+// This file should map directly to
+// src/ui/common/src/handlers/v2/DagResultCancel.tsx
+//
+// Route: /v2/workflow/{workflowId}/result/{dagResultID}/cancel
+// Method: POST
+// Params:
+//	`workflowId`: ID for `workflow` object
+//  `dagResultID`: ID for `workflow_dag_result` object
+// Request:
+//	Headers:
+//		`api-key`: user's API Key
+// Response: none
+//
+// Cancels an in-progress workflow run. All of its running operators are stopped,
+// and all operators that have not completed are marked as canceled.
+
+type dagResultCancelArgs struct {
+	*aq_context.AqContext
+	workflowID  uuid.UUID
+	dagResultID uuid.UUID
+}
+
+type DAGResultCancelHandler struct {
+	handler.PostHandler
+
+	Database database.Database
+	Engine   engine.Engine
+
+	WorkflowRepo  repos.Workflow
+	DAGRepo       repos.DAG
+	DAGResultRepo repos.DAGResult
+}
+
+func (*DAGResultCancelHandler) Name() string {
+	return "DAGResultCancel"
+}
+
+func (h *DAGResultCancelHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	aqContext, statusCode, err := aq_context.ParseAqContext(r.Context())
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	workflowID, err := (parser.WorkflowIDParser{}).Parse(r)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	dagResultID, err := (parser.DAGResultIDParser{}).Parse(r)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	return &dagResultCancelArgs{
+		AqContext:   aqContext,
+		workflowID:  workflowID,
+		dagResultID: dagResultID,
+	}, http.StatusOK, nil
+}
+
+func (h *DAGResultCancelHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*dagResultCancelArgs)
+
+	emptyResp := struct{}{}
+
+	ok, err := h.WorkflowRepo.ValidateOrg(
+		ctx,
+		args.workflowID,
+		args.OrgID,
+		h.Database,
+	)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during workflow ownership validation.")
+	}
+
+	if !ok {
+		return emptyResp, http.StatusBadRequest, errors.Wrap(err, "The organization does not own this workflow.")
+	}
+
+	dbDAGResult, err := h.DAGResultRepo.Get(ctx, args.dagResultID, h.Database)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error reading workflow run.")
+	}
+
+	dbDAG, err := h.DAGRepo.Get(ctx, dbDAGResult.DagID, h.Database)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error reading workflow dag.")
+	}
+
+	if dbDAG.WorkflowID != args.workflowID {
+		return emptyResp, http.StatusBadRequest, errors.New("The workflow run does not belong to this workflow.")
+	}
+
+	if err := h.Engine.CancelWorkflowRun(ctx, args.dagResultID); err != nil {
+		if aq_errors.Is(err, engine.ErrWorkflowRunNotInProgress) {
+			return emptyResp, http.StatusBadRequest, err
+		}
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to cancel workflow run.")
+	}
+
+	return emptyResp, http.StatusOK, nil
+}
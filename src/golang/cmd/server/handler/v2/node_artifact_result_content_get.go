@@ -160,8 +160,10 @@ func (h *NodeArtifactResultContentGetHandler) Perform(ctx context.Context, inter
 			return emptyResp, http.StatusOK, nil
 		}
 
-		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Failed to retrieve data for the artifact result.")
-	} else if !errors.Is(err, storage.ErrObjectDoesNotExist()) {
+		if errors.Is(err, storage.ErrContentCorrupted()) {
+			return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "The data of the artifact result is corrupted.")
+		}
+
 		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Failed to retrieve data for the artifact result.")
 	}
 
@@ -140,6 +140,9 @@ func (h *NodesResultsGetHandler) Perform(ctx context.Context, interfaceArgs inte
 					// If the data does not exist, skip the fetch.
 					continue
 				}
+				if err == nil {
+					err = storage.VerifyChecksum(path, contentBytes, artfResult.Metadata.Checksum)
+				}
 				if err != nil {
 					return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to get artifact content from storage")
 				}
@@ -116,6 +116,11 @@ func main() {
 		log.Fatalf("Failed to start workflow retention cronjob: %v", err)
 	}
 
+	err = s.StartStorageGCJob(config.StorageGCJobPeriod())
+	if err != nil {
+		log.Fatalf("Failed to start storage garbage collection cronjob: %v", err)
+	}
+
 	err = s.StartDynamicTeardownJob()
 	if err != nil {
 		log.Fatalf("Failed to deployed dynamic teardown cronjob: %v", err)
@@ -0,0 +1,26 @@
+package parser
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/routes"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+type BackfillIDParser struct{}
+
+func (BackfillIDParser) Parse(r *http.Request) (uuid.UUID, error) {
+	backfillIDStr := (pathParser{URLParam: routes.BackfillIDParam}).Parse(r)
+
+	id, err := uuid.Parse(backfillIDStr)
+	if err != nil {
+		return uuid.UUID{}, errors.Wrap(
+			err,
+			fmt.Sprintf("Malformed backfill ID %s", backfillIDStr),
+		)
+	}
+
+	return id, nil
+}
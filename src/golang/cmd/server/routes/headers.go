@@ -17,6 +17,17 @@ const (
 	StorageMigrationLimitHeader          = "limit"
 	StorageMigrationCompletedSinceHeader = "completed-since"
 
+	// Storage Config Headers
+	StorageCompressionHeader = "compression"
+	StorageEncryptHeader     = "encrypt"
+
+	// Vault Headers
+	ResumeHeader = "resume"
+	KeyIDHeader  = "key-id"
+
+	// Storage Garbage Collection Headers
+	DryRunHeader = "dry-run"
+
 	// Export Function headers
 	ExportFnUserFriendlyHeader = "user-friendly"
 
@@ -24,6 +35,9 @@ const (
 
 	MetadataOnlyHeader = "metadata-only"
 
+	// Register Workflow headers
+	StorageIntegrationIDHeader = "storage-integration-id"
+
 	RunNowHeader              = "run-now"
 	DynamicEngineActionHeader = "action"
 )
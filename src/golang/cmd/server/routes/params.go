@@ -15,6 +15,7 @@ const (
 	WorkflowIDParam   = "workflowID"
 	DagIDParam        = "dagID"
 	DAGResultIDParam  = "dagResultID"
+	BackfillIDParam   = "backfillID"
 	NodeIDParam       = "nodeID"
 	NodeResultIDParam = "nodeResultID"
 )
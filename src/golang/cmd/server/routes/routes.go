@@ -7,9 +7,13 @@ const (
 	WorkflowsRoute            = "/api/v2/workflows"
 
 	WorkflowRoute                  = "/api/v2/workflow/{workflowID}"
+	BackfillsRoute                 = "/api/v2/workflow/{workflowID}/backfill"
+	BackfillRoute                  = "/api/v2/workflow/{workflowID}/backfill/{backfillID}"
 	DAGRoute                       = "/api/v2/workflow/{workflowID}/dag/{dagID}"
 	DAGResultsRoute                = "/api/v2/workflow/{workflowID}/results"
 	DAGResultRoute                 = "/api/v2/workflow/{workflowID}/result/{dagResultID}"
+	DAGResultCancelRoute           = "/api/v2/workflow/{workflowID}/result/{dagResultID}/cancel"
+	DAGResultResumeRoute           = "/api/v2/workflow/{workflowID}/result/{dagResultID}/resume"
 	NodesRoute                     = "/api/v2/workflow/{workflowID}/dag/{dagID}/nodes"
 	NodeArtifactRoute              = "/api/v2/workflow/{workflowID}/dag/{dagID}/node/artifact/{nodeID}"
 	NodeArtifactResultContentRoute = "/api/v2/workflow/{workflowID}/dag/{dagID}/node/artifact/{nodeID}/result/{nodeResultID}/content"
@@ -42,6 +46,9 @@ const (
 
 	ResetApiKeyRoute = "/api/keys/reset" // nolint:gosec
 
+	RotateEncryptionKeyRoute = "/api/vault/rotate-key"
+	RetireEncryptionKeyRoute = "/api/vault/retire-key"
+
 	ListNotificationsRoute   = "/api/notifications"
 	ArchiveNotificationRoute = "/api/notifications/{notificationId}/archive"
 
@@ -50,6 +57,10 @@ const (
 	GetNodePositionsRoute = "/api/positioning"
 	PreviewRoute          = "/api/preview"
 
+	GetPreviewCacheStatsRoute = "/api/preview/cache/stats"
+
+	CollectStorageGarbageRoute = "/api/storage/gc"
+
 	GetUserProfileRoute = "/api/user"
 
 	ListWorkflowsRoute           = "/api/workflows"
@@ -64,6 +75,7 @@ const (
 	RefreshWorkflowRoute         = "/api/workflow/{workflowId}/refresh"
 	GetWorkflowDagResultRoute    = "/api/workflow/{workflowId}/result/{workflowDagResultId}"
 	GetWorkflowHistoryRoute      = "/api/workflow/{workflowId}/history"
+	MigrateWorkflowStorageRoute  = "/api/workflow/{workflowId}/storage/{integrationId}"
 
 	GetServerVersionRoute     = "/api/version"
 	GetServerEnvironmentRoute = "/api/environment"
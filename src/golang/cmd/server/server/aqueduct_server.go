@@ -21,7 +21,9 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/job"
 	"github.com/aqueducthq/aqueduct/lib/logging"
 	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
+	"github.com/aqueducthq/aqueduct/lib/storage_gc"
 	"github.com/aqueducthq/aqueduct/lib/vault"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/github"
 	"github.com/aqueducthq/aqueduct/lib/workflow/preview_cache"
@@ -36,8 +38,8 @@ import (
 const (
 	accountOrganizationId = "aqueduct"
 
-	// The maximum number of entries this cache can have.
-	previewCacheSize = 200
+	// The maximum total size of the preview cache's data, if the config does not set one.
+	defaultPreviewCacheMaxBytes = 1 << 30
 )
 
 var uiDir = path.Join(os.Getenv("HOME"), ".aqueduct", "ui")
@@ -53,9 +55,10 @@ type AqServer struct {
 	// Only the following group of fields will be reinitialized when the server is restarted
 	GithubManager github.Manager
 	// TODO ENG-1483: Move JobManager from Server to Handlers
-	JobManager job.JobManager
-	AqEngine   engine.AqEngine
-	AqPath     string
+	JobManager          job.JobManager
+	AqEngine            engine.AqEngine
+	PreviewCacheManager preview_cache.CacheManager
+	AqPath              string
 
 	// UnderMaintenance indicates whether the server is currently down for system maintenance.
 	UnderMaintenance atomic.Value
@@ -70,13 +73,10 @@ type AqServer struct {
 
 func NewAqServer(environment string, externalIP string, port int, disableUsageStats bool) *AqServer {
 	ctx := context.Background()
-	aqPath := config.AqueductPath()
 
 	// The database cannot be reinitialized when the server restarts, because the database is passed
 	// to the middleware functions.
-	db, err := database.NewSqliteDatabase(&database.SqliteConfig{
-		File: path.Join(aqPath, database.SqliteDatabasePath),
-	})
+	db, err := database.NewDatabase(config.Database())
 	if err != nil {
 		log.Fatalf("Unable to initialize database: %v", err)
 	}
@@ -96,7 +96,7 @@ func NewAqServer(environment string, externalIP string, port int, disableUsageSt
 		ExternalIP:        externalIP,
 		Port:              port,
 		Database:          db,
-		Repos:             CreateRepos(),
+		Repos:             CreateRepos(db.Type()),
 		UnderMaintenance:  atomic.Value{},
 		RequestMutex:      sync.RWMutex{},
 		Environment:       environment,
@@ -177,15 +177,19 @@ func (s *AqServer) Init() error {
 
 	storageConfig := config.Storage()
 
-	previewCacheManager, err := preview_cache.NewInMemoryPreviewCacheManager(
+	previewCacheMaxBytes := config.PreviewCacheMaxBytes()
+	if previewCacheMaxBytes == 0 {
+		previewCacheMaxBytes = defaultPreviewCacheMaxBytes
+	}
+
+	previewCacheManager := preview_cache.NewPersistentPreviewCacheManager(
 		&storageConfig,
-		previewCacheSize,
+		previewCacheMaxBytes,
+		s.PreviewCacheEntryRepo,
+		s.Database,
 	)
-	if err != nil {
-		return err
-	}
 
-	vault, err := vault.NewVault(&storageConfig, config.EncryptionKey())
+	vault, err := vault.NewVault(config.Vault(), &storageConfig, config.EncryptionKeyring())
 	if err != nil {
 		return err
 	}
@@ -198,12 +202,47 @@ func (s *AqServer) Init() error {
 		return err
 	}
 
+	// Workflow schedules are deployed in process unless a k8s scheduler is configured.
+	var scheduleJobManager job.JobManager
+	if schedulerConfig := config.K8sScheduler(); schedulerConfig != nil {
+		// The scheduled runs execute on the cluster, so they cannot reach a database
+		// or a storage layer that is on the server's filesystem.
+		if config.Database().Type != database.PostgresType {
+			return errors.New("Deploying workflow schedules to k8s requires a Postgres database.")
+		}
+
+		if storageConfig.Type == shared.FileStorageType {
+			return errors.New("Deploying workflow schedules to k8s requires a remote storage layer.")
+		}
+
+		k8sJobManager, err := job.NewK8sJobManager(&job.K8sJobManagerConfig{
+			KubeconfigPath:      schedulerConfig.KubeconfigPath,
+			UseSameCluster:      schedulerConfig.UseSameCluster,
+			AwsAccessKeyId:      schedulerConfig.AwsAccessKeyId,
+			AwsSecretAccessKey:  schedulerConfig.AwsSecretAccessKey,
+			MountExecutorConfig: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		// The server is initialized again after its keyring changes, and the scheduled runs must
+		// be able to read what the server encrypts with the current key. If the keys cannot be
+		// written now, they are written again once the job manager is used.
+		if err := k8sJobManager.SyncStorageKeys(context.Background()); err != nil {
+			log.Errorf("Unable to sync the storage keys of scheduled runs: %v", err)
+		}
+		scheduleJobManager = k8sJobManager
+	}
+
 	eng, err := engine.NewAqEngine(
 		s.Database,
 		githubManager,
 		previewCacheManager,
 		aqPath,
 		s.fullDisplayAddress(),
+		config.MaxConcurrentOperators(),
+		scheduleJobManager,
 		GetEngineRepos(s.Repos),
 	)
 	if err != nil {
@@ -214,6 +253,7 @@ func (s *AqServer) Init() error {
 	s.JobManager = jobManager
 	s.AqPath = aqPath
 	s.AqEngine = eng
+	s.PreviewCacheManager = previewCacheManager
 
 	return nil
 }
@@ -272,6 +312,48 @@ func (s *AqServer) StartWorkflowRetentionJob(period string) error {
 	return nil
 }
 
+// StartStorageGCJob deploys the storage garbage collector with the cron schedule `period`.
+// If `period` is empty, the garbage collector is not deployed.
+func (s *AqServer) StartStorageGCJob(period string) error {
+	name := job.StorageGCName
+	ctx := context.Background()
+
+	gracePeriod, err := config.StorageGCGracePeriod()
+	if err != nil {
+		return err
+	}
+
+	if gracePeriod == 0 {
+		gracePeriod = storage_gc.DefaultGracePeriod
+	}
+
+	// Delete old CronJob if it exists
+	if err := s.JobManager.DeleteCronJob(ctx, name); err != nil {
+		return errors.Wrap(err, "Unable to delete existing storage garbage collection job")
+	}
+
+	if period == "" {
+		return nil
+	}
+
+	spec := job.NewStorageGCJobSpec(
+		s.Database.Config(),
+		s.JobManager.Config(),
+		gracePeriod,
+		config.StorageGCDryRun(),
+	)
+
+	if err := s.JobManager.DeployCronJob(
+		ctx,
+		name,
+		period,
+		spec,
+	); err != nil {
+		return errors.Wrap(err, "Unable to start storage garbage collection cron job")
+	}
+	return nil
+}
+
 func (s *AqServer) AddHandler(route string, handlerObj handler.Handler) {
 	middleware := alice.New()
 
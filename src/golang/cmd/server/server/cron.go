@@ -2,18 +2,20 @@ package server
 
 import (
 	"context"
+	"math/rand"
 	"time"
 
 	"github.com/aqueducthq/aqueduct/cmd/server/handler"
 	"github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/backfill"
+	"github.com/aqueducthq/aqueduct/lib/cronjob"
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/engine"
-	shared_utils "github.com/aqueducthq/aqueduct/lib/lib_utils"
+	"github.com/aqueducthq/aqueduct/lib/models"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/vault"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/google/uuid"
-	"github.com/gorhill/cronexpr"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -29,17 +31,24 @@ func (s *AqServer) SyncCronJobs() error {
 func (s *AqServer) triggerMissedCronJobs(
 	ctx context.Context,
 	workflowId uuid.UUID,
-	cronSchedule string,
+	schedule *shared.Schedule,
 	referenceTime time.Time,
 ) {
-	nextTwoTriggerTimeStamp := cronexpr.MustParse(cronSchedule).NextN(time.Now(), 2)
-	// We subtract the next two trigger timestamp to get the duration between consecutive cron jobs.
-	duration := nextTwoTriggerTimeStamp[1].Unix() - nextTwoTriggerTimeStamp[0].Unix()
-	// Subtracting the duration from the next trigger timestamp gives us the last expected trigger timestamp.
-	lastExpectedTriggerTime := nextTwoTriggerTimeStamp[0].Unix() - duration
-	if lastExpectedTriggerTime > referenceTime.Unix() {
-		// This means that the workflow should have been triggered, but it wasn't.
-		// So we manually trigger the workflow here.
+	// The schedule is evaluated in its own time zone, so that the expected trigger times
+	// are correct across daylight saving time transitions.
+	nextTriggerTime, err := cronjob.NextTrigger(string(schedule.CronSchedule), schedule.Timezone, referenceTime)
+	if err != nil {
+		log.Errorf("Unable to compute the next trigger time of workflow %s: %v", workflowId, err)
+		return
+	}
+
+	if nextTriggerTime.IsZero() || nextTriggerTime.After(time.Now()) {
+		return
+	}
+
+	// This means that the workflow should have been triggered, but it wasn't.
+	// So we manually trigger the workflow here, after the same random delay as a scheduled run.
+	triggerWorkflow := func() {
 		_, _, err := (&handler.RefreshWorkflowHandler{
 			Database: s.Database,
 			Engine:   s.AqEngine,
@@ -55,11 +64,112 @@ func (s *AqServer) triggerMissedCronJobs(
 			log.Errorf("Unable to trigger workflow: %v", err)
 		}
 	}
+
+	if jitter := schedule.Jitter(); jitter > 0 {
+		time.AfterFunc(time.Duration(rand.Int63n(int64(jitter))), triggerWorkflow)
+		return
+	}
+	triggerWorkflow()
+}
+
+// survivingRuns are the runs that are still in progress after the server restarted,
+// along with their results that are still in progress.
+type survivingRuns struct {
+	dagResults      []models.DAGResult
+	operatorResults []models.OperatorResult
+	artifactResults []models.ArtifactResult
+}
+
+// getSurvivingRuns returns the runs in progress of the workflows whose scheduled run executes
+// in a cron job of the engine's ScheduleJobManager, which keeps running while the server restarts.
+func (s *AqServer) getSurvivingRuns(ctx context.Context, DB database.Database) (*survivingRuns, error) {
+	workflowIDs, err := s.AqEngine.ScheduledWorkflowsInProgress(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := &survivingRuns{}
+	for workflowID := range workflowIDs {
+		dagResults, err := s.DAGResultRepo.GetInProgressByWorkflow(ctx, workflowID, DB)
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to get workflow runs in progress.")
+		}
+		runs.dagResults = append(runs.dagResults, dagResults...)
+	}
+
+	if len(runs.dagResults) == 0 {
+		return runs, nil
+	}
+
+	dagResultIDs := make([]uuid.UUID, 0, len(runs.dagResults))
+	for _, dagResult := range runs.dagResults {
+		dagResultIDs = append(dagResultIDs, dagResult.ID)
+	}
+
+	operatorResults, err := s.OperatorResultRepo.GetByDAGResultBatch(ctx, dagResultIDs, DB)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to get operator results.")
+	}
+	for _, operatorResult := range operatorResults {
+		if isInProgress(operatorResult.ExecState) {
+			runs.operatorResults = append(runs.operatorResults, operatorResult)
+		}
+	}
+
+	artifactResults, err := s.ArtifactResultRepo.GetByDAGResults(ctx, dagResultIDs, DB)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to get artifact results.")
+	}
+	for _, artifactResult := range artifactResults {
+		if isInProgress(artifactResult.ExecState) {
+			runs.artifactResults = append(runs.artifactResults, artifactResult)
+		}
+	}
+
+	return runs, nil
+}
+
+// restoreSurvivingRuns sets the results of runs back to the state they were in before the server canceled them.
+func (s *AqServer) restoreSurvivingRuns(ctx context.Context, runs *survivingRuns, DB database.Database) error {
+	for _, dagResult := range runs.dagResults {
+		if _, err := s.DAGResultRepo.Update(ctx, dagResult.ID, map[string]interface{}{
+			models.DAGResultStatus:    dagResult.Status,
+			models.DAGResultExecState: &dagResult.ExecState.ExecutionState,
+		}, DB); err != nil {
+			return errors.Wrap(err, "Unable to restore workflow run.")
+		}
+	}
+
+	for _, operatorResult := range runs.operatorResults {
+		if _, err := s.OperatorResultRepo.Update(ctx, operatorResult.ID, map[string]interface{}{
+			models.OperatorResultStatus:    operatorResult.Status,
+			models.OperatorResultExecState: &operatorResult.ExecState.ExecutionState,
+		}, DB); err != nil {
+			return errors.Wrap(err, "Unable to restore operator result.")
+		}
+	}
+
+	for _, artifactResult := range runs.artifactResults {
+		if _, err := s.ArtifactResultRepo.Update(ctx, artifactResult.ID, map[string]interface{}{
+			models.ArtifactResultStatus:    artifactResult.Status,
+			models.ArtifactResultExecState: &artifactResult.ExecState.ExecutionState,
+		}, DB); err != nil {
+			return errors.Wrap(err, "Unable to restore artifact result.")
+		}
+	}
+
+	return nil
+}
+
+func isInProgress(execState shared.NullExecutionState) bool {
+	return !execState.IsNull && (execState.Status == shared.PendingExecutionStatus ||
+		execState.Status == shared.RunningExecutionStatus)
 }
 
 // backfillKilledJobs backfills all pending and running op/artf/DAG _results
 // and mark them as canceled. For non-aqueduct jobs like Airflow, we sync these
-// jobs from the remote servers.
+// jobs from the remote servers. Runs that execute in a k8s cron job are left as is,
+// since they are not interrupted by the restart.
 func (s *AqServer) backfillKilledJobs(ctx context.Context) error {
 	txn, err := s.Database.BeginTx(ctx)
 	if err != nil {
@@ -67,6 +177,13 @@ func (s *AqServer) backfillKilledJobs(ctx context.Context) error {
 	}
 	defer database.TxnRollbackIgnoreErr(ctx, txn)
 
+	// The surviving runs are canceled along with the others below, and then restored within the same
+	// transaction, so that their executors never see them as canceled.
+	runs, err := s.getSurvivingRuns(ctx, txn)
+	if err != nil {
+		return err
+	}
+
 	if _, err := s.OperatorResultRepo.UpdateBatchStatusByStatus(
 		ctx,
 		shared.RunningExecutionStatus,
@@ -121,8 +238,16 @@ func (s *AqServer) backfillKilledJobs(ctx context.Context) error {
 		return err
 	}
 
+	if err := s.restoreSurvivingRuns(ctx, runs, txn); err != nil {
+		return err
+	}
+
+	if err := backfill.FailInterrupted(ctx, s.BackfillRepo, txn); err != nil {
+		return err
+	}
+
 	storageConfig := config.Storage()
-	vaultObject, err := vault.NewVault(&storageConfig, config.EncryptionKey())
+	vaultObject, err := vault.NewVault(config.Vault(), &storageConfig, config.EncryptionKeyring())
 	if err != nil {
 		return err
 	}
@@ -170,7 +295,7 @@ func (s *AqServer) runMissedCronJobs(ctx context.Context) error {
 			s.triggerMissedCronJobs(
 				ctx,
 				wfLastRun.ID,
-				string(wfLastRun.Schedule.CronSchedule),
+				&wfLastRun.Schedule,
 				wfLastRun.LastRunAt,
 			)
 		}
@@ -189,7 +314,7 @@ func (s *AqServer) runMissedCronJobs(ctx context.Context) error {
 				s.triggerMissedCronJobs(
 					ctx,
 					workflow.ID,
-					string(workflow.Schedule.CronSchedule),
+					&workflow.Schedule,
 					workflow.CreatedAt,
 				)
 			}
@@ -205,25 +330,5 @@ func (s *AqServer) initializeWorkflowCronJobs(ctx context.Context) error {
 		return err
 	}
 
-	for _, wf := range workflows {
-		if wf.Schedule.CronSchedule != "" {
-			if wf.Schedule.Paused {
-				wf.Schedule.CronSchedule = ""
-			}
-			name := shared_utils.AppendPrefix(wf.ID.String())
-			period := string(wf.Schedule.CronSchedule)
-
-			err = s.AqEngine.ScheduleWorkflow(
-				ctx,
-				wf.ID,
-				name,
-				period,
-			)
-			if err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
+	return s.AqEngine.InitializeWorkflowSchedules(ctx, workflows)
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/engine"
 	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/repos/postgres"
 	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
 	"github.com/dropbox/godropbox/errors"
 )
@@ -13,6 +14,8 @@ import (
 type Repos struct {
 	ArtifactRepo             repos.Artifact
 	ArtifactResultRepo       repos.ArtifactResult
+	BackfillRepo             repos.Backfill
+	ContentBlobRepo          repos.ContentBlob
 	DAGRepo                  repos.DAG
 	DAGEdgeRepo              repos.DAGEdge
 	DAGResultRepo            repos.DAGResult
@@ -22,16 +25,43 @@ type Repos struct {
 	NotificationRepo         repos.Notification
 	OperatorRepo             repos.Operator
 	OperatorResultRepo       repos.OperatorResult
+	PreviewCacheEntryRepo    repos.PreviewCacheEntry
 	SchemaVersionRepo        repos.SchemaVersion
 	UserRepo                 repos.User
 	WatcherRepo              repos.Watcher
 	WorkflowRepo             repos.Workflow
 }
 
-func CreateRepos() *Repos {
+// CreateRepos returns the repos for the database of type dbType.
+func CreateRepos(dbType database.Type) *Repos {
+	if dbType == database.PostgresType {
+		return &Repos{
+			ArtifactRepo:             postgres.NewArtifactRepo(),
+			ArtifactResultRepo:       postgres.NewArtifactResultRepo(),
+			BackfillRepo:             postgres.NewBackfillRepo(),
+			ContentBlobRepo:          postgres.NewContentBlobRepo(),
+			DAGRepo:                  postgres.NewDAGRepo(),
+			DAGEdgeRepo:              postgres.NewDAGEdgeRepo(),
+			DAGResultRepo:            postgres.NewDAGResultRepo(),
+			ExecutionEnvironmentRepo: postgres.NewExecutionEnvironmentRepo(),
+			IntegrationRepo:          postgres.NewIntegrationRepo(),
+			StorageMigrationRepo:     postgres.NewStorageMigrationRepo(),
+			NotificationRepo:         postgres.NewNotificationRepo(),
+			OperatorRepo:             postgres.NewOperatorRepo(),
+			OperatorResultRepo:       postgres.NewOperatorResultRepo(),
+			PreviewCacheEntryRepo:    postgres.NewPreviewCacheEntryRepo(),
+			SchemaVersionRepo:        postgres.NewSchemaVersionRepo(),
+			UserRepo:                 postgres.NewUserRepo(),
+			WatcherRepo:              postgres.NewWatcherRepo(),
+			WorkflowRepo:             postgres.NewWorkflowRepo(),
+		}
+	}
+
 	return &Repos{
 		ArtifactRepo:             sqlite.NewArtifactRepo(),
 		ArtifactResultRepo:       sqlite.NewArtifactResultRepo(),
+		BackfillRepo:             sqlite.NewBackfillRepo(),
+		ContentBlobRepo:          sqlite.NewContentBlobRepo(),
 		DAGRepo:                  sqlite.NewDAGRepo(),
 		DAGEdgeRepo:              sqlite.NewDAGEdgeRepo(),
 		DAGResultRepo:            sqlite.NewDAGResultRepo(),
@@ -41,6 +71,7 @@ func CreateRepos() *Repos {
 		NotificationRepo:         sqlite.NewNotificationRepo(),
 		OperatorRepo:             sqlite.NewOperatorRepo(),
 		OperatorResultRepo:       sqlite.NewOperatorResultRepo(),
+		PreviewCacheEntryRepo:    sqlite.NewPreviewCacheEntryRepo(),
 		SchemaVersionRepo:        sqlite.NewSchemaVersionRepo(),
 		UserRepo:                 sqlite.NewUserRepo(),
 		WatcherRepo:              sqlite.NewWatcherRepo(),
@@ -52,6 +83,8 @@ func GetEngineRepos(repos *Repos) *engine.Repos {
 	return &engine.Repos{
 		ArtifactRepo:             repos.ArtifactRepo,
 		ArtifactResultRepo:       repos.ArtifactResultRepo,
+		BackfillRepo:             repos.BackfillRepo,
+		ContentBlobRepo:          repos.ContentBlobRepo,
 		DAGRepo:                  repos.DAGRepo,
 		DAGEdgeRepo:              repos.DAGEdgeRepo,
 		DAGResultRepo:            repos.DAGResultRepo,
@@ -43,8 +43,10 @@ func HandleSuccess(
 
 func ExecuteHandler(server *AqServer, handlerObj handler.Handler) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if handlerObj.Name() != new(handler.ConfigureStorageHandler).Name() {
-			// ConfigureStorageHandler requests an exclusive Lock on RequestMutex,
+		if handlerObj.Name() != new(handler.ConfigureStorageHandler).Name() &&
+			handlerObj.Name() != new(handler.RotateEncryptionKeyHandler).Name() &&
+			handlerObj.Name() != new(handler.RetireEncryptionKeyHandler).Name() {
+			// ConfigureStorageHandler and the encryption key handlers request an exclusive Lock on RequestMutex,
 			// so there would be dead-lock if this request first acquired a shared lock
 			server.RequestMutex.RLock()
 			defer server.RequestMutex.RUnlock()
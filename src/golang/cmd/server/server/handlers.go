@@ -4,6 +4,7 @@ import (
 	"github.com/aqueducthq/aqueduct/cmd/server/handler"
 	v2 "github.com/aqueducthq/aqueduct/cmd/server/handler/v2"
 	"github.com/aqueducthq/aqueduct/cmd/server/routes"
+	"github.com/aqueducthq/aqueduct/lib/workflow/preview_cache"
 )
 
 func (s *AqServer) Handlers() map[string]handler.Handler {
@@ -13,6 +14,22 @@ func (s *AqServer) Handlers() map[string]handler.Handler {
 			Database:     s.Database,
 			WorkflowRepo: s.WorkflowRepo,
 		},
+		routes.BackfillsRoute: &v2.BackfillCreateHandler{
+			Database: s.Database,
+			Engine:   s.AqEngine,
+
+			BackfillRepo:  s.BackfillRepo,
+			DAGRepo:       s.DAGRepo,
+			DAGResultRepo: s.DAGResultRepo,
+			WorkflowRepo:  s.WorkflowRepo,
+		},
+		routes.BackfillRoute: &v2.BackfillGetHandler{
+			Database: s.Database,
+
+			BackfillRepo:  s.BackfillRepo,
+			DAGResultRepo: s.DAGResultRepo,
+			WorkflowRepo:  s.WorkflowRepo,
+		},
 		routes.DAGRoute: &v2.DAGGetHandler{
 			Database:     s.Database,
 			WorkflowRepo: s.WorkflowRepo,
@@ -23,6 +40,22 @@ func (s *AqServer) Handlers() map[string]handler.Handler {
 			WorkflowRepo:  s.WorkflowRepo,
 			DAGResultRepo: s.DAGResultRepo,
 		},
+		routes.DAGResultCancelRoute: &v2.DAGResultCancelHandler{
+			Database: s.Database,
+			Engine:   s.AqEngine,
+
+			WorkflowRepo:  s.WorkflowRepo,
+			DAGRepo:       s.DAGRepo,
+			DAGResultRepo: s.DAGResultRepo,
+		},
+		routes.DAGResultResumeRoute: &v2.DAGResultResumeHandler{
+			Database: s.Database,
+			Engine:   s.AqEngine,
+
+			WorkflowRepo:  s.WorkflowRepo,
+			DAGRepo:       s.DAGRepo,
+			DAGResultRepo: s.DAGResultRepo,
+		},
 		routes.DAGResultsRoute: &v2.DAGResultsGetHandler{
 			Database:      s.Database,
 			WorkflowRepo:  s.WorkflowRepo,
@@ -180,6 +213,36 @@ func (s *AqServer) Handlers() map[string]handler.Handler {
 			PauseServerFn:   s.Pause,
 			RestartServerFn: s.Restart,
 		},
+		routes.CollectStorageGarbageRoute: &handler.CollectStorageGarbageHandler{
+			Database: s.Database,
+
+			ArtifactRepo:       s.ArtifactRepo,
+			ArtifactResultRepo: s.ArtifactResultRepo,
+			ContentBlobRepo:    s.ContentBlobRepo,
+			DAGRepo:            s.DAGRepo,
+			IntegrationRepo:    s.IntegrationRepo,
+			OperatorRepo:       s.OperatorRepo,
+
+			PreviewCacheManagerFn: func() preview_cache.CacheManager { return s.PreviewCacheManager },
+		},
+		routes.GetPreviewCacheStatsRoute: &handler.GetPreviewCacheStatsHandler{
+			PreviewCacheManagerFn: func() preview_cache.CacheManager { return s.PreviewCacheManager },
+		},
+		routes.RotateEncryptionKeyRoute: &handler.RotateEncryptionKeyHandler{
+			Database:        s.Database,
+			IntegrationRepo: s.IntegrationRepo,
+
+			PauseServerFn:   s.Pause,
+			RestartServerFn: s.Restart,
+		},
+		routes.RetireEncryptionKeyRoute: &handler.RetireEncryptionKeyHandler{
+			Database:        s.Database,
+			DAGRepo:         s.DAGRepo,
+			IntegrationRepo: s.IntegrationRepo,
+
+			PauseServerFn:   s.Pause,
+			RestartServerFn: s.Restart,
+		},
 		routes.GetNodePositionsRoute: &handler.GetNodePositionsHandler{},
 		routes.GetOperatorResultRoute: &handler.GetOperatorResultHandlerDeprecated{
 			Database: s.Database,
@@ -314,6 +377,17 @@ func (s *AqServer) Handlers() map[string]handler.Handler {
 
 			IntegrationRepo: s.IntegrationRepo,
 		},
+		routes.MigrateWorkflowStorageRoute: &handler.MigrateWorkflowStorageHandler{
+			Database: s.Database,
+
+			ArtifactResultRepo: s.ArtifactResultRepo,
+			ContentBlobRepo:    s.ContentBlobRepo,
+			DAGRepo:            s.DAGRepo,
+			DAGResultRepo:      s.DAGResultRepo,
+			IntegrationRepo:    s.IntegrationRepo,
+			OperatorRepo:       s.OperatorRepo,
+			WorkflowRepo:       s.WorkflowRepo,
+		},
 		routes.RefreshWorkflowRoute: &handler.RefreshWorkflowHandler{
 			Database: s.Database,
 			Engine:   s.AqEngine,
@@ -32,13 +32,14 @@ func syncVaultWithStorage(
 	}
 
 	oldVault, err := vault.NewVault(
+		nil, /* vaultConf */
 		&shared.StorageConfig{
 			Type: shared.FileStorageType,
 			FileConfig: &shared.FileConfig{
 				Directory: config.AqueductPath(),
 			},
 		},
-		config.EncryptionKey(),
+		config.EncryptionKeyring(),
 	)
 	if err != nil {
 		return err
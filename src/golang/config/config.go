@@ -3,12 +3,18 @@ package config
 import (
 	"os"
 	"path"
+	"time"
 
+	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/dropbox/godropbox/errors"
 	"gopkg.in/yaml.v2"
 )
 
+// PathEnvVar overrides the default path of the server config, for processes that
+// do not run on the server's machine, such as the executor in a k8s cron job.
+const PathEnvVar = "AQUEDUCT_CONFIG_PATH"
+
 var (
 	// globalConfigPath is set during Init
 	globalConfigPath string
@@ -22,6 +28,50 @@ type serverConfiguration struct {
 	RetentionJobPeriod string                `yaml:"retentionJobPeriod"`
 	ApiKey             string                `yaml:"apiKey"`
 	StorageConfig      *shared.StorageConfig `yaml:"storageConfig"`
+	// If 0, the number of operators that can execute at once is unlimited.
+	MaxConcurrentOperators int `yaml:"maxConcurrentOperators"`
+
+	// Additional keys that vault entries can be encrypted with, by key ID.
+	EncryptionKeys map[string]string `yaml:"encryptionKeys,omitempty"`
+	// The ID of the key in EncryptionKeys that new vault entries are encrypted with.
+	// If empty, EncryptionKey is used.
+	CurrentEncryptionKeyID string `yaml:"currentEncryptionKeyId,omitempty"`
+
+	// If not set, integration credentials are stored encrypted in the storage layer.
+	VaultConfig *shared.VaultConfig `yaml:"vaultConfig,omitempty"`
+
+	// The cron schedule of the storage garbage collector. If empty, it does not run.
+	StorageGCJobPeriod string `yaml:"storageGcJobPeriod,omitempty"`
+	// How old an unreferenced storage object must be before it is deleted, such as `24h`.
+	StorageGCGracePeriod string `yaml:"storageGcGracePeriod,omitempty"`
+	// If set, the storage garbage collector only reports the unreferenced objects.
+	StorageGCDryRun bool `yaml:"storageGcDryRun,omitempty"`
+
+	// The byte budget of the preview cache. If 0, the default budget is used.
+	// If negative, the preview cache is not bounded by size.
+	PreviewCacheMaxBytes int64 `yaml:"previewCacheMaxBytes,omitempty"`
+
+	// If not set, metadata is stored in the SQLite database under AqPath.
+	DatabaseConfig *database.DatabaseConfig `yaml:"databaseConfig,omitempty"`
+
+	// If set, workflow schedules are deployed as cron jobs on this k8s cluster, so that scheduled
+	// workflows run even while the server is down. Otherwise, the server schedules them itself.
+	K8sSchedulerConfig *K8sSchedulerConfig `yaml:"k8sSchedulerConfig,omitempty"`
+}
+
+// K8sSchedulerConfig is the k8s cluster that workflow schedules are deployed to.
+type K8sSchedulerConfig struct {
+	KubeconfigPath string `yaml:"kubeconfigPath"`
+	// If set, the server runs on the same cluster, and KubeconfigPath is ignored.
+	UseSameCluster bool `yaml:"useSameCluster"`
+	// The AWS credentials that the scheduled runs use to access an S3 storage layer.
+	AwsAccessKeyId     string `yaml:"awsAccessKeyId,omitempty"`
+	AwsSecretAccessKey string `yaml:"awsSecretAccessKey,omitempty"`
+}
+
+// Path returns the path of the file that the config was loaded from.
+func Path() string {
+	return globalConfigPath
 }
 
 // AqueductPath is the filepath to the Aqueduct installation.
@@ -29,26 +79,196 @@ func AqueductPath() string {
 	return globalConfig.AqPath
 }
 
-// EncryptionKey is used for encrypting objects stored in the Aqueduct vault.
+// EncryptionKey is the original key of the Aqueduct keyring, which is used until another key is added.
+// Objects in the storage layer that were encrypted before keys were derived from the keyring are
+// encrypted with it directly. It is empty once it has been retired.
 func EncryptionKey() string {
 	return globalConfig.EncryptionKey
 }
 
+// Keyring holds all of the keys that vault entries and stored objects can be encrypted with.
+type Keyring struct {
+	// CurrentKeyID identifies the key that new vault entries and stored objects are encrypted with.
+	CurrentKeyID string
+	// Keys maps each key ID to its key. The original EncryptionKey has the empty ID, unless it was retired.
+	Keys map[string]string
+}
+
+// CurrentKey returns the key that new vault entries are encrypted with.
+func (k Keyring) CurrentKey() string {
+	return k.Keys[k.CurrentKeyID]
+}
+
+// EncryptionKeyring returns the keyring that is used for the Aqueduct vault.
+func EncryptionKeyring() Keyring {
+	keys := make(map[string]string, len(globalConfig.EncryptionKeys)+1)
+	for keyID, key := range globalConfig.EncryptionKeys {
+		keys[keyID] = key
+	}
+
+	if globalConfig.EncryptionKey != "" {
+		keys[""] = globalConfig.EncryptionKey
+	}
+
+	return Keyring{
+		CurrentKeyID: globalConfig.CurrentEncryptionKeyID,
+		Keys:         keys,
+	}
+}
+
+// AddEncryptionKey adds a key to the vault keyring, and makes it the key that new vault entries
+// are encrypted with. The previous keys stay in the keyring, so that existing entries can still be decrypted.
+func AddEncryptionKey(keyID string, key string) error {
+	if keyID == "" {
+		return errors.New("The encryption key ID cannot be empty.")
+	}
+
+	if _, ok := globalConfig.EncryptionKeys[keyID]; ok {
+		return errors.Newf("Encryption key %s already exists.", keyID)
+	}
+
+	if globalConfig.EncryptionKeys == nil {
+		globalConfig.EncryptionKeys = map[string]string{}
+	}
+	globalConfig.EncryptionKeys[keyID] = key
+	globalConfig.CurrentEncryptionKeyID = keyID
+	return dumpConfig()
+}
+
+// RetireEncryptionKey removes a key from the keyring. Everything that was encrypted with
+// it must have been re-encrypted with another key first, since it can no longer be decrypted.
+// The current key cannot be retired.
+func RetireEncryptionKey(keyID string) error {
+	if keyID == globalConfig.CurrentEncryptionKeyID {
+		return errors.Newf("Encryption key %s is the current key, so it cannot be retired.", keyID)
+	}
+
+	if keyID == "" {
+		if globalConfig.EncryptionKey == "" {
+			return errors.New("The original encryption key has already been retired.")
+		}
+		globalConfig.EncryptionKey = ""
+		return dumpConfig()
+	}
+
+	if _, ok := globalConfig.EncryptionKeys[keyID]; !ok {
+		return errors.Newf("Encryption key %s does not exist.", keyID)
+	}
+
+	delete(globalConfig.EncryptionKeys, keyID)
+	return dumpConfig()
+}
+
 // RetentionJobPeriod defines how long to wait before garbage collecting workflow runs.
 func RetentionJobPeriod() string {
 	return globalConfig.RetentionJobPeriod
 }
 
+// StorageGCJobPeriod is the cron schedule of the storage garbage collector.
+// It is empty if the garbage collector is disabled.
+func StorageGCJobPeriod() string {
+	return globalConfig.StorageGCJobPeriod
+}
+
+// StorageGCGracePeriod returns how old an unreferenced storage object must be before it is deleted.
+// It is 0 if no grace period is configured.
+func StorageGCGracePeriod() (time.Duration, error) {
+	if globalConfig.StorageGCGracePeriod == "" {
+		return 0, nil
+	}
+
+	gracePeriod, err := time.ParseDuration(globalConfig.StorageGCGracePeriod)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Invalid storage garbage collection grace period %s.", globalConfig.StorageGCGracePeriod)
+	}
+	return gracePeriod, nil
+}
+
+// StorageGCDryRun returns whether the storage garbage collector only reports the unreferenced objects.
+func StorageGCDryRun() bool {
+	return globalConfig.StorageGCDryRun
+}
+
+// PreviewCacheMaxBytes returns the byte budget of the preview cache. It is 0 if no budget is configured.
+func PreviewCacheMaxBytes() int64 {
+	return globalConfig.PreviewCacheMaxBytes
+}
+
 // APIKey returns the API key the user must use when issuing requests.
 func APIKey() string {
 	return globalConfig.ApiKey
 }
 
+// MaxConcurrentOperators is the default limit on how many operators of a workflow run
+// can execute at once, for workflows that do not set their own. It is 0 if there is no limit.
+func MaxConcurrentOperators() int {
+	return globalConfig.MaxConcurrentOperators
+}
+
 // Storage returns the storage layer config.
 func Storage() shared.StorageConfig {
 	return *globalConfig.StorageConfig
 }
 
+// Vault returns the config of where integration credentials are kept.
+// It is nil if they are stored in the storage layer.
+func Vault() *shared.VaultConfig {
+	if globalConfig.VaultConfig == nil {
+		return nil
+	}
+
+	vaultConfig := *globalConfig.VaultConfig
+	return &vaultConfig
+}
+
+// Database returns the config of the database that the server's metadata is stored in.
+func Database() *database.DatabaseConfig {
+	if globalConfig.DatabaseConfig == nil {
+		return &database.DatabaseConfig{
+			Type: database.SqliteType,
+			Sqlite: &database.SqliteConfig{
+				File: path.Join(globalConfig.AqPath, database.SqliteDatabasePath),
+			},
+		}
+	}
+
+	databaseConfig := *globalConfig.DatabaseConfig
+	return &databaseConfig
+}
+
+// K8sScheduler returns the k8s cluster that workflow schedules are deployed to.
+// It is nil if the server schedules workflows itself.
+func K8sScheduler() *K8sSchedulerConfig {
+	if globalConfig.K8sSchedulerConfig == nil {
+		return nil
+	}
+
+	schedulerConfig := *globalConfig.K8sSchedulerConfig
+	return &schedulerConfig
+}
+
+// ExecutorConfig returns the config file of an executor that runs away from the server, such as in
+// a k8s cron job. It only has the fields that the executor reads, so that the server's API key and
+// the credentials of its scheduler are not exposed. The executor gets the database from its spec,
+// and the keys of the encrypted storage layer separately. The keyring is only included if integration
+// credentials are kept in the storage layer, since they are encrypted with it.
+func ExecutorConfig() ([]byte, error) {
+	executorConfig := serverConfiguration{
+		AqPath:                 globalConfig.AqPath,
+		StorageConfig:          globalConfig.StorageConfig,
+		VaultConfig:            globalConfig.VaultConfig,
+		MaxConcurrentOperators: globalConfig.MaxConcurrentOperators,
+	}
+
+	if !globalConfig.VaultConfig.IsExternal() {
+		executorConfig.EncryptionKey = globalConfig.EncryptionKey
+		executorConfig.EncryptionKeys = globalConfig.EncryptionKeys
+		executorConfig.CurrentEncryptionKeyID = globalConfig.CurrentEncryptionKeyID
+	}
+
+	return yaml.Marshal(&executorConfig)
+}
+
 // UpdateStorage updates the storage layer config.
 func UpdateStorage(newStorage *shared.StorageConfig) error {
 	globalConfig.StorageConfig = newStorage
@@ -63,6 +63,34 @@ func TestUpdateStorage(t *testing.T) {
 	require.True(t, reflect.DeepEqual(expectedStorage, &actualStorage))
 }
 
+func TestRetireEncryptionKey(t *testing.T) {
+	defer cleanup()
+	setup(t)
+
+	err := Init(testConfigPath)
+	require.Nil(t, err)
+
+	// The current key cannot be retired.
+	require.NotNil(t, RetireEncryptionKey(""))
+
+	require.Nil(t, AddEncryptionKey("first", "first-key"))
+	require.Nil(t, AddEncryptionKey("second", "second-key"))
+	require.NotNil(t, RetireEncryptionKey("second"))
+
+	require.Nil(t, RetireEncryptionKey(""))
+	require.Nil(t, RetireEncryptionKey("first"))
+	require.NotNil(t, RetireEncryptionKey("first"))
+	require.NotNil(t, RetireEncryptionKey(""))
+
+	// The retired keys stay retired once the config is reloaded.
+	err = Init(testConfigPath)
+	require.Nil(t, err)
+	require.Equal(t, Keyring{
+		CurrentKeyID: "second",
+		Keys:         map[string]string{"second": "second-key"},
+	}, EncryptionKeyring())
+}
+
 func TestLoadConfig(t *testing.T) {
 	defer cleanup()
 	setup(t)
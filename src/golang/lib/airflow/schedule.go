@@ -121,6 +121,7 @@ func ScheduleWorkflow(
 				artifactIDToExecPaths[artifactId],
 				nil, /* artifactRepo */
 				nil, /* artifactResultRepo */
+				nil, /* contentBlobRepo */
 				&dag.StorageConfig,
 				nil, /* artifactCacheManager */
 				nil, /* db */
@@ -148,6 +149,7 @@ func ScheduleWorkflow(
 				artifactIDToExecPaths[artifactId],
 				nil, /* artifactWriter */
 				nil, /* artifactResultWriter */
+				nil, /* contentBlobRepo */
 				&dag.StorageConfig,
 				nil, /* previewCacheManager */
 				nil, /* db */
@@ -161,6 +163,7 @@ func ScheduleWorkflow(
 		}
 		airflowOperator, err := operator.NewOperator(
 			ctx,
+			uuid.Nil, /* signature */
 			op,
 			inputArtifacts,
 			outputArtifacts,
@@ -216,6 +219,8 @@ func ScheduleWorkflow(
 		operatorOutputPath,
 		dagId,
 		string(dag.Metadata.Schedule.CronSchedule),
+		dag.Metadata.Schedule.Timezone,
+		dag.Metadata.Schedule.JitterSeconds,
 		taskToJobSpec,
 		taskEdges,
 	)
@@ -300,19 +305,41 @@ func prepareStorageConfig(
 		return emptyStorageConf, err
 	}
 
+	return airflowStorageConfig(storageConfig, airflowConf)
+}
+
+// airflowStorageConfig returns a copy of storageConfig that accesses the storage layer with the
+// S3 credentials of the Airflow workers. Settings that the workers cannot honor are rejected.
+func airflowStorageConfig(storageConfig *shared.StorageConfig, airflowConf *config) (shared.StorageConfig, error) {
+	emptyStorageConf := shared.StorageConfig{}
+
 	if storageConfig.Type != shared.S3StorageType {
 		return emptyStorageConf, errors.New("The StorageType must be S3 to use the Airflow engine.")
 	}
 
-	return shared.StorageConfig{
-		Type: storageConfig.Type,
-		S3Config: &shared.S3Config{
-			Region:             storageConfig.S3Config.Region,
-			Bucket:             storageConfig.S3Config.Bucket,
-			CredentialsPath:    airflowConf.S3CredentialsPath,
-			CredentialsProfile: airflowConf.S3CredentialsProfile,
-		},
-	}, nil
+	if storageConfig.S3Config.CABundlePath != "" {
+		// The file only exists on the server, not on the Airflow workers.
+		return emptyStorageConf, errors.New("The Airflow engine cannot be used with a storage layer that trusts a custom CA bundle.")
+	}
+
+	if storageConfig.Encrypt {
+		// The keys would have to be written into the Airflow DAG file, which is readable by anyone with access to Airflow.
+		return emptyStorageConf, errors.New("The Airflow engine cannot be used with an encrypted storage layer.")
+	}
+
+	s3Config := *storageConfig.S3Config
+	s3Config.CredentialsPath = airflowConf.S3CredentialsPath
+	s3Config.CredentialsProfile = airflowConf.S3CredentialsProfile
+	s3Config.AWSAccessKeyID = ""
+	s3Config.AWSSecretAccessKey = ""
+
+	airflowStorageConf := *storageConfig
+	airflowStorageConf.S3Config = &s3Config
+	// Airflow tasks write artifact content to the paths that they are given, which
+	// is not moved to the hash of the content afterwards.
+	airflowStorageConf.ContentAddressed = false
+	airflowStorageConf.EncryptionKeys = nil
+	return airflowStorageConf, nil
 }
 
 // generateStoragePathPrefixes generates a storage path prefix for each ID.
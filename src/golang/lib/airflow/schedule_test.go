@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
@@ -101,3 +102,60 @@ func compareEdges(a, b map[string][]string, t *testing.T) bool {
 
 	return true
 }
+
+func TestAirflowStorageConfig(t *testing.T) {
+	airflowConf := &config{
+		S3CredentialsPath:    "/home/airflow/.aws/credentials",
+		S3CredentialsProfile: "airflow",
+	}
+	storageConfig := &shared.StorageConfig{
+		Type: shared.S3StorageType,
+		S3Config: &shared.S3Config{
+			Region:             "us-east-2",
+			Bucket:             "aqueduct",
+			RootDir:            "server/",
+			CredentialsPath:    "/home/aqueduct/.aws/credentials",
+			CredentialsProfile: "default",
+			AWSAccessKeyID:     "id",
+			AWSSecretAccessKey: "secret",
+			Endpoint:           "https://minio.internal:9000",
+			ForcePathStyle:     true,
+		},
+		ContentAddressed: true,
+		Compression:      shared.ZstdCompressionType,
+	}
+
+	airflowStorageConf, err := airflowStorageConfig(storageConfig, airflowConf)
+	require.Nil(t, err)
+	require.Equal(t, shared.StorageConfig{
+		Type: shared.S3StorageType,
+		S3Config: &shared.S3Config{
+			Region:             "us-east-2",
+			Bucket:             "aqueduct",
+			RootDir:            "server/",
+			CredentialsPath:    "/home/airflow/.aws/credentials",
+			CredentialsProfile: "airflow",
+			Endpoint:           "https://minio.internal:9000",
+			ForcePathStyle:     true,
+		},
+		Compression: shared.ZstdCompressionType,
+	}, airflowStorageConf)
+
+	// The server's storage config is left as is.
+	require.Equal(t, "secret", storageConfig.S3Config.AWSSecretAccessKey)
+
+	caBundleConfig := *storageConfig
+	caBundleS3Config := *storageConfig.S3Config
+	caBundleS3Config.CABundlePath = "/etc/aqueduct/ca.pem"
+	caBundleConfig.S3Config = &caBundleS3Config
+	_, err = airflowStorageConfig(&caBundleConfig, airflowConf)
+	require.NotNil(t, err)
+
+	encryptedConfig := *storageConfig
+	encryptedConfig.Encrypt = true
+	_, err = airflowStorageConfig(&encryptedConfig, airflowConf)
+	require.NotNil(t, err)
+
+	_, err = airflowStorageConfig(&shared.StorageConfig{Type: shared.FileStorageType}, airflowConf)
+	require.NotNil(t, err)
+}
@@ -0,0 +1,276 @@
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/cronjob"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/engine"
+	"github.com/aqueducthq/aqueduct/lib/lib_utils"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// MaxTicks is the maximum number of workflow runs that a single backfill can trigger.
+const MaxTicks = 1000
+
+var (
+	// How often the progress of the triggered workflow runs is checked.
+	pollInterval = 5 * time.Second
+
+	// A triggered workflow run that has not created its DAG result within this long is considered failed,
+	// since its executor must have exited before it got to start the run.
+	runStartTimeout = 10 * time.Minute
+)
+
+var ErrTooManyTicks = errors.Newf("A backfill cannot trigger more than %d workflow runs.", MaxTicks)
+
+// Ticks returns every time in [start, end] at which `cronSchedule` fires in `timezone`, in increasing order.
+// The ticks are computed the same way as the workflow's scheduled runs, so a backfill across a daylight
+// saving time transition runs the workflow at the same times as its schedule would have.
+// It returns ErrTooManyTicks if there are more than MaxTicks such times.
+func Ticks(cronSchedule string, timezone string, start time.Time, end time.Time) ([]time.Time, error) {
+	ticks := []time.Time{}
+	// `NextTrigger` returns the first tick strictly after the given time, so we start right
+	// before `start` to include a tick that falls exactly on it.
+	tick, err := cronjob.NextTrigger(cronSchedule, timezone, start.Add(-time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+
+	for !tick.IsZero() && !tick.After(end) {
+		if len(ticks) == MaxTicks {
+			return nil, ErrTooManyTicks
+		}
+
+		ticks = append(ticks, tick)
+		tick, err = cronjob.NextTrigger(cronSchedule, timezone, tick)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ticks, nil
+}
+
+// Perform starts a backfill of the workflow with `workflowID` over [start, end].
+// The workflow is run once for every tick of `cronSchedule` in `timezone` in the range, with at most
+// `maxConcurrency` runs in progress at a time. The backfill is tracked as a new entry in
+// the `workflow_backfill` table, which is returned. This method does not block on the workflow runs.
+func Perform(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	cronSchedule string,
+	timezone string,
+	start time.Time,
+	end time.Time,
+	maxConcurrency int,
+	eng engine.Engine,
+	backfillRepo repos.Backfill,
+	dagResultRepo repos.DAGResult,
+	DB database.Database,
+) (*models.Backfill, error) {
+	ticks, err := Ticks(cronSchedule, timezone, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	backfill, err := backfillRepo.Create(
+		ctx,
+		workflowID,
+		cronSchedule,
+		timezone,
+		start,
+		end,
+		maxConcurrency,
+		DB,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to create backfill.")
+	}
+
+	go func() {
+		// Shadows the context in the outer scope on purpose.
+		ctx := context.Background()
+
+		execState := backfill.ExecState
+
+		runningAt := time.Now()
+		execState.Status = shared.RunningExecutionStatus
+		execState.Timestamps.RunningAt = &runningAt
+		if err := updateBackfillExecState(ctx, backfill.ID, &execState, backfillRepo, DB); err != nil {
+			log.Errorf("Unexpected error when updating backfill %s to RUNNING: %v", backfill.ID, err)
+			return
+		}
+
+		numSucceeded, err := runTicks(ctx, backfill, ticks, eng, dagResultRepo, DB)
+		if err != nil {
+			execState.UpdateWithFailure(
+				shared.SystemFailure,
+				&shared.Error{
+					Tip:     "Failure occurred when triggering the backfill runs.",
+					Context: err.Error(),
+				},
+			)
+		} else if numSucceeded < len(ticks) {
+			execState.UpdateWithFailure(
+				shared.UserFatalFailure,
+				&shared.Error{
+					Tip:     fmt.Sprintf("%d of %d backfill runs did not succeed.", len(ticks)-numSucceeded, len(ticks)),
+					Context: "",
+				},
+			)
+		} else {
+			finishedAt := time.Now()
+			execState.Status = shared.SucceededExecutionStatus
+			execState.Timestamps.FinishedAt = &finishedAt
+		}
+
+		if err := updateBackfillExecState(ctx, backfill.ID, &execState, backfillRepo, DB); err != nil {
+			log.Errorf("Unexpected error when updating backfill %s to %s: %v", backfill.ID, execState.Status, err)
+		}
+	}()
+
+	return backfill, nil
+}
+
+// runTicks triggers a workflow run for each tick, keeping at most `backfill.MaxConcurrency`
+// runs in progress. It blocks until all of the runs have terminated or have exceeded their
+// deadline, and returns the number of runs that succeeded.
+func runTicks(
+	ctx context.Context,
+	backfill *models.Backfill,
+	ticks []time.Time,
+	eng engine.Engine,
+	dagResultRepo repos.DAGResult,
+	DB database.Database,
+) (int, error) {
+	timeConfig := &engine.AqueductTimeConfig{
+		OperatorPollInterval: engine.DefaultPollIntervalMillisec,
+		ExecTimeout:          engine.DefaultExecutionTimeout,
+		CleanupTimeout:       engine.DefaultCleanupTimeout,
+	}
+
+	// A run that is still in progress after this long is considered failed, since the engine
+	// would have timed it out by then unless its executor exited.
+	runTimeout := runStartTimeout + timeConfig.ExecTimeout + timeConfig.CleanupTimeout
+
+	// The time at which the run of each tick was triggered, for the ticks that have been launched so far.
+	triggeredAt := make([]time.Time, 0, len(ticks))
+	for {
+		dagResults, err := dagResultRepo.GetByBackfill(ctx, backfill.ID, DB)
+		if err != nil {
+			return 0, errors.Wrap(err, "Unable to read backfill runs.")
+		}
+
+		// The DAG result of a run is identified by the tick that it was triggered for.
+		tickToDAGResult := make(map[int64]models.DAGResult, len(dagResults))
+		for _, dagResult := range dagResults {
+			if !dagResult.ExecutionTime.IsNull {
+				tickToDAGResult[dagResult.ExecutionTime.Time.Unix()] = dagResult
+			}
+		}
+
+		numTerminated := 0
+		numSucceeded := 0
+		for i, tick := range ticks[:len(triggeredAt)] {
+			dagResult, ok := tickToDAGResult[tick.Unix()]
+			if ok && dagResult.ExecState.Terminated() {
+				numTerminated++
+				if dagResult.Status == shared.SucceededExecutionStatus {
+					numSucceeded++
+				}
+			} else if !ok && time.Since(triggeredAt[i]) > runStartTimeout {
+				log.Errorf("Backfill run for %s of backfill %s did not start.", tick.Format(time.RFC3339), backfill.ID)
+				numTerminated++
+			} else if time.Since(triggeredAt[i]) > runTimeout {
+				log.Errorf("Backfill run for %s of backfill %s did not finish.", tick.Format(time.RFC3339), backfill.ID)
+				numTerminated++
+			}
+		}
+
+		if numTerminated == len(ticks) {
+			return numSucceeded, nil
+		}
+
+		// A run that has been triggered but has not created its DAG result yet is still in progress.
+		for launched := len(triggeredAt); launched < len(ticks) && launched-numTerminated < backfill.MaxConcurrency; launched++ {
+			_, err := eng.TriggerBackfillRun(
+				ctx,
+				backfill.WorkflowID,
+				lib_utils.AppendPrefix(backfill.WorkflowID.String()),
+				&engine.BackfillRun{
+					BackfillID:    backfill.ID,
+					ExecutionTime: ticks[launched],
+				},
+				timeConfig,
+			)
+			if err != nil {
+				return 0, errors.Wrapf(err, "Unable to trigger the backfill run for %s.", ticks[launched].Format(time.RFC3339))
+			}
+
+			triggeredAt = append(triggeredAt, time.Now())
+		}
+
+		time.Sleep(pollInterval)
+	}
+}
+
+// FailInterrupted marks the backfills that were in progress when the server stopped as failed.
+// Backfills are orchestrated by the server, so they cannot continue after a restart.
+func FailInterrupted(
+	ctx context.Context,
+	backfillRepo repos.Backfill,
+	DB database.Database,
+) error {
+	for _, status := range []shared.ExecutionStatus{shared.RunningExecutionStatus, shared.PendingExecutionStatus} {
+		backfills, err := backfillRepo.UpdateBatchStatusByStatus(ctx, status, shared.FailedExecutionStatus, DB)
+		if err != nil {
+			return errors.Wrap(err, "Unable to update interrupted backfills.")
+		}
+
+		for _, backfill := range backfills {
+			execState := backfill.ExecState
+			if execState.Timestamps == nil {
+				execState.Timestamps = &shared.ExecutionTimestamps{}
+			}
+			execState.UpdateWithFailure(
+				shared.SystemFailure,
+				&shared.Error{
+					Tip:     "The server stopped while the backfill was in progress. The backfill can be started again.",
+					Context: "",
+				},
+			)
+			if err := updateBackfillExecState(ctx, backfill.ID, &execState, backfillRepo, DB); err != nil {
+				return errors.Wrapf(err, "Unable to update backfill %s.", backfill.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func updateBackfillExecState(
+	ctx context.Context,
+	backfillID uuid.UUID,
+	execState *shared.ExecutionState,
+	backfillRepo repos.Backfill,
+	DB database.Database,
+) error {
+	_, err := backfillRepo.Update(
+		ctx,
+		backfillID,
+		map[string]interface{}{
+			models.BackfillStatus:    execState.Status,
+			models.BackfillExecState: execState,
+		},
+		DB,
+	)
+	return err
+}
@@ -0,0 +1,236 @@
+package backfill
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/engine"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/utils"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicks(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// The range is inclusive on both ends.
+	ticks, err := Ticks("0 * * * *", "" /* timezone */, start, start.Add(3*time.Hour))
+	require.Nil(t, err)
+	require.Equal(t, []time.Time{
+		start,
+		start.Add(time.Hour),
+		start.Add(2 * time.Hour),
+		start.Add(3 * time.Hour),
+	}, ticks)
+
+	// No tick falls within the range.
+	ticks, err = Ticks("0 0 * * *", "" /* timezone */, start.Add(time.Minute), start.Add(time.Hour))
+	require.Nil(t, err)
+	require.Empty(t, ticks)
+
+	_, err = Ticks("* * * * *", "" /* timezone */, start, start.Add(MaxTicks*time.Minute))
+	require.Equal(t, ErrTooManyTicks, err)
+
+	_, err = Ticks("not a schedule", "" /* timezone */, start, start.Add(time.Hour))
+	require.NotNil(t, err)
+}
+
+func TestTicksAcrossDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.Nil(t, err)
+
+	// Daylight saving time starts on 2023-03-12 in New York, so the daily run keeps happening at
+	// 9am local time while it moves by an hour in UTC.
+	start := time.Date(2023, 3, 11, 0, 0, 0, 0, newYork)
+	ticks, err := Ticks("0 9 * * *", "America/New_York", start, start.Add(72*time.Hour))
+	require.Nil(t, err)
+	require.Len(t, ticks, 3)
+	for i, tick := range ticks {
+		require.True(t, time.Date(2023, 3, 11+i, 9, 0, 0, 0, newYork).Equal(tick))
+	}
+	require.Equal(t, 23*time.Hour, ticks[1].Sub(ticks[0]))
+	require.Equal(t, 24*time.Hour, ticks[2].Sub(ticks[1]))
+
+	// Daylight saving time ends on 2023-11-05, when 1:30am happens twice. The workflow's schedule
+	// fires at both, so the backfill runs the workflow for both as well.
+	start = time.Date(2023, 11, 5, 0, 0, 0, 0, newYork)
+	ticks, err = Ticks("30 1 * * *", "America/New_York", start, start.Add(24*time.Hour))
+	require.Nil(t, err)
+	require.Len(t, ticks, 2)
+	require.Equal(t, time.Hour, ticks[1].Sub(ticks[0]))
+
+	// An unknown time zone is rejected.
+	_, err = Ticks("0 9 * * *", "Not/A_Timezone", start, start.Add(time.Hour))
+	require.NotNil(t, err)
+}
+
+// fakeEngine runs `onTrigger` for every backfill run that is triggered.
+type fakeEngine struct {
+	engine.Engine
+
+	onTrigger func(backfillRun *engine.BackfillRun)
+
+	mu             sync.Mutex
+	executionTimes []time.Time
+}
+
+func (e *fakeEngine) TriggerBackfillRun(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	name string,
+	backfillRun *engine.BackfillRun,
+	timeConfig *engine.AqueductTimeConfig,
+) (shared.ExecutionStatus, error) {
+	e.mu.Lock()
+	e.executionTimes = append(e.executionTimes, backfillRun.ExecutionTime)
+	e.mu.Unlock()
+
+	e.onTrigger(backfillRun)
+	return shared.PendingExecutionStatus, nil
+}
+
+type fakeDAGResultRepo struct {
+	repos.DAGResult
+
+	mu         sync.Mutex
+	dagResults []models.DAGResult
+}
+
+func (r *fakeDAGResultRepo) add(backfillRun *engine.BackfillRun, status shared.ExecutionStatus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dagResults = append(r.dagResults, models.DAGResult{
+		ID:            uuid.New(),
+		Status:        status,
+		ExecState:     shared.NullExecutionState{ExecutionState: shared.ExecutionState{Status: status}},
+		BackfillID:    utils.NullUUID{UUID: backfillRun.BackfillID},
+		ExecutionTime: utils.NullTime{Time: backfillRun.ExecutionTime},
+	})
+}
+
+func (r *fakeDAGResultRepo) GetByBackfill(ctx context.Context, backfillID uuid.UUID, DB database.Database) ([]models.DAGResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.DAGResult{}, r.dagResults...), nil
+}
+
+type fakeBackfillRepo struct {
+	repos.Backfill
+
+	backfills map[uuid.UUID]*models.Backfill
+}
+
+func (r *fakeBackfillRepo) UpdateBatchStatusByStatus(
+	ctx context.Context,
+	from shared.ExecutionStatus,
+	to shared.ExecutionStatus,
+	DB database.Database,
+) ([]models.Backfill, error) {
+	updated := []models.Backfill{}
+	for _, backfill := range r.backfills {
+		if backfill.Status == from {
+			backfill.Status = to
+			backfill.ExecState.Status = to
+			updated = append(updated, *backfill)
+		}
+	}
+	return updated, nil
+}
+
+func (r *fakeBackfillRepo) Update(
+	ctx context.Context,
+	ID uuid.UUID,
+	changes map[string]interface{},
+	DB database.Database,
+) (*models.Backfill, error) {
+	backfill := r.backfills[ID]
+	backfill.Status = changes[models.BackfillStatus].(shared.ExecutionStatus)
+	backfill.ExecState = *changes[models.BackfillExecState].(*shared.ExecutionState)
+	return backfill, nil
+}
+
+func TestRunTicks(t *testing.T) {
+	prevPollInterval, prevRunStartTimeout := pollInterval, runStartTimeout
+	pollInterval, runStartTimeout = 10*time.Millisecond, 200*time.Millisecond
+	t.Cleanup(func() {
+		pollInterval, runStartTimeout = prevPollInterval, prevRunStartTimeout
+	})
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	ticks := []time.Time{start, start.Add(time.Hour), start.Add(2 * time.Hour), start.Add(3 * time.Hour)}
+	backfill := &models.Backfill{ID: uuid.New(), WorkflowID: uuid.New(), MaxConcurrency: 2}
+
+	// The second run dies before it creates its DAG result, and the third one fails.
+	dagResultRepo := &fakeDAGResultRepo{}
+	eng := &fakeEngine{onTrigger: func(backfillRun *engine.BackfillRun) {
+		switch {
+		case backfillRun.ExecutionTime.Equal(ticks[1]):
+			// The executor exits without creating a DAG result.
+		case backfillRun.ExecutionTime.Equal(ticks[2]):
+			dagResultRepo.add(backfillRun, shared.FailedExecutionStatus)
+		default:
+			dagResultRepo.add(backfillRun, shared.SucceededExecutionStatus)
+		}
+	}}
+
+	type result struct {
+		numSucceeded int
+		err          error
+	}
+	done := make(chan result)
+	go func() {
+		numSucceeded, err := runTicks(context.Background(), backfill, ticks, eng, dagResultRepo, nil /* DB */)
+		done <- result{numSucceeded, err}
+	}()
+
+	select {
+	case res := <-done:
+		require.Nil(t, res.err)
+		require.Equal(t, 2, res.numSucceeded)
+	case <-time.After(10 * time.Second):
+		t.Fatal("The backfill did not finish, even though none of its runs are in progress.")
+	}
+
+	// Every tick was triggered once, in order.
+	require.Equal(t, ticks, eng.executionTimes)
+}
+
+func TestFailInterrupted(t *testing.T) {
+	backfillRepo := &fakeBackfillRepo{backfills: map[uuid.UUID]*models.Backfill{}}
+	for _, status := range []shared.ExecutionStatus{
+		shared.RunningExecutionStatus,
+		shared.PendingExecutionStatus,
+		shared.SucceededExecutionStatus,
+	} {
+		backfill := &models.Backfill{
+			ID:        uuid.New(),
+			Status:    status,
+			ExecState: shared.ExecutionState{Status: status, Timestamps: &shared.ExecutionTimestamps{}},
+		}
+		backfillRepo.backfills[backfill.ID] = backfill
+	}
+
+	require.Nil(t, FailInterrupted(context.Background(), backfillRepo, nil /* DB */))
+
+	numFailed := 0
+	for _, backfill := range backfillRepo.backfills {
+		if backfill.Status == shared.SucceededExecutionStatus {
+			require.Nil(t, backfill.ExecState.Error)
+			continue
+		}
+
+		numFailed++
+		require.Equal(t, shared.FailedExecutionStatus, backfill.Status)
+		require.Equal(t, shared.FailedExecutionStatus, backfill.ExecState.Status)
+		require.Equal(t, shared.SystemFailure, *backfill.ExecState.FailureType)
+		require.NotNil(t, backfill.ExecState.Error)
+		require.NotNil(t, backfill.ExecState.Timestamps.FinishedAt)
+	}
+	require.Equal(t, 2, numFailed)
+}
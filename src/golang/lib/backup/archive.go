@@ -0,0 +1,137 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+// An archive is a gzipped tarball with the following entries, in this order:
+//   - manifest.json: the archive's Manifest
+//   - metadata.json: the exported Metadata
+//   - secrets: the integration credentials, encrypted with the export passphrase
+//   - objects/<path>: the storage object at path, for each operator's code and artifact result's content
+//
+// The entries are read in the same order they are written, so an archive can be imported
+// without buffering it.
+const (
+	manifestEntry = "manifest.json"
+	metadataEntry = "metadata.json"
+	secretsEntry  = "secrets"
+	objectsDir    = "objects/"
+
+	// formatVersion is bumped whenever the layout of the archive changes.
+	formatVersion = 1
+)
+
+// Manifest describes an archive.
+type Manifest struct {
+	FormatVersion int `json:"format_version"`
+	// SchemaVersion is the database schema version of the server that the archive was exported from.
+	SchemaVersion int64     `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Integration is the archived form of an integration. Its credentials are archived separately.
+type Integration struct {
+	ID        uuid.UUID                `json:"id"`
+	UserID    utils.NullUUID           `json:"user_id"`
+	Service   shared.Service           `json:"service"`
+	Name      string                   `json:"name"`
+	Config    shared.IntegrationConfig `json:"config"`
+	CreatedAt time.Time                `json:"created_at"`
+}
+
+// Metadata is everything that is exported from the database.
+type Metadata struct {
+	Integrations          []Integration                 `json:"integrations"`
+	ExecutionEnvironments []models.ExecutionEnvironment `json:"execution_environments"`
+	Workflows             []models.Workflow             `json:"workflows"`
+	DAGs                  []models.DAG                  `json:"dags"`
+	DAGEdges              []models.DAGEdge              `json:"dag_edges"`
+	Operators             []models.Operator             `json:"operators"`
+	Artifacts             []models.Artifact             `json:"artifacts"`
+	DAGResults            []models.DAGResult            `json:"dag_results"`
+	OperatorResults       []models.OperatorResult       `json:"operator_results"`
+	ArtifactResults       []models.ArtifactResult       `json:"artifact_results"`
+}
+
+// Summary counts what was exported or imported.
+type Summary struct {
+	Integrations int `json:"integrations"`
+	Workflows    int `json:"workflows"`
+	DAGs         int `json:"dags"`
+	DAGResults   int `json:"dag_results"`
+	Objects      int `json:"objects"`
+}
+
+func newSummary(metadata *Metadata) *Summary {
+	return &Summary{
+		Integrations: len(metadata.Integrations),
+		Workflows:    len(metadata.Workflows),
+		DAGs:         len(metadata.DAGs),
+		DAGResults:   len(metadata.DAGResults),
+	}
+}
+
+// writeEntry writes an entry of size bytes with the content read from r to tw.
+func writeEntry(tw *tar.Writer, name string, size int64, r io.Reader) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0o600,
+		Size:     size,
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	}); err != nil {
+		return err
+	}
+
+	_, err := io.Copy(tw, r)
+	return err
+}
+
+func writeJSONEntry(tw *tar.Writer, name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return writeEntry(tw, name, int64(len(data)), bytes.NewReader(data))
+}
+
+// nextEntry advances tr to the next entry, which must be called name.
+func nextEntry(tr *tar.Reader, name string) error {
+	header, err := tr.Next()
+	if err == io.EOF {
+		return errors.Newf("The archive is missing %s.", name)
+	}
+	if err != nil {
+		return errors.Wrap(err, "Unable to read the archive.")
+	}
+
+	if header.Name != name {
+		return errors.Newf("Expected %s in the archive, but found %s.", name, header.Name)
+	}
+
+	return nil
+}
+
+func readJSONEntry(tr *tar.Reader, name string, v interface{}) error {
+	if err := nextEntry(tr, name); err != nil {
+		return err
+	}
+
+	if err := json.NewDecoder(tr).Decode(v); err != nil {
+		return errors.Wrapf(err, "Unable to parse %s.", name)
+	}
+
+	return nil
+}
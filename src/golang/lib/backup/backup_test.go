@@ -0,0 +1,202 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/connector"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptSecrets(t *testing.T) {
+	secrets := map[string]map[string]string{
+		uuid.New().String(): {"password": "hunter2"},
+	}
+
+	encrypted, err := encryptSecrets(secrets, "passphrase")
+	require.Nil(t, err)
+	require.NotContains(t, string(encrypted), "hunter2")
+
+	decrypted, err := decryptSecrets(encrypted, "passphrase")
+	require.Nil(t, err)
+	require.Equal(t, secrets, decrypted)
+
+	_, err = decryptSecrets(encrypted, "wrong passphrase")
+	require.NotNil(t, err)
+
+	_, err = encryptSecrets(secrets, "")
+	require.NotNil(t, err)
+}
+
+func TestRemapIDs(t *testing.T) {
+	oldIntegrationID, newIntegrationID := uuid.New(), uuid.New()
+	oldArtifactID, newArtifactID := uuid.New(), uuid.New()
+	oldWorkflowID, newWorkflowID := uuid.New(), uuid.New()
+	otherIntegrationID := uuid.New()
+	ids := map[uuid.UUID]uuid.UUID{
+		oldIntegrationID: newIntegrationID,
+		oldArtifactID:    newArtifactID,
+		oldWorkflowID:    newWorkflowID,
+	}
+
+	spec := operator.NewSpecFromExtract(connector.Extract{
+		Service:       shared.Postgres,
+		IntegrationId: oldIntegrationID,
+		Parameters:    &connector.PostgresExtractParams{RelationalDBExtractParams: connector.RelationalDBExtractParams{Query: "SELECT 1"}},
+	})
+	spec.SetEngineConfig(&shared.EngineConfig{
+		Type:      shared.K8sEngineType,
+		K8sConfig: &shared.K8sConfig{IntegrationID: oldIntegrationID},
+	})
+
+	remappedSpec, err := remapSpec(*spec, ids)
+	require.Nil(t, err)
+	require.Equal(t, newIntegrationID, remappedSpec.Extract().IntegrationId)
+	require.Equal(t, newIntegrationID, remappedSpec.EngineConfig().K8sConfig.IntegrationID)
+	require.Equal(t, "SELECT 1", remappedSpec.Extract().Parameters.(*connector.PostgresExtractParams).Query)
+	require.Equal(t, oldIntegrationID, spec.Extract().IntegrationId)
+	require.Equal(t, oldIntegrationID, spec.EngineConfig().K8sConfig.IntegrationID)
+
+	// Only the fields that refer to other objects are remapped, even if other fields happen to contain an ID.
+	var paramSpec operator.Spec
+	require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"type": "param", "param": {"val": "%s"}}`, oldIntegrationID)), &paramSpec))
+	remappedParamSpec, err := remapSpec(paramSpec, ids)
+	require.Nil(t, err)
+	require.Equal(t, oldIntegrationID.String(), remappedParamSpec.Param().Val)
+
+	var conditionSpec operator.Spec
+	require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(
+		`{"type": "function", "function": {}, "condition": {"artifact_id": "%s", "value": true}}`,
+		oldArtifactID,
+	)), &conditionSpec))
+	remappedConditionSpec, err := remapSpec(conditionSpec, ids)
+	require.Nil(t, err)
+	require.Equal(t, newArtifactID, remappedConditionSpec.Condition().ArtifactID)
+	require.Equal(t, oldArtifactID, conditionSpec.Condition().ArtifactID)
+
+	settings := shared.NotificationSettings{
+		Settings: map[uuid.UUID]shared.NotificationLevel{
+			oldIntegrationID:   shared.ErrorNotificationLevel,
+			otherIntegrationID: shared.WarningNotificationLevel,
+		},
+	}
+
+	remappedSettings := remapNotificationSettings(settings, ids)
+	require.Equal(t, map[uuid.UUID]shared.NotificationLevel{
+		newIntegrationID:   shared.ErrorNotificationLevel,
+		otherIntegrationID: shared.WarningNotificationLevel,
+	}, remappedSettings.Settings)
+
+	schedule := remapSchedule(shared.Schedule{Trigger: shared.CascadingUpdateTrigger, SourceID: oldWorkflowID}, ids)
+	require.Equal(t, newWorkflowID, schedule.SourceID)
+
+	config := shared.IntegrationConfig{
+		shared.K8sCloudIntegrationIdKey: oldIntegrationID.String(),
+		shared.K8sClusterNameKey:        oldWorkflowID.String(),
+	}
+	remappedConfig := remapIntegrationConfig(config, ids)
+	require.Equal(t, newIntegrationID.String(), remappedConfig[shared.K8sCloudIntegrationIdKey])
+	require.Equal(t, oldWorkflowID.String(), remappedConfig[shared.K8sClusterNameKey])
+	require.Equal(t, oldIntegrationID.String(), config[shared.K8sCloudIntegrationIdKey])
+
+	oldOperatorID, newOperatorID := uuid.New(), uuid.New()
+	ids[oldOperatorID] = newOperatorID
+	engineConfig, err := remapEngineConfig(shared.EngineConfig{
+		Type: shared.AirflowEngineType,
+		AirflowConfig: &shared.AirflowConfig{
+			IntegrationID:             oldIntegrationID,
+			OperatorToTask:            map[uuid.UUID]string{oldOperatorID: "task"},
+			ArtifactContentPathPrefix: map[uuid.UUID]string{oldArtifactID: "prefix"},
+		},
+	}, ids)
+	require.Nil(t, err)
+	require.Equal(t, newIntegrationID, engineConfig.AirflowConfig.IntegrationID)
+	require.Equal(t, map[uuid.UUID]string{newOperatorID: "task"}, engineConfig.AirflowConfig.OperatorToTask)
+	require.Equal(t, map[uuid.UUID]string{newArtifactID: "prefix"}, engineConfig.AirflowConfig.ArtifactContentPathPrefix)
+}
+
+type fakeContentBlobRepo struct {
+	repos.ContentBlob
+	refCounts map[string]int
+}
+
+func (r *fakeContentBlobRepo) IncrementRefCount(ctx context.Context, hash string, DB database.Database) (*models.ContentBlob, error) {
+	r.refCounts[hash] += 1
+	return &models.ContentBlob{Hash: hash, RefCount: r.refCounts[hash]}, nil
+}
+
+func TestImportContentAddressedObjects(t *testing.T) {
+	ctx := context.Background()
+
+	storageConf := &shared.StorageConfig{
+		Type:       shared.FileStorageType,
+		FileConfig: &shared.FileConfig{Directory: t.TempDir()},
+	}
+	store := storage.NewStorage(storageConf)
+
+	// Content with the same hash is already stored by another workflow.
+	storedPath := storage.ContentAddressedPath(strings.Repeat("a", 64))
+	newPath := storage.ContentAddressedPath(strings.Repeat("b", 64))
+	require.Nil(t, store.Put(ctx, storedPath, []byte("stored content")))
+
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	for path, content := range map[string]string{
+		"plain":    "plain content",
+		storedPath: "archived content",
+		newPath:    "new content",
+	} {
+		require.Nil(t, writeEntry(tw, objectsDir+path, int64(len(content)), strings.NewReader(content)))
+	}
+	require.Nil(t, tw.Close())
+
+	contentBlobRepo := &fakeContentBlobRepo{refCounts: map[string]int{}}
+	im := &importer{
+		storageConf: storageConf,
+		repos:       &Repos{ContentBlobRepo: contentBlobRepo},
+		newPaths:    map[string]string{},
+		summary:     &Summary{},
+	}
+	require.Nil(t, im.importObjects(ctx, tar.NewReader(&archive)))
+	require.Equal(t, 3, im.summary.Objects)
+
+	// Content that is stored by its hash keeps its path.
+	require.NotEqual(t, "plain", im.newPaths["plain"])
+	require.Equal(t, storedPath, im.newPaths[storedPath])
+	require.Equal(t, newPath, im.newPaths[newPath])
+
+	value, err := store.Get(ctx, storedPath)
+	require.Nil(t, err)
+	require.Equal(t, []byte("stored content"), value)
+
+	value, err = store.Get(ctx, newPath)
+	require.Nil(t, err)
+	require.Equal(t, []byte("new content"), value)
+
+	// Each artifact result that shares the content references it.
+	for _, path := range []string{im.newPaths["plain"], newPath, newPath, storedPath} {
+		require.Nil(t, im.referenceContent(ctx, path, nil /* DB */))
+	}
+	require.Equal(t, map[string]int{
+		strings.Repeat("a", 64): 1,
+		strings.Repeat("b", 64): 2,
+	}, contentBlobRepo.refCounts)
+
+	// A failed import only deletes the objects that nothing else can reference.
+	im.cleanup(ctx)
+	require.False(t, store.Exists(ctx, im.newPaths["plain"]))
+	require.True(t, store.Exists(ctx, storedPath))
+	require.True(t, store.Exists(ctx, newPath))
+}
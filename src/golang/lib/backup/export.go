@@ -0,0 +1,338 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// Repos are the repos that metadata is exported from and imported to.
+type Repos struct {
+	ArtifactRepo             repos.Artifact
+	ArtifactResultRepo       repos.ArtifactResult
+	ContentBlobRepo          repos.ContentBlob
+	DAGRepo                  repos.DAG
+	DAGEdgeRepo              repos.DAGEdge
+	DAGResultRepo            repos.DAGResult
+	ExecutionEnvironmentRepo repos.ExecutionEnvironment
+	IntegrationRepo          repos.Integration
+	OperatorRepo             repos.Operator
+	OperatorResultRepo       repos.OperatorResult
+	WorkflowRepo             repos.Workflow
+}
+
+// object is a storage object that is referenced by the exported metadata.
+type object struct {
+	path string
+	// The storage layer of the dag that references the object.
+	storageConfig *shared.StorageConfig
+	// Whether the object must exist. Artifact results that did not succeed may have no content.
+	required bool
+}
+
+// Export writes an archive to w with all of the workflows of the server and the integrations
+// that user has access to. The archive includes the workflows' dags, operators, artifacts and
+// results, the content of the artifact results and the code of the operators, which are read from
+// the storage layer of each dag. The credentials of the integrations are read from `vaultObj` and
+// encrypted with passphrase.
+//
+// Backfills, notifications, watchers and the preview cache are not exported. The content of
+// Airflow workflows is kept by Airflow, so only their metadata is exported.
+func Export(
+	ctx context.Context,
+	w io.Writer,
+	passphrase string,
+	user *models.User,
+	vaultObj vault.Vault,
+	repos *Repos,
+	DB database.Database,
+) (*Summary, error) {
+	// Check the passphrase before doing any work, so that a missing passphrase is reported right away.
+	if passphrase == "" {
+		return nil, errors.New("The export passphrase must not be empty.")
+	}
+
+	txn, err := DB.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer database.TxnRollbackIgnoreErr(ctx, txn)
+
+	metadata, objects, err := readMetadata(ctx, user, repos, txn)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make(map[string]map[string]string, len(metadata.Integrations))
+	for _, integrationObj := range metadata.Integrations {
+		// The vault key for the credentials is the integration record's ID
+		key := integrationObj.ID.String()
+		val, err := vaultObj.Get(ctx, key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Unable to read the credentials of integration %s.", integrationObj.Name)
+		}
+		secrets[key] = val
+	}
+
+	encryptedSecrets, err := encryptSecrets(secrets, passphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to encrypt integration credentials.")
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	if err := writeJSONEntry(tw, manifestEntry, &Manifest{
+		FormatVersion: formatVersion,
+		SchemaVersion: models.CurrentSchemaVersion,
+		CreatedAt:     time.Now(),
+	}); err != nil {
+		return nil, errors.Wrap(err, "Unable to write the archive manifest.")
+	}
+
+	if err := writeJSONEntry(tw, metadataEntry, metadata); err != nil {
+		return nil, errors.Wrap(err, "Unable to write the archived metadata.")
+	}
+
+	if err := writeEntry(tw, secretsEntry, int64(len(encryptedSecrets)), bytes.NewReader(encryptedSecrets)); err != nil {
+		return nil, errors.Wrap(err, "Unable to write the archived secrets.")
+	}
+
+	summary := newSummary(metadata)
+	for _, obj := range objects {
+		exists, err := writeObject(ctx, tw, obj)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Unable to archive storage object %s.", obj.path)
+		}
+
+		if !exists {
+			if obj.required {
+				return nil, errors.Newf("Storage object %s is missing from the storage layer.", obj.path)
+			}
+			continue
+		}
+		summary.Objects++
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// readMetadata reads the metadata to export, and the storage objects that it references.
+func readMetadata(
+	ctx context.Context,
+	user *models.User,
+	repos *Repos,
+	DB database.Database,
+) (*Metadata, []object, error) {
+	metadata := &Metadata{}
+
+	integrations, err := repos.IntegrationRepo.GetByUser(ctx, user.OrgID, user.ID, DB)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to retrieve integrations.")
+	}
+
+	for _, integrationObj := range integrations {
+		metadata.Integrations = append(metadata.Integrations, Integration{
+			ID:        integrationObj.ID,
+			UserID:    integrationObj.UserID,
+			Service:   integrationObj.Service,
+			Name:      integrationObj.Name,
+			Config:    integrationObj.Config,
+			CreatedAt: integrationObj.CreatedAt,
+		})
+	}
+
+	metadata.Workflows, err = repos.WorkflowRepo.List(ctx, DB)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to retrieve workflows.")
+	}
+
+	objects := []object{}
+	archivedPaths := map[string]bool{}
+	addObject := func(path string, dag *models.DAG, required bool) {
+		if archivedPaths[path] {
+			return
+		}
+
+		archivedPaths[path] = true
+		objects = append(objects, object{
+			path:          path,
+			storageConfig: &dag.StorageConfig,
+			required:      required && dag.EngineConfig.Type != shared.AirflowEngineType,
+		})
+	}
+
+	// Operators and artifacts are shared by the dags of a workflow.
+	archivedOperators := map[uuid.UUID]bool{}
+	archivedArtifacts := map[uuid.UUID]bool{}
+	execEnvIDs := []uuid.UUID{}
+
+	for _, workflowObj := range metadata.Workflows {
+		dags, err := repos.DAGRepo.GetByWorkflow(ctx, workflowObj.ID, DB)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "Unable to retrieve workflow dags.")
+		}
+
+		dagByID := make(map[uuid.UUID]*models.DAG, len(dags))
+		dagIDs := make([]uuid.UUID, 0, len(dags))
+		for i, dag := range dags {
+			dagByID[dag.ID] = &dags[i]
+			dagIDs = append(dagIDs, dag.ID)
+		}
+		metadata.DAGs = append(metadata.DAGs, dags...)
+
+		if len(dagIDs) > 0 {
+			edges, err := repos.DAGEdgeRepo.GetByDAGBatch(ctx, dagIDs, DB)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "Unable to retrieve workflow dag edges.")
+			}
+			metadata.DAGEdges = append(metadata.DAGEdges, edges...)
+		}
+
+		for _, dag := range dags {
+			operators, err := repos.OperatorRepo.GetByDAG(ctx, dag.ID, DB)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "Unable to retrieve operators.")
+			}
+
+			for _, operatorObj := range operators {
+				if archivedOperators[operatorObj.ID] {
+					continue
+				}
+				archivedOperators[operatorObj.ID] = true
+				metadata.Operators = append(metadata.Operators, operatorObj)
+
+				if !operatorObj.ExecutionEnvironmentID.IsNull {
+					execEnvIDs = append(execEnvIDs, operatorObj.ExecutionEnvironmentID.UUID)
+				}
+
+				if operatorObj.Spec.HasFunction() {
+					addObject(operatorObj.Spec.Function().StoragePath, dagByID[dag.ID], true /* required */)
+				}
+			}
+
+			artifacts, err := repos.ArtifactRepo.GetByDAG(ctx, dag.ID, DB)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "Unable to retrieve artifacts.")
+			}
+
+			for _, artifactObj := range artifacts {
+				if archivedArtifacts[artifactObj.ID] {
+					continue
+				}
+				archivedArtifacts[artifactObj.ID] = true
+				metadata.Artifacts = append(metadata.Artifacts, artifactObj)
+			}
+		}
+
+		dagResults, err := repos.DAGResultRepo.GetByWorkflow(ctx, workflowObj.ID, DB)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "Unable to retrieve workflow dag results.")
+		}
+
+		if len(dagResults) == 0 {
+			continue
+		}
+		metadata.DAGResults = append(metadata.DAGResults, dagResults...)
+
+		dagResultIDs := make([]uuid.UUID, 0, len(dagResults))
+		dagIDByDAGResultID := make(map[uuid.UUID]uuid.UUID, len(dagResults))
+		for _, dagResult := range dagResults {
+			dagResultIDs = append(dagResultIDs, dagResult.ID)
+			dagIDByDAGResultID[dagResult.ID] = dagResult.DagID
+		}
+
+		operatorResults, err := repos.OperatorResultRepo.GetByDAGResultBatch(ctx, dagResultIDs, DB)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "Unable to retrieve operator results.")
+		}
+		metadata.OperatorResults = append(metadata.OperatorResults, operatorResults...)
+
+		artifactResults, err := repos.ArtifactResultRepo.GetByDAGResults(ctx, dagResultIDs, DB)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "Unable to retrieve artifact results.")
+		}
+		metadata.ArtifactResults = append(metadata.ArtifactResults, artifactResults...)
+
+		for _, artifactResult := range artifactResults {
+			dag := dagByID[dagIDByDAGResultID[artifactResult.DAGResultID]]
+			if dag == nil {
+				return nil, nil, errors.Newf("Unable to find the workflow dag of artifact result %v.", artifactResult.ID)
+			}
+
+			addObject(
+				artifactResult.ContentPath,
+				dag,
+				artifactResult.Status == shared.SucceededExecutionStatus,
+			)
+		}
+	}
+
+	if len(execEnvIDs) > 0 {
+		metadata.ExecutionEnvironments, err = repos.ExecutionEnvironmentRepo.GetBatch(ctx, execEnvIDs, DB)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "Unable to retrieve execution environments.")
+		}
+	}
+
+	return metadata, objects, nil
+}
+
+// writeObject writes obj to tw. It returns whether the object exists.
+func writeObject(ctx context.Context, tw *tar.Writer, obj object) (bool, error) {
+	r, err := storage.NewStorage(obj.storageConfig).GetReader(ctx, obj.path)
+	if err != nil {
+		if aq_errors.Is(err, storage.ErrObjectDoesNotExist()) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer r.Close()
+
+	// The size of a tar entry must be known before its content is written, but objects can be
+	// compressed in the storage layer, and too large to fit in memory, so they are spooled to disk.
+	f, err := os.CreateTemp("", "aqueduct-export-")
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		f.Close()
+		if err := os.Remove(f.Name()); err != nil {
+			log.Errorf("Unable to delete temporary file %s: %v", f.Name(), err)
+		}
+	}()
+
+	size, err := io.Copy(f, r)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return false, err
+	}
+
+	return true, writeEntry(tw, objectsDir+obj.path, size, f)
+}
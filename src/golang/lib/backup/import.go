@@ -0,0 +1,621 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// importedWorkflowSuffix is appended to the name of an imported workflow
+// if the user already has a workflow with the same name.
+const importedWorkflowSuffix = " (imported)"
+
+// Import reads an archive that was written by `Export` from r, and adds its contents to the server.
+// Everything is imported with a new ID, so an archive can be imported into a server that already
+// has workflows, or imported more than once. The IDs that are nested in specs, configs and settings
+// are remapped to the new IDs.
+//
+// The imported workflows and integrations are owned by user. An integration is not imported if the
+// user already has access to an integration with the same name and service, which is used instead.
+// Imported workflows are renamed if the user already has a workflow with the same name.
+//
+// The archived storage objects are written to new paths in the storage layer `storageConf`, which
+// all imported dags store their content in. Content that is stored by its hash keeps its path, and
+// is referenced once by each imported artifact result, so `storageConf` must be the server's storage layer. The credentials of imported integrations are decrypted
+// with passphrase and written to `vaultObj`. The metadata is imported in a single transaction, and
+// the written storage objects and credentials are deleted if the import fails.
+func Import(
+	ctx context.Context,
+	r io.Reader,
+	passphrase string,
+	user *models.User,
+	storageConf *shared.StorageConfig,
+	vaultObj vault.Vault,
+	repos *Repos,
+	DB database.Database,
+) (_ *Summary, err error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to read the archive.")
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+
+	var manifest Manifest
+	if err := readJSONEntry(tr, manifestEntry, &manifest); err != nil {
+		return nil, err
+	}
+
+	if manifest.FormatVersion != formatVersion {
+		return nil, errors.Newf("Unsupported archive format version %d.", manifest.FormatVersion)
+	}
+
+	if manifest.SchemaVersion > models.CurrentSchemaVersion {
+		return nil, errors.Newf(
+			"The archive was exported from a server with schema version %d, but this server only supports up to %d.",
+			manifest.SchemaVersion,
+			models.CurrentSchemaVersion,
+		)
+	}
+
+	var metadata Metadata
+	if err := readJSONEntry(tr, metadataEntry, &metadata); err != nil {
+		return nil, err
+	}
+
+	if err := nextEntry(tr, secretsEntry); err != nil {
+		return nil, err
+	}
+
+	encryptedSecrets, err := io.ReadAll(tr)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to read the archived secrets.")
+	}
+
+	secrets, err := decryptSecrets(encryptedSecrets, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	im := &importer{
+		user:        user,
+		storageConf: storageConf,
+		vault:       vaultObj,
+		repos:       repos,
+		secrets:     secrets,
+		ids:         map[uuid.UUID]uuid.UUID{},
+		newPaths:    map[string]string{},
+		summary:     &Summary{},
+	}
+
+	// Clean up everything that was written outside of the transaction if the import fails.
+	defer func() {
+		if err != nil {
+			im.cleanup(ctx)
+		}
+	}()
+
+	if err := im.importObjects(ctx, tr); err != nil {
+		return nil, err
+	}
+
+	txn, err := DB.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer database.TxnRollbackIgnoreErr(ctx, txn)
+
+	if err := im.importMetadata(ctx, &metadata, txn); err != nil {
+		return nil, err
+	}
+
+	if err := txn.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return im.summary, nil
+}
+
+// importer keeps track of what has been imported so far.
+type importer struct {
+	user        *models.User
+	storageConf *shared.StorageConfig
+	vault       vault.Vault
+	repos       *Repos
+	secrets     map[string]map[string]string
+
+	// ids maps the archived ID of each imported record to its new ID.
+	ids map[uuid.UUID]uuid.UUID
+	// newPaths maps the archived path of each imported storage object to its new path.
+	newPaths map[string]string
+	// The vault keys that credentials have been written to.
+	vaultKeys []string
+
+	summary *Summary
+}
+
+// importObjects writes the storage objects that remain in tr to new paths in the storage layer.
+func (im *importer) importObjects(ctx context.Context, tr *tar.Reader) error {
+	store := storage.NewStorage(im.storageConf)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "Unable to read the archive.")
+		}
+
+		if !strings.HasPrefix(header.Name, objectsDir) {
+			return errors.Newf("Unexpected entry %s in the archive.", header.Name)
+		}
+
+		path := strings.TrimPrefix(header.Name, objectsDir)
+		newPath := utils.InitializePath(false /* isPreview */)
+		if _, ok := storage.ParseContentAddressedPath(path); ok {
+			// The content may be shared by several artifact results, and by those that are already stored.
+			newPath = path
+			if store.Exists(ctx, newPath) {
+				im.newPaths[path] = newPath
+				im.summary.Objects++
+				continue
+			}
+		}
+
+		// The content is streamed, since artifact results can be too large to fit in memory.
+		if err := store.PutReader(ctx, newPath, tr); err != nil {
+			return errors.Wrapf(err, "Unable to import storage object %s.", path)
+		}
+
+		im.newPaths[path] = newPath
+		im.summary.Objects++
+	}
+}
+
+// referenceContent adds a reference to the content at contentPath if it is stored by its hash,
+// since each artifact result that stores its content by its hash holds a reference to it.
+func (im *importer) referenceContent(ctx context.Context, contentPath string, DB database.Database) error {
+	hash, ok := storage.ParseContentAddressedPath(contentPath)
+	if !ok {
+		return nil
+	}
+
+	if _, err := im.repos.ContentBlobRepo.IncrementRefCount(ctx, hash, DB); err != nil {
+		return errors.Wrap(err, "Unable to reference artifact result content.")
+	}
+	return nil
+}
+
+func (im *importer) importMetadata(ctx context.Context, metadata *Metadata, DB database.Database) error {
+	if err := im.importIntegrations(ctx, metadata.Integrations, DB); err != nil {
+		return err
+	}
+
+	if err := im.importExecutionEnvironments(ctx, metadata.ExecutionEnvironments, DB); err != nil {
+		return err
+	}
+
+	if err := im.importWorkflows(ctx, metadata.Workflows, DB); err != nil {
+		return err
+	}
+
+	for _, artifactObj := range metadata.Artifacts {
+		newArtifact, err := im.repos.ArtifactRepo.Create(
+			ctx,
+			artifactObj.Name,
+			artifactObj.Description,
+			artifactObj.Type,
+			DB,
+		)
+		if err != nil {
+			return errors.Wrapf(err, "Unable to import artifact %s.", artifactObj.Name)
+		}
+		im.ids[artifactObj.ID] = newArtifact.ID
+	}
+
+	// Operators are imported after artifacts, since their conditions refer to artifacts.
+	for _, operatorObj := range metadata.Operators {
+		if err := im.importOperator(ctx, &operatorObj, DB); err != nil {
+			return errors.Wrapf(err, "Unable to import operator %s.", operatorObj.Name)
+		}
+	}
+
+	// Dags are imported after operators, since the engine configs of Airflow dags refer to operators.
+	for _, dag := range metadata.DAGs {
+		if err := im.importDAG(ctx, &dag, DB); err != nil {
+			return errors.Wrapf(err, "Unable to import workflow dag %v.", dag.ID)
+		}
+	}
+	im.summary.DAGs = len(metadata.DAGs)
+
+	for _, edge := range metadata.DAGEdges {
+		if _, err := im.repos.DAGEdgeRepo.Create(
+			ctx,
+			im.ids[edge.DagID],
+			edge.Type,
+			im.ids[edge.FromID],
+			im.ids[edge.ToID],
+			edge.Idx,
+			DB,
+		); err != nil {
+			return errors.Wrap(err, "Unable to import workflow dag edge.")
+		}
+	}
+
+	return im.importResults(ctx, metadata, DB)
+}
+
+func (im *importer) importIntegrations(ctx context.Context, integrations []Integration, DB database.Database) error {
+	existingIntegrations, err := im.repos.IntegrationRepo.GetByUser(ctx, im.user.OrgID, im.user.ID, DB)
+	if err != nil {
+		return errors.Wrap(err, "Unable to retrieve integrations.")
+	}
+
+	imported := make([]Integration, 0, len(integrations))
+	for _, integrationObj := range integrations {
+		reused := false
+		for _, existingIntegration := range existingIntegrations {
+			if existingIntegration.Name == integrationObj.Name && existingIntegration.Service == integrationObj.Service {
+				log.Infof("Using the existing integration %s instead of importing it.", integrationObj.Name)
+				im.ids[integrationObj.ID] = existingIntegration.ID
+				reused = true
+				break
+			}
+		}
+
+		if reused {
+			continue
+		}
+
+		var newIntegration *models.Integration
+		if integrationObj.UserID.IsNull {
+			newIntegration, err = im.repos.IntegrationRepo.Create(
+				ctx,
+				im.user.OrgID,
+				integrationObj.Service,
+				integrationObj.Name,
+				&integrationObj.Config,
+				DB,
+			)
+		} else {
+			newIntegration, err = im.repos.IntegrationRepo.CreateForUser(
+				ctx,
+				im.user.OrgID,
+				im.user.ID,
+				integrationObj.Service,
+				integrationObj.Name,
+				&integrationObj.Config,
+				DB,
+			)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "Unable to import integration %s.", integrationObj.Name)
+		}
+		im.ids[integrationObj.ID] = newIntegration.ID
+
+		if secret, ok := im.secrets[integrationObj.ID.String()]; ok {
+			// The vault key for the credentials is the integration record's ID
+			key := newIntegration.ID.String()
+			if err := im.vault.Put(ctx, key, secret); err != nil {
+				return errors.Wrapf(err, "Unable to import the credentials of integration %s.", integrationObj.Name)
+			}
+			im.vaultKeys = append(im.vaultKeys, key)
+		}
+
+		imported = append(imported, integrationObj)
+	}
+
+	// The configs of some integrations refer to other integrations, which may have been imported after them.
+	for _, integrationObj := range imported {
+		config := remapIntegrationConfig(integrationObj.Config, im.ids)
+		if _, err := im.repos.IntegrationRepo.Update(
+			ctx,
+			im.ids[integrationObj.ID],
+			map[string]interface{}{
+				models.IntegrationConfig:    &config,
+				models.IntegrationCreatedAt: integrationObj.CreatedAt,
+			},
+			DB,
+		); err != nil {
+			return errors.Wrapf(err, "Unable to import integration %s.", integrationObj.Name)
+		}
+	}
+
+	im.summary.Integrations = len(imported)
+	return nil
+}
+
+func (im *importer) importExecutionEnvironments(
+	ctx context.Context,
+	execEnvs []models.ExecutionEnvironment,
+	DB database.Database,
+) error {
+	for _, execEnv := range execEnvs {
+		// Execution environments are identified by the hash of their spec, so they can be shared.
+		existingExecEnv, err := im.repos.ExecutionEnvironmentRepo.GetByHash(ctx, execEnv.Hash, DB)
+		if err == nil {
+			im.ids[execEnv.ID] = existingExecEnv.ID
+			continue
+		}
+
+		if !aq_errors.Is(err, database.ErrNoRows()) {
+			return errors.Wrap(err, "Unable to retrieve execution environment.")
+		}
+
+		newExecEnv, err := im.repos.ExecutionEnvironmentRepo.Create(ctx, &execEnv.Spec, execEnv.Hash, DB)
+		if err != nil {
+			return errors.Wrap(err, "Unable to import execution environment.")
+		}
+		im.ids[execEnv.ID] = newExecEnv.ID
+	}
+
+	return nil
+}
+
+func (im *importer) importWorkflows(ctx context.Context, workflows []models.Workflow, DB database.Database) error {
+	for _, workflowObj := range workflows {
+		name, err := im.availableWorkflowName(ctx, workflowObj.Name, DB)
+		if err != nil {
+			return err
+		}
+
+		if name != workflowObj.Name {
+			log.Infof("Importing workflow %s as %s, since the name is already taken.", workflowObj.Name, name)
+		}
+
+		newWorkflow, err := im.repos.WorkflowRepo.Create(
+			ctx,
+			im.user.ID,
+			name,
+			workflowObj.Description,
+			&workflowObj.Schedule,
+			&workflowObj.RetentionPolicy,
+			&shared.NotificationSettings{},
+			workflowObj.MaxConcurrentOperators,
+			DB,
+		)
+		if err != nil {
+			return errors.Wrapf(err, "Unable to import workflow %s.", workflowObj.Name)
+		}
+		im.ids[workflowObj.ID] = newWorkflow.ID
+	}
+
+	// Schedules refer to the workflow that triggers them, which may have been imported after them.
+	for _, workflowObj := range workflows {
+		schedule := remapSchedule(workflowObj.Schedule, im.ids)
+		notificationSettings := remapNotificationSettings(workflowObj.NotificationSettings, im.ids)
+		if _, err := im.repos.WorkflowRepo.Update(
+			ctx,
+			im.ids[workflowObj.ID],
+			map[string]interface{}{
+				models.WorkflowSchedule:             &schedule,
+				models.WorkflowNotificationSettings: &notificationSettings,
+				models.WorkflowCreatedAt:            workflowObj.CreatedAt,
+			},
+			DB,
+		); err != nil {
+			return errors.Wrapf(err, "Unable to import workflow %s.", workflowObj.Name)
+		}
+	}
+
+	im.summary.Workflows = len(workflows)
+	return nil
+}
+
+// availableWorkflowName returns name, or name with a suffix if the user already has a workflow called name.
+func (im *importer) availableWorkflowName(ctx context.Context, name string, DB database.Database) (string, error) {
+	candidate := name
+	for i := 1; ; i++ {
+		_, err := im.repos.WorkflowRepo.GetByOwnerAndName(ctx, im.user.ID, candidate, DB)
+		if aq_errors.Is(err, database.ErrNoRows()) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", errors.Wrap(err, "Unable to check for existing workflows.")
+		}
+
+		candidate = name + importedWorkflowSuffix
+		if i > 1 {
+			candidate = fmt.Sprintf("%s (imported %d)", name, i)
+		}
+	}
+}
+
+func (im *importer) importOperator(ctx context.Context, operatorObj *models.Operator, DB database.Database) error {
+	spec, err := remapSpec(operatorObj.Spec, im.ids)
+	if err != nil {
+		return err
+	}
+
+	if spec.HasFunction() {
+		// The code of Airflow operators is not archived, so it keeps its old path.
+		if newPath, ok := im.newPaths[spec.Function().StoragePath]; ok {
+			spec.Function().StoragePath = newPath
+		}
+	}
+
+	var execEnvID *uuid.UUID
+	if !operatorObj.ExecutionEnvironmentID.IsNull {
+		id := im.ids[operatorObj.ExecutionEnvironmentID.UUID]
+		execEnvID = &id
+	}
+
+	newOperator, err := im.repos.OperatorRepo.Create(
+		ctx,
+		operatorObj.Name,
+		operatorObj.Description,
+		&spec,
+		execEnvID,
+		DB,
+	)
+	if err != nil {
+		return err
+	}
+
+	im.ids[operatorObj.ID] = newOperator.ID
+	return nil
+}
+
+func (im *importer) importDAG(ctx context.Context, dag *models.DAG, DB database.Database) error {
+	engineConfig, err := remapEngineConfig(dag.EngineConfig, im.ids)
+	if err != nil {
+		return err
+	}
+
+	// All imported content is stored in the server's storage layer.
+	newDAG, err := im.repos.DAGRepo.Create(
+		ctx,
+		im.ids[dag.WorkflowID],
+		im.storageConf,
+		&engineConfig,
+		nil, /* storageIntegrationID */
+		DB,
+	)
+	if err != nil {
+		return err
+	}
+	im.ids[dag.ID] = newDAG.ID
+
+	_, err = im.repos.DAGRepo.Update(
+		ctx,
+		newDAG.ID,
+		map[string]interface{}{
+			models.DagCreatedAt: dag.CreatedAt,
+		},
+		DB,
+	)
+	return err
+}
+
+func (im *importer) importResults(ctx context.Context, metadata *Metadata, DB database.Database) error {
+	for _, dagResult := range metadata.DAGResults {
+		newDAGResult, err := im.repos.DAGResultRepo.Create(
+			ctx,
+			im.ids[dagResult.DagID],
+			execStateOf(dagResult.Status, &dagResult.ExecState),
+			DB,
+		)
+		if err != nil {
+			return errors.Wrap(err, "Unable to import workflow dag result.")
+		}
+		im.ids[dagResult.ID] = newDAGResult.ID
+	}
+
+	// Runs refer to the run they were resumed from, which may have been imported after them.
+	// Backfills are not exported, so imported runs do not belong to any backfill.
+	for _, dagResult := range metadata.DAGResults {
+		changes := map[string]interface{}{
+			models.DAGResultCreatedAt: dagResult.CreatedAt,
+		}
+
+		if !dagResult.SourceDAGResultID.IsNull {
+			if sourceID, ok := im.ids[dagResult.SourceDAGResultID.UUID]; ok {
+				changes[models.DAGResultSourceDAGResultID] = sourceID
+			}
+		}
+
+		if !dagResult.ExecutionTime.IsNull {
+			changes[models.DAGResultExecutionTime] = dagResult.ExecutionTime.Time
+		}
+
+		if _, err := im.repos.DAGResultRepo.Update(ctx, im.ids[dagResult.ID], changes, DB); err != nil {
+			return errors.Wrap(err, "Unable to import workflow dag result.")
+		}
+	}
+	im.summary.DAGResults = len(metadata.DAGResults)
+
+	for _, operatorResult := range metadata.OperatorResults {
+		if _, err := im.repos.OperatorResultRepo.Create(
+			ctx,
+			im.ids[operatorResult.DAGResultID],
+			im.ids[operatorResult.OperatorID],
+			execStateOf(operatorResult.Status, &operatorResult.ExecState),
+			DB,
+		); err != nil {
+			return errors.Wrap(err, "Unable to import operator result.")
+		}
+	}
+
+	for _, artifactResult := range metadata.ArtifactResults {
+		contentPath, ok := im.newPaths[artifactResult.ContentPath]
+		if !ok {
+			// The artifact result has no content, so it is given a path of its own that nothing is written to.
+			contentPath = utils.InitializePath(false /* isPreview */)
+		}
+
+		newArtifactResult, err := im.repos.ArtifactResultRepo.Create(
+			ctx,
+			im.ids[artifactResult.DAGResultID],
+			im.ids[artifactResult.ArtifactID],
+			contentPath,
+			DB,
+		)
+		if err != nil {
+			return errors.Wrap(err, "Unable to import artifact result.")
+		}
+
+		if err := im.referenceContent(ctx, contentPath, DB); err != nil {
+			return err
+		}
+
+		if _, err := im.repos.ArtifactResultRepo.Update(
+			ctx,
+			newArtifactResult.ID,
+			map[string]interface{}{
+				models.ArtifactResultStatus:    artifactResult.Status,
+				models.ArtifactResultExecState: execStateOf(artifactResult.Status, &artifactResult.ExecState),
+				models.ArtifactResultMetadata:  &artifactResult.Metadata,
+			},
+			DB,
+		); err != nil {
+			return errors.Wrap(err, "Unable to import artifact result.")
+		}
+	}
+
+	return nil
+}
+
+// cleanup deletes the storage objects and credentials that were imported, on a best-effort basis.
+func (im *importer) cleanup(ctx context.Context) {
+	paths := make([]string, 0, len(im.newPaths))
+	for _, path := range im.newPaths {
+		// Content that is stored by its hash may have been referenced by a workflow run in the meantime.
+		// It is left to the storage garbage collector if nothing references it.
+		if _, ok := storage.ParseContentAddressedPath(path); ok {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	utils.CleanupStorageFiles(ctx, im.storageConf, paths)
+
+	for _, key := range im.vaultKeys {
+		if err := im.vault.Delete(ctx, key); err != nil {
+			log.Errorf("Unable to delete the imported credentials %s: %v", key, err)
+		}
+	}
+}
+
+// execStateOf returns the execution state of a result, which only has a status if it was created
+// before execution states were recorded.
+func execStateOf(status shared.ExecutionStatus, execState *shared.NullExecutionState) *shared.ExecutionState {
+	if execState.IsNull {
+		return &shared.ExecutionState{Status: status}
+	}
+	return &execState.ExecutionState
+}
@@ -0,0 +1,130 @@
+package backup
+
+import (
+	"encoding/json"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator"
+	"github.com/google/uuid"
+)
+
+// The remap functions below return a copy of an object in which the IDs of the objects that it
+// refers to are replaced by the IDs of the imported objects, such as the integration of an extract
+// operator or the source workflow of a schedule. IDs that are not in ids are left as is.
+
+// remapID returns the ID that id maps to, or id itself if it is not in ids.
+func remapID(id uuid.UUID, ids map[uuid.UUID]uuid.UUID) uuid.UUID {
+	if newID, ok := ids[id]; ok {
+		return newID
+	}
+	return id
+}
+
+// remapIDKeys returns a copy of m in which every key is remapped.
+func remapIDKeys[V any](m map[uuid.UUID]V, ids map[uuid.UUID]uuid.UUID) map[uuid.UUID]V {
+	if m == nil {
+		return nil
+	}
+
+	remapped := make(map[uuid.UUID]V, len(m))
+	for id, v := range m {
+		remapped[remapID(id, ids)] = v
+	}
+	return remapped
+}
+
+// deepCopy returns a copy of v that shares no pointers with it.
+func deepCopy[T any](v T) (T, error) {
+	var copied T
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return copied, err
+	}
+
+	err = json.Unmarshal(data, &copied)
+	return copied, err
+}
+
+func remapIntegrationConfig(config shared.IntegrationConfig, ids map[uuid.UUID]uuid.UUID) shared.IntegrationConfig {
+	remapped := make(shared.IntegrationConfig, len(config))
+	for key, value := range config {
+		remapped[key] = value
+	}
+
+	// Dynamic k8s integrations refer to the cloud integration that they create clusters with.
+	if value, ok := remapped[shared.K8sCloudIntegrationIdKey]; ok {
+		if id, err := uuid.Parse(value); err == nil {
+			remapped[shared.K8sCloudIntegrationIdKey] = remapID(id, ids).String()
+		}
+	}
+	return remapped
+}
+
+func remapSchedule(schedule shared.Schedule, ids map[uuid.UUID]uuid.UUID) shared.Schedule {
+	schedule.SourceID = remapID(schedule.SourceID, ids)
+	return schedule
+}
+
+func remapNotificationSettings(
+	settings shared.NotificationSettings,
+	ids map[uuid.UUID]uuid.UUID,
+) shared.NotificationSettings {
+	return shared.NotificationSettings{Settings: remapIDKeys(settings.Settings, ids)}
+}
+
+func remapEngineConfig(config shared.EngineConfig, ids map[uuid.UUID]uuid.UUID) (shared.EngineConfig, error) {
+	remapped, err := deepCopy(config)
+	if err != nil {
+		return remapped, err
+	}
+
+	if remapped.AirflowConfig != nil {
+		airflowConfig := remapped.AirflowConfig
+		airflowConfig.IntegrationID = remapID(airflowConfig.IntegrationID, ids)
+		airflowConfig.OperatorToTask = remapIDKeys(airflowConfig.OperatorToTask, ids)
+		airflowConfig.OperatorMetadataPathPrefix = remapIDKeys(airflowConfig.OperatorMetadataPathPrefix, ids)
+		airflowConfig.ArtifactContentPathPrefix = remapIDKeys(airflowConfig.ArtifactContentPathPrefix, ids)
+		airflowConfig.ArtifactMetadataPathPrefix = remapIDKeys(airflowConfig.ArtifactMetadataPathPrefix, ids)
+	}
+	if remapped.K8sConfig != nil {
+		remapped.K8sConfig.IntegrationID = remapID(remapped.K8sConfig.IntegrationID, ids)
+	}
+	if remapped.LambdaConfig != nil {
+		remapped.LambdaConfig.IntegrationID = remapID(remapped.LambdaConfig.IntegrationID, ids)
+	}
+	if remapped.DatabricksConfig != nil {
+		remapped.DatabricksConfig.IntegrationID = remapID(remapped.DatabricksConfig.IntegrationID, ids)
+	}
+	if remapped.SparkConfig != nil {
+		remapped.SparkConfig.IntegrationId = remapID(remapped.SparkConfig.IntegrationId, ids)
+	}
+
+	return remapped, nil
+}
+
+func remapSpec(spec operator.Spec, ids map[uuid.UUID]uuid.UUID) (operator.Spec, error) {
+	remapped, err := deepCopy(spec)
+	if err != nil {
+		return remapped, err
+	}
+
+	if remapped.Extract() != nil {
+		remapped.Extract().IntegrationId = remapID(remapped.Extract().IntegrationId, ids)
+	}
+	if remapped.Load() != nil {
+		remapped.Load().IntegrationId = remapID(remapped.Load().IntegrationId, ids)
+	}
+	if remapped.Condition() != nil {
+		remapped.Condition().ArtifactID = remapID(remapped.Condition().ArtifactID, ids)
+	}
+	if remapped.EngineConfig() != nil {
+		engineConfig, err := remapEngineConfig(*remapped.EngineConfig(), ids)
+		if err != nil {
+			return remapped, err
+		}
+		remapped.SetEngineConfig(&engineConfig)
+	}
+
+	return remapped, nil
+}
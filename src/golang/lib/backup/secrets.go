@@ -0,0 +1,99 @@
+package backup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+
+	"github.com/dropbox/godropbox/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+// The integration credentials are encrypted with AES-GCM, using a key that is derived from the
+// export passphrase with scrypt. The encrypted secrets consist of the salt, the nonce and the ciphertext.
+const (
+	saltSize = 16
+	keySize  = 32
+
+	// The recommended scrypt parameters for interactive logins as of 2017.
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+func newPassphraseCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	if passphrase == "" {
+		return nil, errors.New("The export passphrase must not be empty.")
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(c)
+}
+
+// encryptSecrets encrypts the credentials of each integration with passphrase.
+func encryptSecrets(secrets map[string]map[string]string, passphrase string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+
+	gcm, err := newPassphraseCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	serialized, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted := append(salt, nonce...)
+	return gcm.Seal(encrypted, nonce, serialized, nil /* additionalData */), nil
+}
+
+// decryptSecrets decrypts the credentials that were encrypted by `encryptSecrets` with passphrase.
+func decryptSecrets(data []byte, passphrase string) (map[string]map[string]string, error) {
+	if len(data) < saltSize {
+		return nil, errors.New("The archived secrets are truncated.")
+	}
+
+	salt, data := data[:saltSize], data[saltSize:]
+	gcm, err := newPassphraseCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errors.New("The archived secrets are truncated.")
+	}
+
+	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil /* additionalData */)
+	if err != nil {
+		return nil, errors.New("Unable to decrypt the archived secrets. Is the passphrase correct?")
+	}
+
+	var secrets map[string]map[string]string
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
@@ -2,11 +2,57 @@ package cronjob
 
 import (
 	"context"
+	"time"
+
+	"github.com/dropbox/godropbox/errors"
+	"github.com/robfig/cron/v3"
 )
 
+// Schedule specifies when a cron job is triggered.
+type Schedule struct {
+	// CronString follows cron convention. An empty CronString means the cron job is paused.
+	CronString string
+	// Timezone is the IANA time zone name in which CronString is interpreted.
+	// It defaults to UTC if empty.
+	Timezone string
+	// Each trigger is delayed by a random duration in [0, Jitter).
+	Jitter time.Duration
+}
+
 type CronjobManager interface {
-	DeployCronJob(ctx context.Context, name string, period string, cronFunction func()) error
+	DeployCronJob(ctx context.Context, name string, schedule Schedule, cronFunction func()) error
 	CronJobExists(ctx context.Context, name string) bool
-	EditCronJob(ctx context.Context, name string, cronString string, cronFunction func()) error
+	EditCronJob(ctx context.Context, name string, schedule Schedule, cronFunction func()) error
 	DeleteCronJob(ctx context.Context, name string) error
 }
+
+// NextTrigger returns the first time after `after` at which the cron string is triggered
+// in the given time zone, or the zero time if it is never triggered again.
+// This uses the same cron parser as the scheduler of ProcessCronjobManager, so that both
+// agree on how daylight saving time transitions are handled.
+func NextTrigger(cronString string, timezone string, after time.Time) (time.Time, error) {
+	loc, err := loadLocation(timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	cronSchedule, err := cron.ParseStandard(cronString)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "Invalid cron schedule %s.", cronString)
+	}
+
+	// The cron string is evaluated in the location of the given time.
+	return cronSchedule.Next(after.In(loc)), nil
+}
+
+func loadLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unknown timezone %s.", timezone)
+	}
+	return loc, nil
+}
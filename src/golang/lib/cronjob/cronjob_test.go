@@ -0,0 +1,76 @@
+package cronjob
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextTriggerAcrossDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.Nil(t, err)
+
+	for _, tc := range []struct {
+		name       string
+		cronString string
+		timezone   string
+		after      time.Time
+		expected   time.Time
+	}{
+		{
+			// 9am EST is 14:00 UTC.
+			name:       "Before spring forward",
+			cronString: "0 9 * * *",
+			timezone:   "America/New_York",
+			after:      time.Date(2023, 3, 11, 10, 0, 0, 0, newYork),
+			expected:   time.Date(2023, 3, 12, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			// 9am EDT is 13:00 UTC.
+			name:       "After spring forward",
+			cronString: "0 9 * * *",
+			timezone:   "America/New_York",
+			after:      time.Date(2023, 3, 12, 10, 0, 0, 0, newYork),
+			expected:   time.Date(2023, 3, 13, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "Fall back",
+			cronString: "0 9 * * *",
+			timezone:   "America/New_York",
+			after:      time.Date(2023, 11, 4, 10, 0, 0, 0, newYork),
+			expected:   time.Date(2023, 11, 5, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			// The hour from 1am to 2am is skipped when the clocks spring forward.
+			name:       "Hourly across spring forward",
+			cronString: "0 * * * *",
+			timezone:   "America/New_York",
+			after:      time.Date(2023, 3, 12, 1, 30, 0, 0, newYork),
+			expected:   time.Date(2023, 3, 12, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			// The schedule is not affected by daylight saving time in UTC.
+			name:       "UTC",
+			cronString: "0 9 * * *",
+			timezone:   "",
+			after:      time.Date(2023, 3, 12, 10, 0, 0, 0, newYork),
+			expected:   time.Date(2023, 3, 13, 9, 0, 0, 0, time.UTC),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			next, err := NextTrigger(tc.cronString, tc.timezone, tc.after)
+			require.Nil(t, err)
+			require.True(t, tc.expected.Equal(next), "expected %s, got %s", tc.expected, next.UTC())
+			require.Equal(t, tc.timezone == "", next.Location() == time.UTC)
+		})
+	}
+}
+
+func TestNextTriggerInvalid(t *testing.T) {
+	_, err := NextTrigger("0 9 * * *", "Mars/Olympus_Mons", time.Now())
+	require.NotNil(t, err)
+
+	_, err = NextTrigger("not a cron string", "", time.Now())
+	require.NotNil(t, err)
+}
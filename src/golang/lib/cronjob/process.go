@@ -2,6 +2,7 @@ package cronjob
 
 import (
 	"context"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -12,27 +13,94 @@ import (
 type cronMetadata struct {
 	// If the cronJob is nil, it means the corresponding workflow has been paused.
 	cronJob *gocron.Job
+	// The scheduler that cronJob belongs to.
+	cronScheduler *gocron.Scheduler
 }
 
 // Please use thread-safe read / insert / remove APIs to maintain maps.
 // These APIs are wrapped with proper locks to support concurrency.
 // Never try to access map using go's native APIs.
 type ProcessCronjobManager struct {
-	cronScheduler *gocron.Scheduler
+	// A gocron scheduler interprets cron strings in a single time zone, so there is
+	// a scheduler for every time zone that is used. This is a mapping from time zone name
+	// to scheduler.
+	cronSchedulers map[string]*gocron.Scheduler
 	// A mapping from cron job name to cron job object pointer.
 	cronMapping map[string]*cronMetadata
 	cronMutex   *sync.RWMutex
+
+	jitterRand  *rand.Rand
+	jitterMutex *sync.Mutex
 }
 
 func NewProcessCronjobManager() *ProcessCronjobManager {
-	cronScheduler := gocron.NewScheduler(time.UTC)
-	cronScheduler.StartAsync()
-
 	return &ProcessCronjobManager{
-		cronScheduler: cronScheduler,
-		cronMapping:   map[string]*cronMetadata{},
-		cronMutex:     &sync.RWMutex{},
+		cronSchedulers: map[string]*gocron.Scheduler{},
+		cronMapping:    map[string]*cronMetadata{},
+		cronMutex:      &sync.RWMutex{},
+		jitterRand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		jitterMutex:    &sync.Mutex{},
+	}
+}
+
+// getScheduler returns the scheduler for the given time zone, and starts it if it does not exist yet.
+func (j *ProcessCronjobManager) getScheduler(timezone string) (*gocron.Scheduler, error) {
+	loc, err := loadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	j.cronMutex.Lock()
+	defer j.cronMutex.Unlock()
+
+	cronScheduler, ok := j.cronSchedulers[loc.String()]
+	if !ok {
+		cronScheduler = gocron.NewScheduler(loc)
+		cronScheduler.StartAsync()
+		j.cronSchedulers[loc.String()] = cronScheduler
+	}
+	return cronScheduler, nil
+}
+
+// scheduleCronJob adds a job that calls `cronFunction` on the given schedule.
+func (j *ProcessCronjobManager) scheduleCronJob(cron *cronMetadata, schedule Schedule, cronFunction func()) error {
+	cronScheduler, err := j.getScheduler(schedule.Timezone)
+	if err != nil {
+		return err
+	}
+
+	cronJob, err := cronScheduler.Cron(schedule.CronString).Do(j.withJitter(schedule.Jitter, cronFunction))
+	if err != nil {
+		return err
+	}
+
+	cron.cronJob = cronJob
+	cron.cronScheduler = cronScheduler
+	return nil
+}
+
+// withJitter returns a function that calls `cronFunction` after a random delay in [0, jitter).
+func (j *ProcessCronjobManager) withJitter(jitter time.Duration, cronFunction func()) func() {
+	if jitter <= 0 {
+		return cronFunction
+	}
+
+	return func() {
+		j.jitterMutex.Lock()
+		delay := time.Duration(j.jitterRand.Int63n(int64(jitter)))
+		j.jitterMutex.Unlock()
+
+		time.Sleep(delay)
+		cronFunction()
+	}
+}
+
+func (j *ProcessCronjobManager) unscheduleCronJob(cron *cronMetadata) {
+	if cron.cronJob != nil {
+		cron.cronScheduler.RemoveByReference(cron.cronJob)
 	}
+	cron.cronJob = nil
+	cron.cronScheduler = nil
 }
 
 func (j *ProcessCronjobManager) getCronMap(key string) (*cronMetadata, bool) {
@@ -57,7 +125,7 @@ func (j *ProcessCronjobManager) deleteCronMap(key string) {
 func (j *ProcessCronjobManager) DeployCronJob(
 	ctx context.Context,
 	name string,
-	period string,
+	schedule Schedule,
 	cronFunction func(),
 ) error {
 	if _, ok := j.getCronMap(name); ok {
@@ -70,13 +138,8 @@ func (j *ProcessCronjobManager) DeployCronJob(
 
 	j.setCronMap(name, cron)
 
-	if period != "" {
-		cronJob, err := j.cronScheduler.Cron(period).Do(cronFunction)
-		if err != nil {
-			return err
-		}
-
-		cron.cronJob = cronJob
+	if schedule.CronString != "" {
+		return j.scheduleCronJob(cron, schedule, cronFunction)
 	}
 
 	return nil
@@ -87,43 +150,26 @@ func (j *ProcessCronjobManager) CronJobExists(ctx context.Context, name string)
 	return ok
 }
 
-func (j *ProcessCronjobManager) EditCronJob(ctx context.Context, name string, cronString string, cronFunction func()) error {
+func (j *ProcessCronjobManager) EditCronJob(ctx context.Context, name string, schedule Schedule, cronFunction func()) error {
 	cronMetadata, ok := j.getCronMap(name)
 	if !ok {
 		return errors.New("Cron job not found")
-	} else {
-		if cronMetadata.cronJob == nil {
-			// This means the current cron job is already paused.
-			if cronString == "" {
-				return nil
-			}
-
-			cronJob, err := j.cronScheduler.Cron(cronString).Do(cronFunction)
-			if err != nil {
-				return err
-			}
-
-			cronMetadata.cronJob = cronJob
-		} else {
-			if cronString == "" {
-				// This means we want to pause the cron job.
-				j.cronScheduler.RemoveByReference(cronMetadata.cronJob)
-				cronMetadata.cronJob = nil
-			} else {
-				_, err := j.cronScheduler.Job(cronMetadata.cronJob).Cron(cronString).Update()
-				if err != nil {
-					return err
-				}
-			}
-		}
+	}
+
+	// The job is replaced, since its time zone or jitter may have changed.
+	// An empty cron string means we want to pause the cron job.
+	j.unscheduleCronJob(cronMetadata)
+	if schedule.CronString == "" {
 		return nil
 	}
+
+	return j.scheduleCronJob(cronMetadata, schedule, cronFunction)
 }
 
 func (j *ProcessCronjobManager) DeleteCronJob(ctx context.Context, name string) error {
 	cronMetadata, ok := j.getCronMap(name)
 	if ok {
-		j.cronScheduler.RemoveByReference(cronMetadata.cronJob)
+		j.unscheduleCronJob(cronMetadata)
 		j.deleteCronMap(name)
 	}
 
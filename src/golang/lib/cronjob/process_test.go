@@ -3,6 +3,7 @@ package cronjob
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/go-co-op/gocron"
 	"github.com/stretchr/testify/require"
@@ -12,29 +13,37 @@ func generateDummyFunction() func() {
 	return func() {}
 }
 
+func numJobs(cronjobManager *ProcessCronjobManager) int {
+	n := 0
+	for _, cronScheduler := range cronjobManager.cronSchedulers {
+		n += len(cronScheduler.Jobs())
+	}
+	return n
+}
+
 func TestDeployCronJob(t *testing.T) {
 	cronjobManager := NewProcessCronjobManager()
 
 	ctx := context.Background()
 
 	workflowName := "workflow"
-	cronString := "0 * * * *"
+	schedule := Schedule{CronString: "0 * * * *"}
 
 	// Deploy an unpaused workflow.
-	err := cronjobManager.DeployCronJob(ctx, workflowName, cronString, generateDummyFunction())
+	err := cronjobManager.DeployCronJob(ctx, workflowName, schedule, generateDummyFunction())
 	require.Nil(t, err)
 	require.Equal(t, 1, len(cronjobManager.cronMapping))
 	require.NotEqual(t, (*gocron.Job)(nil), cronjobManager.cronMapping[workflowName].cronJob)
-	require.Equal(t, 1, len(cronjobManager.cronScheduler.Jobs()))
+	require.Equal(t, 1, numJobs(cronjobManager))
 
 	// Deploy a paused workflow.
 	pausedWorkflowName := "paused_workflow"
-	emptyCronString := ""
-	err = cronjobManager.DeployCronJob(ctx, pausedWorkflowName, emptyCronString, generateDummyFunction())
+	pausedSchedule := Schedule{}
+	err = cronjobManager.DeployCronJob(ctx, pausedWorkflowName, pausedSchedule, generateDummyFunction())
 	require.Nil(t, err)
 	require.Equal(t, 2, len(cronjobManager.cronMapping))
 	require.Equal(t, (*gocron.Job)(nil), cronjobManager.cronMapping[pausedWorkflowName].cronJob)
-	require.Equal(t, 1, len(cronjobManager.cronScheduler.Jobs()))
+	require.Equal(t, 1, numJobs(cronjobManager))
 }
 
 func TestEditCronJob(t *testing.T) {
@@ -44,33 +53,33 @@ func TestEditCronJob(t *testing.T) {
 
 	workflowName := "workflow"
 	pausedWorkflowName := "paused_workflow"
-	cronString := "0 * * * *"
-	newCronString := "1 * * * *"
-	emptyCronString := ""
+	schedule := Schedule{CronString: "0 * * * *"}
+	newSchedule := Schedule{CronString: "1 * * * *"}
+	pausedSchedule := Schedule{}
 
-	cronjobManager.DeployCronJob(ctx, workflowName, cronString, generateDummyFunction())
-	cronjobManager.DeployCronJob(ctx, pausedWorkflowName, emptyCronString, generateDummyFunction())
+	cronjobManager.DeployCronJob(ctx, workflowName, schedule, generateDummyFunction())
+	cronjobManager.DeployCronJob(ctx, pausedWorkflowName, pausedSchedule, generateDummyFunction())
 
 	// Edit an unpaused workflow to another schedule.
-	err := cronjobManager.EditCronJob(ctx, workflowName, newCronString, generateDummyFunction())
+	err := cronjobManager.EditCronJob(ctx, workflowName, newSchedule, generateDummyFunction())
 	require.Nil(t, err)
 	require.Equal(t, 2, len(cronjobManager.cronMapping))
 	require.NotEqual(t, (*gocron.Job)(nil), cronjobManager.cronMapping[workflowName].cronJob)
-	require.Equal(t, 1, len(cronjobManager.cronScheduler.Jobs()))
+	require.Equal(t, 1, numJobs(cronjobManager))
 
 	// Edit an unpaused workflow to paused.
-	err = cronjobManager.EditCronJob(ctx, workflowName, emptyCronString, generateDummyFunction())
+	err = cronjobManager.EditCronJob(ctx, workflowName, pausedSchedule, generateDummyFunction())
 	require.Nil(t, err)
 	require.Equal(t, 2, len(cronjobManager.cronMapping))
 	require.Equal(t, (*gocron.Job)(nil), cronjobManager.cronMapping[workflowName].cronJob)
-	require.Equal(t, 0, len(cronjobManager.cronScheduler.Jobs()))
+	require.Equal(t, 0, numJobs(cronjobManager))
 
 	// Edit a paused workflow to unpaused.
-	err = cronjobManager.EditCronJob(ctx, pausedWorkflowName, cronString, generateDummyFunction())
+	err = cronjobManager.EditCronJob(ctx, pausedWorkflowName, schedule, generateDummyFunction())
 	require.Nil(t, err)
 	require.Equal(t, 2, len(cronjobManager.cronMapping))
 	require.NotEqual(t, (*gocron.Job)(nil), cronjobManager.cronMapping[pausedWorkflowName].cronJob)
-	require.Equal(t, 1, len(cronjobManager.cronScheduler.Jobs()))
+	require.Equal(t, 1, numJobs(cronjobManager))
 }
 
 func TestDeleteCronJob(t *testing.T) {
@@ -79,11 +88,65 @@ func TestDeleteCronJob(t *testing.T) {
 	ctx := context.Background()
 
 	workflowName := "workflow"
-	cronString := "0 * * * *"
+	schedule := Schedule{CronString: "0 * * * *"}
 
-	cronjobManager.DeployCronJob(ctx, workflowName, cronString, generateDummyFunction())
+	cronjobManager.DeployCronJob(ctx, workflowName, schedule, generateDummyFunction())
 	err := cronjobManager.DeleteCronJob(ctx, workflowName)
 	require.Nil(t, err)
 	require.Equal(t, 0, len(cronjobManager.cronMapping))
-	require.Equal(t, 0, len(cronjobManager.cronScheduler.Jobs()))
+	require.Equal(t, 0, numJobs(cronjobManager))
+}
+
+func TestEditCronJobTimezone(t *testing.T) {
+	cronjobManager := NewProcessCronjobManager()
+
+	ctx := context.Background()
+
+	workflowName := "workflow"
+	schedule := Schedule{CronString: "0 9 * * *"}
+	newSchedule := Schedule{CronString: "0 9 * * *", Timezone: "America/New_York"}
+
+	err := cronjobManager.DeployCronJob(ctx, workflowName, schedule, generateDummyFunction())
+	require.Nil(t, err)
+	require.Equal(t, time.UTC, cronjobManager.cronMapping[workflowName].cronScheduler.Location())
+
+	// The job is moved to the scheduler of the new time zone.
+	err = cronjobManager.EditCronJob(ctx, workflowName, newSchedule, generateDummyFunction())
+	require.Nil(t, err)
+	require.Equal(t, 1, numJobs(cronjobManager))
+	require.Equal(t, "America/New_York", cronjobManager.cronMapping[workflowName].cronScheduler.Location().String())
+
+	// Unknown time zones are rejected.
+	err = cronjobManager.EditCronJob(ctx, workflowName, Schedule{CronString: "0 9 * * *", Timezone: "Mars/Olympus_Mons"}, generateDummyFunction())
+	require.NotNil(t, err)
+}
+
+func TestDeployCronJobTimezoneNextRun(t *testing.T) {
+	cronjobManager := NewProcessCronjobManager()
+
+	ctx := context.Background()
+
+	workflowName := "workflow"
+	schedule := Schedule{CronString: "0 9 * * *", Timezone: "America/New_York"}
+
+	err := cronjobManager.DeployCronJob(ctx, workflowName, schedule, generateDummyFunction())
+	require.Nil(t, err)
+
+	// The next run of the job is the next 9am in New York, whether or not daylight saving time is in effect.
+	expectedNextRun, err := NextTrigger(schedule.CronString, schedule.Timezone, time.Now())
+	require.Nil(t, err)
+	require.True(t, expectedNextRun.Equal(cronjobManager.cronMapping[workflowName].cronJob.NextRun()))
+}
+
+func TestWithJitter(t *testing.T) {
+	cronjobManager := NewProcessCronjobManager()
+
+	jitter := 20 * time.Millisecond
+	for i := 0; i < 5; i++ {
+		called := false
+		start := time.Now()
+		cronjobManager.withJitter(jitter, func() { called = true })()
+		require.True(t, called)
+		require.Less(t, time.Since(start), jitter+10*time.Millisecond)
+	}
 }
@@ -10,4 +10,8 @@ const (
 	DatabricksParamScript    = "paramScript.py"
 	DatabricksMetricScript   = "metricScript.py"
 	DatabricksDataScript     = "dataScript.py"
+
+	// The Databricks secret that job clusters expose the storage keys to jobs through.
+	StorageKeysSecretScope = "aqueduct"
+	StorageKeysSecretKey   = "storage-keys"
 )
@@ -5,10 +5,12 @@ import (
 	"fmt"
 
 	"github.com/aqueducthq/aqueduct/lib"
+	"github.com/aqueducthq/aqueduct/lib/storage"
 	databricks_sdk "github.com/databricks/databricks-sdk-go"
 	"github.com/databricks/databricks-sdk-go/service/clusters"
 	"github.com/databricks/databricks-sdk-go/service/jobs"
 	"github.com/databricks/databricks-sdk-go/service/libraries"
+	"github.com/databricks/databricks-sdk-go/service/secrets"
 	"github.com/dropbox/godropbox/errors"
 )
 
@@ -47,6 +49,7 @@ func CreateJob(
 	name string,
 	s3InstanceProfileArn string,
 	instancePoolID *string,
+	sparkEnvVars map[string]string,
 	tasks []jobs.JobTaskSettings,
 ) (int64, error) {
 	sparkVersions, err := databricksClient.Clusters.SparkVersions(ctx)
@@ -71,6 +74,7 @@ func CreateJob(
 		AwsAttributes: &clusters.AwsAttributes{
 			InstanceProfileArn: s3InstanceProfileArn,
 		},
+		SparkEnvVars: sparkEnvVars,
 	}
 	if instancePoolID != nil {
 		jobCluster.InstancePoolId = *instancePoolID
@@ -95,6 +99,53 @@ func CreateJob(
 	return createResp.JobId, nil
 }
 
+// PutStorageKeys writes the JSON serialized storage keys to the Databricks secret that job clusters
+// expose to jobs through their environment (see `StorageKeysSparkEnvVars`). Unlike the parameters
+// of a job, the secret is not visible to anyone who can read the job.
+func PutStorageKeys(
+	ctx context.Context,
+	databricksClient *databricks_sdk.WorkspaceClient,
+	storageKeys string,
+) error {
+	scopes, err := databricksClient.Secrets.ListScopesAll(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Unable to list secret scopes in Databricks.")
+	}
+
+	scopeExists := false
+	for _, scope := range scopes {
+		if scope.Name == StorageKeysSecretScope {
+			scopeExists = true
+			break
+		}
+	}
+
+	if !scopeExists {
+		err := databricksClient.Secrets.CreateScope(ctx, secrets.CreateScope{Scope: StorageKeysSecretScope})
+		if err != nil {
+			return errors.Wrap(err, "Unable to create secret scope in Databricks.")
+		}
+	}
+
+	err = databricksClient.Secrets.PutSecret(ctx, secrets.PutSecret{
+		Scope:       StorageKeysSecretScope,
+		Key:         StorageKeysSecretKey,
+		StringValue: storageKeys,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Unable to write the storage keys to Databricks secrets.")
+	}
+	return nil
+}
+
+// StorageKeysSparkEnvVars returns the environment variables of a job cluster that expose the
+// storage keys written by `PutStorageKeys` to its jobs.
+func StorageKeysSparkEnvVars() map[string]string {
+	return map[string]string{
+		storage.KeysEnvVar: fmt.Sprintf("{{secrets/%s/%s}}", StorageKeysSecretScope, StorageKeysSecretKey),
+	}
+}
+
 func CreateTask(
 	ctx context.Context,
 	databricksClient *databricks_sdk.WorkspaceClient,
@@ -167,6 +218,20 @@ func GetRun(
 	return getRunResp, nil
 }
 
+func CancelRun(
+	ctx context.Context,
+	databricksClient *databricks_sdk.WorkspaceClient,
+	runID int64,
+) error {
+	err := databricksClient.Jobs.CancelRun(ctx, jobs.CancelRun{
+		RunId: runID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Unable to cancel run in databricks.")
+	}
+	return nil
+}
+
 func GetTaskRunIDs(
 	ctx context.Context,
 	databricksClient *databricks_sdk.WorkspaceClient,
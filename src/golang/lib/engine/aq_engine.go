@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"path"
-	"reflect"
 	"time"
 
 	"github.com/aqueducthq/aqueduct/config"
@@ -19,6 +18,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/param"
 	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage"
 	"github.com/aqueducthq/aqueduct/lib/vault"
 	dag_utils "github.com/aqueducthq/aqueduct/lib/workflow/dag"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator"
@@ -46,6 +46,8 @@ type AqueductTimeConfig struct {
 type Repos struct {
 	ArtifactRepo             repos.Artifact
 	ArtifactResultRepo       repos.ArtifactResult
+	BackfillRepo             repos.Backfill
+	ContentBlobRepo          repos.ContentBlob
 	DAGRepo                  repos.DAG
 	DAGEdgeRepo              repos.DAGEdge
 	DAGResultRepo            repos.DAGResult
@@ -65,6 +67,14 @@ type aqEngine struct {
 	CronjobManager cronjob.CronjobManager
 	AqPath         string
 
+	// If set, workflow schedules are deployed as cron jobs of this job manager instead of
+	// in process by CronjobManager, so that they are triggered even while the server is down.
+	ScheduleJobManager job.JobManager
+
+	// The server-wide default for how many operators of a workflow run can execute at once.
+	// It applies to workflows that do not set their own limit, and is 0 if there is no limit.
+	MaxConcurrentOperators int
+
 	// Only used for previews.
 	PreviewCacheManager preview_cache.CacheManager
 
@@ -99,18 +109,22 @@ func NewAqEngine(
 	previewCacheManager preview_cache.CacheManager,
 	aqPath string,
 	displayIP string,
+	maxConcurrentOperators int,
+	scheduleJobManager job.JobManager,
 	repos *Repos,
 ) (*aqEngine, error) {
 	cronjobManager := cronjob.NewProcessCronjobManager()
 
 	return &aqEngine{
-		DisplayIP:           displayIP,
-		Database:            database,
-		GithubManager:       githubManager,
-		PreviewCacheManager: previewCacheManager,
-		CronjobManager:      cronjobManager,
-		AqPath:              aqPath,
-		Repos:               repos,
+		DisplayIP:              displayIP,
+		Database:               database,
+		GithubManager:          githubManager,
+		PreviewCacheManager:    previewCacheManager,
+		CronjobManager:         cronjobManager,
+		ScheduleJobManager:     scheduleJobManager,
+		AqPath:                 aqPath,
+		MaxConcurrentOperators: maxConcurrentOperators,
+		Repos:                  repos,
 	}, nil
 }
 
@@ -119,27 +133,9 @@ func (eng *aqEngine) ScheduleWorkflow(
 	ctx context.Context,
 	workflowId uuid.UUID,
 	name string,
-	period string,
+	schedule *shared.Schedule,
 ) error {
-	jobSpec := job.NewWorkflowSpec(
-		name,
-		workflowId.String(),
-		eng.Database.Config(),
-		&job.ProcessConfig{
-			BinaryDir:          path.Join(eng.AqPath, job.BinaryDir),
-			OperatorStorageDir: path.Join(eng.AqPath, job.OperatorStorageDir),
-		},
-		eng.GithubManager.Config(),
-		eng.AqPath,
-		eng.DisplayIP,
-		nil,
-	)
-	err := eng.CronjobManager.DeployCronJob(
-		ctx,
-		name,
-		period,
-		eng.generateCronFunction(name, jobSpec),
-	)
+	err := eng.deployWorkflowSchedule(ctx, workflowId, name, schedule)
 	if err != nil {
 		return errors.Wrap(err, "Unable to schedule workflow.")
 	}
@@ -151,7 +147,7 @@ func (eng *aqEngine) ExecuteWorkflow(
 	workflowID uuid.UUID,
 	timeConfig *AqueductTimeConfig,
 	parameters map[string]param.Param,
-) (_ shared.ExecutionStatus, err error) {
+) (shared.ExecutionStatus, error) {
 	dbDAG, err := workflow_utils.ReadLatestDAGFromDatabase(
 		ctx,
 		workflowID,
@@ -166,36 +162,104 @@ func (eng *aqEngine) ExecuteWorkflow(
 		return shared.FailedExecutionStatus, errors.Wrap(err, "Error reading latest workflowDag.")
 	}
 
-	pendingAt := time.Now()
-	execState := &shared.ExecutionState{
-		Status: shared.PendingExecutionStatus,
-		Timestamps: &shared.ExecutionTimestamps{
-			PendingAt: &pendingAt,
-		},
+	// Depending on the workflow's concurrency policy, previous runs that are still in progress
+	// may keep this run from being executed right away, or at all.
+	dagResult, err := eng.startRun(ctx, dbDAG, timeConfig, nil /* source */, nil /* backfillRun */)
+	if err != nil {
+		return shared.FailedExecutionStatus, err
+	}
+	if dagResult == nil {
+		return shared.SkippedExecutionStatus, nil
 	}
 
-	dagResult, err := eng.DAGResultRepo.Create(
+	return eng.executeWorkflow(ctx, dbDAG, dagResult, timeConfig, parameters, nil /* source */)
+}
+
+func (eng *aqEngine) ExecuteBackfillRun(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	backfillRun *BackfillRun,
+	timeConfig *AqueductTimeConfig,
+) (shared.ExecutionStatus, error) {
+	dbDAG, err := workflow_utils.ReadLatestDAGFromDatabase(
 		ctx,
-		dbDAG.ID,
-		execState,
+		workflowID,
+		eng.WorkflowRepo,
+		eng.DAGRepo,
+		eng.OperatorRepo,
+		eng.ArtifactRepo,
+		eng.DAGEdgeRepo,
 		eng.Database,
 	)
 	if err != nil {
-		return shared.FailedExecutionStatus, errors.Wrap(err, "Error initializing workflowDagResult.")
+		return shared.FailedExecutionStatus, errors.Wrap(err, "Error reading latest workflowDag.")
+	}
+
+	parameters := map[string]param.Param{
+		param.ExecutionTimeParamName: param.NewExecutionTimeParam(backfillRun.ExecutionTime),
+	}
+	dagResult, err := eng.startRun(ctx, dbDAG, timeConfig, nil /* source */, backfillRun)
+	if err != nil {
+		return shared.FailedExecutionStatus, err
+	}
+	if dagResult == nil {
+		return shared.SkippedExecutionStatus, nil
+	}
+
+	return eng.executeWorkflow(ctx, dbDAG, dagResult, timeConfig, parameters, nil /* source */)
+}
+
+func (eng *aqEngine) ExecuteResumedWorkflowRun(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	sourceDAGResultID uuid.UUID,
+	timeConfig *AqueductTimeConfig,
+) (shared.ExecutionStatus, error) {
+	source, err := eng.readResumeSource(ctx, workflowID, sourceDAGResultID)
+	if err != nil {
+		return shared.FailedExecutionStatus, errors.Wrap(err, "Unable to read the workflow run to resume from.")
 	}
 
-	// Any errors after this point should be persisted to the WorkflowDagResult created above.
+	dagResult, err := eng.startRun(ctx, source.dag, timeConfig, source, nil /* backfillRun */)
+	if err != nil {
+		return shared.FailedExecutionStatus, err
+	}
+	if dagResult == nil {
+		return shared.SkippedExecutionStatus, nil
+	}
+
+	return eng.executeWorkflow(ctx, source.dag, dagResult, timeConfig, nil /* parameters */, source)
+}
+
+// executeWorkflow executes `dbDAG` as the run recorded by `dagResult`.
+// If `source` is set, the run resumes from the source run instead of using the latest
+// version of the workflow, and the parameters are the ones used by the source run.
+func (eng *aqEngine) executeWorkflow(
+	ctx context.Context,
+	dbDAG *models.DAG,
+	dagResult *models.DAGResult,
+	timeConfig *AqueductTimeConfig,
+	parameters map[string]param.Param,
+	source *resumeSource,
+) (_ shared.ExecutionStatus, err error) {
+	execState := &dagResult.ExecState.ExecutionState
+
+	// Any errors after this point should be persisted to the pending WorkflowDagResult.
 	defer func() {
 		if err != nil {
-			// Mark the workflow dag result as failed
-			execState.Status = shared.FailedExecutionStatus
-
-			// Only set the workflow-level error if the error occurred outside the context
-			// of any single operator's execution. (eg. workflow timed out)
-			if !isOpFailureError(err) {
-				execState.Error = &shared.Error{
-					Context: err.Error(),
-					Tip:     "A workflow-level error occurred!",
+			if isRunCanceledError(err) {
+				execState.Status = shared.CanceledExecutionStatus
+			} else {
+				// Mark the workflow dag result as failed
+				execState.Status = shared.FailedExecutionStatus
+
+				// Only set the workflow-level error if the error occurred outside the context
+				// of any single operator's execution. (eg. workflow timed out)
+				if !isOpFailureError(err) {
+					execState.Error = &shared.Error{
+						Context: err.Error(),
+						Tip:     "A workflow-level error occurred!",
+					}
 				}
 			}
 
@@ -218,24 +282,39 @@ func (eng *aqEngine) ExecuteWorkflow(
 		}
 	}()
 
-	githubClient, err := eng.GithubManager.GetClient(ctx, dbDAG.Metadata.UserID)
-	if err != nil {
-		return shared.FailedExecutionStatus, errors.Wrap(err, "Error getting github client.")
-	}
+	if source == nil {
+		githubClient, err := eng.GithubManager.GetClient(ctx, dbDAG.Metadata.UserID)
+		if err != nil {
+			return shared.FailedExecutionStatus, errors.Wrap(err, "Error getting github client.")
+		}
 
-	dbDAG, err = workflow_utils.UpdateWorkflowDagToLatest(
-		ctx,
-		githubClient,
-		dbDAG,
-		eng.WorkflowRepo,
-		eng.DAGRepo,
-		eng.OperatorRepo,
-		eng.DAGEdgeRepo,
-		eng.ArtifactRepo,
-		eng.Database,
-	)
-	if err != nil {
-		return shared.FailedExecutionStatus, errors.Wrap(err, "Error updating workflowDag to latest.")
+		dbDAG, err = workflow_utils.UpdateWorkflowDagToLatest(
+			ctx,
+			githubClient,
+			dbDAG,
+			eng.WorkflowRepo,
+			eng.DAGRepo,
+			eng.OperatorRepo,
+			eng.DAGEdgeRepo,
+			eng.ArtifactRepo,
+			eng.Database,
+		)
+		if err != nil {
+			return shared.FailedExecutionStatus, errors.Wrap(err, "Error updating workflowDag to latest.")
+		}
+
+		// The run records the DAG that it executes, so that it can be resumed against that DAG.
+		if dbDAG.ID != dagResult.DagID {
+			_, err = eng.DAGResultRepo.Update(
+				ctx,
+				dagResult.ID,
+				map[string]interface{}{models.DAGResultDagID: dbDAG.ID},
+				eng.Database,
+			)
+			if err != nil {
+				return shared.FailedExecutionStatus, errors.Wrap(err, "Error recording the workflowDag of the workflow run.")
+			}
+		}
 	}
 
 	// Overwrite the parameter specs for all custom parameters defined by the user.
@@ -275,7 +354,7 @@ func (eng *aqEngine) ExecuteWorkflow(
 	}
 
 	storageConfig := config.Storage()
-	vaultObject, err := vault.NewVault(&storageConfig, config.EncryptionKey())
+	vaultObject, err := vault.NewVault(config.Vault(), &storageConfig, config.EncryptionKeyring())
 	if err != nil {
 		return shared.FailedExecutionStatus, errors.Wrap(err, "Unable to initialize vault.")
 	}
@@ -302,6 +381,7 @@ func (eng *aqEngine) ExecuteWorkflow(
 		eng.OperatorResultRepo,
 		eng.ArtifactRepo,
 		eng.ArtifactResultRepo,
+		eng.ContentBlobRepo,
 		vaultObject,
 		nil, /* artifactCacheManager */
 		execEnvsByOpId,
@@ -335,6 +415,13 @@ func (eng *aqEngine) ExecuteWorkflow(
 		return shared.FailedExecutionStatus, errors.Wrap(err, "Unable to initialize dag results.")
 	}
 
+	if source != nil {
+		err = reuseSourceResults(ctx, dag, source)
+		if err != nil {
+			return shared.FailedExecutionStatus, errors.Wrap(err, "Unable to reuse the results of the resumed workflow run.")
+		}
+	}
+
 	execState.Status = shared.RunningExecutionStatus
 	runningAt := time.Now()
 	execState.Timestamps.RunningAt = &runningAt
@@ -351,6 +438,10 @@ func (eng *aqEngine) ExecuteWorkflow(
 		jobManager,
 	)
 	if err != nil {
+		if isRunCanceledError(err) {
+			return shared.CanceledExecutionStatus, err
+		}
+
 		execState.Status = shared.FailedExecutionStatus
 		now := time.Now()
 		execState.Timestamps.FinishedAt = &now
@@ -371,7 +462,7 @@ func (eng *aqEngine) PreviewWorkflow(
 	timeConfig *AqueductTimeConfig,
 ) (*WorkflowPreviewResult, error) {
 	storageConfig := config.Storage()
-	vaultObject, err := vault.NewVault(&storageConfig, config.EncryptionKey())
+	vaultObject, err := vault.NewVault(config.Vault(), &storageConfig, config.EncryptionKeyring())
 	if err != nil {
 		return nil, errors.Wrap(err, "Unable to initialize vault.")
 	}
@@ -400,6 +491,7 @@ func (eng *aqEngine) PreviewWorkflow(
 		eng.OperatorResultRepo,
 		eng.ArtifactRepo,
 		eng.ArtifactResultRepo,
+		eng.ContentBlobRepo,
 		vaultObject,
 		previewCacheManager,
 		execEnvByOperatorId,
@@ -613,6 +705,12 @@ func (eng *aqEngine) DeleteWorkflow(
 		return errors.Wrap(err, "Unexpected error occurred while deleting workflow dag results.")
 	}
 
+	// Backfills can only be deleted after the DAG results that reference them.
+	err = eng.BackfillRepo.DeleteByWorkflow(ctx, workflowID, txn)
+	if err != nil {
+		return errors.Wrap(err, "Unexpected error occurred while deleting workflow backfills.")
+	}
+
 	err = eng.DAGEdgeRepo.DeleteByDAGBatch(ctx, dagIDs, txn)
 	if err != nil {
 		return errors.Wrap(err, "Unexpected error occurred while deleting workflow dag edges.")
@@ -661,37 +759,48 @@ func (eng *aqEngine) DeleteWorkflow(
 		}
 	}
 
+	// All of the dags of a workflow store their content in the workflow's storage layer, since
+	// the storage layer is migrated for all of them at once.
+	storageConfig := dagsToDelete[0].StorageConfig
+	for _, workflowDag := range dagsToDelete {
+		if !storage.SameLocation(&workflowDag.StorageConfig, &storageConfig) {
+			return errors.New("Workflow Dags have mismatching storage config.")
+		}
+	}
+
+	contentPaths := make([]string, 0, len(artifactResultsToDelete))
+	for _, art := range artifactResultsToDelete {
+		contentPaths = append(contentPaths, art.ContentPath)
+	}
+
+	// Content that is stored by its hash can be shared with other workflows, so it is only
+	// deleted once nothing references it anymore.
+	contentPaths, releasedPaths, err := workflow_utils.ReleaseContent(ctx, contentPaths, eng.ContentBlobRepo, txn)
+	if err != nil {
+		return errors.Wrap(err, "Unexpected error occurred while releasing artifact content.")
+	}
+
 	if err := txn.Commit(ctx); err != nil {
 		return errors.Wrap(err, "Failed to delete workflow.")
 	}
 
+	workflow_utils.DeleteReleasedContent(ctx, &storageConfig, releasedPaths, eng.ContentBlobRepo, eng.Database)
+
 	// Delete storage files (artifact content and function files)
-	storagePaths := make([]string, 0, len(operatorIDs)+len(artifactResultIDs))
+	storagePaths := make([]string, 0, len(operatorIDs)+len(contentPaths))
 	for _, op := range operatorsToDelete {
 		if op.Spec.HasFunction() {
 			storagePaths = append(storagePaths, op.Spec.Function().StoragePath)
 		}
 	}
-
-	for _, art := range artifactResultsToDelete {
-		storagePaths = append(storagePaths, art.ContentPath)
-	}
-
-	// Note: for now we assume all workflow dags have the same storage config.
-	// This assumption will stay true until we allow users to configure custom storage config to store stuff.
-	storageConfig := dagsToDelete[0].StorageConfig
-	for _, workflowDag := range dagsToDelete {
-		if !reflect.DeepEqual(workflowDag.StorageConfig, storageConfig) {
-			return errors.New("Workflow Dags have mismatching storage config.")
-		}
-	}
+	storagePaths = append(storagePaths, contentPaths...)
 
 	workflow_utils.CleanupStorageFiles(ctx, &storageConfig, storagePaths)
 
 	// Delete the cron job if it had one.
 	if workflowObj.Schedule.CronSchedule != "" {
 		cronjobName := shared_utils.AppendPrefix(workflowID.String())
-		err = eng.CronjobManager.DeleteCronJob(ctx, cronjobName)
+		err = eng.deleteWorkflowSchedule(ctx, cronjobName)
 		if err != nil {
 			return errors.Wrap(err, "Failed to delete workflow's cronjob.")
 		}
@@ -699,6 +808,55 @@ func (eng *aqEngine) DeleteWorkflow(
 	return nil
 }
 
+func (eng *aqEngine) CancelWorkflowRun(
+	ctx context.Context,
+	dagResultID uuid.UUID,
+) error {
+	dagResult, err := eng.DAGResultRepo.Get(ctx, dagResultID, eng.Database)
+	if err != nil {
+		return errors.Wrap(err, "Unable to retrieve workflow run.")
+	}
+
+	if dagResult.Status != shared.PendingExecutionStatus && dagResult.Status != shared.RunningExecutionStatus {
+		return ErrWorkflowRunNotInProgress
+	}
+
+	dbDAG, err := eng.DAGRepo.Get(ctx, dagResult.DagID, eng.Database)
+	if err != nil {
+		return errors.Wrap(err, "Unable to retrieve workflow dag.")
+	}
+
+	if dbDAG.EngineConfig.Type == shared.AirflowEngineType {
+		return errors.New("Canceling a workflow run is not supported for workflows running on Airflow.")
+	}
+
+	// The run is executed by a separate process, which checks for this status and then
+	// stops all of its in-progress operators.
+	execState := dagResult.ExecState.ExecutionState
+	execState.Status = shared.CanceledExecutionStatus
+	now := time.Now()
+	if execState.Timestamps == nil {
+		execState.Timestamps = &shared.ExecutionTimestamps{}
+	}
+	execState.Timestamps.FinishedAt = &now
+
+	_, err = eng.DAGResultRepo.Update(
+		ctx,
+		dagResultID,
+		map[string]interface{}{
+			models.DAGResultStatus:    shared.CanceledExecutionStatus,
+			models.DAGResultExecState: &execState,
+		},
+		eng.Database,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Unable to cancel workflow run.")
+	}
+
+	log.Infof("Requested cancellation of workflow run %s.", dagResultID)
+	return nil
+}
+
 func (eng *aqEngine) EditWorkflow(
 	ctx context.Context,
 	txn database.Database,
@@ -708,6 +866,7 @@ func (eng *aqEngine) EditWorkflow(
 	schedule *shared.Schedule,
 	retentionPolicy *shared.RetentionPolicy,
 	notificationSettings *shared.NotificationSettings,
+	maxConcurrentOperators *int,
 ) error {
 	changes := map[string]interface{}{}
 	if workflowName != "" {
@@ -726,6 +885,10 @@ func (eng *aqEngine) EditWorkflow(
 		changes[models.WorkflowNotificationSettings] = notificationSettings
 	}
 
+	if maxConcurrentOperators != nil {
+		changes[models.WorkflowMaxConcurrentOperators] = *maxConcurrentOperators
+	}
+
 	if schedule.Trigger != "" {
 		cronjobName := shared_utils.AppendPrefix(workflowID.String())
 		err := eng.updateWorkflowSchedule(ctx, workflowID, cronjobName, schedule)
@@ -767,7 +930,7 @@ func (eng *aqEngine) TriggerWorkflow(
 	}
 
 	storageConfig := config.Storage()
-	vaultObject, err := vault.NewVault(&storageConfig, config.EncryptionKey())
+	vaultObject, err := vault.NewVault(config.Vault(), &storageConfig, config.EncryptionKeyring())
 	if err != nil {
 		return shared.FailedExecutionStatus, errors.Wrap(err, "Unable to initialize vault.")
 	}
@@ -783,16 +946,51 @@ func (eng *aqEngine) TriggerWorkflow(
 		return shared.SucceededExecutionStatus, nil
 	}
 
-	jobManager, err := job.NewProcessJobManager(
-		&job.ProcessConfig{
-			BinaryDir:          path.Join(eng.AqPath, job.BinaryDir),
-			OperatorStorageDir: path.Join(eng.AqPath, job.OperatorStorageDir),
-		},
-	)
+	jobSpec := eng.newWorkflowSpec(name, workflowID, parameters)
+
+	return eng.launchWorkflowJob(name, jobSpec)
+}
+
+func (eng *aqEngine) ResumeWorkflowRun(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	sourceDAGResultID uuid.UUID,
+	name string,
+	timeConfig *AqueductTimeConfig,
+) (shared.ExecutionStatus, error) {
+	// The source run is validated here as well, so that the caller learns about
+	// invalid requests before the executor is launched.
+	_, _, err := eng.validateResumeSource(ctx, workflowID, sourceDAGResultID)
 	if err != nil {
-		log.Errorf("Unable to create JobManager: %v", err)
+		return shared.FailedExecutionStatus, err
 	}
 
+	jobSpec := eng.newWorkflowSpec(name, workflowID, nil /* parameters */)
+	jobSpec.SourceDagResultId = sourceDAGResultID.String()
+
+	return eng.launchWorkflowJob(name, jobSpec)
+}
+
+func (eng *aqEngine) TriggerBackfillRun(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	name string,
+	backfillRun *BackfillRun,
+	timeConfig *AqueductTimeConfig,
+) (shared.ExecutionStatus, error) {
+	jobSpec := eng.newWorkflowSpec(name, workflowID, nil /* parameters */)
+	jobSpec.BackfillId = backfillRun.BackfillID.String()
+	jobSpec.ExecutionTime = &backfillRun.ExecutionTime
+
+	return eng.launchWorkflowJob(name, jobSpec)
+}
+
+// newWorkflowSpec returns the spec of an executor job that runs the given workflow.
+func (eng *aqEngine) newWorkflowSpec(
+	name string,
+	workflowID uuid.UUID,
+	parameters map[string]param.Param,
+) *job.WorkflowSpec {
 	jobSpec := job.NewWorkflowSpec(
 		name,
 		workflowID.String(),
@@ -806,6 +1004,21 @@ func (eng *aqEngine) TriggerWorkflow(
 		eng.DisplayIP,
 		parameters,
 	)
+	jobSpec.MaxConcurrentOperators = eng.MaxConcurrentOperators
+	return jobSpec
+}
+
+// launchWorkflowJob launches the executor binary for the given workflow job spec.
+func (eng *aqEngine) launchWorkflowJob(name string, jobSpec *job.WorkflowSpec) (shared.ExecutionStatus, error) {
+	jobManager, err := job.NewProcessJobManager(
+		&job.ProcessConfig{
+			BinaryDir:          path.Join(eng.AqPath, job.BinaryDir),
+			OperatorStorageDir: path.Join(eng.AqPath, job.OperatorStorageDir),
+		},
+	)
+	if err != nil {
+		log.Errorf("Unable to create JobManager: %v", err)
+	}
 
 	jobName := fmt.Sprintf("%s-%d", name, time.Now().Unix())
 	err = jobManager.Launch(context.Background(), jobName, jobSpec)
@@ -852,6 +1065,7 @@ func (eng *aqEngine) executeWithEngine(
 			databricksJobManager,
 			vaultObject,
 			eng.IntegrationRepo,
+			eng.DAGResultRepo,
 			eng.Database,
 		)
 	default:
@@ -948,15 +1162,57 @@ func (eng *aqEngine) execute(
 		)
 	}()
 
+	// Operators that are waiting to be retried are not launched again until their backoff has elapsed.
+	opToRetryAt := make(map[uuid.UUID]time.Time)
+
+	// Operators that depend on a skipped operator are skipped as well.
+	opsWithSkippedParent := make(map[uuid.UUID]bool)
+
+	// Operators that are ready to run are launched in order, as long as there are fewer than
+	// `maxConcurrentOperators` running. The workflow's own limit takes precedence over the server's.
+	readyOps := newOperatorQueue()
+	maxConcurrentOperators := dag.MaxConcurrentOperators()
+	if maxConcurrentOperators <= 0 {
+		maxConcurrentOperators = eng.MaxConcurrentOperators
+	}
+
+	// Operators whose job managers push completion events don't need to be polled on an interval.
+	// The watches are stopped once execution finishes.
+	watchCtx, stopWatches := context.WithCancel(ctx)
+	defer stopWatches()
+	completions := newCompletionWatcher(watchCtx)
+
 	start := time.Now()
+	lastCancelCheck := start
 
 	for len(inProgressOps) > 0 {
 		if time.Since(start) > timeConfig.ExecTimeout {
 			return errors.Newf("Reached timeout %s waiting for workflow to complete.", timeConfig.ExecTimeout)
 		}
 
+		if opExecMode == operator.Publish && time.Since(lastCancelCheck) > CancelCheckInterval {
+			lastCancelCheck = time.Now()
+			if isRunCanceled(ctx, dag.ResultID(), eng.DAGResultRepo, eng.Database) {
+				log.Infof("Workflow run %s was canceled, stopping execution.", dag.ResultID())
+				err = cancelRemainingOperators(ctx, dag, inProgressOps, completedOps, opExecMode)
+				if err != nil {
+					return err
+				}
+
+				notificationContent = &notificationContentStruct{
+					level:            shared.WarningNotificationLevel,
+					systemErrContext: "The workflow run was canceled.",
+				}
+				return ErrWorkflowRunCanceled
+			}
+		}
+
+		// Whether any operator completed during this pass over the in-progress operators.
+		progressed := false
+
 		for _, op := range inProgressOps {
-			if op.Dynamic() && !op.GetDynamicProperties().Prepared() {
+			// Operators whose results were reused from a previous run do not need a cluster.
+			if op.Dynamic() && !op.GetDynamicProperties().Prepared() && !op.ExecState().Terminated() {
 				err = dynamic.PrepareCluster(
 					ctx,
 					&shared.DynamicK8sConfig{}, // empty configDelta map
@@ -978,13 +1234,27 @@ func (eng *aqEngine) execute(
 			}
 
 			if execState.Status == shared.PendingExecutionStatus {
-				err = op.Launch(ctx)
-				if err != nil {
-					return errors.Wrapf(err, "Unable to schedule operator %s.", op.Name())
+				if retryAt, ok := opToRetryAt[op.ID()]; ok {
+					if time.Now().Before(retryAt) {
+						continue
+					}
+					delete(opToRetryAt, op.ID())
 				}
+
+				readyOps.push(op)
 				continue
 			} else if execState.Status == shared.RunningExecutionStatus {
-				continue
+				deadline, ok := operatorDeadline(op)
+				if !ok || time.Now().Before(deadline) {
+					continue
+				}
+
+				log.Infof("Operator %s exceeded its timeout of %s, stopping it.", op.Name(), op.Timeout())
+				err = op.TimeOut(ctx)
+				if err != nil {
+					return errors.Wrapf(err, "Unable to time out operator %s.", op.Name())
+				}
+				execState = op.ExecState()
 			}
 
 			if !execState.Terminated() {
@@ -992,6 +1262,26 @@ func (eng *aqEngine) execute(
 			}
 
 			// From here on we can assume that the operator has terminated.
+			progressed = true
+			completions.forget(op)
+
+			if shouldRetryOperator(op, execState) {
+				delay := op.RetryPolicy().Delay(execState.NumAttempts())
+				log.Infof(
+					"Operator %s failed on attempt %d, retrying in %s.",
+					op.Name(),
+					execState.NumAttempts(),
+					delay,
+				)
+
+				err = op.Retry(ctx)
+				if err != nil {
+					return errors.Wrapf(err, "Unable to retry operator %s.", op.Name())
+				}
+				opToRetryAt[op.ID()] = time.Now().Add(delay)
+				continue
+			}
+
 			if opExecMode == operator.Publish {
 				err = op.PersistResult(ctx)
 				if err != nil {
@@ -999,8 +1289,8 @@ func (eng *aqEngine) execute(
 				}
 			}
 
-			// We can continue orchestration on non-fatal errors; currently, this only allows through succeeded operators
-			// and check operators with warning severity.
+			// We can continue orchestration on non-fatal errors; currently, this only allows through succeeded
+			// and skipped operators, and check operators with warning severity.
 			if execState.HasBlockingFailure() {
 				log.Infof("Stopping execution of operator %v", op.ID())
 				for id, dagOp := range workflowDag.Operators() {
@@ -1009,7 +1299,8 @@ func (eng *aqEngine) execute(
 					if _, ok := completedOps[id]; ok {
 						continue
 					}
-					if _, ok := inProgressOps[id]; ok {
+					// Operators that are still waiting to be launched will not be anymore.
+					if _, ok := inProgressOps[id]; ok && dagOp.ExecState().Status != shared.PendingExecutionStatus {
 						continue
 					}
 
@@ -1073,6 +1364,10 @@ func (eng *aqEngine) execute(
 				}
 
 				for _, nextOp := range nextOps {
+					if execState.Status == shared.SkippedExecutionStatus {
+						opsWithSkippedParent[nextOp.ID()] = true
+					}
+
 					// Decrement the active dependency count for every downstream operator.
 					// Once this count reaches zero, we can schedule the next operator.
 					opToDependencyCount[nextOp.ID()] -= 1
@@ -1085,13 +1380,54 @@ func (eng *aqEngine) execute(
 						// Defensive check: do not reschedule an already in-progress operator. This shouldn't actually
 						// matter because we only keep and update a single copy an on operator.
 						if _, ok := inProgressOps[nextOp.ID()]; !ok {
+							// A skipped operator is completed on the next pass, which skips its downstream operators in turn.
+							skip, err := shouldSkipOperator(ctx, dag, nextOp, opsWithSkippedParent)
+							if err != nil {
+								return err
+							}
+							if skip {
+								log.Infof("Skipping operator %s.", nextOp.Name())
+								nextOp.Skip()
+							}
+
 							inProgressOps[nextOp.ID()] = nextOp
 						}
 					}
 				}
 			}
+		}
+
+		numRunning := 0
+		for _, op := range inProgressOps {
+			if op.ExecState().Status == shared.RunningExecutionStatus {
+				numRunning += 1
+			}
+		}
+
+		for readyOps.len() > 0 && (maxConcurrentOperators <= 0 || numRunning < maxConcurrentOperators) {
+			op := readyOps.pop()
+			err = op.Launch(ctx)
+			if err != nil {
+				return errors.Wrapf(err, "Unable to schedule operator %s.", op.Name())
+			}
+			completions.watch(op)
+			numRunning += 1
+		}
+
+		// The remaining ready operators wait until a running operator completes.
+		for _, op := range readyOps.ops {
+			op.Queue(ctx)
+		}
 
-			time.Sleep(timeConfig.OperatorPollInterval)
+		// Newly scheduled operators can be launched right away. Otherwise, wait until an operator
+		// completes, falling back to polling if some running operator cannot push its completion.
+		if !progressed {
+			completions.wait(
+				inProgressOps,
+				opToRetryAt,
+				timeConfig.OperatorPollInterval,
+				CancelCheckInterval,
+			)
 		}
 	}
 
@@ -1148,14 +1484,14 @@ func (eng *aqEngine) updateWorkflowSchedule(
 	// How we update the workflow schedule depends on whether a cron job already exists.
 	// A manually triggered workflow does not have a cron job. If we're editing it to have a periodic
 	// schedule, we'll need to create a new cron job.
-	if !eng.CronjobManager.CronJobExists(ctx, cronjobName) {
+	if !eng.workflowScheduleExists(ctx, cronjobName) {
 		if newSchedule.CronSchedule != "" {
 
 			err := eng.ScheduleWorkflow(
 				ctx,
 				workflowId,
 				cronjobName,
-				string(newSchedule.CronSchedule),
+				newSchedule,
 			)
 			if err != nil {
 				return errors.Wrap(err, "Unable to deploy new cron job.")
@@ -1168,33 +1504,7 @@ func (eng *aqEngine) updateWorkflowSchedule(
 		// database by the changes map above, and `prepare` guarantees us that
 		// if `Paused` is true, then the workflow type is `Periodic`, which in
 		// turn means a schedule must be set.
-		newCronSchedule := string(newSchedule.CronSchedule)
-		if newSchedule.Paused {
-			// The `EditCronJob` helper automatically pauses a workflow when
-			// you set the cron job schedule to an empty string.
-			newCronSchedule = ""
-		}
-		// TODO ENG-1444: Remove jobSpec once executor is removed.
-		jobSpec := job.NewWorkflowSpec(
-			cronjobName,
-			workflowId.String(),
-			eng.Database.Config(),
-			&job.ProcessConfig{
-				BinaryDir:          path.Join(eng.AqPath, job.BinaryDir),
-				OperatorStorageDir: path.Join(eng.AqPath, job.OperatorStorageDir),
-			},
-			eng.GithubManager.Config(),
-			eng.AqPath,
-			eng.DisplayIP,
-			nil,
-		)
-
-		err := eng.CronjobManager.EditCronJob(
-			ctx,
-			cronjobName,
-			newCronSchedule,
-			eng.generateCronFunction(cronjobName, jobSpec),
-		)
+		err := eng.editWorkflowSchedule(ctx, workflowId, cronjobName, newSchedule)
 		if err != nil {
 			return errors.Wrap(err, "Unable to change workflow schedule.")
 		}
@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator"
+	"github.com/google/uuid"
+)
+
+type completionEvent struct {
+	opID uuid.UUID
+	// Identifies the watch that produced this event, since an operator that is retried is watched again.
+	watchID int
+}
+
+// completionWatcher collects the completion events of operators whose job managers can push them,
+// so that the engine can react to an operator completing without waiting for the next poll.
+type completionWatcher struct {
+	ctx    context.Context
+	events chan completionEvent
+
+	// The current watch of every operator that is being watched.
+	watched     map[uuid.UUID]int
+	nextWatchID int
+}
+
+func newCompletionWatcher(ctx context.Context) *completionWatcher {
+	return &completionWatcher{
+		ctx:     ctx,
+		events:  make(chan completionEvent),
+		watched: map[uuid.UUID]int{},
+	}
+}
+
+// watch starts watching the launched operator for completion, if its job manager supports it.
+// Otherwise, the operator needs to be polled.
+func (w *completionWatcher) watch(op operator.Operator) {
+	done := op.Completion(w.ctx)
+	if done == nil {
+		return
+	}
+
+	w.nextWatchID++
+	event := completionEvent{opID: op.ID(), watchID: w.nextWatchID}
+	w.watched[op.ID()] = event.watchID
+
+	go func() {
+		select {
+		case <-done:
+			select {
+			case w.events <- event:
+			case <-w.ctx.Done():
+			}
+		case <-w.ctx.Done():
+		}
+	}()
+}
+
+// forget stops tracking the operator, which has terminated.
+func (w *completionWatcher) forget(op operator.Operator) {
+	delete(w.watched, op.ID())
+}
+
+// wait blocks until a watched operator completes, or until the in-progress operators need to be
+// checked on again. That is after `pollInterval` if a running operator is not being watched,
+// once the earliest retry is due or the earliest operator timeout is exceeded, and after `maxWait` otherwise.
+func (w *completionWatcher) wait(
+	inProgressOps map[uuid.UUID]operator.Operator,
+	opToRetryAt map[uuid.UUID]time.Time,
+	pollInterval time.Duration,
+	maxWait time.Duration,
+) {
+	timeout := maxWait
+	for opID, op := range inProgressOps {
+		if retryAt, ok := opToRetryAt[opID]; ok {
+			if untilRetry := time.Until(retryAt); untilRetry < timeout {
+				timeout = untilRetry
+			}
+			continue
+		}
+
+		// Operators that are queued for a slot can only be launched once a running operator completes.
+		if op.ExecState().Status == shared.PendingExecutionStatus {
+			continue
+		}
+
+		if _, ok := w.watched[opID]; !ok && pollInterval < timeout {
+			timeout = pollInterval
+		}
+
+		if deadline, ok := operatorDeadline(op); ok {
+			if untilDeadline := time.Until(deadline); untilDeadline < timeout {
+				timeout = untilDeadline
+			}
+		}
+	}
+
+	if timeout <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case event := <-w.events:
+		// The operator is polled on the next pass, so it does not need to be watched anymore.
+		if w.watched[event.opID] == event.watchID {
+			delete(w.watched, event.opID)
+		}
+	case <-timer.C:
+	case <-w.ctx.Done():
+	}
+}
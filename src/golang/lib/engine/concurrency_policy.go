@@ -0,0 +1,285 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// How often a queued run checks whether the previous runs of its workflow have finished.
+var queuedRunPollInterval = 5 * time.Second
+
+// createDAGResult creates a DAGResult with execState for a new run of the DAG with dagID.
+func (eng *aqEngine) createDAGResult(
+	ctx context.Context,
+	dagID uuid.UUID,
+	execState *shared.ExecutionState,
+	DB database.Database,
+) (*models.DAGResult, error) {
+	dagResult, err := eng.DAGResultRepo.Create(
+		ctx,
+		dagID,
+		execState,
+		DB,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error initializing workflowDagResult.")
+	}
+	return dagResult, nil
+}
+
+// startRun applies the workflow's concurrency policy to a new run of the DAG, and creates
+// the DAGResult of the run. If `source` or `backfillRun` is set, the DAGResult records
+// the run that it resumes from or the backfill that it is part of.
+// It returns a nil DAGResult if the run should not be executed, in which case the
+// DAGResult already records why.
+func (eng *aqEngine) startRun(
+	ctx context.Context,
+	dbDAG *models.DAG,
+	timeConfig *AqueductTimeConfig,
+	source *resumeSource,
+	backfillRun *BackfillRun,
+) (_ *models.DAGResult, err error) {
+	policy := dbDAG.Metadata.Schedule.ConcurrencyPolicy
+	if policy == "" {
+		policy = shared.AllowConcurrencyPolicy
+	}
+
+	// The runs in progress are retrieved in the same transaction that creates the new run, while the
+	// workflow is locked, so that concurrent runs of the workflow never miss each other.
+	txn, err := eng.Database.BeginTx(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to start the workflow run.")
+	}
+	defer database.TxnRollbackIgnoreErr(ctx, txn)
+
+	var previousRuns []models.DAGResult
+	if policy != shared.AllowConcurrencyPolicy {
+		if err := eng.WorkflowRepo.Lock(ctx, dbDAG.WorkflowID, txn); err != nil {
+			return nil, errors.Wrap(err, "Unable to lock the workflow.")
+		}
+
+		previousRuns, err = eng.DAGResultRepo.GetInProgressByWorkflow(ctx, dbDAG.WorkflowID, txn)
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to retrieve the workflow runs in progress.")
+		}
+	}
+
+	now := time.Now()
+	execState := &shared.ExecutionState{
+		Status: shared.PendingExecutionStatus,
+		Timestamps: &shared.ExecutionTimestamps{
+			PendingAt: &now,
+		},
+	}
+
+	if len(previousRuns) > 0 {
+		switch policy {
+		case shared.SkipIfRunningConcurrencyPolicy:
+			execState.Status = shared.SkippedExecutionStatus
+			execState.Reason = shared.PreviousRunInProgressExecutionReason
+			execState.Error = &shared.Error{
+				Tip: fmt.Sprintf(
+					"This run was skipped, since run %s of the workflow was still in progress.",
+					previousRuns[0].ID,
+				),
+			}
+			execState.Timestamps.FinishedAt = &now
+		case shared.QueueConcurrencyPolicy:
+			execState.Reason = shared.QueuedExecutionReason
+		case shared.CancelPreviousConcurrencyPolicy:
+		default:
+			return nil, errors.Newf("Unsupported concurrency policy %s.", policy)
+		}
+	}
+
+	dagResult, err := eng.createDAGResult(ctx, dbDAG.ID, execState, txn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := txn.Commit(ctx); err != nil {
+		return nil, errors.Wrap(err, "Unable to start the workflow run.")
+	}
+
+	// The run is never executed if it could not be started.
+	defer func() {
+		if err != nil {
+			execState := dagResult.ExecState.ExecutionState
+			execState.Reason = ""
+			execState.UpdateWithFailure(shared.SystemFailure, &shared.Error{
+				Context: err.Error(),
+				Tip:     "Unable to start the workflow run.",
+			})
+			if _, updateErr := eng.updateDAGResultExecState(ctx, dagResult.ID, &execState); updateErr != nil {
+				log.Errorf("Unable to update DAGResult %s: %v", dagResult.ID, updateErr)
+			}
+		}
+	}()
+
+	lineageChanges := map[string]interface{}{}
+	if source != nil {
+		lineageChanges[models.DAGResultSourceDAGResultID] = source.dagResult.ID
+	}
+	if backfillRun != nil {
+		lineageChanges[models.DAGResultBackfillID] = backfillRun.BackfillID
+		lineageChanges[models.DAGResultExecutionTime] = backfillRun.ExecutionTime
+	}
+
+	if len(lineageChanges) > 0 {
+		dagResult, err = eng.DAGResultRepo.Update(ctx, dagResult.ID, lineageChanges, eng.Database)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error recording the origin of the workflow run.")
+		}
+	}
+
+	if len(previousRuns) == 0 {
+		return dagResult, nil
+	}
+
+	switch policy {
+	case shared.SkipIfRunningConcurrencyPolicy:
+		log.Infof("Skipped workflow run %s, since run %s is still in progress.", dagResult.ID, previousRuns[0].ID)
+		return nil, nil
+
+	case shared.CancelPreviousConcurrencyPolicy:
+		canceledRuns := make([]uuid.UUID, 0, len(previousRuns))
+		for _, previousRun := range previousRuns {
+			log.Infof("Canceling workflow run %s in favor of run %s.", previousRun.ID, dagResult.ID)
+
+			err = eng.CancelWorkflowRun(ctx, previousRun.ID)
+			// The previous run may have finished in the meantime.
+			if err != nil && !errors.Is(err, ErrWorkflowRunNotInProgress) {
+				return nil, err
+			}
+			canceledRuns = append(canceledRuns, previousRun.ID)
+		}
+
+		// The canceled runs stop their operators asynchronously, so the new run only starts once they have.
+		return eng.waitForPreviousRuns(ctx, dbDAG.WorkflowID, dagResult, canceledRuns, timeConfig)
+
+	default:
+		return eng.waitForPreviousRuns(ctx, dbDAG.WorkflowID, dagResult, nil /* canceledRuns */, timeConfig)
+	}
+}
+
+// waitForPreviousRuns blocks until all runs of the workflow that were created before the
+// DAGResult have finished, and until the runs in canceledRuns have stopped all of their operators.
+// It returns a nil DAGResult if the run was canceled in the meantime.
+func (eng *aqEngine) waitForPreviousRuns(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	dagResult *models.DAGResult,
+	canceledRuns []uuid.UUID,
+	timeConfig *AqueductTimeConfig,
+) (*models.DAGResult, error) {
+	log.Infof("Queueing workflow run %s until the previous runs of the workflow have finished.", dagResult.ID)
+
+	start := time.Now()
+	for {
+		if isRunCanceled(ctx, dagResult.ID, eng.DAGResultRepo, eng.Database) {
+			log.Infof("Queued workflow run %s was canceled.", dagResult.ID)
+			return nil, nil
+		}
+
+		previousRuns, err := eng.getPreviousRunsInProgress(ctx, workflowID, dagResult)
+		if err != nil {
+			return nil, err
+		}
+
+		stopped, err := eng.runsStopped(ctx, canceledRuns)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(previousRuns) == 0 && stopped {
+			break
+		}
+
+		if time.Since(start) > timeConfig.ExecTimeout {
+			return nil, errors.Newf(
+				"Reached timeout %s waiting for the previous runs of the workflow to complete.",
+				timeConfig.ExecTimeout,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(queuedRunPollInterval):
+		}
+	}
+
+	execState := dagResult.ExecState.ExecutionState
+	execState.Reason = ""
+	return eng.updateDAGResultExecState(ctx, dagResult.ID, &execState)
+}
+
+// getPreviousRunsInProgress returns the runs of the workflow that were created before
+// the given DAGResult and are still in progress.
+func (eng *aqEngine) getPreviousRunsInProgress(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	dagResult *models.DAGResult,
+) ([]models.DAGResult, error) {
+	inProgressRuns, err := eng.DAGResultRepo.GetInProgressByWorkflow(ctx, workflowID, eng.Database)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to retrieve the workflow runs in progress.")
+	}
+
+	previousRuns := make([]models.DAGResult, 0, len(inProgressRuns))
+	for _, run := range inProgressRuns {
+		// Runs that were created at the same time are ordered by ID, so that exactly one of them goes first.
+		if run.CreatedAt.Before(dagResult.CreatedAt) ||
+			(run.CreatedAt.Equal(dagResult.CreatedAt) && run.ID.String() < dagResult.ID.String()) {
+			previousRuns = append(previousRuns, run)
+		}
+	}
+	return previousRuns, nil
+}
+
+// runsStopped returns whether none of the operators of the runs with dagResultIDs are in progress anymore.
+func (eng *aqEngine) runsStopped(ctx context.Context, dagResultIDs []uuid.UUID) (bool, error) {
+	if len(dagResultIDs) == 0 {
+		return true, nil
+	}
+
+	operatorResults, err := eng.OperatorResultRepo.GetByDAGResultBatch(ctx, dagResultIDs, eng.Database)
+	if err != nil {
+		return false, errors.Wrap(err, "Unable to retrieve the operator results of the canceled runs.")
+	}
+
+	for _, operatorResult := range operatorResults {
+		if !operatorResult.ExecState.IsNull && !operatorResult.ExecState.Terminated() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (eng *aqEngine) updateDAGResultExecState(
+	ctx context.Context,
+	dagResultID uuid.UUID,
+	execState *shared.ExecutionState,
+) (*models.DAGResult, error) {
+	dagResult, err := eng.DAGResultRepo.Update(
+		ctx,
+		dagResultID,
+		map[string]interface{}{
+			models.DAGResultStatus:    execState.Status,
+			models.DAGResultExecState: execState,
+		},
+		eng.Database,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to update workflow run.")
+	}
+	return dagResult, nil
+}
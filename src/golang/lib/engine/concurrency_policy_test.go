@@ -0,0 +1,308 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/utils"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDAGResultRepo keeps the DAGResults of a single workflow in memory.
+type fakeDAGResultRepo struct {
+	repos.DAGResult
+
+	dagResults []*models.DAGResult
+	// If set, onCreate is called after a DAGResult is created.
+	onCreate func()
+	// The number of times the runs in progress were retrieved.
+	numInProgressQueries int
+}
+
+func (r *fakeDAGResultRepo) Create(
+	ctx context.Context,
+	dagID uuid.UUID,
+	execState *shared.ExecutionState,
+	DB database.Database,
+) (*models.DAGResult, error) {
+	dagResult := &models.DAGResult{
+		ID:        uuid.New(),
+		DagID:     dagID,
+		Status:    execState.Status,
+		CreatedAt: *execState.Timestamps.PendingAt,
+		ExecState: shared.NullExecutionState{ExecutionState: *execState},
+	}
+	r.dagResults = append(r.dagResults, dagResult)
+
+	if r.onCreate != nil {
+		r.onCreate()
+	}
+
+	result := *dagResult
+	return &result, nil
+}
+
+func (r *fakeDAGResultRepo) Get(ctx context.Context, ID uuid.UUID, DB database.Database) (*models.DAGResult, error) {
+	for _, dagResult := range r.dagResults {
+		if dagResult.ID == ID {
+			result := *dagResult
+			return &result, nil
+		}
+	}
+	return nil, database.ErrNoRows()
+}
+
+func (r *fakeDAGResultRepo) Update(
+	ctx context.Context,
+	ID uuid.UUID,
+	changes map[string]interface{},
+	DB database.Database,
+) (*models.DAGResult, error) {
+	for _, dagResult := range r.dagResults {
+		if dagResult.ID != ID {
+			continue
+		}
+
+		for column, value := range changes {
+			switch column {
+			case models.DAGResultStatus:
+				dagResult.Status = value.(shared.ExecutionStatus)
+			case models.DAGResultExecState:
+				dagResult.ExecState.ExecutionState = *value.(*shared.ExecutionState)
+			case models.DAGResultSourceDAGResultID:
+				dagResult.SourceDAGResultID = utils.NullUUID{UUID: value.(uuid.UUID)}
+			case models.DAGResultBackfillID:
+				dagResult.BackfillID = utils.NullUUID{UUID: value.(uuid.UUID)}
+			case models.DAGResultExecutionTime:
+				dagResult.ExecutionTime = utils.NullTime{Time: value.(time.Time)}
+			}
+		}
+
+		result := *dagResult
+		return &result, nil
+	}
+	return nil, database.ErrNoRows()
+}
+
+func (r *fakeDAGResultRepo) GetInProgressByWorkflow(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	DB database.Database,
+) ([]models.DAGResult, error) {
+	r.numInProgressQueries++
+
+	inProgress := []models.DAGResult{}
+	for _, dagResult := range r.dagResults {
+		if dagResult.Status == shared.PendingExecutionStatus || dagResult.Status == shared.RunningExecutionStatus {
+			inProgress = append(inProgress, *dagResult)
+		}
+	}
+	return inProgress, nil
+}
+
+// fakeWorkflowRepo records which workflows were locked.
+type fakeWorkflowRepo struct {
+	repos.Workflow
+	numLocks int
+}
+
+func (r *fakeWorkflowRepo) Lock(ctx context.Context, ID uuid.UUID, DB database.Database) error {
+	r.numLocks++
+	return nil
+}
+
+type fakeDAGRepo struct {
+	repos.DAG
+	dag *models.DAG
+}
+
+func (r *fakeDAGRepo) Get(ctx context.Context, ID uuid.UUID, DB database.Database) (*models.DAG, error) {
+	return r.dag, nil
+}
+
+// fakeOperatorResultRepo reports the operator results of every run as running until stopped is set.
+type fakeOperatorResultRepo struct {
+	repos.OperatorResult
+	stopped  bool
+	onGetter func()
+}
+
+func (r *fakeOperatorResultRepo) GetByDAGResultBatch(
+	ctx context.Context,
+	dagResultIDs []uuid.UUID,
+	DB database.Database,
+) ([]models.OperatorResult, error) {
+	status := shared.RunningExecutionStatus
+	if r.stopped {
+		status = shared.CanceledExecutionStatus
+	}
+
+	operatorResults := make([]models.OperatorResult, 0, len(dagResultIDs))
+	for _, dagResultID := range dagResultIDs {
+		operatorResults = append(operatorResults, models.OperatorResult{
+			DAGResultID: dagResultID,
+			Status:      status,
+			ExecState:   shared.NullExecutionState{ExecutionState: shared.ExecutionState{Status: status}},
+		})
+	}
+
+	if r.onGetter != nil {
+		r.onGetter()
+	}
+	return operatorResults, nil
+}
+
+func newConcurrencyPolicyTest(
+	policy shared.ConcurrencyPolicy,
+	previousStatus shared.ExecutionStatus,
+) (*aqEngine, *fakeDAGResultRepo, *models.DAG) {
+	dbDAG := &models.DAG{
+		ID:         uuid.New(),
+		WorkflowID: uuid.New(),
+		Metadata: &models.Workflow{
+			Schedule: shared.Schedule{ConcurrencyPolicy: policy},
+		},
+	}
+
+	createdAt := time.Now().Add(-time.Minute)
+	dagResultRepo := &fakeDAGResultRepo{
+		dagResults: []*models.DAGResult{
+			{
+				ID:        uuid.New(),
+				DagID:     dbDAG.ID,
+				Status:    previousStatus,
+				CreatedAt: createdAt,
+				ExecState: shared.NullExecutionState{ExecutionState: shared.ExecutionState{
+					Status:     previousStatus,
+					Timestamps: &shared.ExecutionTimestamps{PendingAt: &createdAt},
+				}},
+			},
+		},
+	}
+
+	eng := &aqEngine{
+		Database: database.NewNoopDatabase(),
+		Repos: &Repos{
+			DAGRepo:            &fakeDAGRepo{dag: dbDAG},
+			DAGResultRepo:      dagResultRepo,
+			OperatorResultRepo: &fakeOperatorResultRepo{},
+			WorkflowRepo:       &fakeWorkflowRepo{},
+		},
+	}
+	return eng, dagResultRepo, dbDAG
+}
+
+func TestStartRunSkipIfRunning(t *testing.T) {
+	ctx := context.Background()
+	timeConfig := &AqueductTimeConfig{ExecTimeout: time.Minute}
+	backfillRun := &BackfillRun{BackfillID: uuid.New(), ExecutionTime: time.Now().Add(-time.Hour)}
+
+	eng, dagResultRepo, dbDAG := newConcurrencyPolicyTest(shared.SkipIfRunningConcurrencyPolicy, shared.RunningExecutionStatus)
+
+	dagResult, err := eng.startRun(ctx, dbDAG, timeConfig, nil /* source */, backfillRun)
+	require.Nil(t, err)
+	require.Nil(t, dagResult)
+
+	// The runs in progress are retrieved while the workflow is locked.
+	require.Equal(t, 1, eng.WorkflowRepo.(*fakeWorkflowRepo).numLocks)
+
+	// The skipped run is recorded as such right away, and is never pending.
+	require.Len(t, dagResultRepo.dagResults, 2)
+	skipped := dagResultRepo.dagResults[1]
+	require.Equal(t, shared.SkippedExecutionStatus, skipped.Status)
+	require.Equal(t, shared.SkippedExecutionStatus, skipped.ExecState.Status)
+	require.Equal(t, shared.PreviousRunInProgressExecutionReason, skipped.ExecState.Reason)
+	require.NotNil(t, skipped.ExecState.Timestamps.FinishedAt)
+	require.Contains(t, skipped.ExecState.Error.Tip, dagResultRepo.dagResults[0].ID.String())
+
+	// Skipped backfill runs are still recorded as part of the backfill.
+	require.Equal(t, backfillRun.BackfillID, skipped.BackfillID.UUID)
+	require.Equal(t, backfillRun.ExecutionTime, skipped.ExecutionTime.Time)
+
+	// The run starts once the previous run has finished.
+	dagResultRepo.dagResults[0].Status = shared.SucceededExecutionStatus
+	dagResult, err = eng.startRun(ctx, dbDAG, timeConfig, nil /* source */, nil /* backfillRun */)
+	require.Nil(t, err)
+	require.NotNil(t, dagResult)
+	require.Equal(t, shared.PendingExecutionStatus, dagResult.Status)
+	require.Equal(t, shared.ExecutionReason(""), dagResult.ExecState.Reason)
+}
+
+func TestStartRunQueue(t *testing.T) {
+	ctx := context.Background()
+	timeConfig := &AqueductTimeConfig{ExecTimeout: time.Minute}
+
+	eng, dagResultRepo, dbDAG := newConcurrencyPolicyTest(shared.QueueConcurrencyPolicy, shared.PendingExecutionStatus)
+
+	// The previous run finishes while the new run is queued.
+	var queuedReason shared.ExecutionReason
+	dagResultRepo.onCreate = func() {
+		queuedReason = dagResultRepo.dagResults[1].ExecState.Reason
+		dagResultRepo.dagResults[0].Status = shared.SucceededExecutionStatus
+	}
+
+	source := &resumeSource{dagResult: &models.DAGResult{ID: uuid.New()}}
+	dagResult, err := eng.startRun(ctx, dbDAG, timeConfig, source, nil /* backfillRun */)
+	require.Nil(t, err)
+	require.NotNil(t, dagResult)
+
+	require.Equal(t, shared.QueuedExecutionReason, queuedReason)
+	require.Equal(t, shared.PendingExecutionStatus, dagResult.Status)
+	require.Equal(t, shared.ExecutionReason(""), dagResult.ExecState.Reason)
+	require.Equal(t, source.dagResult.ID, dagResultRepo.dagResults[1].SourceDAGResultID.UUID)
+}
+
+func TestStartRunAllow(t *testing.T) {
+	ctx := context.Background()
+	timeConfig := &AqueductTimeConfig{ExecTimeout: time.Minute}
+
+	for _, policy := range []shared.ConcurrencyPolicy{"", shared.AllowConcurrencyPolicy} {
+		eng, dagResultRepo, dbDAG := newConcurrencyPolicyTest(policy, shared.RunningExecutionStatus)
+
+		dagResult, err := eng.startRun(ctx, dbDAG, timeConfig, nil /* source */, nil /* backfillRun */)
+		require.Nil(t, err)
+		require.NotNil(t, dagResult)
+		require.Equal(t, shared.PendingExecutionStatus, dagResult.Status)
+
+		// The runs in progress are not even retrieved.
+		require.Equal(t, 0, dagResultRepo.numInProgressQueries)
+		require.Equal(t, 0, eng.WorkflowRepo.(*fakeWorkflowRepo).numLocks)
+	}
+}
+
+func TestStartRunCancelPrevious(t *testing.T) {
+	ctx := context.Background()
+	timeConfig := &AqueductTimeConfig{ExecTimeout: time.Minute}
+
+	pollInterval := queuedRunPollInterval
+	queuedRunPollInterval = time.Millisecond
+	defer func() { queuedRunPollInterval = pollInterval }()
+
+	eng, dagResultRepo, dbDAG := newConcurrencyPolicyTest(shared.CancelPreviousConcurrencyPolicy, shared.RunningExecutionStatus)
+	dbDAG.EngineConfig.Type = shared.AqueductEngineType
+
+	// The previous run stops its operators a while after it was canceled.
+	operatorResultRepo := eng.OperatorResultRepo.(*fakeOperatorResultRepo)
+	numChecks := 0
+	operatorResultRepo.onGetter = func() {
+		require.Equal(t, shared.CanceledExecutionStatus, dagResultRepo.dagResults[0].Status)
+		numChecks++
+		if numChecks == 3 {
+			operatorResultRepo.stopped = true
+		}
+	}
+
+	dagResult, err := eng.startRun(ctx, dbDAG, timeConfig, nil /* source */, nil /* backfillRun */)
+	require.Nil(t, err)
+	require.NotNil(t, dagResult)
+	require.Equal(t, shared.PendingExecutionStatus, dagResult.Status)
+
+	// The new run only starts once the canceled run has stopped.
+	require.Equal(t, 4, numChecks)
+}
@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	dag_utils "github.com/aqueducthq/aqueduct/lib/workflow/dag"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator"
+	"github.com/google/uuid"
+)
+
+// shouldSkipOperator returns whether the operator, whose dependencies have all completed,
+// should be skipped instead of run. That is the case if any of the operators it depends on
+// was skipped, or if its condition is not satisfied.
+func shouldSkipOperator(
+	ctx context.Context,
+	dag dag_utils.WorkflowDag,
+	op operator.Operator,
+	opsWithSkippedParent map[uuid.UUID]bool,
+) (bool, error) {
+	if opsWithSkippedParent[op.ID()] {
+		return true, nil
+	}
+
+	condition := op.Condition()
+	if condition == nil {
+		return false, nil
+	}
+
+	conditionArtifact, ok := dag.Artifacts()[condition.ArtifactID]
+	if !ok {
+		return false, errors.Newf("Internal error: the condition artifact of operator %s is not in the DAG.", op.Name())
+	}
+
+	// The artifact may not have been computed if it is the output of a warning-level check that
+	// raised an error, in which case it cannot have the condition's value.
+	if !conditionArtifact.Computed(ctx) {
+		return true, nil
+	}
+
+	metadata, err := conditionArtifact.GetMetadata(ctx)
+	if err != nil {
+		return false, errors.Wrapf(err, "Unable to evaluate the condition of operator %s.", op.Name())
+	}
+
+	content, err := conditionArtifact.GetContent(ctx)
+	if err != nil {
+		return false, errors.Wrapf(err, "Unable to evaluate the condition of operator %s.", op.Name())
+	}
+
+	satisfied, err := condition.Satisfied(content, metadata.SerializationType)
+	if err != nil {
+		return false, errors.Wrapf(err, "Unable to evaluate the condition of operator %s.", op.Name())
+	}
+	return !satisfied, nil
+}
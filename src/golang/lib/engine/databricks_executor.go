@@ -14,6 +14,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator"
 	"github.com/databricks/databricks-sdk-go/service/jobs"
 	"github.com/dropbox/godropbox/errors"
+	log "github.com/sirupsen/logrus"
 )
 
 // We separate out the execution step for Databricks Jobs since
@@ -33,6 +34,7 @@ func ExecuteDatabricks(
 	databricksJobManager *job.DatabricksJobManager,
 	vaultObject vault.Vault,
 	integrationRepo repos.Integration,
+	dagResultRepo repos.DAGResult,
 	DB database.Database,
 ) (err error) {
 	inProgressOps := workflowRunMetadata.InProgressOps
@@ -81,6 +83,7 @@ func ExecuteDatabricks(
 	}()
 
 	start := time.Now()
+	lastCancelCheck := start
 	var operatorError error
 
 	for len(inProgressOps) > 0 {
@@ -88,11 +91,43 @@ func ExecuteDatabricks(
 			return errors.New("Reached timeout waiting for workflow to complete.")
 		}
 
+		if opExecMode == operator.Publish && time.Since(lastCancelCheck) > CancelCheckInterval {
+			lastCancelCheck = time.Now()
+			if isRunCanceled(ctx, dag.ResultID(), dagResultRepo, DB) {
+				// Canceling the multi-task job stops all of its tasks.
+				if jobErr := databricksJobManager.Cancel(ctx, workflowName); jobErr != nil {
+					return errors.Wrap(jobErr, "Unable to cancel workflow job on Databricks.")
+				}
+
+				err = cancelRemainingOperators(ctx, dag, inProgressOps, completedOps, opExecMode)
+				if err != nil {
+					return err
+				}
+
+				notificationContent = &notificationContentStruct{
+					level:            shared.WarningNotificationLevel,
+					systemErrContext: "The workflow run was canceled.",
+				}
+				return ErrWorkflowRunCanceled
+			}
+		}
+
 		for _, op := range inProgressOps {
 			// Poll on the individual operator
 			execState := PollDatabricksOperator(ctx, op, databricksJobManager)
 			if !execState.Terminated() {
-				continue
+				deadline, ok := operatorDeadline(op)
+				if !ok || time.Now().Before(deadline) {
+					continue
+				}
+
+				// This cancels the operator's task run, and Databricks skips the tasks that depend on it.
+				log.Infof("Operator %s exceeded its timeout of %s, stopping it.", op.Name(), op.Timeout())
+				err := op.TimeOut(ctx)
+				if err != nil {
+					return errors.Wrapf(err, "Unable to time out operator %s.", op.Name())
+				}
+				execState = op.ExecState()
 			}
 
 			// From here on we can assume that the operator has terminated.
@@ -213,7 +248,11 @@ func PollDatabricksOperator(
 			return op.ExecState()
 		}
 
-		// The job must exist at this point, but it hasn't completed (running).
+		// The job must exist at this point, but it hasn't completed. The operator's timeout starts
+		// once its task starts running.
+		if status == shared.RunningExecutionStatus && op.ExecState().Status != shared.RunningExecutionStatus {
+			op.UpdateExecState(&shared.ExecutionState{Status: shared.RunningExecutionStatus})
+		}
 		return op.ExecState()
 	}
 }
@@ -17,19 +17,32 @@ const (
 	DefaultExecutionTimeout     = 48 * time.Hour
 	DefaultCleanupTimeout       = 2 * time.Minute
 	DefaultPollIntervalMillisec = 300
+
+	// Configures how often a running workflow checks whether it has been canceled.
+	CancelCheckInterval = 2 * time.Second
 )
 
 var (
 	ErrOpExecSystemFailure       = errors.New("Operator execution failed due to system error.")
 	ErrOpExecBlockingUserFailure = errors.New("Operator execution failed due to user error.")
+	ErrWorkflowRunCanceled       = errors.New("Workflow run was canceled.")
+	ErrWorkflowRunNotInProgress  = errors.New("Workflow run is not in progress.")
+	ErrWorkflowRunNotResumable   = errors.New("Only failed or canceled workflow runs can be resumed.")
 )
 
+// BackfillRun identifies a workflow run that is triggered by a backfill.
+type BackfillRun struct {
+	BackfillID uuid.UUID
+	// The logical time that the run executes for.
+	ExecutionTime time.Time
+}
+
 type Engine interface {
 	ScheduleWorkflow(
 		ctx context.Context,
 		workflowId uuid.UUID,
 		name string,
-		period string,
+		schedule *shared.Schedule,
 	) error
 	ExecuteWorkflow(
 		ctx context.Context,
@@ -37,10 +50,32 @@ type Engine interface {
 		timeConfig *AqueductTimeConfig,
 		parameters map[string]param.Param,
 	) (shared.ExecutionStatus, error)
+	// ExecuteResumedWorkflowRun executes the DAG of a failed or canceled workflow run again,
+	// reusing the results of all operators that succeeded in that run.
+	ExecuteResumedWorkflowRun(
+		ctx context.Context,
+		workflowId uuid.UUID,
+		sourceDAGResultID uuid.UUID,
+		timeConfig *AqueductTimeConfig,
+	) (shared.ExecutionStatus, error)
+	// ExecuteBackfillRun executes the latest DAG of a workflow as part of a backfill.
+	// The run's execution time is passed to the workflow as the reserved execution time parameter.
+	ExecuteBackfillRun(
+		ctx context.Context,
+		workflowId uuid.UUID,
+		backfillRun *BackfillRun,
+		timeConfig *AqueductTimeConfig,
+	) (shared.ExecutionStatus, error)
 	DeleteWorkflow(
 		ctx context.Context,
 		workflowId uuid.UUID,
 	) error
+	// CancelWorkflowRun requests the cancellation of an in-progress workflow run.
+	// Returns ErrWorkflowRunNotInProgress if the run has already terminated.
+	CancelWorkflowRun(
+		ctx context.Context,
+		dagResultID uuid.UUID,
+	) error
 	EditWorkflow(
 		ctx context.Context,
 		txn database.Database,
@@ -50,6 +85,7 @@ type Engine interface {
 		schedule *shared.Schedule,
 		retentionPolicy *shared.RetentionPolicy,
 		notificationSettings *shared.NotificationSettings,
+		maxConcurrentOperators *int,
 	) error
 
 	// TODO ENG-1444: Used as a wrapper to trigger a workflow via executor binary.
@@ -61,6 +97,23 @@ type Engine interface {
 		timeConfig *AqueductTimeConfig,
 		parameters map[string]param.Param,
 	) (shared.ExecutionStatus, error)
+	// TriggerBackfillRun triggers a run of a workflow that is part of a backfill.
+	TriggerBackfillRun(
+		ctx context.Context,
+		workflowId uuid.UUID,
+		name string,
+		backfillRun *BackfillRun,
+		timeConfig *AqueductTimeConfig,
+	) (shared.ExecutionStatus, error)
+	// ResumeWorkflowRun triggers a new run of a workflow that resumes from the given
+	// failed or canceled run. Returns ErrWorkflowRunNotResumable if the run has another status.
+	ResumeWorkflowRun(
+		ctx context.Context,
+		workflowId uuid.UUID,
+		sourceDAGResultID uuid.UUID,
+		name string,
+		timeConfig *AqueductTimeConfig,
+	) (shared.ExecutionStatus, error)
 }
 
 // AqEngine should be implemented by aqEngine
@@ -68,6 +121,14 @@ type Engine interface {
 type AqEngine interface {
 	Engine
 
+	// InitializeWorkflowSchedules makes sure that each of the given workflows that has a
+	// schedule is triggered accordingly. It is called once when the server starts.
+	InitializeWorkflowSchedules(ctx context.Context, workflows []models.Workflow) error
+
+	// ScheduledWorkflowsInProgress returns the IDs of the workflows that have a scheduled run
+	// executing outside of the server process, which is not interrupted when the server restarts.
+	ScheduledWorkflowsInProgress(ctx context.Context) (map[uuid.UUID]bool, error)
+
 	PreviewWorkflow(
 		ctx context.Context,
 		dbDAG *models.DAG,
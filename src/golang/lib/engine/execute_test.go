@@ -0,0 +1,702 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	op_model "github.com/aqueducthq/aqueduct/lib/models/shared/operator"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/workflow/artifact"
+	dag_utils "github.com/aqueducthq/aqueduct/lib/workflow/dag"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOperator simulates an operator whose job runs for a fixed duration, or is run
+// by `jobManager` if it is set. Only the methods used by `execute` are implemented.
+type fakeOperator struct {
+	operator.Operator
+
+	id        uuid.UUID
+	signature uuid.UUID
+	duration  time.Duration
+	timeout   time.Duration
+	// Whether the operator's job manager can push completion events.
+	push bool
+	// If set, tracks how many operators are running at once.
+	concurrency *concurrencyTracker
+	condition   *op_model.Condition
+	retryPolicy *op_model.RetryPolicy
+	jobManager  *fakeJobManager
+
+	execState shared.ExecutionState
+	done      chan struct{}
+	// The output results that the operator reused from a previous run, if any.
+	reusedResults map[uuid.UUID]*models.ArtifactResult
+}
+
+func (op *fakeOperator) ID() uuid.UUID                           { return op.id }
+func (op *fakeOperator) Signature() uuid.UUID                    { return op.signature }
+func (op *fakeOperator) Name() string                            { return op.id.String() }
+func (op *fakeOperator) Dynamic() bool                           { return false }
+func (op *fakeOperator) RetryPolicy() *op_model.RetryPolicy      { return op.retryPolicy }
+func (op *fakeOperator) Condition() *op_model.Condition          { return op.condition }
+func (op *fakeOperator) ExecState() *shared.ExecutionState       { return &op.execState }
+func (op *fakeOperator) Timeout() time.Duration                  { return op.timeout }
+func (op *fakeOperator) Finish(ctx context.Context)              {}
+func (op *fakeOperator) PersistResult(ctx context.Context) error { return nil }
+
+func (op *fakeOperator) Queue(ctx context.Context) {
+	if op.concurrency != nil && op.execState.Reason != shared.QueuedExecutionReason {
+		op.concurrency.queued += 1
+	}
+	op.execState.Reason = shared.QueuedExecutionReason
+}
+
+func (op *fakeOperator) Cancel() {
+	op.execState.Status = shared.CanceledExecutionStatus
+}
+
+func (op *fakeOperator) Skip() {
+	op.execState.Status = shared.SkippedExecutionStatus
+}
+
+func (op *fakeOperator) Launch(ctx context.Context) error {
+	now := time.Now()
+	op.execState.Status = shared.RunningExecutionStatus
+	op.execState.Reason = ""
+	op.execState.Timestamps = &shared.ExecutionTimestamps{RunningAt: &now}
+	if op.concurrency != nil {
+		op.concurrency.start()
+	}
+	if op.jobManager != nil {
+		if err := op.jobManager.Launch(ctx, op.Name(), nil /* spec */); err != nil {
+			return err
+		}
+		return nil
+	}
+	op.done = make(chan struct{})
+	time.AfterFunc(op.duration, func() { close(op.done) })
+	return nil
+}
+
+func (op *fakeOperator) Poll(ctx context.Context) (*shared.ExecutionState, error) {
+	if op.jobManager != nil && op.execState.Status == shared.RunningExecutionStatus {
+		status, err := op.jobManager.Poll(ctx, op.Name())
+		if err != nil {
+			return nil, err
+		}
+		if status == shared.FailedExecutionStatus {
+			op.execState.UpdateWithFailure(op.jobManager.failureType, &shared.Error{Context: "The job failed."})
+		} else {
+			op.execState.Status = status
+		}
+		return &op.execState, nil
+	}
+
+	if op.execState.Status == shared.RunningExecutionStatus {
+		select {
+		case <-op.done:
+			op.execState.Status = shared.SucceededExecutionStatus
+			if op.concurrency != nil {
+				op.concurrency.running -= 1
+			}
+		default:
+		}
+	}
+	return &op.execState, nil
+}
+
+func (op *fakeOperator) Kill(ctx context.Context) error {
+	if op.jobManager != nil && op.execState.Status == shared.RunningExecutionStatus {
+		if err := op.jobManager.Cancel(ctx, op.Name()); err != nil {
+			return err
+		}
+	}
+	op.Cancel()
+	return nil
+}
+
+func (op *fakeOperator) Retry(ctx context.Context) error {
+	attempts := append(op.execState.Attempts, shared.ExecutionAttempt{
+		Attempt:     op.execState.NumAttempts(),
+		Status:      op.execState.Status,
+		FailureType: op.execState.FailureType,
+		Error:       op.execState.Error,
+		Timestamps:  op.execState.Timestamps,
+	})
+	op.execState = shared.ExecutionState{Status: shared.PendingExecutionStatus, Attempts: attempts}
+	return nil
+}
+
+func (op *fakeOperator) TimeOut(ctx context.Context) error {
+	op.execState.UpdateWithFailure(shared.UserFatalFailure, &shared.Error{Code: shared.TimeoutErrorCode})
+	return nil
+}
+
+func (op *fakeOperator) Reuse(
+	ctx context.Context,
+	execState *shared.ExecutionState,
+	outputResults map[uuid.UUID]*models.ArtifactResult,
+) error {
+	op.execState = *execState
+	op.reusedResults = outputResults
+	return nil
+}
+
+func (op *fakeOperator) Completion(ctx context.Context) <-chan struct{} {
+	if !op.push {
+		return nil
+	}
+	return op.done
+}
+
+// fakeJobManager runs jobs that fail with `failureType` on their first `numFailures` launches.
+// Afterwards, a job keeps running until it is canceled, unless `succeed` is set.
+type fakeJobManager struct {
+	job.JobManager
+
+	numFailures int
+	failureType shared.FailureType
+	succeed     bool
+
+	// The names of the jobs that were launched and canceled, in order.
+	launched []string
+	canceled []string
+}
+
+func (jm *fakeJobManager) Launch(ctx context.Context, name string, spec job.Spec) job.JobError {
+	jm.launched = append(jm.launched, name)
+	return nil
+}
+
+func (jm *fakeJobManager) Poll(ctx context.Context, name string) (shared.ExecutionStatus, job.JobError) {
+	for _, canceled := range jm.canceled {
+		if canceled == name {
+			return shared.CanceledExecutionStatus, nil
+		}
+	}
+
+	numLaunches := 0
+	for _, launched := range jm.launched {
+		if launched == name {
+			numLaunches += 1
+		}
+	}
+	if numLaunches <= jm.numFailures {
+		return shared.FailedExecutionStatus, nil
+	}
+	if jm.succeed {
+		return shared.SucceededExecutionStatus, nil
+	}
+	return shared.RunningExecutionStatus, nil
+}
+
+func (jm *fakeJobManager) Cancel(ctx context.Context, name string) job.JobError {
+	jm.canceled = append(jm.canceled, name)
+	return nil
+}
+
+// fakeIntegrationRepo has no integrations, so that no notifications are sent.
+type fakeIntegrationRepo struct {
+	repos.Integration
+}
+
+func (r *fakeIntegrationRepo) GetByServiceAndUser(
+	ctx context.Context,
+	service shared.Service,
+	userID uuid.UUID,
+	DB database.Database,
+) ([]models.Integration, error) {
+	return nil, nil
+}
+
+type concurrencyTracker struct {
+	running    int
+	maxRunning int
+	// The number of times that an operator was queued.
+	queued int
+}
+
+func (c *concurrencyTracker) start() {
+	c.running += 1
+	if c.running > c.maxRunning {
+		c.maxRunning = c.running
+	}
+}
+
+type fakeArtifact struct {
+	artifact.Artifact
+
+	id uuid.UUID
+	// The JSON serialized content of the artifact, if it is read by the engine.
+	content []byte
+}
+
+func (a *fakeArtifact) ID() uuid.UUID                                  { return a.id }
+func (a *fakeArtifact) Computed(ctx context.Context) bool              { return a.content != nil }
+func (a *fakeArtifact) GetContent(ctx context.Context) ([]byte, error) { return a.content, nil }
+
+func (a *fakeArtifact) GetMetadata(ctx context.Context) (*shared.ArtifactResultMetadata, error) {
+	return &shared.ArtifactResultMetadata{SerializationType: shared.JsonSerialization}, nil
+}
+
+// fakeDag is a DAG where every operator produces a single artifact.
+type fakeDag struct {
+	dag_utils.WorkflowDag
+
+	operators              map[uuid.UUID]operator.Operator
+	outputs                map[uuid.UUID]artifact.Artifact
+	consumers              map[uuid.UUID][]operator.Operator
+	maxConcurrentOperators int
+	// The ID of the DAGResult of the run, if the run is checked for cancellation.
+	resultID uuid.UUID
+}
+
+func (d *fakeDag) ResultID() uuid.UUID                        { return d.resultID }
+func (d *fakeDag) UserID() uuid.UUID                          { return uuid.Nil }
+func (d *fakeDag) MaxConcurrentOperators() int                { return d.maxConcurrentOperators }
+func (d *fakeDag) Operators() map[uuid.UUID]operator.Operator { return d.operators }
+
+func (d *fakeDag) NotificationSettings() shared.NotificationSettings {
+	return shared.NotificationSettings{}
+}
+
+func (d *fakeDag) Artifacts() map[uuid.UUID]artifact.Artifact {
+	artifacts := make(map[uuid.UUID]artifact.Artifact, len(d.outputs))
+	for _, output := range d.outputs {
+		artifacts[output.ID()] = output
+	}
+	return artifacts
+}
+
+func (d *fakeDag) OperatorOutputs(op operator.Operator) ([]artifact.Artifact, error) {
+	return []artifact.Artifact{d.outputs[op.ID()]}, nil
+}
+
+func (d *fakeDag) OperatorParents(op operator.Operator) ([]operator.Operator, error) {
+	parents := []operator.Operator{}
+	for parentID, output := range d.outputs {
+		for _, consumer := range d.consumers[output.ID()] {
+			if consumer.ID() == op.ID() {
+				parents = append(parents, d.operators[parentID])
+			}
+		}
+	}
+	return parents, nil
+}
+
+func (d *fakeDag) OperatorsOnArtifact(a artifact.Artifact) ([]operator.Operator, error) {
+	return d.consumers[a.ID()], nil
+}
+
+// newChainsDag returns a DAG of `width` independent chains of `depth` operators each,
+// along with the number of dependencies of every operator.
+func newChainsDag(width int, depth int, duration time.Duration, push bool) (*fakeDag, map[uuid.UUID]int) {
+	dag := &fakeDag{
+		operators: map[uuid.UUID]operator.Operator{},
+		outputs:   map[uuid.UUID]artifact.Artifact{},
+		consumers: map[uuid.UUID][]operator.Operator{},
+	}
+	opToDependencyCount := map[uuid.UUID]int{}
+
+	for i := 0; i < width; i++ {
+		var parent *fakeArtifact
+		for j := 0; j < depth; j++ {
+			op := &fakeOperator{
+				id:        uuid.New(),
+				duration:  duration,
+				push:      push,
+				execState: shared.ExecutionState{Status: shared.PendingExecutionStatus},
+			}
+			output := &fakeArtifact{id: uuid.New()}
+
+			dag.operators[op.id] = op
+			dag.outputs[op.id] = output
+			opToDependencyCount[op.id] = 0
+			if parent != nil {
+				dag.consumers[parent.id] = []operator.Operator{op}
+				opToDependencyCount[op.id] = 1
+			}
+			parent = output
+		}
+	}
+
+	return dag, opToDependencyCount
+}
+
+func executeChainsDag(b *testing.B, push bool) {
+	const (
+		width        = 10
+		depth        = 20
+		jobDuration  = time.Millisecond
+		pollInterval = 5 * time.Millisecond
+	)
+
+	eng := &aqEngine{Repos: &Repos{}}
+	timeConfig := &AqueductTimeConfig{
+		OperatorPollInterval: pollInterval,
+		ExecTimeout:          time.Minute,
+		CleanupTimeout:       time.Minute,
+	}
+
+	var overhead time.Duration
+	for i := 0; i < b.N; i++ {
+		dag, opToDependencyCount := newChainsDag(width, depth, jobDuration, push)
+		metadata := &WorkflowRunMetadata{
+			OpToDependencyCount: opToDependencyCount,
+			InProgressOps:       map[uuid.UUID]operator.Operator{},
+			CompletedOps:        map[uuid.UUID]operator.Operator{},
+		}
+
+		start := time.Now()
+		err := eng.execute(context.Background(), dag, metadata, timeConfig, nil /* vaultObject */, operator.Preview)
+		require.Nil(b, err)
+		require.Len(b, metadata.CompletedOps, width*depth)
+
+		// The chains run in parallel, so the remaining time is spent orchestrating.
+		overhead += time.Since(start) - depth*jobDuration
+	}
+
+	b.ReportMetric(float64(overhead.Milliseconds())/float64(b.N), "overhead-ms/op")
+}
+
+func TestExecuteOperatorTimeout(t *testing.T) {
+	for _, push := range []bool{true, false} {
+		dag, opToDependencyCount := newChainsDag(1 /* width */, 2 /* depth */, time.Hour, push)
+		for _, op := range dag.operators {
+			op.(*fakeOperator).timeout = 10 * time.Millisecond
+		}
+
+		metadata := &WorkflowRunMetadata{
+			OpToDependencyCount: opToDependencyCount,
+			InProgressOps:       map[uuid.UUID]operator.Operator{},
+			CompletedOps:        map[uuid.UUID]operator.Operator{},
+		}
+		timeConfig := &AqueductTimeConfig{
+			OperatorPollInterval: time.Second,
+			ExecTimeout:          time.Minute,
+			CleanupTimeout:       time.Minute,
+		}
+
+		eng := &aqEngine{Repos: &Repos{}}
+		err := eng.execute(context.Background(), dag, metadata, timeConfig, nil /* vaultObject */, operator.Preview)
+		require.ErrorIs(t, err, ErrOpExecBlockingUserFailure)
+
+		for _, op := range dag.operators {
+			execState := op.ExecState()
+			if opToDependencyCount[op.ID()] == 0 {
+				require.Equal(t, shared.FailedExecutionStatus, execState.Status)
+				require.True(t, execState.Error.IsTimeout())
+
+				// The engine should wake up once the timeout is exceeded, rather than after the poll interval.
+				ranFor := execState.Timestamps.FinishedAt.Sub(*execState.Timestamps.RunningAt)
+				require.Less(t, ranFor, timeConfig.OperatorPollInterval)
+			} else {
+				require.Equal(t, shared.CanceledExecutionStatus, execState.Status)
+			}
+		}
+	}
+}
+
+func TestExecuteCancel(t *testing.T) {
+	ctx := context.Background()
+	dag, opToDependencyCount := newChainsDag(2 /* width */, 2 /* depth */, time.Hour, false /* push */)
+
+	jobManager := &fakeJobManager{}
+	for _, op := range dag.operators {
+		op.(*fakeOperator).jobManager = jobManager
+	}
+
+	dagResultRepo := &fakeDAGResultRepo{}
+	createdAt := time.Now()
+	dagResult, err := dagResultRepo.Create(ctx, uuid.New(), &shared.ExecutionState{
+		Status:     shared.PendingExecutionStatus,
+		Timestamps: &shared.ExecutionTimestamps{PendingAt: &createdAt},
+	}, nil /* DB */)
+	require.Nil(t, err)
+	dag.resultID = dagResult.ID
+
+	// The run is canceled before it starts, but this is only noticed at the first cancellation
+	// check, by which time the operators without dependencies are running.
+	dagResultRepo.dagResults[0].Status = shared.CanceledExecutionStatus
+
+	metadata := &WorkflowRunMetadata{
+		OpToDependencyCount: opToDependencyCount,
+		InProgressOps:       map[uuid.UUID]operator.Operator{},
+		CompletedOps:        map[uuid.UUID]operator.Operator{},
+	}
+	timeConfig := &AqueductTimeConfig{
+		OperatorPollInterval: 10 * time.Millisecond,
+		ExecTimeout:          time.Minute,
+		CleanupTimeout:       time.Minute,
+	}
+
+	eng := &aqEngine{Repos: &Repos{DAGResultRepo: dagResultRepo, IntegrationRepo: &fakeIntegrationRepo{}}}
+	err = eng.execute(ctx, dag, metadata, timeConfig, nil /* vaultObject */, operator.Publish)
+	require.ErrorIs(t, err, ErrWorkflowRunCanceled)
+
+	for _, op := range dag.operators {
+		require.Equal(t, shared.CanceledExecutionStatus, op.ExecState().Status)
+	}
+	require.Empty(t, metadata.InProgressOps)
+	require.Empty(t, metadata.CompletedOps)
+
+	// Only the jobs of the operators that were running are canceled.
+	require.Len(t, jobManager.launched, 2)
+	require.ElementsMatch(t, jobManager.launched, jobManager.canceled)
+}
+
+func newRetryDag(policy *op_model.RetryPolicy, jobManager *fakeJobManager) (*fakeDag, *WorkflowRunMetadata) {
+	dag, opToDependencyCount := newChainsDag(1 /* width */, 1 /* depth */, time.Hour, false /* push */)
+	for _, op := range dag.operators {
+		op.(*fakeOperator).retryPolicy = policy
+		op.(*fakeOperator).jobManager = jobManager
+	}
+
+	metadata := &WorkflowRunMetadata{
+		OpToDependencyCount: opToDependencyCount,
+		InProgressOps:       map[uuid.UUID]operator.Operator{},
+		CompletedOps:        map[uuid.UUID]operator.Operator{},
+	}
+	return dag, metadata
+}
+
+func TestExecuteRetries(t *testing.T) {
+	for _, tc := range []struct {
+		name                  string
+		maxAttempts           int
+		retryableFailureTypes []shared.FailureType
+		numFailures           int
+		failureType           shared.FailureType
+		expectedStatus        shared.ExecutionStatus
+		expectedErr           error
+		expectedLaunches      int
+	}{
+		{
+			name:             "succeeds after retries",
+			maxAttempts:      3,
+			numFailures:      2,
+			failureType:      shared.SystemFailure,
+			expectedStatus:   shared.SucceededExecutionStatus,
+			expectedLaunches: 3,
+		},
+		{
+			name:             "runs out of attempts",
+			maxAttempts:      3,
+			numFailures:      5,
+			failureType:      shared.SystemFailure,
+			expectedStatus:   shared.FailedExecutionStatus,
+			expectedErr:      ErrOpExecSystemFailure,
+			expectedLaunches: 3,
+		},
+		{
+			name:             "user failures are not retried by default",
+			maxAttempts:      3,
+			numFailures:      1,
+			failureType:      shared.UserFatalFailure,
+			expectedStatus:   shared.FailedExecutionStatus,
+			expectedErr:      ErrOpExecBlockingUserFailure,
+			expectedLaunches: 1,
+		},
+		{
+			name:                  "retryable user failure",
+			maxAttempts:           3,
+			retryableFailureTypes: []shared.FailureType{shared.UserFatalFailure},
+			numFailures:           1,
+			failureType:           shared.UserFatalFailure,
+			expectedStatus:        shared.SucceededExecutionStatus,
+			expectedLaunches:      2,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			policy := &op_model.RetryPolicy{
+				MaxAttempts:           tc.maxAttempts,
+				Backoff:               op_model.ConstantBackoff,
+				RetryableFailureTypes: tc.retryableFailureTypes,
+			}
+			jobManager := &fakeJobManager{numFailures: tc.numFailures, failureType: tc.failureType, succeed: true}
+			dag, metadata := newRetryDag(policy, jobManager)
+
+			timeConfig := &AqueductTimeConfig{
+				OperatorPollInterval: 10 * time.Millisecond,
+				ExecTimeout:          time.Minute,
+				CleanupTimeout:       time.Minute,
+			}
+
+			eng := &aqEngine{Repos: &Repos{}}
+			err := eng.execute(context.Background(), dag, metadata, timeConfig, nil /* vaultObject */, operator.Preview)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				require.Nil(t, err)
+			}
+
+			require.Len(t, jobManager.launched, tc.expectedLaunches)
+			for _, op := range dag.operators {
+				execState := op.ExecState()
+				require.Equal(t, tc.expectedStatus, execState.Status)
+				require.Equal(t, tc.expectedLaunches, execState.NumAttempts())
+
+				// Every previous attempt is recorded as a failure.
+				for i, attempt := range execState.Attempts {
+					require.Equal(t, i+1, attempt.Attempt)
+					require.Equal(t, shared.FailedExecutionStatus, attempt.Status)
+					require.Equal(t, tc.failureType, *attempt.FailureType)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteRetryBackoff(t *testing.T) {
+	policy := &op_model.RetryPolicy{
+		MaxAttempts:    3,
+		Backoff:        op_model.LinearBackoff,
+		BackoffSeconds: 1,
+	}
+	jobManager := &fakeJobManager{numFailures: 2, failureType: shared.SystemFailure, succeed: true}
+	dag, metadata := newRetryDag(policy, jobManager)
+
+	timeConfig := &AqueductTimeConfig{
+		OperatorPollInterval: 10 * time.Millisecond,
+		ExecTimeout:          time.Minute,
+		CleanupTimeout:       time.Minute,
+	}
+
+	eng := &aqEngine{Repos: &Repos{}}
+	err := eng.execute(context.Background(), dag, metadata, timeConfig, nil /* vaultObject */, operator.Preview)
+	require.Nil(t, err)
+
+	for _, op := range dag.operators {
+		execState := op.ExecState()
+		require.Equal(t, shared.SucceededExecutionStatus, execState.Status)
+		require.Len(t, execState.Attempts, 2)
+
+		// Each attempt is launched once the backoff after the previous one has elapsed,
+		// which grows linearly with the number of attempts.
+		timestamps := []*shared.ExecutionTimestamps{
+			execState.Attempts[0].Timestamps,
+			execState.Attempts[1].Timestamps,
+			execState.Timestamps,
+		}
+		for i := 1; i < len(timestamps); i++ {
+			delay := policy.Delay(i)
+			require.Equal(t, time.Duration(i)*time.Second, delay)
+
+			waited := timestamps[i].RunningAt.Sub(*timestamps[i-1].FinishedAt)
+			require.GreaterOrEqual(t, waited, delay)
+			require.Less(t, waited, delay+500*time.Millisecond)
+		}
+	}
+}
+
+func TestExecuteMaxConcurrentOperators(t *testing.T) {
+	for _, tc := range []struct {
+		name                  string
+		workflowLimit         int
+		serverLimit           int
+		expectedMaxConcurrent int
+		expectQueued          bool
+	}{
+		{name: "Unlimited", expectedMaxConcurrent: 10},
+		{name: "Server", serverLimit: 3, expectedMaxConcurrent: 3, expectQueued: true},
+		{name: "Workflow", workflowLimit: 2, serverLimit: 3, expectedMaxConcurrent: 2, expectQueued: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dag, opToDependencyCount := newChainsDag(10 /* width */, 2 /* depth */, 5*time.Millisecond, true /* push */)
+			dag.maxConcurrentOperators = tc.workflowLimit
+
+			concurrency := &concurrencyTracker{}
+			for _, op := range dag.operators {
+				op.(*fakeOperator).concurrency = concurrency
+			}
+
+			metadata := &WorkflowRunMetadata{
+				OpToDependencyCount: opToDependencyCount,
+				InProgressOps:       map[uuid.UUID]operator.Operator{},
+				CompletedOps:        map[uuid.UUID]operator.Operator{},
+			}
+			timeConfig := &AqueductTimeConfig{
+				OperatorPollInterval: time.Millisecond,
+				ExecTimeout:          time.Minute,
+				CleanupTimeout:       time.Minute,
+			}
+
+			eng := &aqEngine{MaxConcurrentOperators: tc.serverLimit, Repos: &Repos{}}
+			err := eng.execute(context.Background(), dag, metadata, timeConfig, nil /* vaultObject */, operator.Preview)
+			require.Nil(t, err)
+			require.Len(t, metadata.CompletedOps, len(dag.operators))
+			require.Equal(t, tc.expectedMaxConcurrent, concurrency.maxRunning)
+			require.Equal(t, tc.expectQueued, concurrency.queued > 0)
+		})
+	}
+}
+
+func TestExecuteConditions(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		content       string
+		expectSkipped bool
+	}{
+		{name: "Satisfied", content: "true"},
+		{name: "NotSatisfied", content: "false", expectSkipped: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dag, opToDependencyCount := newChainsDag(2 /* width */, 3 /* depth */, time.Millisecond, true /* push */)
+
+			// The second operator of one chain only runs if the output of the first one has the value true.
+			var first *fakeOperator
+			for id, count := range opToDependencyCount {
+				if count == 0 {
+					first = dag.operators[id].(*fakeOperator)
+					break
+				}
+			}
+			firstOutput := dag.outputs[first.id].(*fakeArtifact)
+			firstOutput.content = []byte(tc.content)
+
+			second := dag.consumers[firstOutput.id][0].(*fakeOperator)
+			second.condition = &op_model.Condition{ArtifactID: firstOutput.id, Value: true}
+			third := dag.consumers[dag.outputs[second.id].ID()][0].(*fakeOperator)
+
+			metadata := &WorkflowRunMetadata{
+				OpToDependencyCount: opToDependencyCount,
+				InProgressOps:       map[uuid.UUID]operator.Operator{},
+				CompletedOps:        map[uuid.UUID]operator.Operator{},
+			}
+			timeConfig := &AqueductTimeConfig{
+				OperatorPollInterval: time.Millisecond,
+				ExecTimeout:          time.Minute,
+				CleanupTimeout:       time.Minute,
+			}
+
+			eng := &aqEngine{Repos: &Repos{}}
+			err := eng.execute(context.Background(), dag, metadata, timeConfig, nil /* vaultObject */, operator.Preview)
+			require.Nil(t, err)
+			require.Len(t, metadata.CompletedOps, len(dag.operators))
+
+			for _, op := range dag.operators {
+				expectedStatus := shared.SucceededExecutionStatus
+				if tc.expectSkipped && (op == second || op == third) {
+					// The operator and its downstream operators are skipped.
+					expectedStatus = shared.SkippedExecutionStatus
+				}
+				require.Equal(t, expectedStatus, op.ExecState().Status)
+			}
+		})
+	}
+}
+
+// BenchmarkExecute200Operators measures the orchestration overhead of a 200 operator DAG,
+// for job managers that push completion events and for those that need to be polled.
+func BenchmarkExecute200Operators(b *testing.B) {
+	b.Run("Push", func(b *testing.B) { executeChainsDag(b, true /* push */) })
+	b.Run("Poll", func(b *testing.B) { executeChainsDag(b, false /* push */) })
+}
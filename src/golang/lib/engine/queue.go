@@ -0,0 +1,40 @@
+package engine
+
+import (
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator"
+	"github.com/google/uuid"
+)
+
+// operatorQueue holds the operators of a workflow run that are ready to be launched, in the order
+// in which they became ready. Launching them in this order ensures that no operator waits
+// indefinitely when the number of operators that can execute at once is limited.
+type operatorQueue struct {
+	ops    []operator.Operator
+	queued map[uuid.UUID]bool
+}
+
+func newOperatorQueue() *operatorQueue {
+	return &operatorQueue{queued: map[uuid.UUID]bool{}}
+}
+
+// push adds the operator to the back of the queue, unless it is already queued.
+func (q *operatorQueue) push(op operator.Operator) {
+	if q.queued[op.ID()] {
+		return
+	}
+
+	q.ops = append(q.ops, op)
+	q.queued[op.ID()] = true
+}
+
+// pop removes and returns the operator at the front of the queue.
+func (q *operatorQueue) pop() operator.Operator {
+	op := q.ops[0]
+	q.ops = q.ops[1:]
+	delete(q.queued, op.ID())
+	return op
+}
+
+func (q *operatorQueue) len() int {
+	return len(q.ops)
+}
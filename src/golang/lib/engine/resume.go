@@ -0,0 +1,249 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	dag_utils "github.com/aqueducthq/aqueduct/lib/workflow/dag"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator"
+	workflow_utils "github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// resumeSource contains the results of a previous workflow run that a new run resumes from.
+type resumeSource struct {
+	dagResult *models.DAGResult
+	// The DAG that the source run executed. The resumed run executes the same DAG.
+	dag *models.DAG
+
+	// The results of the source run's operators, keyed by the operator's signature.
+	results map[uuid.UUID]*sourceOperatorResult
+}
+
+// sourceOperatorResult contains the results of an operator of the source run.
+type sourceOperatorResult struct {
+	operatorResult *models.OperatorResult
+	// The results of the operator's outputs, in the order of the operator's outputs.
+	// An output without a result has a nil entry.
+	artifactResults []*models.ArtifactResult
+}
+
+// validateResumeSource checks that the given DAG result can be resumed and
+// returns the DAG it executed.
+func (eng *aqEngine) validateResumeSource(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	sourceDAGResultID uuid.UUID,
+) (*models.DAGResult, *models.DAG, error) {
+	dagResult, err := eng.DAGResultRepo.Get(ctx, sourceDAGResultID, eng.Database)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to retrieve workflow run.")
+	}
+
+	if dagResult.Status != shared.FailedExecutionStatus && dagResult.Status != shared.CanceledExecutionStatus {
+		return nil, nil, ErrWorkflowRunNotResumable
+	}
+
+	dbDAG, err := workflow_utils.ReadDAGFromDatabase(
+		ctx,
+		dagResult.DagID,
+		eng.WorkflowRepo,
+		eng.DAGRepo,
+		eng.OperatorRepo,
+		eng.ArtifactRepo,
+		eng.DAGEdgeRepo,
+		eng.Database,
+	)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to read workflow dag.")
+	}
+
+	if dbDAG.WorkflowID != workflowID {
+		return nil, nil, errors.Newf("Workflow run %s does not belong to workflow %s.", sourceDAGResultID, workflowID)
+	}
+
+	// Airflow and Databricks run the whole DAG as a single job, so individual
+	// operators cannot be skipped.
+	if dbDAG.EngineConfig.Type == shared.AirflowEngineType || dbDAG.EngineConfig.Type == shared.DatabricksEngineType {
+		return nil, nil, errors.Newf("Resuming a workflow run is not supported for workflows running on %s.", dbDAG.EngineConfig.Type)
+	}
+
+	return dagResult, dbDAG, nil
+}
+
+func (eng *aqEngine) readResumeSource(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	sourceDAGResultID uuid.UUID,
+) (*resumeSource, error) {
+	dagResult, dbDAG, err := eng.validateResumeSource(ctx, workflowID, sourceDAGResultID)
+	if err != nil {
+		return nil, err
+	}
+
+	operatorResults, err := eng.OperatorResultRepo.GetByDAGResultBatch(
+		ctx,
+		[]uuid.UUID{sourceDAGResultID},
+		eng.Database,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to read operator results of the workflow run.")
+	}
+
+	artifactResults, err := eng.ArtifactResultRepo.GetByDAGResults(
+		ctx,
+		[]uuid.UUID{sourceDAGResultID},
+		eng.Database,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to read artifact results of the workflow run.")
+	}
+
+	return newResumeSource(dagResult, dbDAG, operatorResults, artifactResults)
+}
+
+// newResumeSource indexes the results of the source run by the signatures of its operators.
+func newResumeSource(
+	dagResult *models.DAGResult,
+	dbDAG *models.DAG,
+	operatorResults []models.OperatorResult,
+	artifactResults []models.ArtifactResult,
+) (*resumeSource, error) {
+	opIDToSignature, err := dag_utils.ComputeOperatorSignatures(dbDAG)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to compute operator signatures of the workflow run.")
+	}
+
+	operatorResultsByOpID := make(map[uuid.UUID]*models.OperatorResult, len(operatorResults))
+	for i := range operatorResults {
+		operatorResultsByOpID[operatorResults[i].OperatorID] = &operatorResults[i]
+	}
+
+	artifactResultsByArtifactID := make(map[uuid.UUID]*models.ArtifactResult, len(artifactResults))
+	for i := range artifactResults {
+		artifactResultsByArtifactID[artifactResults[i].ArtifactID] = &artifactResults[i]
+	}
+
+	source := &resumeSource{
+		dagResult: dagResult,
+		dag:       dbDAG,
+		results:   make(map[uuid.UUID]*sourceOperatorResult, len(operatorResults)),
+	}
+
+	for opID, dbOperator := range dbDAG.Operators {
+		operatorResult, ok := operatorResultsByOpID[opID]
+		if !ok {
+			continue
+		}
+
+		result := &sourceOperatorResult{
+			operatorResult:  operatorResult,
+			artifactResults: make([]*models.ArtifactResult, 0, len(dbOperator.Outputs)),
+		}
+		for _, artifactID := range dbOperator.Outputs {
+			result.artifactResults = append(result.artifactResults, artifactResultsByArtifactID[artifactID])
+		}
+		source.results[opIDToSignature[opID]] = result
+	}
+
+	return source, nil
+}
+
+// reuseSourceResults marks every operator that succeeded in the source run, along with
+// all of its upstream operators, as completed using the source run's results.
+// Operators are matched to the source run's operators by their signature, so that the results
+// of an operator are never reused for a different computation.
+// The remaining operators are left pending, so that they are executed as usual.
+func reuseSourceResults(
+	ctx context.Context,
+	dag dag_utils.WorkflowDag,
+	source *resumeSource,
+) error {
+	reusable := make(map[uuid.UUID]bool, len(dag.Operators()))
+
+	var isReusable func(op operator.Operator) (bool, error)
+	isReusable = func(op operator.Operator) (bool, error) {
+		if result, ok := reusable[op.ID()]; ok {
+			return result, nil
+		}
+
+		result, ok := source.results[op.Signature()]
+		if !ok || result.operatorResult.Status != shared.SucceededExecutionStatus || result.operatorResult.ExecState.IsNull {
+			reusable[op.ID()] = false
+			return false, nil
+		}
+
+		outputs, err := dag.OperatorOutputs(op)
+		if err != nil {
+			return false, err
+		}
+
+		if len(outputs) != len(result.artifactResults) {
+			reusable[op.ID()] = false
+			return false, nil
+		}
+
+		for _, artifactResult := range result.artifactResults {
+			if artifactResult == nil || artifactResult.Status != shared.SucceededExecutionStatus || artifactResult.Metadata.IsNull {
+				reusable[op.ID()] = false
+				return false, nil
+			}
+		}
+
+		parents, err := dag.OperatorParents(op)
+		if err != nil {
+			return false, err
+		}
+
+		for _, parent := range parents {
+			parentReusable, err := isReusable(parent)
+			if err != nil {
+				return false, err
+			}
+
+			if !parentReusable {
+				reusable[op.ID()] = false
+				return false, nil
+			}
+		}
+
+		reusable[op.ID()] = true
+		return true, nil
+	}
+
+	for _, op := range dag.Operators() {
+		ok, err := isReusable(op)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			continue
+		}
+
+		result := source.results[op.Signature()]
+
+		outputs, err := dag.OperatorOutputs(op)
+		if err != nil {
+			return err
+		}
+
+		outputResults := make(map[uuid.UUID]*models.ArtifactResult, len(outputs))
+		for i, output := range outputs {
+			outputResults[output.ID()] = result.artifactResults[i]
+		}
+
+		execState := result.operatorResult.ExecState.ExecutionState
+		err = op.Reuse(ctx, &execState, outputResults)
+		if err != nil {
+			return errors.Wrapf(err, "Unable to reuse the results of operator %s.", op.Name())
+		}
+
+		log.Infof("Reusing the results of operator %s from workflow run %s.", op.Name(), source.dagResult.ID)
+	}
+
+	return nil
+}
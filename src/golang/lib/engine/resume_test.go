@@ -0,0 +1,205 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/workflow/artifact"
+	dag_utils "github.com/aqueducthq/aqueduct/lib/workflow/dag"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestChainsDAG returns a DAG of independent chains of operators, where the operators
+// of each chain are given in order. Every operator produces the artifact in `outputs`.
+func newTestChainsDAG(chains [][]uuid.UUID, outputs map[uuid.UUID]uuid.UUID) *models.DAG {
+	dbDAG := &models.DAG{
+		ID:        uuid.New(),
+		Operators: map[uuid.UUID]models.Operator{},
+		Artifacts: map[uuid.UUID]models.Artifact{},
+	}
+
+	for _, chain := range chains {
+		inputs := []uuid.UUID{}
+		for _, opID := range chain {
+			dbDAG.Operators[opID] = models.Operator{
+				ID:      opID,
+				Inputs:  inputs,
+				Outputs: []uuid.UUID{outputs[opID]},
+			}
+			dbDAG.Artifacts[outputs[opID]] = models.Artifact{ID: outputs[opID]}
+			inputs = []uuid.UUID{outputs[opID]}
+		}
+	}
+	return dbDAG
+}
+
+// newResumedDag returns the DAG that a resumed run executes for `dbDAG`, with pending operators.
+func newResumedDag(t *testing.T, dbDAG *models.DAG) *fakeDag {
+	signatures, err := dag_utils.ComputeOperatorSignatures(dbDAG)
+	require.Nil(t, err)
+
+	dag := &fakeDag{
+		operators: map[uuid.UUID]operator.Operator{},
+		outputs:   map[uuid.UUID]artifact.Artifact{},
+		consumers: map[uuid.UUID][]operator.Operator{},
+	}
+	for opID, dbOperator := range dbDAG.Operators {
+		dag.operators[opID] = &fakeOperator{
+			id:        opID,
+			signature: signatures[opID],
+			execState: shared.ExecutionState{Status: shared.PendingExecutionStatus},
+		}
+		dag.outputs[opID] = &fakeArtifact{id: dbOperator.Outputs[0]}
+	}
+	for opID, dbOperator := range dbDAG.Operators {
+		for _, input := range dbOperator.Inputs {
+			dag.consumers[input] = append(dag.consumers[input], dag.operators[opID])
+		}
+	}
+	return dag
+}
+
+// newTestResumeSource returns the results of a run of `dbDAG`, in which each operator
+// ended with the given status.
+func newTestResumeSource(
+	t *testing.T,
+	dbDAG *models.DAG,
+	statuses map[uuid.UUID]shared.ExecutionStatus,
+) *resumeSource {
+	dagResult := &models.DAGResult{ID: uuid.New(), DagID: dbDAG.ID}
+
+	operatorResults := []models.OperatorResult{}
+	artifactResults := []models.ArtifactResult{}
+	for opID, status := range statuses {
+		operatorResults = append(operatorResults, models.OperatorResult{
+			OperatorID: opID,
+			Status:     status,
+			ExecState:  shared.NullExecutionState{ExecutionState: shared.ExecutionState{Status: status}},
+		})
+
+		artifactResult := models.ArtifactResult{
+			ArtifactID:  dbDAG.Operators[opID].Outputs[0],
+			ContentPath: "content-" + opID.String(),
+			Status:      status,
+		}
+		if status == shared.SucceededExecutionStatus {
+			artifactResult.Metadata = shared.NullArtifactResultMetadata{
+				ArtifactResultMetadata: shared.ArtifactResultMetadata{SerializationType: shared.JsonSerialization},
+			}
+		} else {
+			artifactResult.Metadata.IsNull = true
+		}
+		artifactResults = append(artifactResults, artifactResult)
+	}
+
+	source, err := newResumeSource(dagResult, dbDAG, operatorResults, artifactResults)
+	require.Nil(t, err)
+	return source
+}
+
+func newTestOperatorIDs(n int) ([]uuid.UUID, map[uuid.UUID]uuid.UUID) {
+	opIDs := make([]uuid.UUID, 0, n)
+	outputs := make(map[uuid.UUID]uuid.UUID, n)
+	for i := 0; i < n; i++ {
+		opID := uuid.New()
+		opIDs = append(opIDs, opID)
+		outputs[opID] = uuid.New()
+	}
+	return opIDs, outputs
+}
+
+// requireReused checks that exactly the operators in `reused` reuse their results from the source run.
+func requireReused(t *testing.T, dag *fakeDag, source *resumeSource, reused ...uuid.UUID) {
+	isReused := make(map[uuid.UUID]bool, len(reused))
+	for _, opID := range reused {
+		isReused[opID] = true
+	}
+
+	for opID, op := range dag.operators {
+		fakeOp := op.(*fakeOperator)
+		if !isReused[opID] {
+			require.Equal(t, shared.PendingExecutionStatus, fakeOp.execState.Status)
+			require.Nil(t, fakeOp.reusedResults)
+			continue
+		}
+
+		require.Equal(t, shared.SucceededExecutionStatus, fakeOp.execState.Status)
+		outputID := dag.outputs[opID].ID()
+		require.Len(t, fakeOp.reusedResults, 1)
+		require.Equal(t, "content-"+opID.String(), fakeOp.reusedResults[outputID].ContentPath)
+		require.Equal(t, source.results[fakeOp.signature].artifactResults[0], fakeOp.reusedResults[outputID])
+	}
+}
+
+func TestResumeReusesSucceededOperators(t *testing.T) {
+	ctx := context.Background()
+
+	opIDs, outputs := newTestOperatorIDs(3)
+	dbDAG := newTestChainsDAG([][]uuid.UUID{opIDs}, outputs)
+	source := newTestResumeSource(t, dbDAG, map[uuid.UUID]shared.ExecutionStatus{
+		opIDs[0]: shared.SucceededExecutionStatus,
+		opIDs[1]: shared.SucceededExecutionStatus,
+		opIDs[2]: shared.FailedExecutionStatus,
+	})
+
+	dag := newResumedDag(t, dbDAG)
+	require.Nil(t, reuseSourceResults(ctx, dag, source))
+	requireReused(t, dag, source, opIDs[0], opIDs[1])
+}
+
+func TestResumeRerunsFailedOperatorsOnly(t *testing.T) {
+	ctx := context.Background()
+
+	succeededChain, outputs := newTestOperatorIDs(2)
+	failedChain, failedOutputs := newTestOperatorIDs(3)
+	for opID, output := range failedOutputs {
+		outputs[opID] = output
+	}
+
+	dbDAG := newTestChainsDAG([][]uuid.UUID{succeededChain, failedChain}, outputs)
+	source := newTestResumeSource(t, dbDAG, map[uuid.UUID]shared.ExecutionStatus{
+		succeededChain[0]: shared.SucceededExecutionStatus,
+		succeededChain[1]: shared.SucceededExecutionStatus,
+		failedChain[0]:    shared.SucceededExecutionStatus,
+		failedChain[1]:    shared.FailedExecutionStatus,
+		failedChain[2]:    shared.CanceledExecutionStatus,
+	})
+
+	// Only the failed operator and the operators downstream of it are executed.
+	dag := newResumedDag(t, dbDAG)
+	require.Nil(t, reuseSourceResults(ctx, dag, source))
+	requireReused(t, dag, source, succeededChain[0], succeededChain[1], failedChain[0])
+}
+
+func TestResumeEditedOperator(t *testing.T) {
+	ctx := context.Background()
+
+	opIDs, outputs := newTestOperatorIDs(4)
+	dbDAG := newTestChainsDAG([][]uuid.UUID{opIDs}, outputs)
+	source := newTestResumeSource(t, dbDAG, map[uuid.UUID]shared.ExecutionStatus{
+		opIDs[0]: shared.SucceededExecutionStatus,
+		opIDs[1]: shared.SucceededExecutionStatus,
+		opIDs[2]: shared.SucceededExecutionStatus,
+		opIDs[3]: shared.FailedExecutionStatus,
+	})
+
+	// Editing an operator replaces it with a new operator that produces the same artifact.
+	editedOpIDs := []uuid.UUID{opIDs[0], uuid.New(), opIDs[2], opIDs[3]}
+	outputs[editedOpIDs[1]] = outputs[opIDs[1]]
+	editedDAG := newTestChainsDAG([][]uuid.UUID{editedOpIDs}, outputs)
+
+	// The results of the edited operator, and of all operators downstream of it, are not reused,
+	// even though the artifacts that they produce are the same.
+	dag := newResumedDag(t, editedDAG)
+	require.Nil(t, reuseSourceResults(ctx, dag, source))
+	requireReused(t, dag, source, opIDs[0])
+
+	for _, opID := range editedOpIDs[1:] {
+		_, ok := source.results[dag.operators[opID].Signature()]
+		require.False(t, ok)
+	}
+}
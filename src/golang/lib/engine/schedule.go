@@ -0,0 +1,205 @@
+package engine
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aqueducthq/aqueduct/lib/job"
+	shared_utils "github.com/aqueducthq/aqueduct/lib/lib_utils"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// The cron job of a workflow is deployed either in process by the CronjobManager, or as a cron job
+// of the ScheduleJobManager if it is set. The helpers below hide which of the two is used.
+
+func (eng *aqEngine) deployWorkflowSchedule(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	name string,
+	schedule *shared.Schedule,
+) error {
+	// TODO ENG-1444: Remove jobSpec once executor is removed.
+	jobSpec := eng.newWorkflowSpec(name, workflowID, nil)
+
+	if eng.ScheduleJobManager != nil {
+		return eng.ScheduleJobManager.DeployCronJob(ctx, name, schedulePeriod(schedule), jobSpec)
+	}
+
+	return eng.CronjobManager.DeployCronJob(
+		ctx,
+		name,
+		cronjobSchedule(schedule),
+		eng.generateCronFunction(name, jobSpec),
+	)
+}
+
+func (eng *aqEngine) workflowScheduleExists(ctx context.Context, name string) bool {
+	if eng.ScheduleJobManager != nil {
+		return eng.ScheduleJobManager.CronJobExists(ctx, name)
+	}
+	return eng.CronjobManager.CronJobExists(ctx, name)
+}
+
+func (eng *aqEngine) editWorkflowSchedule(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	name string,
+	schedule *shared.Schedule,
+) error {
+	if eng.ScheduleJobManager != nil {
+		return eng.ScheduleJobManager.EditCronJob(ctx, name, schedulePeriod(schedule))
+	}
+
+	// TODO ENG-1444: Remove jobSpec once executor is removed.
+	jobSpec := eng.newWorkflowSpec(name, workflowID, nil)
+	return eng.CronjobManager.EditCronJob(
+		ctx,
+		name,
+		cronjobSchedule(schedule),
+		eng.generateCronFunction(name, jobSpec),
+	)
+}
+
+func (eng *aqEngine) deleteWorkflowSchedule(ctx context.Context, name string) error {
+	if eng.ScheduleJobManager != nil {
+		return eng.ScheduleJobManager.DeleteCronJob(ctx, name)
+	}
+	return eng.CronjobManager.DeleteCronJob(ctx, name)
+}
+
+func (eng *aqEngine) InitializeWorkflowSchedules(ctx context.Context, workflows []models.Workflow) error {
+	if eng.ScheduleJobManager == nil {
+		// The in-process cron jobs do not survive a restart, so they are all deployed again.
+		for _, wf := range workflows {
+			if wf.Schedule.CronSchedule == "" {
+				continue
+			}
+
+			// The cron job of a paused workflow is deployed without a cron string.
+			if err := eng.ScheduleWorkflow(
+				ctx,
+				wf.ID,
+				shared_utils.AppendPrefix(wf.ID.String()),
+				&wf.Schedule,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return eng.reconcileWorkflowSchedules(ctx, workflows)
+}
+
+// reconcileWorkflowSchedules makes the cron jobs of the ScheduleJobManager, which outlive the server,
+// match the schedules of workflows. This catches up with any changes that were made to the database
+// without going through the engine, such as a metadata import, or that failed halfway.
+func (eng *aqEngine) reconcileWorkflowSchedules(ctx context.Context, workflows []models.Workflow) error {
+	scheduled := make(map[string]bool, len(workflows))
+	for _, wf := range workflows {
+		if wf.Schedule.CronSchedule == "" {
+			continue
+		}
+
+		name := shared_utils.AppendPrefix(wf.ID.String())
+		scheduled[name] = true
+
+		if eng.ScheduleJobManager.CronJobExists(ctx, name) {
+			// The spec of the cron job is left as is, since it only depends on the workflow's ID
+			// and on the server config.
+			if err := eng.editWorkflowSchedule(ctx, wf.ID, name, &wf.Schedule); err != nil {
+				return errors.Wrapf(err, "Unable to update the cron job of workflow %s.", wf.ID)
+			}
+			continue
+		}
+
+		if err := eng.deployWorkflowSchedule(ctx, wf.ID, name, &wf.Schedule); err != nil {
+			return errors.Wrapf(err, "Unable to deploy the cron job of workflow %s.", wf.ID)
+		}
+	}
+
+	lister, ok := eng.ScheduleJobManager.(job.CronJobLister)
+	if !ok {
+		return nil
+	}
+
+	names, err := lister.ListCronJobs(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Unable to list cron jobs.")
+	}
+
+	for _, name := range names {
+		if scheduled[name] || !isWorkflowCronJobName(name) {
+			continue
+		}
+
+		// The workflow was deleted, or made manually triggered, while its cron job could not be deleted.
+		log.Infof("Deleting cron job %s, which does not belong to a scheduled workflow.", name)
+		if err := eng.ScheduleJobManager.DeleteCronJob(ctx, name); err != nil {
+			return errors.Wrapf(err, "Unable to delete cron job %s.", name)
+		}
+	}
+
+	return nil
+}
+
+func (eng *aqEngine) ScheduledWorkflowsInProgress(ctx context.Context) (map[uuid.UUID]bool, error) {
+	workflowIDs := map[uuid.UUID]bool{}
+
+	// The in-process cron jobs run workflows in executors that are stopped along with the server.
+	lister, ok := eng.ScheduleJobManager.(job.CronJobLister)
+	if !ok {
+		return workflowIDs, nil
+	}
+
+	names, err := lister.ListActiveCronJobs(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to list active cron jobs.")
+	}
+
+	for _, name := range names {
+		if !isWorkflowCronJobName(name) {
+			continue
+		}
+
+		workflowIDs[uuid.MustParse(strings.TrimPrefix(name, shared_utils.AppendPrefix("")))] = true
+	}
+	return workflowIDs, nil
+}
+
+// schedulePeriod returns the period of the job manager cron job for schedule. It is empty if
+// the workflow is paused.
+func schedulePeriod(schedule *shared.Schedule) string {
+	if schedule.Paused {
+		return ""
+	}
+
+	// Jobs launched by a JobManager's cron job are not jittered, which is why schedules with
+	// a jitter are rejected. Schedules that were created before still run, without the jitter.
+	if schedule.JitterSeconds > 0 {
+		log.Warnf("Ignoring the jitter of schedule %s, since the cron job that runs it cannot be jittered.", schedule.CronSchedule)
+	}
+
+	// The time zone is always set, since the cluster's default time zone may not be UTC.
+	timezone := schedule.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	return job.CronPeriodWithTimezone(string(schedule.CronSchedule), timezone)
+}
+
+// isWorkflowCronJobName returns whether name is the name of a workflow's cron job,
+// as opposed to one of the server's own cron jobs.
+func isWorkflowCronJobName(name string) bool {
+	workflowID := strings.TrimPrefix(name, shared_utils.AppendPrefix(""))
+	if workflowID == name {
+		return false
+	}
+
+	_, err := uuid.Parse(workflowID)
+	return err == nil
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"time"
 
+	"github.com/aqueducthq/aqueduct/lib/cronjob"
+	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/errors"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	dag_utils "github.com/aqueducthq/aqueduct/lib/workflow/dag"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator"
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
@@ -36,6 +40,83 @@ func waitForInProgressOperators(
 	}
 }
 
+// shouldRetryOperator returns whether a terminated operator failed in a way that its
+// retry policy allows another attempt for.
+func shouldRetryOperator(op operator.Operator, execState *shared.ExecutionState) bool {
+	retryPolicy := op.RetryPolicy()
+	if retryPolicy == nil || !execState.HasBlockingFailure() {
+		return false
+	}
+
+	return retryPolicy.ShouldRetry(execState.NumAttempts(), *execState.FailureType)
+}
+
+// operatorDeadline returns when the running operator exceeds its timeout.
+// The second return value is false if the operator has no timeout or is not running.
+func operatorDeadline(op operator.Operator) (time.Time, bool) {
+	timeout := op.Timeout()
+	execState := op.ExecState()
+	if timeout <= 0 || execState.Status != shared.RunningExecutionStatus ||
+		execState.Timestamps == nil || execState.Timestamps.RunningAt == nil {
+		return time.Time{}, false
+	}
+
+	return execState.Timestamps.RunningAt.Add(timeout), true
+}
+
+// isRunCanceled returns whether a cancellation has been requested for the DAG result being executed.
+// Cancellation requests are written to the database, since the run is usually executed by a
+// different process than the one serving the request.
+func isRunCanceled(
+	ctx context.Context,
+	dagResultID uuid.UUID,
+	dagResultRepo repos.DAGResult,
+	DB database.Database,
+) bool {
+	if dagResultID == uuid.Nil {
+		return false
+	}
+
+	dagResult, err := dagResultRepo.Get(ctx, dagResultID, DB)
+	if err != nil {
+		log.Errorf("Unable to check whether workflow run %s was canceled: %v", dagResultID, err)
+		return false
+	}
+	return dagResult.Status == shared.CanceledExecutionStatus
+}
+
+// cancelRemainingOperators kills all in-progress operators and marks every operator
+// that has not completed as canceled. Killed operators are removed from `inProgressOps`.
+func cancelRemainingOperators(
+	ctx context.Context,
+	dag dag_utils.WorkflowDag,
+	inProgressOps map[uuid.UUID]operator.Operator,
+	completedOps map[uuid.UUID]operator.Operator,
+	opExecMode operator.ExecutionMode,
+) error {
+	for id, op := range dag.Operators() {
+		if _, ok := completedOps[id]; ok {
+			continue
+		}
+
+		if _, ok := inProgressOps[id]; ok {
+			if err := op.Kill(ctx); err != nil {
+				return err
+			}
+			delete(inProgressOps, id)
+		} else {
+			op.Cancel()
+		}
+
+		if opExecMode == operator.Publish {
+			if err := op.PersistResult(ctx); err != nil {
+				return errors.Wrapf(err, "Error when canceling operator %s", op.Name())
+			}
+		}
+	}
+	return nil
+}
+
 // The two error types returned here indicate that the issue happened within the context
 // of the operator.
 func opFailureError(failureType shared.FailureType, op operator.Operator) error {
@@ -51,3 +132,22 @@ func opFailureError(failureType shared.FailureType, op operator.Operator) error
 func isOpFailureError(err error) bool {
 	return errors.Is(err, ErrOpExecSystemFailure) || errors.Is(err, ErrOpExecBlockingUserFailure)
 }
+
+func isRunCanceledError(err error) bool {
+	return errors.Is(err, ErrWorkflowRunCanceled)
+}
+
+// cronjobSchedule returns the cron job schedule of a workflow with the given schedule.
+// A paused workflow has a cron job with an empty cron string.
+func cronjobSchedule(schedule *shared.Schedule) cronjob.Schedule {
+	cronString := string(schedule.CronSchedule)
+	if schedule.Paused {
+		cronString = ""
+	}
+
+	return cronjob.Schedule{
+		CronString: cronString,
+		Timezone:   schedule.Timezone,
+		Jitter:     schedule.Jitter(),
+	}
+}
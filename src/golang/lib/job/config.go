@@ -45,6 +45,16 @@ type K8sJobManagerConfig struct {
 	AwsRegion string `yaml:"awsRegion" json:"aws_region"`
 
 	Dynamic bool `yaml:"dynamic" json:"dynamic"`
+
+	// Operators can only override the namespace that they run in and the service account
+	// that they run as with these. The default Aqueduct namespace is always allowed.
+	AllowedNamespaces      []string `yaml:"allowedNamespaces" json:"allowed_namespaces"`
+	AllowedServiceAccounts []string `yaml:"allowedServiceAccounts" json:"allowed_service_accounts"`
+
+	// If set, the executor config that is derived from the server config is mounted into the pods
+	// that cron jobs spawn, since they run the executor, which reads it. It is only set by the server,
+	// so it is never persisted.
+	MountExecutorConfig bool `yaml:"-" json:"-"`
 }
 
 type LambdaJobManagerConfig struct {
@@ -111,6 +121,7 @@ func RegisterGobTypes() {
 	gob.Register(&WorkflowSpec{})
 	gob.Register(&WorkflowRetentionSpec{})
 	gob.Register(&DynamicTeardownSpec{})
+	gob.Register(&StorageGCSpec{})
 }
 
 func init() {
@@ -188,12 +199,14 @@ func GenerateJobManagerConfig(
 		}
 
 		return &K8sJobManagerConfig{
-			KubeconfigPath:     k8sConfig.KubeconfigPath,
-			ClusterName:        k8sConfig.ClusterName,
-			UseSameCluster:     bool(k8sConfig.UseSameCluster),
-			AwsAccessKeyId:     awsAccessKeyId,
-			AwsSecretAccessKey: awsSecretAccessKey,
-			Dynamic:            bool(k8sConfig.Dynamic),
+			KubeconfigPath:         k8sConfig.KubeconfigPath,
+			ClusterName:            k8sConfig.ClusterName,
+			UseSameCluster:         bool(k8sConfig.UseSameCluster),
+			AwsAccessKeyId:         awsAccessKeyId,
+			AwsSecretAccessKey:     awsSecretAccessKey,
+			Dynamic:                bool(k8sConfig.Dynamic),
+			AllowedNamespaces:      k8sConfig.AllowedNamespaces,
+			AllowedServiceAccounts: k8sConfig.AllowedServiceAccounts,
 		}, nil
 	case shared.LambdaEngineType:
 		if storageConfig.Type != shared.S3StorageType {
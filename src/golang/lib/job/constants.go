@@ -16,6 +16,7 @@ const (
 	GpuCuda1141Python39  = "aqueducthq/gpu_cuda1141_py39"
 	GpuCuda1141Python310 = "aqueducthq/gpu_cuda1141_py310"
 
+	ExecutorDockerImage           = "aqueducthq/executor"
 	ParameterDockerImage          = "aqueducthq/param"
 	SystemMetricDockerImage       = "aqueducthq/system-metric"
 	PostgresConnectorDockerImage  = "aqueducthq/postgres-connector"
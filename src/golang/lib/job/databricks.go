@@ -17,6 +17,9 @@ type DatabricksJobManager struct {
 	databricksClient *databricks_sdk.WorkspaceClient
 	conf             *DatabricksJobManagerConfig
 	runMap           map[string]int64
+	// The JSON serialized storage keys that the tasks created so far need, if any of them encrypts
+	// the objects it stores. They are passed to the tasks through the environment of their cluster.
+	storageKeys string
 }
 
 func NewDatabricksJobManager(conf *DatabricksJobManagerConfig) (*DatabricksJobManager, error) {
@@ -51,7 +54,12 @@ func (j *DatabricksJobManager) Launch(
 		return systemError(err)
 	}
 
-	jobID, err := databricks_lib.CreateJob(ctx, j.databricksClient, name, j.conf.S3InstanceProfileARN, j.conf.InstancePoolID, []jobs.JobTaskSettings{*task})
+	sparkEnvVars, err := j.clusterEnvVars(ctx)
+	if err != nil {
+		return systemError(err)
+	}
+
+	jobID, err := databricks_lib.CreateJob(ctx, j.databricksClient, name, j.conf.S3InstanceProfileARN, j.conf.InstancePoolID, sparkEnvVars, []jobs.JobTaskSettings{*task})
 	if err != nil {
 		return systemError(errors.Wrap(err, "Error creating job in Databricks."))
 	}
@@ -95,6 +103,18 @@ func (j *DatabricksJobManager) Poll(ctx context.Context, name string) (shared.Ex
 	}
 }
 
+func (j *DatabricksJobManager) Cancel(ctx context.Context, name string) JobError {
+	runID, ok := j.runMap[name]
+	if !ok {
+		return jobMissingError(errors.New("Job doesn't exist."))
+	}
+
+	if err := databricks_lib.CancelRun(ctx, j.databricksClient, runID); err != nil {
+		return systemError(err)
+	}
+	return nil
+}
+
 func (j *DatabricksJobManager) DeployCronJob(
 	ctx context.Context,
 	name string,
@@ -131,6 +151,15 @@ func (j *DatabricksJobManager) CreateTask(
 	if err != nil {
 		return nil, err
 	}
+
+	// The spec is part of the parameters of the task, which are visible to anyone who can read the job.
+	storageKeys, err := jobStorageKeys(spec)
+	if err != nil {
+		return nil, err
+	}
+	if storageKeys != "" {
+		j.storageKeys = storageKeys
+	}
 	bucket := storageConfig.S3Config.Bucket
 	pythonFilePath := fmt.Sprintf("%s/%s", bucket, scriptFile)
 
@@ -155,8 +184,13 @@ func (j *DatabricksJobManager) LaunchMultipleTaskJob(
 	name string,
 	taskList []jobs.JobTaskSettings,
 ) (int64, JobError) {
+	sparkEnvVars, err := j.clusterEnvVars(ctx)
+	if err != nil {
+		return -1, systemError(err)
+	}
+
 	// Create and register the job with Databricks.
-	jobID, err := databricks_lib.CreateJob(ctx, j.databricksClient, name, j.conf.S3InstanceProfileARN, j.conf.InstancePoolID, taskList)
+	jobID, err := databricks_lib.CreateJob(ctx, j.databricksClient, name, j.conf.S3InstanceProfileARN, j.conf.InstancePoolID, sparkEnvVars, taskList)
 	if err != nil {
 		return -1, systemError(errors.Wrap(err, "Error creating job in Databricks."))
 	}
@@ -177,6 +211,19 @@ func (j *DatabricksJobManager) LaunchMultipleTaskJob(
 	return runID, nil
 }
 
+// clusterEnvVars returns the environment variables of the cluster that runs the tasks created so far.
+// The storage keys are written to a Databricks secret, which the cluster exposes to the tasks.
+func (j *DatabricksJobManager) clusterEnvVars(ctx context.Context) (map[string]string, error) {
+	if j.storageKeys == "" {
+		return nil, nil
+	}
+
+	if err := databricks_lib.PutStorageKeys(ctx, j.databricksClient, j.storageKeys); err != nil {
+		return nil, err
+	}
+	return databricks_lib.StorageKeysSparkEnvVars(), nil
+}
+
 func (j *DatabricksJobManager) mapJobTypeToFile(spec Spec) (string, string, error) {
 	// Add S3 Access Keys to all specs
 	storageConfig, err := spec.GetStorageConfig()
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator"
 	"github.com/dropbox/godropbox/errors"
 )
 
@@ -11,12 +12,47 @@ type JobManager interface {
 	Config() Config
 	Launch(ctx context.Context, name string, spec Spec) JobError
 	Poll(ctx context.Context, name string) (shared.ExecutionStatus, JobError)
+	// Cancel stops the job with the given name if it is still running.
+	Cancel(ctx context.Context, name string) JobError
 	DeployCronJob(ctx context.Context, name string, period string, spec Spec) JobError
 	CronJobExists(ctx context.Context, name string) bool
 	EditCronJob(ctx context.Context, name string, cronString string) JobError
 	DeleteCronJob(ctx context.Context, name string) JobError
 }
 
+// CompletionWatcher is implemented by JobManagers that can notify callers as soon as a job
+// terminates. Callers of JobManagers that don't implement it need to Poll for completion instead.
+type CompletionWatcher interface {
+	// Watch returns a channel that is closed once the job with the given name has terminated.
+	// The job's final status still needs to be retrieved with Poll.
+	Watch(ctx context.Context, name string) (<-chan struct{}, JobError)
+}
+
+// CronJobLister is implemented by JobManagers whose cron jobs outlive the server process,
+// so that the server can find the cron jobs that no longer have a reason to exist.
+type CronJobLister interface {
+	// ListCronJobs returns the names of all the cron jobs that are deployed by the JobManager.
+	ListCronJobs(ctx context.Context) ([]string, JobError)
+	// ListActiveCronJobs returns the names of the cron jobs that have launched a job which is still running.
+	ListActiveCronJobs(ctx context.Context) ([]string, JobError)
+}
+
+// StorageKeysSyncer is implemented by JobManagers that hand the storage keys to their jobs through secrets
+// which outlive the server process, so that the secrets can be updated whenever the server's keyring changes.
+type StorageKeysSyncer interface {
+	// SyncStorageKeys writes the storage keys that are derived from the server's current keyring to the secrets.
+	SyncStorageKeys(ctx context.Context) JobError
+}
+
+// ResourceAwareJobManager is implemented by JobManagers that run a job in a place that depends on
+// the resource config of its operator, eg. a Kubernetes namespace. Callers that have the resource
+// config use these methods, so that the job is found even if it was launched by another server process.
+type ResourceAwareJobManager interface {
+	PollWithResources(ctx context.Context, name string, resources *operator.ResourceConfig) (shared.ExecutionStatus, JobError)
+	CancelWithResources(ctx context.Context, name string, resources *operator.ResourceConfig) JobError
+	WatchWithResources(ctx context.Context, name string, resources *operator.ResourceConfig) (<-chan struct{}, JobError)
+}
+
 func NewJobManager(conf Config) (JobManager, error) {
 	if conf.Type() == ProcessType {
 		processConfig, ok := conf.(*ProcessConfig)
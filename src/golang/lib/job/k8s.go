@@ -2,22 +2,40 @@ package job
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"path"
 	"strconv"
+	"strings"
+	"sync"
 
+	"github.com/aqueducthq/aqueduct/config"
 	"github.com/aqueducthq/aqueduct/lib"
 	"github.com/aqueducthq/aqueduct/lib/k8s"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator"
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/function"
+	"github.com/aqueducthq/aqueduct/lib/storage"
 	"github.com/dropbox/godropbox/errors"
+	log "github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 )
 
 const (
 	jobSpecEnvVarKey = "JOB_SPEC"
+
+	// The period of a cron job can be prefixed with `CRON_TZ=<time zone> `, in which case
+	// the cron string is interpreted in that time zone instead of the cluster's.
+	cronTimezonePrefix = "CRON_TZ="
+
+	// A cron job that is deployed paused does not have a period, but k8s requires a schedule.
+	// This one is never used, since the cron job is suspended until it is given a new period.
+	pausedCronSchedule = "0 0 1 1 *"
 )
 
 type k8sJobManager struct {
@@ -26,12 +44,31 @@ type k8sJobManager struct {
 	// cluster may not exist yet, so k8s client creation will fail. We defer the initialization
 	// to Launch and Poll, at which point regardless of dynamic or static k8s integration, we expect
 	// the k8s client creation to succeed.
-	k8sClient *kubernetes.Clientset
+	k8sClient kubernetes.Interface
 	conf      *K8sJobManagerConfig
+
+	// Operators can override the namespace that their job runs in. Callers that have the operator's
+	// resource config locate the job with it, but for the others we keep track of the namespace of
+	// each job that we launched until it terminates or is canceled. Jobs that are not tracked are
+	// looked up in k8s.AqueductNamespace.
+	mu            sync.Mutex
+	jobNamespaces map[string]string
+	// The namespaces other than k8s.AqueductNamespace that have been set up for jobs.
+	preparedNamespaces map[string]bool
+}
+
+// k8sContainer is the container that runs a job, along with its configuration.
+type k8sContainer struct {
+	image                string
+	environmentVariables map[string]string
+	secretEnvVars        []string
+	resourceRequest      map[string]string
+	// This is nil if the job has no scheduling requirements.
+	scheduling *k8s.SchedulingConfig
 }
 
-func setupNamespaceAndSecrets(k8sClient *kubernetes.Clientset, conf *K8sJobManagerConfig) error {
-	err := k8s.CreateNamespaces(k8sClient)
+func setupNamespaceAndSecrets(k8sClient kubernetes.Interface, namespace string, conf *K8sJobManagerConfig) error {
+	err := k8s.CreateNamespaces(namespace, k8sClient)
 	if err != nil {
 		return errors.Wrap(err, "Error while creating K8s Namespaces")
 	}
@@ -39,14 +76,69 @@ func setupNamespaceAndSecrets(k8sClient *kubernetes.Clientset, conf *K8sJobManag
 	secretsMap := map[string]string{}
 	secretsMap[k8s.AwsAccessKeyIdName] = conf.AwsAccessKeyId
 	secretsMap[k8s.AwsAccessKeyName] = conf.AwsSecretAccessKey
-	err = k8s.CreateSecret(context.Background(), k8s.AwsCredentialsSecretName, secretsMap, k8sClient)
+	err = k8s.CreateSecret(context.Background(), k8s.AwsCredentialsSecretName, namespace, secretsMap, k8sClient)
 	if err != nil {
 		// Double-check that we didn't race against another process to create this secret.
-		if _, secretExistsErr := k8s.GetSecret(context.Background(), k8s.AwsCredentialsSecretName, k8sClient); secretExistsErr != nil {
+		if _, secretExistsErr := k8s.GetSecret(context.Background(), k8s.AwsCredentialsSecretName, namespace, k8sClient); secretExistsErr != nil {
 			return errors.Wrap(err, "Error while creating K8s Secrets")
 		}
 	}
 
+	return syncStorageKeys(context.Background(), k8sClient, namespace)
+}
+
+// syncStorageKeys writes the current storage keys (see `storage.CurrentKeys`) to the secret
+// that jobs in namespace get them from. Jobs get the storage keys from a secret rather than from their spec,
+// which is visible to anyone who can read the job.
+func syncStorageKeys(ctx context.Context, k8sClient kubernetes.Interface, namespace string) error {
+	storageKeys, err := storage.CurrentKeys()
+	if err != nil {
+		return err
+	}
+
+	serializedKeys, err := json.Marshal(storageKeys)
+	if err != nil {
+		return err
+	}
+
+	secrets := map[string]string{k8s.StorageKeysEnvVarName: string(serializedKeys)}
+	err = k8s.CreateSecret(ctx, k8s.StorageKeysSecretName, namespace, secrets, k8sClient)
+	if k8s_errors.IsAlreadyExists(err) || k8s_errors.IsConflict(err) {
+		// We raced against another process that wrote the secret, which may be running with an older
+		// keyring, so the secret is written again rather than left as is.
+		err = k8s.CreateSecret(ctx, k8s.StorageKeysSecretName, namespace, secrets, k8sClient)
+	}
+	if err != nil {
+		return errors.Wrap(err, "Error while writing the storage keys to K8s Secrets")
+	}
+
+	return nil
+}
+
+// SyncStorageKeys writes the storage keys that are derived from the server's current keyring to the
+// secrets of all the namespaces that jobs have been launched in.
+func (j *k8sJobManager) SyncStorageKeys(ctx context.Context) JobError {
+	if j.k8sClient == nil {
+		// Initializing the job manager sets up the secrets of the default namespace.
+		if err := j.initialize(); err != nil {
+			return systemError(err)
+		}
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	namespaces := []string{k8s.AqueductNamespace}
+	for namespace := range j.preparedNamespaces {
+		namespaces = append(namespaces, namespace)
+	}
+
+	for _, namespace := range namespaces {
+		if err := syncStorageKeys(ctx, j.k8sClient, namespace); err != nil {
+			return systemError(err)
+		}
+	}
 	return nil
 }
 
@@ -56,7 +148,7 @@ func (j *k8sJobManager) initialize() error {
 		return errors.Wrap(err, "Error while creating K8sClient")
 	}
 
-	err = setupNamespaceAndSecrets(k8sClient, j.conf)
+	err = setupNamespaceAndSecrets(k8sClient, k8s.AqueductNamespace, j.conf)
 	if err != nil {
 		return err
 	}
@@ -68,8 +160,10 @@ func (j *k8sJobManager) initialize() error {
 
 func NewK8sJobManager(conf *K8sJobManagerConfig) (*k8sJobManager, error) {
 	return &k8sJobManager{
-		k8sClient: nil,
-		conf:      conf,
+		k8sClient:          nil,
+		conf:               conf,
+		jobNamespaces:      map[string]string{},
+		preparedNamespaces: map[string]bool{},
 	}, nil
 }
 
@@ -84,19 +178,105 @@ func (j *k8sJobManager) Launch(ctx context.Context, name string, spec Spec) JobE
 		}
 	}
 
+	container, jobErr := newK8sContainer(spec)
+	if jobErr != nil {
+		return jobErr
+	}
+
+	err := k8s.CheckSchedulingAllowed(container.scheduling, j.conf.AllowedNamespaces, j.conf.AllowedServiceAccounts)
+	if err != nil {
+		return userError(err)
+	}
+
+	namespace := k8s.JobNamespace(container.scheduling)
+	if err := j.prepareNamespace(namespace); err != nil {
+		return systemError(err)
+	}
+
+	err = k8s.LaunchJob(
+		name,
+		container.image,
+		&container.environmentVariables,
+		container.secretEnvVars,
+		&container.resourceRequest,
+		container.scheduling,
+		j.k8sClient,
+	)
+	if err != nil {
+		// The job is rejected if the operator's scheduling config is invalid, eg. it has a malformed label.
+		if k8s_errors.IsInvalid(errors.RootError(err)) {
+			return userError(err)
+		}
+		return systemError(err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.jobNamespaces[name] = namespace
+	return nil
+}
+
+// prepareNamespace creates the namespace and the secrets that jobs in it need,
+// if this has not been done yet. The default namespace is set up on initialization.
+func (j *k8sJobManager) prepareNamespace(namespace string) error {
+	if namespace == k8s.AqueductNamespace {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.preparedNamespaces[namespace] {
+		return nil
+	}
+
+	if err := setupNamespaceAndSecrets(j.k8sClient, namespace, j.conf); err != nil {
+		return err
+	}
+	j.preparedNamespaces[namespace] = true
+	return nil
+}
+
+// jobNamespace returns the namespace that the job `name` was launched in.
+func (j *k8sJobManager) jobNamespace(name string) string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if namespace, ok := j.jobNamespaces[name]; ok {
+		return namespace
+	}
+	return k8s.AqueductNamespace
+}
+
+// forgetJob stops tracking the namespace of the job `name`.
+func (j *k8sJobManager) forgetJob(name string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	delete(j.jobNamespaces, name)
+}
+
+// resourcesNamespace returns the namespace that the job of an operator with the given resource config runs in.
+func resourcesNamespace(resources *operator.ResourceConfig) string {
+	if resources == nil {
+		return k8s.AqueductNamespace
+	}
+	return k8s.JobNamespace(k8sSchedulingConfig(resources.K8s))
+}
+
+// newK8sContainer returns the container that runs spec.
+func newK8sContainer(spec Spec) (*k8sContainer, JobError) {
 	launchGpu := false
 	var cudaVersion operator.CudaVersionNumber
 	resourceRequest := map[string]string{
 		k8s.PodResourceCPUKey:    k8s.DefaultCPURequest,
 		k8s.PodResourceMemoryKey: k8s.DefaultMemoryRequest,
 	}
+	var scheduling *k8s.SchedulingConfig
 
 	environmentVariables := map[string]string{}
 
 	if spec.Type() == FunctionJobType {
 		functionSpec, ok := spec.(*FunctionSpec)
 		if !ok {
-			return systemError(errors.Newf("Function Spec is expected, but got %v", spec))
+			return nil, systemError(errors.Newf("Function Spec is expected, but got %v", spec))
 		}
 
 		functionSpec.FunctionExtractPath = defaultFunctionExtractPath
@@ -122,6 +302,13 @@ func (j *k8sJobManager) Launch(ctx context.Context, name string, spec Spec) JobE
 					strconv.Itoa(*functionSpec.Resources.MemoryMB),
 				)
 			}
+			if functionSpec.Resources.EphemeralStorageMB != nil {
+				resourceRequest[k8s.PodResourceEphemeralStorageKey] = fmt.Sprintf("%sM",
+					strconv.Itoa(*functionSpec.Resources.EphemeralStorageMB),
+				)
+			}
+
+			scheduling = k8sSchedulingConfig(functionSpec.Resources.K8s)
 		}
 	}
 
@@ -129,7 +316,7 @@ func (j *k8sJobManager) Launch(ctx context.Context, name string, spec Spec) JobE
 	serializationType := JsonSerializationType
 	encodedSpec, err := EncodeSpec(spec, serializationType)
 	if err != nil {
-		return systemError(err)
+		return nil, systemError(err)
 	}
 
 	environmentVariables[jobSpecEnvVarKey] = encodedSpec
@@ -140,33 +327,48 @@ func (j *k8sJobManager) Launch(ctx context.Context, name string, spec Spec) JobE
 		// This job spec has a storage config that k8s needs access to
 		storageConfig, err := spec.GetStorageConfig()
 		if err != nil {
-			return systemError(err)
+			return nil, systemError(err)
 		}
 
 		if storageConfig.Type == shared.S3StorageType {
 			// k8s clusters access S3 via credentials passed as a secret
 			secretEnvVars = append(secretEnvVars, k8s.AwsCredentialsSecretName)
 		}
+
+		if storageConfig.Encrypt {
+			secretEnvVars = append(secretEnvVars, k8s.StorageKeysSecretName)
+		}
 	}
 
 	containerRepo, err := mapJobTypeToDockerImage(spec, launchGpu, cudaVersion)
 	if err != nil {
-		return userError(err)
+		return nil, userError(err)
 	}
-	containerImage := fmt.Sprintf("%s:%s", containerRepo, lib.ServerVersionNumber)
 
-	err = k8s.LaunchJob(
-		name,
-		containerImage,
-		&environmentVariables,
-		secretEnvVars,
-		&resourceRequest,
-		j.k8sClient,
-	)
-	if err != nil {
-		return systemError(err)
+	return &k8sContainer{
+		image:                fmt.Sprintf("%s:%s", containerRepo, lib.ServerVersionNumber),
+		environmentVariables: environmentVariables,
+		secretEnvVars:        secretEnvVars,
+		resourceRequest:      resourceRequest,
+		scheduling:           scheduling,
+	}, nil
+}
+
+// k8sSchedulingConfig converts the scheduling config of an operator, which may be nil.
+func k8sSchedulingConfig(conf *operator.K8sSchedulingConfig) *k8s.SchedulingConfig {
+	if conf == nil {
+		return nil
+	}
+
+	return &k8s.SchedulingConfig{
+		Namespace:          conf.Namespace,
+		NodeSelector:       conf.NodeSelector,
+		Tolerations:        conf.Tolerations,
+		Affinity:           conf.Affinity,
+		ServiceAccountName: conf.ServiceAccountName,
+		Labels:             conf.Labels,
+		Annotations:        conf.Annotations,
 	}
-	return nil
 }
 
 func containerStatusFromPod(pod *corev1.Pod, name string) (*corev1.ContainerStatus, error) {
@@ -188,13 +390,25 @@ func containerStatusFromPod(pod *corev1.Pod, name string) (*corev1.ContainerStat
 }
 
 func (j *k8sJobManager) Poll(ctx context.Context, name string) (shared.ExecutionStatus, JobError) {
+	return j.poll(ctx, name, j.jobNamespace(name))
+}
+
+func (j *k8sJobManager) PollWithResources(
+	ctx context.Context,
+	name string,
+	resources *operator.ResourceConfig,
+) (shared.ExecutionStatus, JobError) {
+	return j.poll(ctx, name, resourcesNamespace(resources))
+}
+
+func (j *k8sJobManager) poll(ctx context.Context, name string, namespace string) (shared.ExecutionStatus, JobError) {
 	if j.k8sClient == nil {
 		if err := j.initialize(); err != nil {
 			return shared.UnknownExecutionStatus, systemError(err)
 		}
 	}
 
-	job, err := k8s.GetJob(ctx, name, j.k8sClient)
+	job, err := k8s.GetJob(ctx, name, namespace, j.k8sClient)
 	if err != nil {
 		return shared.UnknownExecutionStatus, jobMissingError(err)
 	}
@@ -202,12 +416,13 @@ func (j *k8sJobManager) Poll(ctx context.Context, name string) (shared.Execution
 	var status shared.ExecutionStatus
 	if job.Status.Succeeded == 1 {
 		status = shared.SucceededExecutionStatus
+		j.forgetJob(name)
 	} else if job.Status.Failed == 1 {
 		status = shared.FailedExecutionStatus
 
 		// Fetch more detailed information about the failure, in case there is valuable
 		// context we can surface to the user.
-		pod, err := k8s.GetPod(ctx, name, j.k8sClient)
+		pod, err := k8s.GetPod(ctx, name, namespace, j.k8sClient)
 		if err != nil {
 			return status, systemError(err)
 		}
@@ -216,6 +431,7 @@ func (j *k8sJobManager) Poll(ctx context.Context, name string) (shared.Execution
 		if err != nil {
 			return status, systemError(err)
 		}
+		j.forgetJob(name)
 
 		if containerStatus.State.Terminated.Reason == "OOMKilled" {
 			return status, userError(errors.New("Operator failed on Kubernetes due to Out-of-Memory exception."))
@@ -226,7 +442,7 @@ func (j *k8sJobManager) Poll(ctx context.Context, name string) (shared.Execution
 		// and not the status of the pod.
 		return status, nil
 	} else {
-		_, err := k8s.GetPod(ctx, name, j.k8sClient)
+		_, err := k8s.GetPod(ctx, name, namespace, j.k8sClient)
 		if err != nil {
 			if err == k8s.ErrNoPodExists {
 				return shared.PendingExecutionStatus, nil
@@ -240,22 +456,307 @@ func (j *k8sJobManager) Poll(ctx context.Context, name string) (shared.Execution
 	return status, nil
 }
 
+func (j *k8sJobManager) Cancel(ctx context.Context, name string) JobError {
+	return j.cancel(ctx, name, j.jobNamespace(name))
+}
+
+func (j *k8sJobManager) CancelWithResources(ctx context.Context, name string, resources *operator.ResourceConfig) JobError {
+	return j.cancel(ctx, name, resourcesNamespace(resources))
+}
+
+func (j *k8sJobManager) cancel(ctx context.Context, name string, namespace string) JobError {
+	if j.k8sClient == nil {
+		if err := j.initialize(); err != nil {
+			return systemError(err)
+		}
+	}
+
+	if err := k8s.DeleteJob(ctx, name, namespace, j.k8sClient); err != nil {
+		if k8s_errors.IsNotFound(err) {
+			j.forgetJob(name)
+			return jobMissingError(err)
+		}
+		return systemError(err)
+	}
+	j.forgetJob(name)
+	return nil
+}
+
+// Watch uses a k8s watch on the job, so that the caller is notified as soon as the job's
+// pod succeeds or fails. If the watch is interrupted, it is re-established until `ctx` is done.
+func (j *k8sJobManager) Watch(ctx context.Context, name string) (<-chan struct{}, JobError) {
+	return j.watch(ctx, name, j.jobNamespace(name))
+}
+
+func (j *k8sJobManager) WatchWithResources(
+	ctx context.Context,
+	name string,
+	resources *operator.ResourceConfig,
+) (<-chan struct{}, JobError) {
+	return j.watch(ctx, name, resourcesNamespace(resources))
+}
+
+func (j *k8sJobManager) watch(ctx context.Context, name string, namespace string) (<-chan struct{}, JobError) {
+	if j.k8sClient == nil {
+		if err := j.initialize(); err != nil {
+			return nil, systemError(err)
+		}
+	}
+
+	watcher, err := k8s.WatchJob(ctx, name, namespace, j.k8sClient)
+	if err != nil {
+		return nil, systemError(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			terminated := waitForJobTermination(ctx, watcher)
+			watcher.Stop()
+			if terminated {
+				close(done)
+				return
+			}
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			// The watch was closed by the API server before the job terminated.
+			watcher, err = k8s.WatchJob(ctx, name, namespace, j.k8sClient)
+			if err != nil {
+				log.Errorf("Unable to re-establish the watch on job %s: %v", name, err)
+				return
+			}
+		}
+	}()
+
+	return done, nil
+}
+
+// waitForJobTermination consumes events from `watcher` until the job terminates or is deleted,
+// in which case it returns true. It returns false if the watch or `ctx` is closed first.
+func waitForJobTermination(ctx context.Context, watcher watch.Interface) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return false
+			}
+
+			if event.Type == watch.Deleted {
+				return true
+			}
+
+			job, ok := event.Object.(*batchv1.Job)
+			if !ok {
+				continue
+			}
+
+			if job.Status.Succeeded > 0 || job.Status.Failed > 0 {
+				return true
+			}
+		}
+	}
+}
+
+// DeployCronJob deploys a k8s CronJob, so that the cluster launches spec according to period
+// even if the server is not running. If period is empty, the CronJob is deployed suspended.
 func (j *k8sJobManager) DeployCronJob(ctx context.Context, name string, period string, spec Spec) JobError {
+	if j.k8sClient == nil {
+		if err := j.initialize(); err != nil {
+			return systemError(err)
+		}
+	}
+
+	container, jobErr := newK8sContainer(spec)
+	if jobErr != nil {
+		return jobErr
+	}
+
+	schedule := &k8s.CronJobSchedule{
+		Schedule: pausedCronSchedule,
+		Suspend:  true,
+	}
+	if period != "" {
+		schedule = cronJobSchedule(period)
+	}
+
+	secretMounts := []k8s.SecretMount{}
+	if j.conf.MountExecutorConfig {
+		if err := j.syncExecutorConfig(ctx); err != nil {
+			return systemError(err)
+		}
+
+		secretMounts = append(secretMounts, k8s.SecretMount{
+			SecretName: k8s.ServerConfigSecretName,
+			MountPath:  k8s.ServerConfigMountPath,
+		})
+		container.environmentVariables[config.PathEnvVar] = path.Join(k8s.ServerConfigMountPath, k8s.ServerConfigFileName)
+
+		// The executor config has no storage keys, so the executor gets them like any other job.
+		if config.Storage().Encrypt {
+			container.secretEnvVars = append(container.secretEnvVars, k8s.StorageKeysSecretName)
+		}
+	}
+
+	err := k8s.CreateCronJob(
+		ctx,
+		name,
+		schedule,
+		container.image,
+		&container.environmentVariables,
+		container.secretEnvVars,
+		&container.resourceRequest,
+		secretMounts,
+		j.k8sClient,
+	)
+	if err != nil {
+		if k8s_errors.IsAlreadyExists(errors.RootError(err)) {
+			return systemError(errors.Newf("Cron job with name %s already exists", name))
+		}
+		return systemError(err)
+	}
 	return nil
 }
 
+// syncExecutorConfig writes the executor config to the secret that is mounted into the pods of cron jobs.
+func (j *k8sJobManager) syncExecutorConfig(ctx context.Context) error {
+	executorConfig, err := config.ExecutorConfig()
+	if err != nil {
+		return errors.Wrap(err, "Unable to create executor config.")
+	}
+
+	return k8s.CreateSecret(
+		ctx,
+		k8s.ServerConfigSecretName,
+		k8s.AqueductNamespace,
+		map[string]string{k8s.ServerConfigFileName: string(executorConfig)},
+		j.k8sClient,
+	)
+}
+
 func (j *k8sJobManager) CronJobExists(ctx context.Context, name string) bool {
-	return false
+	if j.k8sClient == nil {
+		if err := j.initialize(); err != nil {
+			log.Errorf("Unable to check whether cron job %s exists: %v", name, err)
+			return false
+		}
+	}
+
+	_, err := k8s.GetCronJob(ctx, name, j.k8sClient)
+	return err == nil
 }
 
+// EditCronJob changes the period of the CronJob with the given name. If cronString is empty,
+// the CronJob is suspended. A suspended CronJob keeps its previous schedule until it is edited again.
+// Unlike with the ProcessJobManager, suspending a CronJob that is already suspended is a no-op.
 func (j *k8sJobManager) EditCronJob(ctx context.Context, name string, cronString string) JobError {
+	if j.k8sClient == nil {
+		if err := j.initialize(); err != nil {
+			return systemError(err)
+		}
+	}
+
+	schedule := &k8s.CronJobSchedule{Suspend: true}
+	if cronString != "" {
+		schedule = cronJobSchedule(cronString)
+	}
+
+	// The server config may have changed since the cron job was deployed.
+	if j.conf.MountExecutorConfig {
+		if err := j.syncExecutorConfig(ctx); err != nil {
+			return systemError(err)
+		}
+	}
+
+	if err := k8s.UpdateCronJobSchedule(ctx, name, schedule, j.k8sClient); err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return systemError(errors.New("Cron job not found"))
+		}
+		return systemError(err)
+	}
 	return nil
 }
 
 func (j *k8sJobManager) DeleteCronJob(ctx context.Context, name string) JobError {
+	if j.k8sClient == nil {
+		if err := j.initialize(); err != nil {
+			return systemError(err)
+		}
+	}
+
+	if err := k8s.DeleteCronJob(ctx, name, j.k8sClient); err != nil && !k8s_errors.IsNotFound(err) {
+		return systemError(err)
+	}
 	return nil
 }
 
+func (j *k8sJobManager) ListCronJobs(ctx context.Context) ([]string, JobError) {
+	if j.k8sClient == nil {
+		if err := j.initialize(); err != nil {
+			return nil, systemError(err)
+		}
+	}
+
+	cronJobs, err := k8s.ListCronJobs(ctx, j.k8sClient)
+	if err != nil {
+		return nil, systemError(err)
+	}
+
+	names := make([]string, 0, len(cronJobs))
+	for _, cronJob := range cronJobs {
+		names = append(names, cronJob.Name)
+	}
+	return names, nil
+}
+
+func (j *k8sJobManager) ListActiveCronJobs(ctx context.Context) ([]string, JobError) {
+	if j.k8sClient == nil {
+		if err := j.initialize(); err != nil {
+			return nil, systemError(err)
+		}
+	}
+
+	cronJobs, err := k8s.ListCronJobs(ctx, j.k8sClient)
+	if err != nil {
+		return nil, systemError(err)
+	}
+
+	names := []string{}
+	for _, cronJob := range cronJobs {
+		if len(cronJob.Status.Active) > 0 {
+			names = append(names, cronJob.Name)
+		}
+	}
+	return names, nil
+}
+
+// cronJobSchedule returns the schedule of a CronJob with the given period, which may be
+// prefixed with a time zone.
+func cronJobSchedule(period string) *k8s.CronJobSchedule {
+	if !strings.HasPrefix(period, cronTimezonePrefix) {
+		return &k8s.CronJobSchedule{Schedule: period}
+	}
+
+	timezone, cronString, _ := strings.Cut(strings.TrimPrefix(period, cronTimezonePrefix), " ")
+	return &k8s.CronJobSchedule{
+		Schedule: strings.TrimSpace(cronString),
+		TimeZone: timezone,
+	}
+}
+
+// CronPeriodWithTimezone returns the period of a k8s cron job that is triggered according to
+// cronString in the given time zone.
+func CronPeriodWithTimezone(cronString string, timezone string) string {
+	if cronString == "" || timezone == "" {
+		return cronString
+	}
+	return fmt.Sprintf("%s%s %s", cronTimezonePrefix, timezone, cronString)
+}
+
 // Maps a job Spec to Docker image.
 func mapJobTypeToDockerImage(spec Spec, launchGpu bool, cudaVersion operator.CudaVersionNumber) (string, error) {
 	switch spec.Type() {
@@ -301,6 +802,9 @@ func mapJobTypeToDockerImage(spec Spec, launchGpu bool, cudaVersion operator.Cud
 		return ParameterDockerImage, nil
 	case SystemMetricJobType:
 		return SystemMetricDockerImage, nil
+	case WorkflowJobType, WorkflowRetentionType, DynamicTeardownType, StorageGCType:
+		// These jobs are run by the executor binary.
+		return ExecutorDockerImage, nil
 	default:
 		return "", errors.Newf("Unsupported job type %v provided", spec.Type())
 	}
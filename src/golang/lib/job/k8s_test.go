@@ -0,0 +1,523 @@
+package job
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/k8s"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testK8sEncryptionKey = "0123456789abcdef0123456789abcdef"
+
+func newFakeK8sJobManager(t *testing.T) *k8sJobManager {
+	// The secrets of a namespace are derived from the server's keyring.
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yml")
+	require.Nil(t, os.WriteFile(configPath, []byte("aqPath: "+dir+"\nencryptionKey: "+testK8sEncryptionKey+"\n"), 0o644))
+	require.Nil(t, config.Init(configPath))
+
+	jobManager, _ := NewK8sJobManager(&K8sJobManagerConfig{
+		AllowedNamespaces:      []string{"ml"},
+		AllowedServiceAccounts: []string{"workload-identity"},
+	})
+	jobManager.k8sClient = fake.NewSimpleClientset()
+	return jobManager
+}
+
+func getFakeCronJob(t *testing.T, jobManager *k8sJobManager, name string) *batchv1.CronJob {
+	cronJob, err := jobManager.k8sClient.BatchV1().CronJobs(k8s.AqueductNamespace).Get(
+		context.Background(),
+		name,
+		metav1.GetOptions{},
+	)
+	require.Nil(t, err)
+	return cronJob
+}
+
+func TestK8sDeployCronJob(t *testing.T) {
+	jobManager := newFakeK8sJobManager(t)
+	ctx := context.Background()
+
+	workflowName := "workflow"
+	cronString := "0 * * * *"
+
+	// Deploy an unpaused workflow.
+	err := jobManager.DeployCronJob(ctx, workflowName, cronString, dummyWorkflowSpec)
+	require.Nil(t, err)
+	require.True(t, jobManager.CronJobExists(ctx, workflowName))
+
+	cronJob := getFakeCronJob(t, jobManager, workflowName)
+	require.Equal(t, cronString, cronJob.Spec.Schedule)
+	require.False(t, *cronJob.Spec.Suspend)
+	require.Nil(t, cronJob.Spec.TimeZone)
+	require.Equal(t, k8s.ManagedByLabelValue, cronJob.Labels[k8s.ManagedByLabelKey])
+
+	containers := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers
+	require.Equal(t, 1, len(containers))
+	require.Contains(t, containers[0].Image, ExecutorDockerImage)
+	require.Equal(t, jobSpecEnvVarKey, containers[0].Env[0].Name)
+
+	// Deploying a cron job with the same name fails.
+	err = jobManager.DeployCronJob(ctx, workflowName, cronString, dummyWorkflowSpec)
+	require.NotNil(t, err)
+
+	// Deploy a paused workflow.
+	pausedWorkflowName := "paused-workflow"
+	err = jobManager.DeployCronJob(ctx, pausedWorkflowName, "", dummyWorkflowSpec)
+	require.Nil(t, err)
+	require.True(t, *getFakeCronJob(t, jobManager, pausedWorkflowName).Spec.Suspend)
+
+	// Deploy a workflow with a time zone.
+	timezoneWorkflowName := "timezone-workflow"
+	err = jobManager.DeployCronJob(
+		ctx,
+		timezoneWorkflowName,
+		CronPeriodWithTimezone(cronString, "America/New_York"),
+		dummyWorkflowSpec,
+	)
+	require.Nil(t, err)
+
+	cronJob = getFakeCronJob(t, jobManager, timezoneWorkflowName)
+	require.Equal(t, cronString, cronJob.Spec.Schedule)
+	require.Equal(t, "America/New_York", *cronJob.Spec.TimeZone)
+
+	names, err := jobManager.ListCronJobs(ctx)
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{workflowName, pausedWorkflowName, timezoneWorkflowName}, names)
+
+	// Only the cron jobs with a running job are active.
+	names, err = jobManager.ListActiveCronJobs(ctx)
+	require.Nil(t, err)
+	require.Empty(t, names)
+
+	cronJob = getFakeCronJob(t, jobManager, workflowName)
+	cronJob.Status.Active = []corev1.ObjectReference{{Name: workflowName + "-28000000"}}
+	_, updateErr := jobManager.k8sClient.BatchV1().CronJobs(k8s.AqueductNamespace).UpdateStatus(ctx, cronJob, metav1.UpdateOptions{})
+	require.Nil(t, updateErr)
+
+	names, err = jobManager.ListActiveCronJobs(ctx)
+	require.Nil(t, err)
+	require.Equal(t, []string{workflowName}, names)
+}
+
+func TestK8sEditCronJob(t *testing.T) {
+	jobManager := newFakeK8sJobManager(t)
+	ctx := context.Background()
+
+	workflowName := "workflow"
+	pausedWorkflowName := "paused-workflow"
+	cronString := "0 * * * *"
+	newCronString := "1 * * * *"
+	emptyCronString := ""
+
+	require.Nil(t, jobManager.DeployCronJob(ctx, workflowName, cronString, dummyWorkflowSpec))
+	require.Nil(t, jobManager.DeployCronJob(ctx, pausedWorkflowName, emptyCronString, dummyWorkflowSpec))
+
+	// Edit an unpaused workflow to another schedule.
+	err := jobManager.EditCronJob(ctx, workflowName, newCronString)
+	require.Nil(t, err)
+	cronJob := getFakeCronJob(t, jobManager, workflowName)
+	require.Equal(t, newCronString, cronJob.Spec.Schedule)
+	require.False(t, *cronJob.Spec.Suspend)
+
+	// Pause an unpaused workflow. It keeps its schedule.
+	err = jobManager.EditCronJob(ctx, workflowName, emptyCronString)
+	require.Nil(t, err)
+	cronJob = getFakeCronJob(t, jobManager, workflowName)
+	require.Equal(t, newCronString, cronJob.Spec.Schedule)
+	require.True(t, *cronJob.Spec.Suspend)
+
+	// Pausing a paused workflow is a no-op.
+	err = jobManager.EditCronJob(ctx, workflowName, emptyCronString)
+	require.Nil(t, err)
+	require.True(t, *getFakeCronJob(t, jobManager, workflowName).Spec.Suspend)
+
+	// Resume a paused workflow.
+	err = jobManager.EditCronJob(ctx, pausedWorkflowName, cronString)
+	require.Nil(t, err)
+	cronJob = getFakeCronJob(t, jobManager, pausedWorkflowName)
+	require.Equal(t, cronString, cronJob.Spec.Schedule)
+	require.False(t, *cronJob.Spec.Suspend)
+
+	// Editing a cron job that does not exist fails.
+	err = jobManager.EditCronJob(ctx, "missing", cronString)
+	require.NotNil(t, err)
+}
+
+func TestK8sDeleteCronJob(t *testing.T) {
+	jobManager := newFakeK8sJobManager(t)
+	ctx := context.Background()
+
+	workflowName := "workflow"
+	require.Nil(t, jobManager.DeployCronJob(ctx, workflowName, "0 * * * *", dummyWorkflowSpec))
+
+	err := jobManager.DeleteCronJob(ctx, workflowName)
+	require.Nil(t, err)
+	require.False(t, jobManager.CronJobExists(ctx, workflowName))
+
+	// Deleting a cron job that does not exist is a no-op.
+	err = jobManager.DeleteCronJob(ctx, workflowName)
+	require.Nil(t, err)
+}
+
+func newTestK8sFunctionSpec(t *testing.T, resources *operator.ResourceConfig) *FunctionSpec {
+	storageConfig := newTestLambdaStorageConfig(t)
+	putTestFunction(t, storageConfig, "function")
+
+	return &FunctionSpec{
+		BasePythonSpec: BasePythonSpec{
+			BaseSpec: BaseSpec{
+				Type: FunctionJobType,
+				Name: "function",
+			},
+			StorageConfig: storageConfig,
+			MetadataPath:  "function-metadata",
+		},
+		FunctionPath: "function",
+		Resources:    resources,
+	}
+}
+
+func TestK8sLaunchSchedulingConfig(t *testing.T) {
+	jobManager := newFakeK8sJobManager(t)
+	ctx := context.Background()
+
+	ephemeralStorageMB := 2048
+	scheduling := &operator.K8sSchedulingConfig{
+		NodeSelector: map[string]string{"pool": "gpu"},
+		Tolerations: []corev1.Toleration{{
+			Key:      "nvidia.com/gpu",
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffectNoSchedule,
+		}},
+		Affinity: &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      "zone",
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{"us-east-2a"},
+						}},
+					}},
+				},
+			},
+		},
+		ServiceAccountName: "workload-identity",
+		Namespace:          "ml",
+		Labels:             map[string]string{"team": "ml", "job-name": "overridden"},
+		Annotations:        map[string]string{"owner": "ml-team"},
+	}
+	spec := newTestK8sFunctionSpec(t, &operator.ResourceConfig{
+		EphemeralStorageMB: &ephemeralStorageMB,
+		K8s:                scheduling,
+	})
+
+	require.Nil(t, jobManager.Launch(ctx, "function", spec))
+
+	job, err := jobManager.k8sClient.BatchV1().Jobs("ml").Get(ctx, "function", metav1.GetOptions{})
+	require.Nil(t, err)
+	require.Equal(t, "ml", job.Labels["team"])
+	require.Equal(t, "ml-team", job.Annotations["owner"])
+
+	podTemplate := job.Spec.Template
+	require.Equal(t, "ml", podTemplate.Namespace)
+	require.Equal(t, "ml", podTemplate.Labels["team"])
+	require.Equal(t, "function", podTemplate.Labels["job-name"])
+	require.Equal(t, "ml-team", podTemplate.Annotations["owner"])
+
+	podSpec := podTemplate.Spec
+	require.Equal(t, scheduling.NodeSelector, podSpec.NodeSelector)
+	require.Equal(t, scheduling.Tolerations, podSpec.Tolerations)
+	require.Equal(t, scheduling.Affinity, podSpec.Affinity)
+	require.Equal(t, "workload-identity", podSpec.ServiceAccountName)
+	require.Equal(
+		t,
+		resource.MustParse("2048M"),
+		podSpec.Containers[0].Resources.Requests[corev1.ResourceEphemeralStorage],
+	)
+
+	// The namespace that the job runs in is set up for it.
+	_, err = k8s.GetSecret(ctx, k8s.AwsCredentialsSecretName, "ml", jobManager.k8sClient)
+	require.Nil(t, err)
+
+	// The job is polled and canceled in its namespace.
+	status, jobErr := jobManager.Poll(ctx, "function")
+	require.Nil(t, jobErr)
+	require.Equal(t, shared.PendingExecutionStatus, status)
+
+	require.Nil(t, jobManager.Cancel(ctx, "function"))
+	_, err = jobManager.k8sClient.BatchV1().Jobs("ml").Get(ctx, "function", metav1.GetOptions{})
+	require.NotNil(t, err)
+	require.Empty(t, jobManager.jobNamespaces)
+}
+
+func TestK8sLaunchDisallowedScheduling(t *testing.T) {
+	jobManager := newFakeK8sJobManager(t)
+	ctx := context.Background()
+
+	for _, scheduling := range []*operator.K8sSchedulingConfig{
+		{Namespace: "kube-system"},
+		{Namespace: "ml", ServiceAccountName: "cluster-admin"},
+		{ServiceAccountName: "cluster-admin"},
+	} {
+		jobErr := jobManager.Launch(ctx, "function", newTestK8sFunctionSpec(t, &operator.ResourceConfig{K8s: scheduling}))
+		require.NotNil(t, jobErr)
+		require.Equal(t, User, jobErr.Code())
+	}
+
+	// Nothing is set up in a namespace that is not allowed.
+	_, err := k8s.GetSecret(ctx, k8s.AwsCredentialsSecretName, "kube-system", jobManager.k8sClient)
+	require.NotNil(t, err)
+	require.Empty(t, jobManager.jobNamespaces)
+}
+
+func TestK8sPollWithResources(t *testing.T) {
+	jobManager := newFakeK8sJobManager(t)
+	ctx := context.Background()
+
+	resources := &operator.ResourceConfig{K8s: &operator.K8sSchedulingConfig{Namespace: "ml"}}
+	require.Nil(t, jobManager.Launch(ctx, "function", newTestK8sFunctionSpec(t, resources)))
+
+	// A job manager of a restarted server only finds the job with the operator's resources.
+	restarted := newFakeK8sJobManager(t)
+	restarted.k8sClient = jobManager.k8sClient
+
+	_, jobErr := restarted.Poll(ctx, "function")
+	require.NotNil(t, jobErr)
+	require.Equal(t, JobMissing, jobErr.Code())
+
+	status, jobErr := restarted.PollWithResources(ctx, "function", resources)
+	require.Nil(t, jobErr)
+	require.Equal(t, shared.PendingExecutionStatus, status)
+
+	job, err := jobManager.k8sClient.BatchV1().Jobs("ml").Get(ctx, "function", metav1.GetOptions{})
+	require.Nil(t, err)
+	job.Status.Succeeded = 1
+	_, err = jobManager.k8sClient.BatchV1().Jobs("ml").UpdateStatus(ctx, job, metav1.UpdateOptions{})
+	require.Nil(t, err)
+
+	// The namespace of a job is no longer tracked once it terminates.
+	status, jobErr = jobManager.Poll(ctx, "function")
+	require.Nil(t, jobErr)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+	require.Empty(t, jobManager.jobNamespaces)
+
+	require.Nil(t, restarted.CancelWithResources(ctx, "function", resources))
+	_, err = jobManager.k8sClient.BatchV1().Jobs("ml").Get(ctx, "function", metav1.GetOptions{})
+	require.NotNil(t, err)
+}
+
+func TestK8sLaunchDefaultNamespace(t *testing.T) {
+	jobManager := newFakeK8sJobManager(t)
+	ctx := context.Background()
+
+	require.Nil(t, jobManager.Launch(ctx, "function", newTestK8sFunctionSpec(t, nil)))
+
+	job, err := jobManager.k8sClient.BatchV1().Jobs(k8s.AqueductNamespace).Get(ctx, "function", metav1.GetOptions{})
+	require.Nil(t, err)
+
+	podSpec := job.Spec.Template.Spec
+	require.Nil(t, podSpec.NodeSelector)
+	require.Nil(t, podSpec.Affinity)
+	require.Empty(t, podSpec.ServiceAccountName)
+	_, ok := podSpec.Containers[0].Resources.Requests[corev1.ResourceEphemeralStorage]
+	require.False(t, ok)
+}
+
+func TestK8sLaunchEncryptedStorage(t *testing.T) {
+	jobManager := newFakeK8sJobManager(t)
+	ctx := context.Background()
+
+	spec := newTestK8sFunctionSpec(t, &operator.ResourceConfig{
+		K8s: &operator.K8sSchedulingConfig{Namespace: "ml"},
+	})
+	spec.StorageConfig.Encrypt = true
+
+	require.Nil(t, jobManager.Launch(ctx, "function", spec))
+
+	job, err := jobManager.k8sClient.BatchV1().Jobs("ml").Get(ctx, "function", metav1.GetOptions{})
+	require.Nil(t, err)
+	container := job.Spec.Template.Spec.Containers[0]
+
+	// The spec does not contain any key, and the keys are passed as a secret instead.
+	decodedSpec, err := base64.StdEncoding.DecodeString(container.Env[0].Value)
+	require.Nil(t, err)
+	require.NotContains(t, string(decodedSpec), "encryption_keys")
+	require.NotContains(t, string(decodedSpec), testK8sEncryptionKey)
+	require.Nil(t, spec.StorageConfig.EncryptionKeys)
+
+	secretNames := []string{}
+	for _, envFrom := range container.EnvFrom {
+		secretNames = append(secretNames, envFrom.SecretRef.Name)
+	}
+	require.Contains(t, secretNames, k8s.StorageKeysSecretName)
+
+	secret, err := k8s.GetSecret(ctx, k8s.StorageKeysSecretName, "ml", jobManager.k8sClient)
+	require.Nil(t, err)
+	require.NotContains(t, secret[k8s.StorageKeysEnvVarName], testK8sEncryptionKey)
+
+	var keys shared.StorageKeys
+	require.Nil(t, json.Unmarshal([]byte(secret[k8s.StorageKeysEnvVarName]), &keys))
+	require.Len(t, keys.Keys, 1)
+
+	// Other job managers pass the same keys through the environment of their jobs,
+	// and only the lambda job manager, whose invocations are private, puts them in the spec.
+	storageKeys, err := jobStorageKeys(spec)
+	require.Nil(t, err)
+	require.Equal(t, secret[k8s.StorageKeysEnvVarName], storageKeys)
+
+	encodedSpec, err := EncodeSpec(spec, JsonSerializationType)
+	require.Nil(t, err)
+	decodedSpec, err = base64.StdEncoding.DecodeString(encodedSpec)
+	require.Nil(t, err)
+	require.NotContains(t, string(decodedSpec), keys.Keys[""])
+
+	encodedSpec, err = encodeSpec(spec, JsonSerializationType, true /* withStorageKeys */)
+	require.Nil(t, err)
+	decodedSpec, err = base64.StdEncoding.DecodeString(encodedSpec)
+	require.Nil(t, err)
+	require.Contains(t, string(decodedSpec), keys.Keys[""])
+	require.NotContains(t, string(decodedSpec), testK8sEncryptionKey)
+	require.Nil(t, spec.StorageConfig.EncryptionKeys)
+
+	spec.StorageConfig.Encrypt = false
+	storageKeys, err = jobStorageKeys(spec)
+	require.Nil(t, err)
+	require.Empty(t, storageKeys)
+}
+
+func TestK8sSyncStorageKeys(t *testing.T) {
+	jobManager := newFakeK8sJobManager(t)
+	ctx := context.Background()
+
+	spec := newTestK8sFunctionSpec(t, &operator.ResourceConfig{
+		K8s: &operator.K8sSchedulingConfig{Namespace: "ml"},
+	})
+	spec.StorageConfig.Encrypt = true
+	require.Nil(t, jobManager.Launch(ctx, "function", spec))
+
+	requireSecretKeys := func(currentKeyID string, keyIDs ...string) {
+		for _, namespace := range []string{k8s.AqueductNamespace, "ml"} {
+			secret, err := k8s.GetSecret(ctx, k8s.StorageKeysSecretName, namespace, jobManager.k8sClient)
+			require.Nil(t, err)
+
+			var keys shared.StorageKeys
+			require.Nil(t, json.Unmarshal([]byte(secret[k8s.StorageKeysEnvVarName]), &keys))
+			require.Equal(t, currentKeyID, keys.CurrentKeyID)
+
+			secretKeyIDs := []string{}
+			for keyID := range keys.Keys {
+				secretKeyIDs = append(secretKeyIDs, keyID)
+			}
+			require.ElementsMatch(t, keyIDs, secretKeyIDs)
+		}
+	}
+
+	require.Nil(t, jobManager.SyncStorageKeys(ctx))
+	requireSecretKeys("", "")
+
+	// The secrets follow the keyring when a key is rotated in, and when a key is retired.
+	require.Nil(t, config.AddEncryptionKey("new", "fedcba9876543210fedcba9876543210"))
+	require.Nil(t, jobManager.SyncStorageKeys(ctx))
+	requireSecretKeys("new", "", "new")
+
+	require.Nil(t, config.RetireEncryptionKey(""))
+	require.Nil(t, jobManager.SyncStorageKeys(ctx))
+	requireSecretKeys("new", "new")
+}
+
+func TestK8sDeployCronJobMountsExecutorConfig(t *testing.T) {
+	jobManager := newFakeK8sJobManager(t)
+	jobManager.conf.MountExecutorConfig = true
+	ctx := context.Background()
+
+	serverConfigPath := filepath.Join(t.TempDir(), "config.yml")
+	serverConfig := strings.Join([]string{
+		"aqPath: /home/aqueduct",
+		"encryptionKey: " + testK8sEncryptionKey,
+		"apiKey: server-api-key",
+		"storageConfig:",
+		"  type: s3",
+		"  encrypt: true",
+		"databaseConfig:",
+		"  type: postgres",
+		"  postgres:",
+		"    password: database-password",
+		"k8sSchedulerConfig:",
+		"  awsSecretAccessKey: scheduler-aws-secret",
+	}, "\n")
+	require.Nil(t, os.WriteFile(serverConfigPath, []byte(serverConfig), 0o644))
+	require.Nil(t, config.Init(serverConfigPath))
+
+	require.Nil(t, jobManager.DeployCronJob(ctx, "workflow", "0 * * * *", dummyWorkflowSpec))
+
+	podSpec := getFakeCronJob(t, jobManager, "workflow").Spec.JobTemplate.Spec.Template.Spec
+	require.Equal(t, []corev1.Volume{{
+		Name: k8s.ServerConfigSecretName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: k8s.ServerConfigSecretName},
+		},
+	}}, podSpec.Volumes)
+
+	container := podSpec.Containers[0]
+	require.Equal(t, []corev1.VolumeMount{{
+		Name:      k8s.ServerConfigSecretName,
+		MountPath: k8s.ServerConfigMountPath,
+		ReadOnly:  true,
+	}}, container.VolumeMounts)
+	require.Contains(t, container.Env, corev1.EnvVar{
+		Name:  config.PathEnvVar,
+		Value: filepath.Join(k8s.ServerConfigMountPath, k8s.ServerConfigFileName),
+	})
+
+	// The storage layer is encrypted, so the executor gets the storage keys from their secret.
+	require.Contains(t, container.EnvFrom, corev1.EnvFromSource{
+		SecretRef: &corev1.SecretEnvSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: k8s.StorageKeysSecretName},
+		},
+	})
+
+	// Only the parts of the server config that the executor needs are mounted. The keyring is
+	// needed to read integration credentials, since they are kept in the storage layer.
+	secret, err := k8s.GetSecret(ctx, k8s.ServerConfigSecretName, k8s.AqueductNamespace, jobManager.k8sClient)
+	require.Nil(t, err)
+	executorConfig := secret[k8s.ServerConfigFileName]
+	require.Contains(t, executorConfig, "/home/aqueduct")
+	require.Contains(t, executorConfig, "encrypt: true")
+	require.Contains(t, executorConfig, testK8sEncryptionKey)
+	require.NotContains(t, executorConfig, "server-api-key")
+	require.NotContains(t, executorConfig, "database-password")
+	require.NotContains(t, executorConfig, "scheduler-aws-secret")
+
+	// Editing the cron job picks up changes to the server config.
+	require.Nil(t, os.WriteFile(serverConfigPath, []byte(strings.Replace(serverConfig, "/home/aqueduct", "/home/other", 1)), 0o644))
+	require.Nil(t, config.Init(serverConfigPath))
+	require.Nil(t, jobManager.EditCronJob(ctx, "workflow", "1 * * * *"))
+	secret, err = k8s.GetSecret(ctx, k8s.ServerConfigSecretName, k8s.AqueductNamespace, jobManager.k8sClient)
+	require.Nil(t, err)
+	require.Contains(t, secret[k8s.ServerConfigFileName], "/home/other")
+
+	// The keyring is left out if integration credentials are kept in an external vault.
+	externalVaultConfig := serverConfig + "\nvaultConfig:\n  type: env\n"
+	require.Nil(t, os.WriteFile(serverConfigPath, []byte(externalVaultConfig), 0o644))
+	require.Nil(t, config.Init(serverConfigPath))
+	require.Nil(t, jobManager.EditCronJob(ctx, "workflow", "1 * * * *"))
+	secret, err = k8s.GetSecret(ctx, k8s.ServerConfigSecretName, k8s.AqueductNamespace, jobManager.k8sClient)
+	require.Nil(t, err)
+	require.NotContains(t, secret[k8s.ServerConfigFileName], testK8sEncryptionKey)
+}
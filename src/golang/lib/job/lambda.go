@@ -3,26 +3,87 @@ package job
 import (
 	"context"
 	"encoding/json"
+	"math"
+	"sync"
 	"time"
 
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
 	lambda_utils "github.com/aqueducthq/aqueduct/lib/lambda"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/function"
+	"github.com/aqueducthq/aqueduct/lib/storage"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
 	"github.com/dropbox/godropbox/errors"
 	log "github.com/sirupsen/logrus"
 )
 
 const (
 	defaultLambdaFunctionExtractPath = "/tmp/app/function/"
-	updateFunctionMemoryTimeout      = 2 * time.Minute
+	updateFunctionConfigTimeout      = 2 * time.Minute
+
+	// Lambda terminates any invocation that runs for longer than this, regardless of the
+	// function's configured timeout.
+	lambdaMaxDuration = 15 * time.Minute
+	// Asynchronous invocations are queued before they run, so a job is only considered timed out
+	// once this much more time has passed.
+	lambdaQueueGracePeriod = 5 * time.Minute
 )
 
+// lambdaJob is a job that was invoked asynchronously. Lambda does not keep track of individual
+// invocations, so a job is known to have terminated once it has written its metadata to storage.
+type lambdaJob struct {
+	functionName  string
+	storageConfig *shared.StorageConfig
+	metadataPath  string
+	launchedAt    time.Time
+	// Whether the job holds a custom memory override of its function, which is released once
+	// the job terminates or is canceled.
+	overridesMemory bool
+	// Whether the job holds a timeout override of its function, which is released along with
+	// the memory override.
+	overridesTimeout bool
+}
+
+// memoryOverride is a custom memory that a lambda function is configured with for the jobs that requested it.
+type memoryOverride struct {
+	memoryMB int64
+	// The function's memory is reset back to this value once no job holds the override anymore.
+	previousMemoryMB *int64
+	numJobs          int
+}
+
+// timeoutOverride caps the timeout of a lambda function for the jobs that run on it with an operator
+// timeout, so that their invocations do not keep running long after the engine has timed them out.
+type timeoutOverride struct {
+	// The timeout, in seconds, that each job holding the override needs the function to have.
+	jobTimeouts    map[string]int64
+	timeoutSeconds int64
+	// The function's timeout is reset back to this value once no job holds the override anymore.
+	previousTimeoutSeconds *int64
+}
+
+// metadataWriter is implemented by all the job specs that can run on Lambda.
+type metadataWriter interface {
+	GetMetadataPath() string
+}
+
 type lambdaJobManager struct {
-	lambdaService *lambda.Lambda
+	lambdaService lambdaiface.LambdaAPI
 	conf          *LambdaJobManagerConfig
+
+	// jobs tracks the jobs that have been launched but have not been seen to terminate yet.
+	jobs   map[string]*lambdaJob
+	jobsMu sync.Mutex
+
+	// memoryOverrides and timeoutOverrides track the functions whose configuration is overridden on
+	// behalf of running jobs. configMu is held while a function's configuration is updated, so that
+	// overrides are serialized.
+	memoryOverrides  map[string]*memoryOverride
+	timeoutOverrides map[string]*timeoutOverride
+	configMu         sync.Mutex
 }
 
 func NewLambdaJobManager(conf *LambdaJobManagerConfig) (*lambdaJobManager, error) {
@@ -32,8 +93,11 @@ func NewLambdaJobManager(conf *LambdaJobManagerConfig) (*lambdaJobManager, error
 	lambdaSvc := lambda.New(sess)
 
 	return &lambdaJobManager{
-		lambdaService: lambdaSvc,
-		conf:          conf,
+		lambdaService:    lambdaSvc,
+		conf:             conf,
+		jobs:             map[string]*lambdaJob{},
+		memoryOverrides:  map[string]*memoryOverride{},
+		timeoutOverrides: map[string]*timeoutOverride{},
 	}, nil
 }
 
@@ -47,39 +111,76 @@ func (j *lambdaJobManager) updateFunctionMemory(
 	functionName string,
 	newMemoryMB *int64,
 ) (*int64, error) {
-	prevLambdaFnConfig, err := j.lambdaService.GetFunctionConfigurationWithContext(
+	prevLambdaFnConfig, err := j.updateFunctionConfiguration(
 		ctx,
-		&lambda.GetFunctionConfigurationInput{
+		&lambda.UpdateFunctionConfigurationInput{
 			FunctionName: &functionName,
+			MemorySize:   newMemoryMB,
+		},
+		func(fnConfig *lambda.FunctionConfiguration) bool {
+			return *fnConfig.MemorySize == *newMemoryMB
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrap(err, "Unable to query Lambda to configure custom memory.")
+		return nil, errors.Wrap(err, "Unable to update Lambda function with custom memory.")
 	}
+	return prevLambdaFnConfig.MemorySize, nil
+}
 
-	prevMemoryMB := prevLambdaFnConfig.MemorySize
-
-	latestLambdaFnConfig, err := j.lambdaService.UpdateFunctionConfigurationWithContext(
+// Updates the timeout of the given function. Returns the previous timeout setting, in seconds.
+func (j *lambdaJobManager) updateFunctionTimeout(
+	ctx context.Context,
+	functionName string,
+	newTimeoutSeconds *int64,
+) (*int64, error) {
+	prevLambdaFnConfig, err := j.updateFunctionConfiguration(
 		ctx,
 		&lambda.UpdateFunctionConfigurationInput{
 			FunctionName: &functionName,
-			MemorySize:   newMemoryMB,
+			Timeout:      newTimeoutSeconds,
+		},
+		func(fnConfig *lambda.FunctionConfiguration) bool {
+			return *fnConfig.Timeout == *newTimeoutSeconds
 		},
 	)
 	if err != nil {
-		return nil, errors.Wrap(err, "Unable to update Lambda function with custom memory.")
+		return nil, errors.Wrap(err, "Unable to update Lambda function with the operator's timeout.")
+	}
+	return prevLambdaFnConfig.Timeout, nil
+}
+
+// updateFunctionConfiguration applies the update to the function's configuration, and waits until
+// `isUpdated` reports that it has taken effect. Returns the previous configuration of the function.
+func (j *lambdaJobManager) updateFunctionConfiguration(
+	ctx context.Context,
+	update *lambda.UpdateFunctionConfigurationInput,
+	isUpdated func(*lambda.FunctionConfiguration) bool,
+) (*lambda.FunctionConfiguration, error) {
+	prevLambdaFnConfig, err := j.lambdaService.GetFunctionConfigurationWithContext(
+		ctx,
+		&lambda.GetFunctionConfigurationInput{
+			FunctionName: update.FunctionName,
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to query Lambda for the function configuration.")
+	}
+
+	latestLambdaFnConfig, err := j.lambdaService.UpdateFunctionConfigurationWithContext(ctx, update)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to update Lambda function configuration.")
 	}
 
 	// Wait for at most a few minutes for the configuration to update.
 	start := time.Now()
 	for {
-		// Check if memory has been updated yet.
+		// Check if the configuration has been updated yet.
 		if *latestLambdaFnConfig.LastUpdateStatus == lambda.LastUpdateStatusSuccessful &&
-			*latestLambdaFnConfig.MemorySize == *newMemoryMB {
+			isUpdated(latestLambdaFnConfig) {
 			break
 		} else if *latestLambdaFnConfig.LastUpdateStatus == lambda.LastUpdateStatusFailed {
 			return nil, errors.Newf(
-				"Unable to update Lambda with custom memory: %v",
+				"Unable to update Lambda function configuration: %v",
 				*latestLambdaFnConfig.LastUpdateStatusReason,
 			)
 		}
@@ -87,7 +188,7 @@ func (j *lambdaJobManager) updateFunctionMemory(
 		polledLambdaFnConfig, err := j.lambdaService.GetFunctionConfigurationWithContext(
 			ctx,
 			&lambda.GetFunctionConfigurationInput{
-				FunctionName: &functionName,
+				FunctionName: update.FunctionName,
 			},
 		)
 		if err != nil {
@@ -98,13 +199,13 @@ func (j *lambdaJobManager) updateFunctionMemory(
 
 		latestLambdaFnConfig = polledLambdaFnConfig
 
-		if time.Since(start) > updateFunctionMemoryTimeout {
-			return nil, errors.New("Unable to update Lambda function with custom memory. The operator timed out.")
+		if time.Since(start) > updateFunctionConfigTimeout {
+			return nil, errors.New("Unable to update Lambda function configuration. The operator timed out.")
 		}
 		time.Sleep(2 * time.Second)
 	}
 
-	return prevMemoryMB, nil
+	return prevLambdaFnConfig, nil
 }
 
 func (j *lambdaJobManager) Launch(ctx context.Context, name string, spec Spec) JobError {
@@ -113,8 +214,25 @@ func (j *lambdaJobManager) Launch(ctx context.Context, name string, spec Spec) J
 		return systemError(err)
 	}
 
-	// If set, we'll need to reset the function's memory back to this value after invocation.
-	var previousMemoryMB *int64
+	pythonSpec, ok := spec.(metadataWriter)
+	if !ok {
+		return systemError(errors.Newf("Job spec of type %v cannot run on Lambda.", spec.Type()))
+	}
+
+	storageConfig, err := spec.GetStorageConfig()
+	if err != nil {
+		return systemError(errors.Wrap(err, "Spec unexpectedly has no storage config."))
+	}
+	if storageConfig.S3Config != nil {
+		storageConfig.S3Config.AWSAccessKeyID = j.conf.AwsAccessKeyId
+		storageConfig.S3Config.AWSSecretAccessKey = j.conf.AwsSecretAccessKey
+	}
+
+	launchedJob := &lambdaJob{
+		functionName:  functionName,
+		storageConfig: storageConfig,
+		metadataPath:  pythonSpec.GetMetadataPath(),
+	}
 
 	if spec.Type() == FunctionJobType {
 		functionSpec, ok := spec.(*FunctionSpec)
@@ -124,73 +242,298 @@ func (j *lambdaJobManager) Launch(ctx context.Context, name string, spec Spec) J
 
 		functionSpec.FunctionExtractPath = defaultLambdaFunctionExtractPath
 
-		if functionSpec.Resources != nil {
-			if functionSpec.Resources.MemoryMB != nil {
-				// Resetting memory back to its original value is best-effort.
-				defer func() {
-					if previousMemoryMB != nil {
-						_, err = j.updateFunctionMemory(ctx, functionName, previousMemoryMB)
-						if err != nil {
-							log.Errorf("Unable to reset function memory back to %v MB: %v", previousMemoryMB, err)
-						}
-					}
-				}()
-
-				newMemoryMBInt64 := int64(*functionSpec.Resources.MemoryMB)
-				previousMemoryMB, err = j.updateFunctionMemory(ctx, functionName, &newMemoryMBInt64)
-				if err != nil {
-					return systemError(err)
-				}
+		// The custom memory stays in place until the job terminates. This does not provide
+		// perfect isolation, since other operators that use the same lambda function while
+		// this one runs get the custom memory as well.
+		if functionSpec.Resources != nil && functionSpec.Resources.MemoryMB != nil {
+			if jobErr := j.overrideFunctionMemory(ctx, functionName, int64(*functionSpec.Resources.MemoryMB)); jobErr != nil {
+				return jobErr
 			}
+			launchedJob.overridesMemory = true
 		}
 	}
 
-	storageConfig, err := spec.GetStorageConfig()
-	if err != nil {
-		return systemError(errors.Wrap(err, "Spec unexpectedly has no storage config."))
+	// The engine fails the operator once it exceeds its timeout, and the launch is bounded by the same
+	// timeout. Since an invoked Lambda function cannot be stopped, the function's timeout is capped
+	// as well, so that the invocation stops around the same time.
+	var timeoutSeconds int64
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < lambdaMaxDuration {
+		timeoutSeconds = int64(math.Ceil(time.Until(deadline).Seconds()))
+		if timeoutSeconds < 1 {
+			timeoutSeconds = 1
+		}
+	}
+
+	overridesTimeout, jobErr := j.overrideFunctionTimeout(ctx, functionName, name, timeoutSeconds)
+	if jobErr != nil {
+		j.release(name, launchedJob)
+		return jobErr
 	}
-	storageConfig.S3Config.AWSAccessKeyID = j.conf.AwsAccessKeyId
-	storageConfig.S3Config.AWSSecretAccessKey = j.conf.AwsSecretAccessKey
+	launchedJob.overridesTimeout = overridesTimeout
+
+	if err := j.invoke(ctx, functionName, spec); err != nil {
+		j.release(name, launchedJob)
+		return systemError(err)
+	}
+
+	launchedJob.launchedAt = time.Now()
+
+	j.jobsMu.Lock()
+	defer j.jobsMu.Unlock()
+	j.jobs[name] = launchedJob
+
+	return nil
+}
 
-	// Encode job spec to prevent data loss
+// invoke invokes the lambda function asynchronously, so that the caller is not blocked
+// for the duration of the job.
+func (j *lambdaJobManager) invoke(ctx context.Context, functionName string, spec Spec) error {
+	// Encode job spec to prevent data loss. The payload of an invocation is only handed to the function,
+	// rather than being part of its configuration, so it can include the storage keys.
 	serializationType := JsonSerializationType
-	encodedSpec, err := EncodeSpec(spec, serializationType)
+	encodedSpec, err := encodeSpec(spec, serializationType, true /* withStorageKeys */)
 	if err != nil {
-		return systemError(err)
+		return err
 	}
 
 	lambdaFunctionRequest := map[string]string{"Spec": encodedSpec}
 	payload, err := json.Marshal(lambdaFunctionRequest)
 	if err != nil {
-		return systemError(errors.Wrap(err, "Unable to marshal request payload."))
-	}
-
-	// Lambda functions with custom memory configurations should be executed synchronously.
-	// This is to prevent such memory configurations from bleeding out to other operators using
-	// the same lambda function. We reset the memory configuration at the very end of this function.
-	// NOTE: this does not provide perfect isolation. It is still possible for operators scheduled
-	// before to race with the memory configuration update, since regular lambda functions are run
-	// asynchronously, and we have no visibility into the AWS's event queue.
-	invocationType := aws.String("Event")
-	if previousMemoryMB != nil {
-		invocationType = aws.String("RequestResponse")
+		return errors.Wrap(err, "Unable to marshal request payload.")
 	}
 
 	invokeInput := &lambda.InvokeInput{
 		FunctionName:   &functionName,
-		InvocationType: invocationType,
+		InvocationType: aws.String(lambda.InvocationTypeEvent),
 		Payload:        payload,
 	}
 
 	_, err = j.lambdaService.InvokeWithContext(ctx, invokeInput)
 	if err != nil {
-		return systemError(errors.Wrap(err, "Unable to invoke lambda function."))
+		return errors.Wrap(err, "Unable to invoke lambda function.")
 	}
 	return nil
 }
 
+// Poll determines the status of the job from the metadata that it writes to storage when it
+// terminates. A job that has not written its metadata within Lambda's time limit has timed out.
 func (j *lambdaJobManager) Poll(ctx context.Context, name string) (shared.ExecutionStatus, JobError) {
-	return shared.UnknownExecutionStatus, noopError(errors.New("Cannot poll a lambda job manager."))
+	j.jobsMu.Lock()
+	launchedJob, ok := j.jobs[name]
+	j.jobsMu.Unlock()
+
+	if !ok {
+		return shared.UnknownExecutionStatus, jobMissingError(errors.Newf("Lambda job %s does not exist.", name))
+	}
+
+	serializedExecState, err := storage.NewStorage(launchedJob.storageConfig).Get(ctx, launchedJob.metadataPath)
+	if err != nil && !aq_errors.Is(err, storage.ErrObjectDoesNotExist()) {
+		return shared.UnknownExecutionStatus, systemError(errors.Wrap(err, "Unable to read job metadata from storage."))
+	}
+
+	if err == nil {
+		var execState shared.ExecutionState
+		if err := json.Unmarshal(serializedExecState, &execState); err != nil {
+			return shared.UnknownExecutionStatus, systemError(errors.Wrap(err, "Unable to parse job metadata."))
+		}
+
+		if !execState.Terminated() {
+			return shared.RunningExecutionStatus, nil
+		}
+
+		j.finish(name, launchedJob)
+		if execState.Status == shared.SucceededExecutionStatus {
+			return shared.SucceededExecutionStatus, nil
+		}
+		return shared.FailedExecutionStatus, nil
+	}
+
+	if time.Since(launchedJob.launchedAt) > lambdaMaxDuration+lambdaQueueGracePeriod {
+		j.finish(name, launchedJob)
+		return shared.FailedExecutionStatus, userError(errors.Newf(
+			"Operator did not finish on Lambda within its %v time limit. "+
+				"It may also have run out of memory, which can be configured in the operator's resources.",
+			lambdaMaxDuration,
+		))
+	}
+
+	// Lambda does not report whether an asynchronous invocation is still queued.
+	return shared.RunningExecutionStatus, nil
+}
+
+// finish stops tracking a job that has terminated or was canceled, and releases its overrides.
+// Only the first call for a job has an effect.
+func (j *lambdaJobManager) finish(name string, launchedJob *lambdaJob) {
+	j.jobsMu.Lock()
+	if j.jobs[name] != launchedJob {
+		j.jobsMu.Unlock()
+		return
+	}
+	delete(j.jobs, name)
+	j.jobsMu.Unlock()
+
+	j.release(name, launchedJob)
+}
+
+// release releases the overrides of its function's configuration that the job holds.
+func (j *lambdaJobManager) release(name string, launchedJob *lambdaJob) {
+	if launchedJob.overridesMemory {
+		j.releaseFunctionMemory(launchedJob.functionName)
+	}
+	if launchedJob.overridesTimeout {
+		j.releaseFunctionTimeout(launchedJob.functionName, name)
+	}
+}
+
+// overrideFunctionMemory configures the function with memoryMB until releaseFunctionMemory is called.
+// Jobs that run concurrently on the same function share the override, so they must all request the
+// same memory. Otherwise, whichever job terminates first would take the memory away from the others.
+func (j *lambdaJobManager) overrideFunctionMemory(ctx context.Context, functionName string, memoryMB int64) JobError {
+	j.configMu.Lock()
+	defer j.configMu.Unlock()
+
+	if override, ok := j.memoryOverrides[functionName]; ok {
+		if override.memoryMB != memoryMB {
+			return userError(errors.Newf(
+				"Unable to run the operator with %v MB of memory on Lambda, since another operator is running "+
+					"on the same Lambda function with %v MB. Operators that run concurrently on Lambda must request the same memory.",
+				memoryMB,
+				override.memoryMB,
+			))
+		}
+		override.numJobs++
+		return nil
+	}
+
+	previousMemoryMB, err := j.updateFunctionMemory(ctx, functionName, &memoryMB)
+	if err != nil {
+		return systemError(err)
+	}
+
+	j.memoryOverrides[functionName] = &memoryOverride{
+		memoryMB:         memoryMB,
+		previousMemoryMB: previousMemoryMB,
+		numJobs:          1,
+	}
+	return nil
+}
+
+// releaseFunctionMemory releases a job's memory override of the function, and resets the function's
+// memory back to its previous value once no job holds the override anymore. This is best-effort, and
+// uses a fresh context since the caller's context may have been canceled because the operator timed out.
+func (j *lambdaJobManager) releaseFunctionMemory(functionName string) {
+	j.configMu.Lock()
+	defer j.configMu.Unlock()
+
+	override, ok := j.memoryOverrides[functionName]
+	if !ok {
+		return
+	}
+
+	override.numJobs--
+	if override.numJobs > 0 {
+		return
+	}
+	delete(j.memoryOverrides, functionName)
+
+	_, err := j.updateFunctionMemory(context.Background(), functionName, override.previousMemoryMB)
+	if err != nil {
+		log.Errorf("Unable to reset function memory back to %v MB: %v", *override.previousMemoryMB, err)
+	}
+}
+
+// overrideFunctionTimeout caps the function's timeout at timeoutSeconds for the job with the given name,
+// until releaseFunctionTimeout is called. It returns whether the job holds the override. Jobs that run
+// concurrently on the same function share the override, so the function's timeout is the longest one
+// that any of them needs. A job without a timeout (timeoutSeconds is 0) only holds an existing override,
+// with the function's previous timeout. This does not provide perfect isolation, since a job without a
+// timeout that was launched before the override may still be queued when the function's timeout is capped.
+func (j *lambdaJobManager) overrideFunctionTimeout(
+	ctx context.Context,
+	functionName string,
+	name string,
+	timeoutSeconds int64,
+) (bool, JobError) {
+	j.configMu.Lock()
+	defer j.configMu.Unlock()
+
+	override, ok := j.timeoutOverrides[functionName]
+	if timeoutSeconds == 0 {
+		if !ok || override.previousTimeoutSeconds == nil {
+			return false, nil
+		}
+		timeoutSeconds = *override.previousTimeoutSeconds
+	}
+
+	if !ok {
+		override = &timeoutOverride{jobTimeouts: map[string]int64{}}
+	}
+	override.jobTimeouts[name] = timeoutSeconds
+
+	if !ok || timeoutSeconds > override.timeoutSeconds {
+		previousTimeoutSeconds, err := j.updateFunctionTimeout(ctx, functionName, &timeoutSeconds)
+		if err != nil {
+			delete(override.jobTimeouts, name)
+			return false, systemError(err)
+		}
+
+		if !ok {
+			override.previousTimeoutSeconds = previousTimeoutSeconds
+			j.timeoutOverrides[functionName] = override
+		}
+		override.timeoutSeconds = timeoutSeconds
+	}
+	return true, nil
+}
+
+// releaseFunctionTimeout releases a job's timeout override of the function. The function's timeout is
+// lowered to the longest one that the remaining jobs need, or reset back to its previous value once no job
+// holds the override anymore. Like releaseFunctionMemory, this is best-effort and uses a fresh context.
+func (j *lambdaJobManager) releaseFunctionTimeout(functionName string, name string) {
+	j.configMu.Lock()
+	defer j.configMu.Unlock()
+
+	override, ok := j.timeoutOverrides[functionName]
+	if !ok {
+		return
+	}
+	delete(override.jobTimeouts, name)
+
+	timeoutSeconds := override.previousTimeoutSeconds
+	if len(override.jobTimeouts) == 0 {
+		delete(j.timeoutOverrides, functionName)
+	} else {
+		var longestTimeoutSeconds int64
+		for _, jobTimeoutSeconds := range override.jobTimeouts {
+			if jobTimeoutSeconds > longestTimeoutSeconds {
+				longestTimeoutSeconds = jobTimeoutSeconds
+			}
+		}
+		if longestTimeoutSeconds == override.timeoutSeconds {
+			return
+		}
+		override.timeoutSeconds = longestTimeoutSeconds
+		timeoutSeconds = &longestTimeoutSeconds
+	}
+
+	_, err := j.updateFunctionTimeout(context.Background(), functionName, timeoutSeconds)
+	if err != nil {
+		log.Errorf("Unable to reset function timeout back to %v seconds: %v", *timeoutSeconds, err)
+	}
+}
+
+// Cancel stops tracking the job and releases its overrides, but it is otherwise a noop because an
+// invoked Lambda function cannot be stopped. The invocation will run until it completes or hits the
+// function's timeout.
+func (j *lambdaJobManager) Cancel(ctx context.Context, name string) JobError {
+	j.jobsMu.Lock()
+	launchedJob, ok := j.jobs[name]
+	j.jobsMu.Unlock()
+
+	if ok {
+		j.finish(name, launchedJob)
+	}
+	return noopError(errors.New("Cannot cancel a lambda job."))
 }
 
 func (j *lambdaJobManager) DeployCronJob(ctx context.Context, name string, period string, spec Spec) JobError {
@@ -1,16 +1,375 @@
 package job
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	lambda_utils "github.com/aqueducthq/aqueduct/lib/lambda"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
 	"github.com/stretchr/testify/require"
 )
 
+// fakeLambdaClient records invocations instead of running them, and keeps track of the
+// memory and timeout of each function. Only the methods used by lambdaJobManager are implemented.
+type fakeLambdaClient struct {
+	lambdaiface.LambdaAPI
+
+	invocations    []*lambda.InvokeInput
+	memoryMB       map[string]int64
+	timeoutSeconds map[string]int64
+}
+
+func newFakeLambdaClient() *fakeLambdaClient {
+	return &fakeLambdaClient{
+		memoryMB:       map[string]int64{},
+		timeoutSeconds: map[string]int64{},
+	}
+}
+
+func (c *fakeLambdaClient) InvokeWithContext(
+	ctx aws.Context,
+	input *lambda.InvokeInput,
+	opts ...request.Option,
+) (*lambda.InvokeOutput, error) {
+	c.invocations = append(c.invocations, input)
+	return &lambda.InvokeOutput{StatusCode: aws.Int64(202)}, nil
+}
+
+func (c *fakeLambdaClient) GetFunctionConfigurationWithContext(
+	ctx aws.Context,
+	input *lambda.GetFunctionConfigurationInput,
+	opts ...request.Option,
+) (*lambda.FunctionConfiguration, error) {
+	return c.functionConfiguration(*input.FunctionName), nil
+}
+
+func (c *fakeLambdaClient) UpdateFunctionConfigurationWithContext(
+	ctx aws.Context,
+	input *lambda.UpdateFunctionConfigurationInput,
+	opts ...request.Option,
+) (*lambda.FunctionConfiguration, error) {
+	if input.MemorySize != nil {
+		c.memoryMB[*input.FunctionName] = *input.MemorySize
+	}
+	if input.Timeout != nil {
+		c.timeoutSeconds[*input.FunctionName] = *input.Timeout
+	}
+	return c.functionConfiguration(*input.FunctionName), nil
+}
+
+func (c *fakeLambdaClient) functionConfiguration(functionName string) *lambda.FunctionConfiguration {
+	memoryMB, ok := c.memoryMB[functionName]
+	if !ok {
+		memoryMB = 128
+	}
+	timeoutSeconds, ok := c.timeoutSeconds[functionName]
+	if !ok {
+		timeoutSeconds = 900
+	}
+
+	return &lambda.FunctionConfiguration{
+		FunctionName:     aws.String(functionName),
+		MemorySize:       aws.Int64(memoryMB),
+		Timeout:          aws.Int64(timeoutSeconds),
+		LastUpdateStatus: aws.String(lambda.LastUpdateStatusSuccessful),
+	}
+}
+
+func newFakeLambdaJobManager(client *fakeLambdaClient) *lambdaJobManager {
+	return &lambdaJobManager{
+		lambdaService:    client,
+		conf:             &LambdaJobManagerConfig{},
+		jobs:             map[string]*lambdaJob{},
+		memoryOverrides:  map[string]*memoryOverride{},
+		timeoutOverrides: map[string]*timeoutOverride{},
+	}
+}
+
+func newTestLambdaStorageConfig(t *testing.T) shared.StorageConfig {
+	return shared.StorageConfig{
+		Type: shared.FileStorageType,
+		FileConfig: &shared.FileConfig{
+			Directory: t.TempDir(),
+		},
+	}
+}
+
+// putTestFunction writes zipped function code for Python 3.8 to `path`, since the
+// Python version of a function is read from its zipped code.
+func putTestFunction(t *testing.T, storageConfig shared.StorageConfig, path string) {
+	var program bytes.Buffer
+	zipWriter := zip.NewWriter(&program)
+	versionFile, err := zipWriter.Create("function/python_version.txt")
+	require.Nil(t, err)
+	_, err = versionFile.Write([]byte("3.8"))
+	require.Nil(t, err)
+	require.Nil(t, zipWriter.Close())
+	require.Nil(t, storage.NewStorage(&storageConfig).Put(context.Background(), path, program.Bytes()))
+}
+
+func newTestParamSpec(storageConfig shared.StorageConfig) *ParamSpec {
+	return &ParamSpec{
+		BasePythonSpec: BasePythonSpec{
+			BaseSpec: BaseSpec{
+				Type: ParamJobType,
+				Name: "param",
+			},
+			StorageConfig: storageConfig,
+			MetadataPath:  "param-metadata",
+		},
+	}
+}
+
+func writeTestExecState(t *testing.T, storageConfig shared.StorageConfig, path string, status shared.ExecutionStatus) {
+	serializedExecState, err := json.Marshal(&shared.ExecutionState{Status: status})
+	require.Nil(t, err)
+	require.Nil(t, storage.NewStorage(&storageConfig).Put(context.Background(), path, serializedExecState))
+}
+
+func TestLambdaPoll(t *testing.T) {
+	client := newFakeLambdaClient()
+	jobManager := newFakeLambdaJobManager(client)
+	ctx := context.Background()
+
+	storageConfig := newTestLambdaStorageConfig(t)
+	spec := newTestParamSpec(storageConfig)
+
+	err := jobManager.Launch(ctx, "param", spec)
+	require.Nil(t, err)
+
+	// The function is invoked asynchronously.
+	require.Equal(t, 1, len(client.invocations))
+	require.Equal(t, lambda.InvocationTypeEvent, *client.invocations[0].InvocationType)
+	require.Equal(t, lambda_utils.ParameterLambdaFunction, *client.invocations[0].FunctionName)
+
+	// The job is running until it writes its metadata.
+	status, jobErr := jobManager.Poll(ctx, "param")
+	require.Nil(t, jobErr)
+	require.Equal(t, shared.RunningExecutionStatus, status)
+
+	writeTestExecState(t, storageConfig, spec.MetadataPath, shared.SucceededExecutionStatus)
+
+	status, jobErr = jobManager.Poll(ctx, "param")
+	require.Nil(t, jobErr)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+
+	// The job is no longer tracked once it has terminated.
+	_, jobErr = jobManager.Poll(ctx, "param")
+	require.NotNil(t, jobErr)
+	require.Equal(t, JobMissing, jobErr.Code())
+}
+
+func TestLambdaPollFailed(t *testing.T) {
+	jobManager := newFakeLambdaJobManager(newFakeLambdaClient())
+	ctx := context.Background()
+
+	storageConfig := newTestLambdaStorageConfig(t)
+	spec := newTestParamSpec(storageConfig)
+	require.Nil(t, jobManager.Launch(ctx, "param", spec))
+
+	writeTestExecState(t, storageConfig, spec.MetadataPath, shared.FailedExecutionStatus)
+
+	status, jobErr := jobManager.Poll(ctx, "param")
+	require.Nil(t, jobErr)
+	require.Equal(t, shared.FailedExecutionStatus, status)
+}
+
+func TestLambdaPollTimeout(t *testing.T) {
+	jobManager := newFakeLambdaJobManager(newFakeLambdaClient())
+	ctx := context.Background()
+
+	spec := newTestParamSpec(newTestLambdaStorageConfig(t))
+	require.Nil(t, jobManager.Launch(ctx, "param", spec))
+
+	// Simulate a job that was launched longer ago than Lambda lets any invocation run.
+	jobManager.jobs["param"].launchedAt = time.Now().Add(-(lambdaMaxDuration + lambdaQueueGracePeriod + time.Minute))
+
+	status, jobErr := jobManager.Poll(ctx, "param")
+	require.NotNil(t, jobErr)
+	require.Equal(t, User, jobErr.Code())
+	require.Equal(t, shared.FailedExecutionStatus, status)
+}
+
+func newTestLambdaFunctionSpec(storageConfig shared.StorageConfig, name string, memoryMB int) *FunctionSpec {
+	return &FunctionSpec{
+		BasePythonSpec: BasePythonSpec{
+			BaseSpec: BaseSpec{
+				Type: FunctionJobType,
+				Name: name,
+			},
+			StorageConfig: storageConfig,
+			MetadataPath:  name + "-metadata",
+		},
+		FunctionPath: "function",
+		Resources: &operator.ResourceConfig{
+			MemoryMB: &memoryMB,
+		},
+	}
+}
+
+func TestLambdaFunctionMemory(t *testing.T) {
+	client := newFakeLambdaClient()
+	jobManager := newFakeLambdaJobManager(client)
+	ctx := context.Background()
+
+	storageConfig := newTestLambdaStorageConfig(t)
+
+	putTestFunction(t, storageConfig, "function")
+
+	spec := newTestLambdaFunctionSpec(storageConfig, "function", 1024)
+
+	functionName := lambda_utils.FunctionLambdaFunction38
+	client.memoryMB[functionName] = 512
+
+	require.Nil(t, jobManager.Launch(ctx, "function", spec))
+	require.Equal(t, int64(1024), client.memoryMB[functionName])
+
+	// The memory is kept until the job terminates.
+	status, jobErr := jobManager.Poll(ctx, "function")
+	require.Nil(t, jobErr)
+	require.Equal(t, shared.RunningExecutionStatus, status)
+	require.Equal(t, int64(1024), client.memoryMB[functionName])
+
+	writeTestExecState(t, storageConfig, spec.MetadataPath, shared.SucceededExecutionStatus)
+
+	status, jobErr = jobManager.Poll(ctx, "function")
+	require.Nil(t, jobErr)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+	require.Equal(t, int64(512), client.memoryMB[functionName])
+}
+
+func TestLambdaCancelFunctionMemory(t *testing.T) {
+	client := newFakeLambdaClient()
+	jobManager := newFakeLambdaJobManager(client)
+	ctx := context.Background()
+
+	storageConfig := newTestLambdaStorageConfig(t)
+	putTestFunction(t, storageConfig, "function")
+
+	functionName := lambda_utils.FunctionLambdaFunction38
+	client.memoryMB[functionName] = 512
+
+	require.Nil(t, jobManager.Launch(ctx, "function", newTestLambdaFunctionSpec(storageConfig, "function", 1024)))
+	require.Equal(t, int64(1024), client.memoryMB[functionName])
+
+	// Canceling a job resets the memory and stops tracking the job, even though the invocation keeps running.
+	jobErr := jobManager.Cancel(ctx, "function")
+	require.NotNil(t, jobErr)
+	require.Equal(t, Noop, jobErr.Code())
+	require.Equal(t, int64(512), client.memoryMB[functionName])
+	require.Empty(t, jobManager.jobs)
+
+	_, jobErr = jobManager.Poll(ctx, "function")
+	require.NotNil(t, jobErr)
+	require.Equal(t, JobMissing, jobErr.Code())
+}
+
+func TestLambdaConcurrentFunctionMemory(t *testing.T) {
+	client := newFakeLambdaClient()
+	jobManager := newFakeLambdaJobManager(client)
+	ctx := context.Background()
+
+	storageConfig := newTestLambdaStorageConfig(t)
+	putTestFunction(t, storageConfig, "function")
+
+	functionName := lambda_utils.FunctionLambdaFunction38
+	client.memoryMB[functionName] = 512
+
+	first := newTestLambdaFunctionSpec(storageConfig, "first", 1024)
+	second := newTestLambdaFunctionSpec(storageConfig, "second", 1024)
+	require.Nil(t, jobManager.Launch(ctx, "first", first))
+	require.Nil(t, jobManager.Launch(ctx, "second", second))
+
+	// A job cannot override the memory of a function that runs with another custom memory.
+	jobErr := jobManager.Launch(ctx, "third", newTestLambdaFunctionSpec(storageConfig, "third", 2048))
+	require.NotNil(t, jobErr)
+	require.Equal(t, User, jobErr.Code())
+	require.Equal(t, int64(1024), client.memoryMB[functionName])
+
+	// The memory is only reset once all the jobs that share it have terminated.
+	writeTestExecState(t, storageConfig, first.MetadataPath, shared.SucceededExecutionStatus)
+	status, jobErr := jobManager.Poll(ctx, "first")
+	require.Nil(t, jobErr)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+	require.Equal(t, int64(1024), client.memoryMB[functionName])
+
+	writeTestExecState(t, storageConfig, second.MetadataPath, shared.SucceededExecutionStatus)
+	status, jobErr = jobManager.Poll(ctx, "second")
+	require.Nil(t, jobErr)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+	require.Equal(t, int64(512), client.memoryMB[functionName])
+
+	// Canceling a job that has terminated has no effect on the function's memory.
+	require.Nil(t, jobManager.Launch(ctx, "third", newTestLambdaFunctionSpec(storageConfig, "third", 2048)))
+	require.Equal(t, Noop, jobManager.Cancel(ctx, "first").Code())
+	require.Equal(t, int64(2048), client.memoryMB[functionName])
+}
+
+func TestLambdaFunctionTimeout(t *testing.T) {
+	client := newFakeLambdaClient()
+	jobManager := newFakeLambdaJobManager(client)
+	ctx := context.Background()
+
+	storageConfig := newTestLambdaStorageConfig(t)
+	functionName := lambda_utils.ParameterLambdaFunction
+
+	// The launch of an operator with a timeout is bounded by it.
+	launchWithTimeout := func(name string, timeout time.Duration) *ParamSpec {
+		launchCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		spec := newTestParamSpec(storageConfig)
+		spec.MetadataPath = name + "-metadata"
+		require.Nil(t, jobManager.Launch(launchCtx, name, spec))
+		return spec
+	}
+
+	short := launchWithTimeout("short", time.Minute)
+	require.Equal(t, int64(60), client.timeoutSeconds[functionName])
+
+	// Concurrent jobs get the longest timeout that any of them needs, including the ones without a timeout.
+	launchWithTimeout("long", 2*time.Minute)
+	require.Equal(t, int64(120), client.timeoutSeconds[functionName])
+
+	unbounded := newTestParamSpec(storageConfig)
+	unbounded.MetadataPath = "unbounded-metadata"
+	require.Nil(t, jobManager.Launch(ctx, "unbounded", unbounded))
+	require.Equal(t, int64(900), client.timeoutSeconds[functionName])
+
+	writeTestExecState(t, storageConfig, unbounded.MetadataPath, shared.SucceededExecutionStatus)
+	_, jobErr := jobManager.Poll(ctx, "unbounded")
+	require.Nil(t, jobErr)
+	require.Equal(t, int64(120), client.timeoutSeconds[functionName])
+
+	// Timing out a job releases its timeout, and the timeout is reset once no job needs it anymore.
+	require.Equal(t, Noop, jobManager.Cancel(ctx, "long").Code())
+	require.Equal(t, int64(60), client.timeoutSeconds[functionName])
+
+	writeTestExecState(t, storageConfig, short.MetadataPath, shared.SucceededExecutionStatus)
+	_, jobErr = jobManager.Poll(ctx, "short")
+	require.Nil(t, jobErr)
+	require.Equal(t, int64(900), client.timeoutSeconds[functionName])
+	require.Empty(t, jobManager.timeoutOverrides)
+
+	// A job without a timeout does not change the function's timeout.
+	client.timeoutSeconds[functionName] = 300
+	require.Nil(t, jobManager.Launch(ctx, "unbounded", unbounded))
+	require.Equal(t, int64(300), client.timeoutSeconds[functionName])
+	require.Empty(t, jobManager.timeoutOverrides)
+}
+
 func TestLambdaAPI(t *testing.T) {
 	t.Skip("This is not really a unit test since it relies on AWS Lambda. Can be manually unskipped.")
 
@@ -9,13 +9,14 @@ import (
 	"path"
 	"path/filepath"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/storage"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/go-co-op/gocron"
 	"github.com/google/uuid"
-	"github.com/shirou/gopsutil/process"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -28,8 +29,6 @@ const (
 	executorBinary               = "executor"
 	functionExecutorBashScript   = "start-function-executor.sh"
 
-	processRunningStatus = "R"
-
 	BinaryDir          = "bin/"
 	OperatorStorageDir = "storage/operators/"
 	LogsDir            = "logs/"
@@ -45,6 +44,25 @@ type Command struct {
 	cmd    *exec.Cmd
 	stdout *bytes.Buffer
 	stderr *bytes.Buffer
+
+	// done is closed once the process has exited, at which point waitErr is set.
+	done    chan struct{}
+	waitErr error
+}
+
+// wait reaps the process once it exits and then closes `done`.
+func (c *Command) wait() {
+	c.waitErr = c.cmd.Wait()
+	close(c.done)
+}
+
+func (c *Command) exited() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
 }
 
 type cronMetadata struct {
@@ -192,6 +210,27 @@ func (j *ProcessJobManager) mapJobTypeToCmd(jobName string, spec Spec) (*exec.Cm
 		logFilePath := path.Join(defaultLogsDir, jobName)
 		log.Infof("Logs for job %s are stored in %s", jobName, logFilePath)
 
+		cmd = exec.Command(
+			fmt.Sprintf("%s/%s", j.conf.BinaryDir, executorBinary),
+			"--spec",
+			specStr,
+			"--logs-path",
+			logFilePath,
+		)
+	} else if spec.Type() == StorageGCType {
+		storageGCSpec, ok := spec.(*StorageGCSpec)
+		if !ok {
+			return nil, errors.New("Unable to cast job spec to storageGCSpec.")
+		}
+
+		specStr, err := EncodeSpec(storageGCSpec, GobSerializationType)
+		if err != nil {
+			return nil, err
+		}
+
+		logFilePath := path.Join(defaultLogsDir, jobName)
+		log.Infof("Logs for job %s are stored in %s", jobName, logFilePath)
+
 		cmd = exec.Command(
 			fmt.Sprintf("%s/%s", j.conf.BinaryDir, executorBinary),
 			"--spec",
@@ -328,20 +367,48 @@ func (j *ProcessJobManager) Launch(
 	}
 	cmd.Env = os.Environ()
 
+	// The spec is part of the command line, which is visible to every user of the machine.
+	storageKeys, err := jobStorageKeys(spec)
+	if err != nil {
+		return systemError(err)
+	}
+	if storageKeys != "" {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", storage.KeysEnvVar, storageKeys))
+	}
+
+	err = j.start(name, cmd)
+	if err != nil {
+		return systemError(err)
+	}
+	return nil
+}
+
+// start runs `cmd` as the job with the given name, and watches for it to exit.
+func (j *ProcessJobManager) start(name string, cmd *exec.Cmd) error {
+	// Run the job in its own process group, so that canceling it also stops
+	// any child processes it spawned (eg. conda or bash wrappers).
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
 	stdout := &bytes.Buffer{}
 	stderr := &bytes.Buffer{}
-	j.setCmd(name, &Command{
+	command := &Command{
 		cmd:    cmd,
 		stdout: stdout,
 		stderr: stderr,
-	})
+		done:   make(chan struct{}),
+	}
+	j.setCmd(name, command)
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
 
-	err = cmd.Start()
+	err := cmd.Start()
 	if err != nil {
-		return systemError(err)
+		j.deleteCmd(name)
+		return err
 	}
+
+	// Watch for the process to exit, so that Watch() callers are notified immediately.
+	go command.wait()
 	return nil
 }
 
@@ -351,25 +418,14 @@ func (j *ProcessJobManager) Poll(ctx context.Context, name string) (shared.Execu
 		return shared.UnknownExecutionStatus, jobMissingError(errors.Newf("Job %s does not exist.", name))
 	}
 
-	proc, err := process.NewProcess(int32(command.cmd.Process.Pid))
-	if err != nil {
-		return shared.UnknownExecutionStatus, systemError(err)
-	}
-
-	status, err := proc.Status()
-	if err != nil {
-		return shared.UnknownExecutionStatus, systemError(err)
-	}
-
-	if status == processRunningStatus {
+	if !command.exited() {
 		return shared.RunningExecutionStatus, nil
 	}
 
-	err = command.cmd.Wait()
-	// After wait, we are done with this job and already consumed all of its output, so we garbage
-	// collect the entry in j.cmds.
+	// The process has exited, so we are done with this job and already consumed all of its output.
+	// We garbage collect the entry in j.cmds.
 	defer j.deleteCmd(name)
-	if err != nil {
+	if err := command.waitErr; err != nil {
 		log.Errorf("Unexpected error occurred while executing job %s: %v. Stdout: \n %s \n Stderr: \n %s",
 			name,
 			err,
@@ -387,6 +443,35 @@ func (j *ProcessJobManager) Poll(ctx context.Context, name string) (shared.Execu
 	return shared.SucceededExecutionStatus, nil
 }
 
+func (j *ProcessJobManager) Cancel(ctx context.Context, name string) JobError {
+	command, ok := j.getCmd(name)
+	if !ok {
+		return jobMissingError(errors.Newf("Job %s does not exist.", name))
+	}
+
+	// A negative pid sends the signal to the entire process group.
+	err := syscall.Kill(-command.cmd.Process.Pid, syscall.SIGKILL)
+	if err != nil && err != syscall.ESRCH {
+		return systemError(errors.Wrapf(err, "Unable to kill job %s.", name))
+	}
+
+	// Wait for the process to be reaped, the error is expected since it was killed.
+	<-command.done
+	j.deleteCmd(name)
+
+	log.Infof("Canceled job %s.", name)
+	return nil
+}
+
+func (j *ProcessJobManager) Watch(ctx context.Context, name string) (<-chan struct{}, JobError) {
+	command, ok := j.getCmd(name)
+	if !ok {
+		return nil, jobMissingError(errors.Newf("Job %s does not exist.", name))
+	}
+
+	return command.done, nil
+}
+
 func (j *ProcessJobManager) DeployCronJob(
 	ctx context.Context,
 	name string,
@@ -2,8 +2,11 @@ package job
 
 import (
 	"context"
+	"os/exec"
 	"testing"
+	"time"
 
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/go-co-op/gocron"
 	"github.com/stretchr/testify/require"
 )
@@ -91,3 +94,44 @@ func TestDeleteCronJob(t *testing.T) {
 	require.Equal(t, 0, len(jobManager.cronMapping))
 	require.Equal(t, 0, len(jobManager.cronScheduler.Jobs()))
 }
+
+func TestWatch(t *testing.T) {
+	jobManager, err := NewProcessJobManager(dummyProcessConfig)
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	_, jobErr := jobManager.Watch(ctx, "missing")
+	require.Equal(t, JobMissing, jobErr.Code())
+
+	err = jobManager.start("succeeded", exec.Command("sleep", "0.1"))
+	require.Nil(t, err)
+
+	done, jobErr := jobManager.Watch(ctx, "succeeded")
+	require.Nil(t, jobErr)
+
+	status, jobErr := jobManager.Poll(ctx, "succeeded")
+	require.Nil(t, jobErr)
+	require.Equal(t, shared.RunningExecutionStatus, status)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the job to complete.")
+	}
+
+	status, jobErr = jobManager.Poll(ctx, "succeeded")
+	require.Nil(t, jobErr)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+
+	err = jobManager.start("failed", exec.Command("false"))
+	require.Nil(t, err)
+
+	done, jobErr = jobManager.Watch(ctx, "failed")
+	require.Nil(t, jobErr)
+	<-done
+
+	status, jobErr = jobManager.Poll(ctx, "failed")
+	require.Nil(t, jobErr)
+	require.Equal(t, shared.FailedExecutionStatus, status)
+}
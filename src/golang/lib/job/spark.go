@@ -2,12 +2,15 @@ package job
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"path"
 	"time"
 
+	"github.com/aqueducthq/aqueduct/config"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/spark"
+	"github.com/aqueducthq/aqueduct/lib/storage"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
@@ -25,14 +28,34 @@ type SparkJobManager struct {
 func NewSparkJobManager(conf *SparkJobManagerConfig) (*SparkJobManager, error) {
 	livyClient := spark.NewLivyClient(conf.LivyServerURL)
 
+	sessionConf := map[string]string{
+		"spark.yarn.appMasterEnv.PYSPARK_PYTHON": "./environment/bin/python",
+		"spark.jars.packages":                    "net.snowflake:snowflake-jdbc:3.13.28,net.snowflake:spark-snowflake_2.12:2.11.1-spark_3.3",
+	}
+
+	// The specs are part of the code of the statements, which is visible to anyone who can read the
+	// session, so the storage keys are passed through the environment instead, and redacted from the UI.
+	if config.Storage().Encrypt {
+		storageKeys, err := storage.CurrentKeys()
+		if err != nil {
+			return nil, err
+		}
+
+		serializedKeys, err := json.Marshal(storageKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		sessionConf["spark.yarn.appMasterEnv."+storage.KeysEnvVar] = string(serializedKeys)
+		sessionConf["spark.executorEnv."+storage.KeysEnvVar] = string(serializedKeys)
+		sessionConf["spark.redaction.regex"] = "(?i)secret|password|token|access[.]?key|" + storage.KeysEnvVar
+	}
+
 	session, err := livyClient.CreateSession(&spark.CreateSessionRequest{
 		Kind:                     "pyspark",
 		HeartbeatTimeoutInSecond: 10,
 		Archives:                 []string{fmt.Sprintf("%s#environment", conf.EnvironmentPathURI)},
-		Conf: map[string]string{
-			"spark.yarn.appMasterEnv.PYSPARK_PYTHON": "./environment/bin/python",
-			"spark.jars.packages":                    "net.snowflake:snowflake-jdbc:3.13.28,net.snowflake:spark-snowflake_2.12:2.11.1-spark_3.3",
-		},
+		Conf:                     sessionConf,
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "Error creating session on spark.")
@@ -100,6 +123,18 @@ func (j *SparkJobManager) Poll(ctx context.Context, name string) (shared.Executi
 	return shared.UnknownExecutionStatus, nil
 }
 
+func (j *SparkJobManager) Cancel(ctx context.Context, name string) JobError {
+	statementID, ok := j.runMap[name]
+	if !ok {
+		return jobMissingError(errors.New("Job doesn't exist."))
+	}
+
+	if err := j.livyClient.CancelStatement(j.sessionID, statementID); err != nil {
+		return systemError(errors.Wrap(err, "Unable to cancel statement on spark."))
+	}
+	return nil
+}
+
 func (j *SparkJobManager) DeployCronJob(
 	ctx context.Context,
 	name string,
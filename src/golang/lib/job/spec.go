@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"encoding/gob"
 	"encoding/json"
+	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
@@ -12,6 +13,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/check"
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/connector"
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/param"
+	"github.com/aqueducthq/aqueduct/lib/storage"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/auth"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/github"
 	"github.com/dropbox/godropbox/errors"
@@ -25,6 +27,7 @@ type JobType string
 const (
 	WorkflowRetentionName = "workflowretentionjob"
 	DynamicTeardownName   = "dynamicteardownjob"
+	StorageGCName         = "storagegcjob"
 )
 
 type SerializationType string
@@ -48,6 +51,7 @@ const (
 	WorkflowRetentionType     JobType = "workflow_retention"
 	CompileAirflowJobType     JobType = "compile_airflow"
 	DynamicTeardownType       JobType = "dynamic_teardown"
+	StorageGCType             JobType = "storage_gc"
 )
 
 // `ExecutorConfiguration` represents the configuration variables that are
@@ -103,6 +107,23 @@ func (wrs *WorkflowRetentionSpec) GetStorageConfig() (*shared.StorageConfig, err
 	return nil, errors.New("WorkflowRetention job specs don't have a storage config.")
 }
 
+type StorageGCSpec struct {
+	BaseSpec
+	ExecutorConfig *ExecutorConfiguration
+	// Unreferenced objects that were modified less than GracePeriod ago are not deleted.
+	GracePeriod time.Duration `json:"grace_period" yaml:"gracePeriod"`
+	// If set, the unreferenced objects are only reported.
+	DryRun bool `json:"dry_run" yaml:"dryRun"`
+}
+
+func (sgs *StorageGCSpec) HasStorageConfig() bool {
+	return false
+}
+
+func (sgs *StorageGCSpec) GetStorageConfig() (*shared.StorageConfig, error) {
+	return nil, errors.New("StorageGC job specs don't have a storage config.")
+}
+
 type WorkflowSpec struct {
 	BaseSpec
 	WorkflowId     string                 `json:"workflow_id" yaml:"workflowId"`
@@ -111,6 +132,14 @@ type WorkflowSpec struct {
 	AqPath         string                 `json:"aq_path" yaml:"aqPath"`
 	DisplayIP      string                 `json:"display_ip" yaml:"displayIP"`
 	ExecutorConfig *ExecutorConfiguration
+	// If set, the workflow run resumes from this failed or canceled DAG result
+	// instead of starting a new run of the latest DAG.
+	SourceDagResultId string `json:"source_dag_result_id" yaml:"sourceDagResultId"`
+	// If set, the workflow run is part of this backfill and executes for ExecutionTime.
+	BackfillId    string     `json:"backfill_id" yaml:"backfillId"`
+	ExecutionTime *time.Time `json:"execution_time" yaml:"executionTime"`
+	// The server-wide default for the number of operators that can execute at once.
+	MaxConcurrentOperators int `json:"max_concurrent_operators" yaml:"maxConcurrentOperators"`
 }
 
 func (ws *WorkflowSpec) HasStorageConfig() bool {
@@ -134,6 +163,11 @@ func (bs *BasePythonSpec) HasStorageConfig() bool {
 	return true
 }
 
+// GetMetadataPath returns the storage path that the job writes its execution state to.
+func (bs *BasePythonSpec) GetMetadataPath() string {
+	return bs.MetadataPath
+}
+
 func (bs *BasePythonSpec) GetStorageConfig() (*shared.StorageConfig, error) {
 	return &bs.StorageConfig, nil
 }
@@ -230,6 +264,8 @@ type CompileAirflowSpec struct {
 	OutputContentPath string              `json:"output_content_path"  yaml:"output_content_path"`
 	DagId             string              `json:"dag_id"  yaml:"dag_id"`
 	CronSchedule      string              `json:"cron_schedule"  yaml:"cron_schedule"`
+	Timezone          string              `json:"timezone"  yaml:"timezone"`
+	JitterSeconds     int                 `json:"jitter_seconds"  yaml:"jitter_seconds"`
 	TaskSpecs         map[string]Spec     `json:"task_specs"  yaml:"task_specs"`
 	TaskEdges         map[string][]string `json:"task_edges"  yaml:"task_edges"`
 }
@@ -242,6 +278,10 @@ func (*WorkflowRetentionSpec) Type() JobType {
 	return WorkflowRetentionType
 }
 
+func (*StorageGCSpec) Type() JobType {
+	return StorageGCType
+}
+
 func (*WorkflowSpec) Type() JobType {
 	return WorkflowJobType
 }
@@ -335,6 +375,28 @@ func NewWorkflowRetentionJobSpec(
 	}
 }
 
+// NewStorageGCJobSpec constructs a Spec for a StorageGCJob.
+func NewStorageGCJobSpec(
+	database *database.DatabaseConfig,
+	jobManager Config,
+	gracePeriod time.Duration,
+	dryRun bool,
+) Spec {
+	return &StorageGCSpec{
+		BaseSpec: BaseSpec{
+			Type: StorageGCType,
+			Name: StorageGCName,
+		},
+
+		ExecutorConfig: &ExecutorConfiguration{
+			Database:   database,
+			JobManager: jobManager,
+		},
+		GracePeriod: gracePeriod,
+		DryRun:      dryRun,
+	}
+}
+
 // NewWorkflowSpec constructs a Spec for a WorkflowJob.
 func NewWorkflowSpec(
 	name string,
@@ -345,7 +407,7 @@ func NewWorkflowSpec(
 	aqPath string,
 	displayIP string,
 	parameters map[string]param.Param,
-) Spec {
+) *WorkflowSpec {
 	return &WorkflowSpec{
 		BaseSpec: BaseSpec{
 			Type: WorkflowJobType,
@@ -533,6 +595,8 @@ func NewCompileAirflowSpec(
 	outputContentPath string,
 	dagId string,
 	cronSchedule string,
+	timezone string,
+	jitterSeconds int,
 	taskSpecs map[string]Spec,
 	taskEdges map[string][]string,
 ) (Spec, error) {
@@ -559,6 +623,8 @@ func NewCompileAirflowSpec(
 		OutputContentPath: outputContentPath,
 		DagId:             dagId,
 		CronSchedule:      cronSchedule,
+		Timezone:          timezone,
+		JitterSeconds:     jitterSeconds,
 		TaskSpecs:         taskSpecs,
 		TaskEdges:         taskEdges,
 	}, nil
@@ -566,7 +632,63 @@ func NewCompileAirflowSpec(
 
 // `EncodeSpec` first serialize `spec` according to `SerializationType` and returns the base64 encoded string.
 // The encoded string can be safely passed around without any escaping issue (e.g. as envVar)
+// It never includes the storage keys, since the encoded spec is visible to anyone who can read the job.
+// Job managers pass the keys to jobs through their environment instead (see `jobStorageKeys`).
 func EncodeSpec(spec Spec, serializationType SerializationType) (string, error) {
+	return encodeSpec(spec, serializationType, false /* withStorageKeys */)
+}
+
+// jobStorageKeys returns the JSON serialized keys that the job of `spec` needs to read and write encrypted
+// objects, since they are not part of the storage config that is persisted. Jobs read them from the
+// `storage.KeysEnvVar` environment variable. It is empty if the job does not encrypt the objects it stores.
+func jobStorageKeys(spec Spec) (string, error) {
+	if !spec.HasStorageConfig() {
+		return "", nil
+	}
+
+	storageConfig, err := spec.GetStorageConfig()
+	if err != nil {
+		return "", err
+	}
+
+	if !storageConfig.Encrypt {
+		return "", nil
+	}
+
+	keys, err := storage.CurrentKeys()
+	if err != nil {
+		return "", err
+	}
+
+	serializedKeys, err := json.Marshal(keys)
+	if err != nil {
+		return "", err
+	}
+	return string(serializedKeys), nil
+}
+
+// encodeSpec encodes `spec`. If `withStorageKeys` is set, the spec includes the keys that jobs need to read
+// and write encrypted objects. This is only for job managers that hand the spec to the job privately.
+func encodeSpec(spec Spec, serializationType SerializationType, withStorageKeys bool) (string, error) {
+	if withStorageKeys && spec.HasStorageConfig() {
+		storageConfig, err := spec.GetStorageConfig()
+		if err != nil {
+			return "", err
+		}
+
+		if storageConfig.Encrypt && storageConfig.EncryptionKeys == nil {
+			keys, err := storage.CurrentKeys()
+			if err != nil {
+				return "", err
+			}
+
+			// The keys are only part of the encoded spec, so that they don't leak
+			// into specs that are encoded without them later on.
+			storageConfig.EncryptionKeys = keys
+			defer func() { storageConfig.EncryptionKeys = nil }()
+		}
+	}
+
 	var specData []byte
 	var err error
 	if serializationType == JsonSerializationType {
@@ -606,6 +728,8 @@ func DecodeSpec(specData string, serializationType SerializationType) (Spec, err
 			spec = &WorkflowSpec{}
 		case WorkflowRetentionType:
 			spec = &WorkflowRetentionSpec{}
+		case StorageGCType:
+			spec = &StorageGCSpec{}
 		case FunctionJobType:
 			spec = &FunctionSpec{}
 		case AuthenticateJobType:
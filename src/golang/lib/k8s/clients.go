@@ -56,19 +56,19 @@ func CreateClientOutsideCluster(kubeconfigPath string) (*kubernetes.Clientset, e
 	return k8sClient, nil
 }
 
-// This is a helper function that creates the user namespace
-// and does not return anything. This function should never
+// This is a helper function that creates the given namespace if it
+// does not exist yet. This function should never
 // fail, so any errors that are encountered call `log.Fatal` and cause the
 // program to crash.
-func CreateNamespaces(k8sClient *kubernetes.Clientset) error {
+func CreateNamespaces(namespace string, k8sClient kubernetes.Interface) error {
 	namespaces := k8sClient.CoreV1().Namespaces()
 
 	// Create the user pod namespace again only after checking if it exists.
-	_, err := namespaces.Get(context.TODO(), AqueductNamespace, metav1.GetOptions{})
+	_, err := namespaces.Get(context.TODO(), namespace, metav1.GetOptions{})
 	if err != nil {
 		userNamespace := &corev1.Namespace{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: AqueductNamespace,
+				Name: namespace,
 			},
 			Spec: corev1.NamespaceSpec{}, // See above for why this is empty.
 		}
@@ -76,12 +76,12 @@ func CreateNamespaces(k8sClient *kubernetes.Clientset) error {
 		_, err = k8sClient.CoreV1().Namespaces().Create(context.TODO(), userNamespace, metav1.CreateOptions{})
 		if err != nil {
 			// Double-check that we didn't race against another process to create this namespace.
-			if _, namespaceExistsErr := namespaces.Get(context.TODO(), AqueductNamespace, metav1.GetOptions{}); namespaceExistsErr != nil {
+			if _, namespaceExistsErr := namespaces.Get(context.TODO(), namespace, metav1.GetOptions{}); namespaceExistsErr != nil {
 				return errors.Wrap(err, "Unable to create namespace.")
 			}
-			log.Infof("Another process raced to create the user namespace (name: %s). Continuing.\n", AqueductNamespace)
+			log.Infof("Another process raced to create the user namespace (name: %s). Continuing.\n", namespace)
 		} else {
-			log.Infof("User namespace (name: %s) created successfully.\n", AqueductNamespace)
+			log.Infof("User namespace (name: %s) created successfully.\n", namespace)
 		}
 	}
 	return nil
@@ -1,5 +1,7 @@
 package k8s
 
+import "github.com/aqueducthq/aqueduct/lib/storage"
+
 const (
 	// The namespaces in which we create Kubernetes pods. As is obvious, the
 	// `AqueductNamespace` is where user workload pods will be deployed, and the
@@ -11,21 +13,39 @@ const (
 	DefaultMemoryRequest = "4Gi"
 
 	// Pod Config
-	PodResourceCPUKey       = "cpu"
-	PodResourceMemoryKey    = "memory"
-	PodSelectorLabelRoleKey = "role"
-	GPUResourceName         = "gpuname"
-	DefaultGPULimit         = "1"
+	PodResourceCPUKey              = "cpu"
+	PodResourceMemoryKey           = "memory"
+	PodResourceEphemeralStorageKey = "ephemeral-storage"
+	PodSelectorLabelRoleKey        = "role"
+	GPUResourceName                = "gpuname"
+	DefaultGPULimit                = "1"
 
 	// Cluster constants
 	DockerSecretName = "regcred"
 	DockerServer     = "https://index.docker.io/v2/" // this corresponds to the Docker Hub server address
 
+	// All the cron jobs that Aqueduct deploys are labeled with this label key and value,
+	// so that they can be listed.
+	ManagedByLabelKey   = "app.kubernetes.io/managed-by"
+	ManagedByLabelValue = "aqueduct"
+
 	// The name of the k8s secret for the AWS credentials.
 	AwsCredentialsSecretName = "awscred"
 	AwsAccessKeyIdName       = "AWS_ACCESS_KEY_ID"
 	AwsAccessKeyName         = "AWS_SECRET_ACCESS_KEY"
 
+	// The name of the k8s secret for the keys that jobs encrypt stored objects with,
+	// and the environment variable that jobs read them from.
+	StorageKeysSecretName = "storagekeys"
+	StorageKeysEnvVarName = storage.KeysEnvVar
+
+	// The name of the k8s secret that holds the executor config, which is mounted into the
+	// pods of cron jobs that run the executor. It only has the parts of the server config
+	// that the executor needs.
+	ServerConfigSecretName = "aqueduct-server-config"
+	ServerConfigFileName   = "config.yml"
+	ServerConfigMountPath  = "/etc/aqueduct/config"
+
 	DefaultCudaVersion = "11.4.1"
 	Cuda11_4_1         = "11.4.1"
 	Cuda11_8_0         = "11.8.0"
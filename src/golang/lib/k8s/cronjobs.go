@@ -0,0 +1,140 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dropbox/godropbox/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CronJobSchedule specifies when a cron job spawns a job.
+type CronJobSchedule struct {
+	// Schedule follows cron convention.
+	Schedule string
+	// TimeZone is the IANA time zone name in which Schedule is interpreted.
+	// It defaults to the time zone of the cluster's controller manager if empty.
+	TimeZone string
+	// A suspended cron job does not spawn any jobs until it is resumed.
+	Suspend bool
+}
+
+// SecretMount mounts the keys of a secret as files in a directory of a container.
+type SecretMount struct {
+	SecretName string
+	MountPath  string
+}
+
+// CreateCronJob creates a cron job that spawns a job with the given container image and
+// configuration according to `schedule`. The jobs it spawns are configured like the ones
+// that are launched by `LaunchJob`, and additionally have `secretMounts` mounted.
+func CreateCronJob(
+	ctx context.Context,
+	name string,
+	schedule *CronJobSchedule,
+	containerImage string,
+	environmentVariables *map[string]string,
+	secretEnvVariables []string,
+	resourceRequests *map[string]string,
+	secretMounts []SecretMount,
+	k8sClient kubernetes.Interface,
+) error {
+	namespace := AqueductNamespace
+
+	cronJob := batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				ManagedByLabelKey: ManagedByLabelValue,
+			},
+		},
+		Spec: batchv1.CronJobSpec{
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: newJobSpec(name, containerImage, environmentVariables, secretEnvVariables, resourceRequests, nil /* scheduling */),
+			},
+		},
+	}
+	setCronJobSchedule(&cronJob, schedule)
+	mountSecrets(&cronJob.Spec.JobTemplate.Spec.Template.Spec, secretMounts)
+
+	_, err := k8sClient.BatchV1().CronJobs(namespace).Create(ctx, &cronJob, metav1.CreateOptions{})
+	if err != nil {
+		return errors.Wrap(err, "Error creating cron job.")
+	}
+	return nil
+}
+
+func GetCronJob(ctx context.Context, name string, k8sClient kubernetes.Interface) (*batchv1.CronJob, error) {
+	namespace := AqueductNamespace
+
+	return k8sClient.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// ListCronJobs returns all of the cron jobs that are deployed by Aqueduct.
+func ListCronJobs(ctx context.Context, k8sClient kubernetes.Interface) ([]batchv1.CronJob, error) {
+	namespace := AqueductNamespace
+
+	cronJobList, err := k8sClient.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", ManagedByLabelKey, ManagedByLabelValue),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cronJobList.Items, nil
+}
+
+// UpdateCronJobSchedule changes when the cron job with the given name spawns jobs.
+// If the Schedule of `schedule` is empty, the cron job keeps its current one, so that
+// a cron job can be suspended without changing when it is triggered once it is resumed.
+func UpdateCronJobSchedule(
+	ctx context.Context,
+	name string,
+	schedule *CronJobSchedule,
+	k8sClient kubernetes.Interface,
+) error {
+	cronJob, err := GetCronJob(ctx, name, k8sClient)
+	if err != nil {
+		return err
+	}
+
+	if schedule.Schedule == "" {
+		schedule = &CronJobSchedule{
+			Schedule: cronJob.Spec.Schedule,
+			TimeZone: schedule.TimeZone,
+			Suspend:  schedule.Suspend,
+		}
+		if cronJob.Spec.TimeZone != nil {
+			schedule.TimeZone = *cronJob.Spec.TimeZone
+		}
+	}
+	setCronJobSchedule(cronJob, schedule)
+
+	_, err = k8sClient.BatchV1().CronJobs(cronJob.Namespace).Update(ctx, cronJob, metav1.UpdateOptions{})
+	return err
+}
+
+// DeleteCronJob deletes the cron job with the given name. The jobs that it has already spawned
+// are left to finish, and are garbage collected like any other job.
+func DeleteCronJob(ctx context.Context, name string, k8sClient kubernetes.Interface) error {
+	namespace := AqueductNamespace
+
+	propagationPolicy := metav1.DeletePropagationOrphan
+	return k8sClient.BatchV1().CronJobs(namespace).Delete(ctx, name, metav1.DeleteOptions{
+		PropagationPolicy: &propagationPolicy,
+	})
+}
+
+func setCronJobSchedule(cronJob *batchv1.CronJob, schedule *CronJobSchedule) {
+	suspend := schedule.Suspend
+	cronJob.Spec.Schedule = schedule.Schedule
+	cronJob.Spec.Suspend = &suspend
+
+	cronJob.Spec.TimeZone = nil
+	if schedule.TimeZone != "" {
+		timeZone := schedule.TimeZone
+		cronJob.Spec.TimeZone = &timeZone
+	}
+}
@@ -9,11 +9,60 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 )
 
 var ErrNoPodExists = errors.New("No pod exists")
 
+// SchedulingConfig controls where and how the pod of a job is scheduled.
+// All of its fields are optional.
+type SchedulingConfig struct {
+	// Defaults to AqueductNamespace if empty.
+	Namespace          string
+	NodeSelector       map[string]string
+	Tolerations        []corev1.Toleration
+	Affinity           *corev1.Affinity
+	ServiceAccountName string
+	// Added to the job and its pod. They cannot override the labels that we set ourselves.
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// JobNamespace returns the namespace that a job with the given scheduling config runs in.
+func JobNamespace(scheduling *SchedulingConfig) string {
+	if scheduling == nil || scheduling.Namespace == "" {
+		return AqueductNamespace
+	}
+	return scheduling.Namespace
+}
+
+// CheckSchedulingAllowed returns an error if a job with the given scheduling config runs in a namespace,
+// other than AqueductNamespace, or as a service account that is not in the corresponding allow-list.
+// Jobs are given the credentials of the namespace and the service account that they run with.
+func CheckSchedulingAllowed(scheduling *SchedulingConfig, allowedNamespaces, allowedServiceAccounts []string) error {
+	namespace := JobNamespace(scheduling)
+	if namespace != AqueductNamespace && !contains(allowedNamespaces, namespace) {
+		return errors.Newf("Namespace %s is not allowed by the Kubernetes integration.", namespace)
+	}
+
+	if scheduling != nil && scheduling.ServiceAccountName != "" &&
+		!contains(allowedServiceAccounts, scheduling.ServiceAccountName) {
+		return errors.Newf("Service account %s is not allowed by the Kubernetes integration.", scheduling.ServiceAccountName)
+	}
+	return nil
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
 // A helper function that takes in the name of a job, a container image, and
 // other configuration parameters. It uses this information to generate a new
 // job and run the job.
@@ -22,18 +71,56 @@ func LaunchJob(
 	environmentVariables *map[string]string,
 	secretEnvVariables []string,
 	resourceRequests *map[string]string,
-	k8sClient *kubernetes.Clientset,
+	scheduling *SchedulingConfig,
+	k8sClient kubernetes.Interface,
 ) error {
-	// Currently, all jobs run workflow operators, which should be in the user namespace.
-	namespace := AqueductNamespace
-	privileged := false
-
-	k8sEnvironmentVariables, resourceRequirements := generateK8sEnvVarAndResourceReq(environmentVariables, resourceRequests)
+	// Workflow operators run in the user namespace, unless they override it.
+	namespace := JobNamespace(scheduling)
 
 	// This is an empty set of create options because we don't need any of these
 	// configurations for now.
 	createOptions := metav1.CreateOptions{}
 
+	job := batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: newJobSpec(name, containerImage, environmentVariables, secretEnvVariables, resourceRequests, scheduling),
+	}
+
+	// We label each pod with the job name, so we can query for it later (when polling).
+	// This is a valid assumption, only because we spawn one pod per job.
+	job.Spec.Template.ObjectMeta.Name = name
+	job.Spec.Template.ObjectMeta.Namespace = namespace
+	job.Spec.Template.ObjectMeta.Labels["job-name"] = name
+
+	if scheduling != nil {
+		job.ObjectMeta.Labels = scheduling.Labels
+		job.ObjectMeta.Annotations = scheduling.Annotations
+	}
+
+	_, err := k8sClient.BatchV1().Jobs(job.ObjectMeta.Namespace).Create(context.Background(), &job, createOptions)
+	if err != nil {
+		return errors.Wrap(err, "Error launching job.")
+	}
+	return nil
+}
+
+// newJobSpec returns the spec of a job that runs a single pod with one container, which is
+// named `name`. It is shared by the jobs we launch and the jobs that cron jobs spawn.
+// `scheduling` may be nil.
+func newJobSpec(
+	name, containerImage string,
+	environmentVariables *map[string]string,
+	secretEnvVariables []string,
+	resourceRequests *map[string]string,
+	scheduling *SchedulingConfig,
+) batchv1.JobSpec {
+	privileged := false
+
+	k8sEnvironmentVariables, resourceRequirements := generateK8sEnvVarAndResourceReq(environmentVariables, resourceRequests)
+
 	// This means if the job fails, we won't attempt to restart it.
 	backoffLimit := int32(0)
 
@@ -44,67 +131,66 @@ func LaunchJob(
 	// This also means parallelism == 1, and the success of this one pod means the success of the job.
 	numCompletions := int32(1)
 
-	job := batchv1.Job{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: namespace,
-		},
-		Spec: batchv1.JobSpec{
-			BackoffLimit:            &backoffLimit,
-			TTLSecondsAfterFinished: &ttlSeconds,
-			Completions:             &numCompletions,
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      name,
-					Namespace: namespace,
-
-					// We label each pod with the job name, so we can query for it later (when polling).
-					// This is a valid assumption, only because we spawn one pod per job.
-					Labels: map[string]string{
-						"job-name": name,
-					},
-				},
-				Spec: corev1.PodSpec{
-					Containers: []corev1.Container{
-						{
-							Name:            name,
-							Image:           containerImage,
-							Env:             k8sEnvironmentVariables,
-							Resources:       *resourceRequirements,
-							ImagePullPolicy: corev1.PullAlways, // Always update the container if there is a new version.
-							SecurityContext: &corev1.SecurityContext{
-								Privileged: &privileged,
-							},
+	jobSpec := batchv1.JobSpec{
+		BackoffLimit:            &backoffLimit,
+		TTLSecondsAfterFinished: &ttlSeconds,
+		Completions:             &numCompletions,
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:            name,
+						Image:           containerImage,
+						Env:             k8sEnvironmentVariables,
+						Resources:       *resourceRequirements,
+						ImagePullPolicy: corev1.PullAlways, // Always update the container if there is a new version.
+						SecurityContext: &corev1.SecurityContext{
+							Privileged: &privileged,
 						},
 					},
-					RestartPolicy:    corev1.RestartPolicyNever,
-					ImagePullSecrets: []corev1.LocalObjectReference{{Name: DockerSecretName}},
 				},
+				RestartPolicy:    corev1.RestartPolicyNever,
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: DockerSecretName}},
 			},
 		},
 	}
 
 	if len(secretEnvVariables) > 0 {
 		// Assign environment variables from secret references
-		job.Spec.Template.Spec.Containers[0].EnvFrom = generateK8sEnvVarFromSecrets(secretEnvVariables)
+		jobSpec.Template.Spec.Containers[0].EnvFrom = generateK8sEnvVarFromSecrets(secretEnvVariables)
 	}
-	_, err := k8sClient.BatchV1().Jobs(job.ObjectMeta.Namespace).Create(context.Background(), &job, createOptions)
-	if err != nil {
-		return errors.Wrap(err, "Error launching job.")
+
+	if scheduling != nil {
+		for key, value := range scheduling.Labels {
+			jobSpec.Template.ObjectMeta.Labels[key] = value
+		}
+		jobSpec.Template.ObjectMeta.Annotations = scheduling.Annotations
+
+		podSpec := &jobSpec.Template.Spec
+		podSpec.NodeSelector = scheduling.NodeSelector
+		podSpec.Tolerations = scheduling.Tolerations
+		podSpec.Affinity = scheduling.Affinity
+		podSpec.ServiceAccountName = scheduling.ServiceAccountName
 	}
-	return nil
+	return jobSpec
 }
 
-func GetJob(ctx context.Context, name string, k8sClient *kubernetes.Clientset) (*batchv1.Job, error) {
-	// Currently, all jobs run workflow operators, which should be in the user namespace.
-	namespace := AqueductNamespace
-
+func GetJob(ctx context.Context, name string, namespace string, k8sClient kubernetes.Interface) (*batchv1.Job, error) {
 	return k8sClient.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
-func GetPod(ctx context.Context, name string, k8sClient *kubernetes.Clientset) (*corev1.Pod, error) {
-	namespace := AqueductNamespace
+// WatchJob starts a watch on the job with the given name. The caller is responsible for
+// stopping the returned watch.
+func WatchJob(ctx context.Context, name string, namespace string, k8sClient kubernetes.Interface) (watch.Interface, error) {
+	return k8sClient.BatchV1().Jobs(namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+	})
+}
 
+func GetPod(ctx context.Context, name string, namespace string, k8sClient kubernetes.Interface) (*corev1.Pod, error) {
 	podList, err := k8sClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("job-name=%s", name),
 	})
@@ -122,3 +208,12 @@ func GetPod(ctx context.Context, name string, k8sClient *kubernetes.Clientset) (
 	}
 	return &podList.Items[0], nil
 }
+
+// DeleteJob deletes the job with the given name, along with any pods it spawned.
+func DeleteJob(ctx context.Context, name string, namespace string, k8sClient kubernetes.Interface) error {
+	// Background propagation makes sure the job's pods are cleaned up as well.
+	propagationPolicy := metav1.DeletePropagationBackground
+	return k8sClient.BatchV1().Jobs(namespace).Delete(ctx, name, metav1.DeleteOptions{
+		PropagationPolicy: &propagationPolicy,
+	})
+}
@@ -14,8 +14,9 @@ import (
 func CreateSecret(
 	ctx context.Context,
 	name string,
+	namespace string,
 	secrets map[string]string,
-	k8sClient *kubernetes.Clientset,
+	k8sClient kubernetes.Interface,
 ) error {
 	// Convert the values of `secrets` to type []byte.
 	castedSecrets := map[string][]byte{}
@@ -26,7 +27,7 @@ func CreateSecret(
 	secret := corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
-			Namespace: AqueductNamespace,
+			Namespace: namespace,
 		},
 		Data: castedSecrets,
 	}
@@ -34,7 +35,7 @@ func CreateSecret(
 	// Call 'create' or 'update' according to whether if a secret already exists
 	// TODO (likawind): use proper context and options
 	// https://www.notion.so/aqueducthq/Use-proper-context-and-options-33da1baeb12144a2a2381c641a44cf7c
-	_, err := GetSecret(ctx, secret.ObjectMeta.Name, k8sClient)
+	_, err := GetSecret(ctx, secret.ObjectMeta.Name, namespace, k8sClient)
 	// The secret doesn't exist
 	if err != nil {
 		_, err := k8sClient.CoreV1().Secrets(secret.ObjectMeta.Namespace).Create(ctx, &secret, metav1.CreateOptions{})
@@ -46,8 +47,8 @@ func CreateSecret(
 	return err
 }
 
-func GetSecret(ctx context.Context, name string, k8sClient *kubernetes.Clientset) (map[string]string, error) {
-	secret, err := k8sClient.CoreV1().Secrets(AqueductNamespace).Get(ctx, name, metav1.GetOptions{})
+func GetSecret(ctx context.Context, name string, namespace string, k8sClient kubernetes.Interface) (map[string]string, error) {
+	secret, err := k8sClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -59,11 +60,11 @@ func GetSecret(ctx context.Context, name string, k8sClient *kubernetes.Clientset
 	return secretMap, nil
 }
 
-func DeleteSecret(ctx context.Context, name string, k8sClient *kubernetes.Clientset) error {
+func DeleteSecret(ctx context.Context, name string, k8sClient kubernetes.Interface) error {
 	return k8sClient.CoreV1().Secrets(AqueductNamespace).Delete(ctx, name, metav1.DeleteOptions{})
 }
 
-func DeleteSecretsByNamespace(ctx context.Context, k8sClient *kubernetes.Clientset, namespace string) {
+func DeleteSecretsByNamespace(ctx context.Context, k8sClient kubernetes.Interface, namespace string) {
 	// Don't delete service account token secrets, as those are managed by service account deletion
 	// Don't delete docker config secrets, as those are managed by `DeleteDockerSecret` in lib/k8s/utils
 	fieldSelector := createExclusiveSecretFieldSelector(corev1.SecretTypeServiceAccountToken, corev1.SecretTypeDockerConfigJson)
@@ -46,6 +46,9 @@ func generateK8sEnvVarAndResourceReq(environmentVariables *map[string]string, re
 		corev1.ResourceCPU:    resource.MustParse((*resourceRequests)["cpu"]),
 		corev1.ResourceMemory: resource.MustParse((*resourceRequests)["memory"]),
 	}
+	if ephemeralStorage, ok := (*resourceRequests)[PodResourceEphemeralStorageKey]; ok {
+		resourceList[corev1.ResourceEphemeralStorage] = resource.MustParse(ephemeralStorage)
+	}
 	if gpuName, ok := (*resourceRequests)[GPUResourceName]; ok {
 		switch gpuName {
 		case "nvidia.com/gpu":
@@ -79,3 +82,20 @@ func generateK8sEnvVarFromSecrets(k8sSecretNames []string) []corev1.EnvFromSourc
 	}
 	return k8sEnvVarRefs
 }
+
+// mountSecrets mounts each secret of `secretMounts` as a read-only volume in the only container of `podSpec`.
+func mountSecrets(podSpec *corev1.PodSpec, secretMounts []SecretMount) {
+	for _, secretMount := range secretMounts {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: secretMount.SecretName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: secretMount.SecretName},
+			},
+		})
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      secretMount.SecretName,
+			MountPath: secretMount.MountPath,
+			ReadOnly:  true,
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	BackfillTable = "workflow_backfill"
+
+	// Backfill column names
+	BackfillID         = "id"
+	BackfillWorkflowID = "workflow_id"
+	// The cron schedule of the workflow when the backfill was created, and the time zone that it is
+	// interpreted in. The backfill runs the workflow once for every tick of this schedule in [start_time, end_time].
+	BackfillCronSchedule   = "cron_schedule"
+	BackfillTimezone       = "timezone"
+	BackfillStartTime      = "start_time"
+	BackfillEndTime        = "end_time"
+	BackfillMaxConcurrency = "max_concurrency"
+	BackfillStatus         = "status"
+	BackfillExecState      = "execution_state"
+)
+
+// A Backfill maps to the workflow_backfill table.
+type Backfill struct {
+	ID             uuid.UUID              `db:"id" json:"id"`
+	WorkflowID     uuid.UUID              `db:"workflow_id" json:"workflow_id"`
+	CronSchedule   string                 `db:"cron_schedule" json:"cron_schedule"`
+	Timezone       string                 `db:"timezone" json:"timezone"`
+	StartTime      time.Time              `db:"start_time" json:"start_time"`
+	EndTime        time.Time              `db:"end_time" json:"end_time"`
+	MaxConcurrency int                    `db:"max_concurrency" json:"max_concurrency"`
+	Status         shared.ExecutionStatus `db:"status" json:"status"`
+	ExecState      shared.ExecutionState  `db:"execution_state" json:"execution_state"`
+}
+
+// BackfillCols returns a comma-separated string of all Backfill columns.
+func BackfillCols() string {
+	return strings.Join(allBackfillCols(), ",")
+}
+
+func allBackfillCols() []string {
+	return []string{
+		BackfillID,
+		BackfillWorkflowID,
+		BackfillCronSchedule,
+		BackfillTimezone,
+		BackfillStartTime,
+		BackfillEndTime,
+		BackfillMaxConcurrency,
+		BackfillStatus,
+		BackfillExecState,
+	}
+}
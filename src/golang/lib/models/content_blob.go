@@ -0,0 +1,37 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	ContentBlobTable = "content_blob"
+
+	// ContentBlob column names
+	// The hash of the content, which also determines where the content is stored.
+	ContentBlobHash = "hash"
+	// The number of ArtifactResults whose content is stored in this blob.
+	ContentBlobRefCount  = "ref_count"
+	ContentBlobCreatedAt = "created_at"
+)
+
+// A ContentBlob maps to the content_blob table.
+type ContentBlob struct {
+	Hash      string    `db:"hash" json:"hash"`
+	RefCount  int       `db:"ref_count" json:"ref_count"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// ContentBlobCols returns a comma-separated string of all ContentBlob columns.
+func ContentBlobCols() string {
+	return strings.Join(allContentBlobCols(), ",")
+}
+
+func allContentBlobCols() []string {
+	return []string{
+		ContentBlobHash,
+		ContentBlobRefCount,
+		ContentBlobCreatedAt,
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/utils"
 	"github.com/google/uuid"
 )
 
@@ -18,6 +19,9 @@ const (
 	DagCreatedAt     = "created_at"
 	DagStorageConfig = "storage_config"
 	DagEngineConfig  = "engine_config"
+	// The integration that the DAG's content is stored in. It is NULL if the DAG
+	// uses the storage layer of the server.
+	DagStorageIntegrationID = "storage_integration_id"
 )
 
 // A DAG maps to the workflow_dag table.
@@ -30,6 +34,9 @@ type DAG struct {
 	// Sets the default engine for DAG execution. Can be overridden by the operator spec.
 	EngineConfig shared.EngineConfig `db:"engine_config" json:"engine_config"`
 
+	// If set, StorageConfig is the storage layer of this integration instead of the server's.
+	StorageIntegrationID utils.NullUUID `db:"storage_integration_id" json:"storage_integration_id"`
+
 	/* Field not stored in DB */
 	Metadata  *Workflow              `json:"metadata"`
 	Operators map[uuid.UUID]Operator `json:"operators,omitempty"`
@@ -59,5 +66,6 @@ func allDAGCols() []string {
 		DagCreatedAt,
 		DagStorageConfig,
 		DagEngineConfig,
+		DagStorageIntegrationID,
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/utils"
 	"github.com/google/uuid"
 )
 
@@ -18,6 +19,12 @@ const (
 	DAGResultStatus    = "status"
 	DAGResultCreatedAt = "created_at"
 	DAGResultExecState = "execution_state"
+	// The DAGResult this run was resumed from, if any.
+	DAGResultSourceDAGResultID = "source_dag_result_id"
+	// The Backfill this run belongs to, if any.
+	DAGResultBackfillID = "backfill_id"
+	// The logical time this run executes for. Only set for backfill runs.
+	DAGResultExecutionTime = "execution_time"
 )
 
 // A DAGResult maps to the workflow_dag_result table.
@@ -28,6 +35,11 @@ type DAGResult struct {
 	// TODO ENG-1701: deprecate `CreatedAt` field.
 	CreatedAt time.Time                 `db:"created_at" json:"created_at"`
 	ExecState shared.NullExecutionState `db:"execution_state" json:"execution_state"`
+	// Set if this run was resumed from a previous, unsuccessful run.
+	SourceDAGResultID utils.NullUUID `db:"source_dag_result_id" json:"source_dag_result_id"`
+	// Set if this run was triggered by a backfill.
+	BackfillID    utils.NullUUID `db:"backfill_id" json:"backfill_id"`
+	ExecutionTime utils.NullTime `db:"execution_time" json:"execution_time"`
 }
 
 // DAGResultCols returns a comma-separated string of all DAGResult columns.
@@ -53,5 +65,8 @@ func allDAGResultCols() []string {
 		DAGResultStatus,
 		DAGResultCreatedAt,
 		DAGResultExecState,
+		DAGResultSourceDAGResultID,
+		DAGResultBackfillID,
+		DAGResultExecutionTime,
 	}
 }
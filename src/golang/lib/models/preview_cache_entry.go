@@ -0,0 +1,51 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PreviewCacheEntryTable = "preview_cache_entry"
+
+	// PreviewCacheEntry column names
+	// The signature of the artifact whose preview result is cached.
+	PreviewCacheEntrySignature            = "signature"
+	PreviewCacheEntryArtifactContentPath  = "artifact_content_path"
+	PreviewCacheEntryArtifactMetadataPath = "artifact_metadata_path"
+	PreviewCacheEntryOpMetadataPath       = "op_metadata_path"
+	// The total size in bytes of the objects at the paths above.
+	PreviewCacheEntrySize           = "size"
+	PreviewCacheEntryCreatedAt      = "created_at"
+	PreviewCacheEntryLastAccessedAt = "last_accessed_at"
+)
+
+// A PreviewCacheEntry maps to the preview_cache_entry table.
+type PreviewCacheEntry struct {
+	Signature            uuid.UUID `db:"signature" json:"signature"`
+	ArtifactContentPath  string    `db:"artifact_content_path" json:"artifact_content_path"`
+	ArtifactMetadataPath string    `db:"artifact_metadata_path" json:"artifact_metadata_path"`
+	OpMetadataPath       string    `db:"op_metadata_path" json:"op_metadata_path"`
+	Size                 int64     `db:"size" json:"size"`
+	CreatedAt            time.Time `db:"created_at" json:"created_at"`
+	LastAccessedAt       time.Time `db:"last_accessed_at" json:"last_accessed_at"`
+}
+
+// PreviewCacheEntryCols returns a comma-separated string of all PreviewCacheEntry columns.
+func PreviewCacheEntryCols() string {
+	return strings.Join(allPreviewCacheEntryCols(), ",")
+}
+
+func allPreviewCacheEntryCols() []string {
+	return []string{
+		PreviewCacheEntrySignature,
+		PreviewCacheEntryArtifactContentPath,
+		PreviewCacheEntryArtifactMetadataPath,
+		PreviewCacheEntryOpMetadataPath,
+		PreviewCacheEntrySize,
+		PreviewCacheEntryCreatedAt,
+		PreviewCacheEntryLastAccessedAt,
+	}
+}
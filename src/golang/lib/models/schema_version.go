@@ -9,7 +9,7 @@ const (
 	// This is the source of truth for the required schema version
 	// for both the server and executor. This value MUST be updated
 	// when a new schema change is added.
-	CurrentSchemaVersion = 26
+	CurrentSchemaVersion = 33
 
 	SchemaVersionTable = "schema_version"
 
@@ -14,6 +14,9 @@ type ArtifactResultMetadata struct {
 	SerializationType ArtifactSerializationType `json:"serialization_type,omitempty"`
 	ArtifactType      ArtifactType              `json:"artifact_type,omitempty"`
 	PythonType        string                    `json:"python_type,omitempty"`
+	// Checksum is the hex-encoded SHA-256 checksum of the serialized content, recorded when the
+	// content is written. It is empty for content written before checksums were recorded.
+	Checksum string `json:"checksum,omitempty"`
 }
 
 type NullArtifactResultMetadata struct {
@@ -2,9 +2,18 @@ package shared
 
 import "fmt"
 
+type ErrorCode string
+
+const (
+	// TimeoutErrorCode is set when the operator's job was killed for exceeding its timeout.
+	TimeoutErrorCode ErrorCode = "timeout"
+)
+
 type Error struct {
 	Context string `json:"context"`
 	Tip     string `json:"tip"`
+	// Code distinguishes errors that callers may want to handle specially. It is empty for most errors.
+	Code ErrorCode `json:"code,omitempty"`
 }
 
 func (e *Error) Message() string {
@@ -15,3 +24,8 @@ func (e *Error) Message() string {
 
 	return fmt.Sprintf("%s%s", e.Tip, errCtxMsg)
 }
+
+// IsTimeout returns whether the error was caused by the operator exceeding its timeout.
+func (e *Error) IsTimeout() bool {
+	return e != nil && e.Code == TimeoutErrorCode
+}
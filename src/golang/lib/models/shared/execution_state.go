@@ -10,16 +10,36 @@ import (
 type ExecutionState struct {
 	UserLogs *Logs           `json:"user_logs"`
 	Status   ExecutionStatus `json:"status"`
+	// Reason is only set if there is more to say about the status, eg. why a pending operator
+	// has not been launched yet.
+	Reason ExecutionReason `json:"reason,omitempty"`
 
 	// These two failure fields are only set if status == Failed.
 	FailureType *FailureType `json:"failure_type"`
 	Error       *Error       `json:"error"`
 
 	Timestamps *ExecutionTimestamps `json:"timestamps"`
+
+	// Attempts holds the outcome of every previous attempt, in order, for operators
+	// that were retried. The current attempt is described by the fields above.
+	Attempts []ExecutionAttempt `json:"attempts,omitempty"`
+}
+
+// ExecutionAttempt is a snapshot of a single terminated attempt at executing an operator.
+type ExecutionAttempt struct {
+	Attempt     int                  `json:"attempt"`
+	Status      ExecutionStatus      `json:"status"`
+	FailureType *FailureType         `json:"failure_type"`
+	Error       *Error               `json:"error"`
+	UserLogs    *Logs                `json:"user_logs"`
+	Timestamps  *ExecutionTimestamps `json:"timestamps"`
 }
 
 func (e ExecutionState) Terminated() bool {
-	return e.Status == FailedExecutionStatus || e.Status == SucceededExecutionStatus || e.Status == CanceledExecutionStatus
+	return e.Status == FailedExecutionStatus ||
+		e.Status == SucceededExecutionStatus ||
+		e.Status == CanceledExecutionStatus ||
+		e.Status == SkippedExecutionStatus
 }
 
 func (e *ExecutionState) HasBlockingFailure() bool {
@@ -34,6 +54,11 @@ func (e *ExecutionState) HasSystemError() bool {
 	return e.Status == FailedExecutionStatus && *e.FailureType == SystemFailure
 }
 
+// NumAttempts returns the number of attempts made so far, including the current one.
+func (e *ExecutionState) NumAttempts() int {
+	return len(e.Attempts) + 1
+}
+
 // UpdateWithFailure also updates the `FinishedAt` timestamp.
 func (e *ExecutionState) UpdateWithFailure(failureType FailureType, execErr *Error) {
 	e.Status = FailedExecutionStatus
@@ -24,6 +24,22 @@ const (
 	FailedExecutionStatus     ExecutionStatus = "failed"
 	SucceededExecutionStatus  ExecutionStatus = "succeeded"
 	UnknownExecutionStatus    ExecutionStatus = "unknown"
+	// Skipped indicates that an operator did not run because its condition was not
+	// satisfied, or because an upstream operator was skipped.
+	SkippedExecutionStatus ExecutionStatus = "skipped"
+)
+
+// ExecutionReason explains why an object is in its current execution status.
+type ExecutionReason string
+
+const (
+	// QueuedExecutionReason means that a pending operator is ready to run, but is waiting
+	// for one of the workflow run's other operators to finish first. For a pending workflow run,
+	// it means that the run is waiting for previous runs of the workflow to finish first.
+	QueuedExecutionReason ExecutionReason = "queued"
+	// PreviousRunInProgressExecutionReason means that a skipped workflow run was never started,
+	// since a previous run of the workflow was still in progress.
+	PreviousRunInProgressExecutionReason ExecutionReason = "previous_run_in_progress"
 )
 
 type NullExecutionStatus struct {
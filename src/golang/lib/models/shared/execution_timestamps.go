@@ -22,7 +22,8 @@ func ExecutionTimestampsJsonFieldByStatus(
 ) (string, error) {
 	if status == SucceededExecutionStatus ||
 		status == FailedExecutionStatus ||
-		status == CanceledExecutionStatus {
+		status == CanceledExecutionStatus ||
+		status == SkippedExecutionStatus {
 		return "finished_at", nil
 	}
 
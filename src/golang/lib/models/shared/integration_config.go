@@ -1,8 +1,10 @@
 package shared
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/dropbox/godropbox/errors"
@@ -28,6 +30,12 @@ type S3IntegrationConfig struct {
 	ConfigFileContent string       `json:"config_file_content"`
 	ConfigFileProfile string       `json:"config_file_profile"`
 	UseAsStorage      ConfigBool   `json:"use_as_storage"`
+
+	// The following fields are only needed for S3-compatible services other than AWS.
+	Endpoint       string     `json:"endpoint"`
+	ForcePathStyle ConfigBool `json:"force_path_style"`
+	// CACertificate is the content of a PEM file of additional certificate authorities to trust.
+	CACertificate string `json:"ca_certificate"`
 }
 
 // AirflowIntegrationConfig contains the fields for connecting an Airflow integration.
@@ -91,6 +99,10 @@ type K8sIntegrationConfig struct {
 	UseSameCluster     ConfigBool `json:"use_same_cluster"  yaml:"useSameCluster"`
 	Dynamic            ConfigBool `json:"dynamic"  yaml:"dynamic"`
 	CloudIntegrationId string     `json:"cloud_integration_id"  yaml:"cloud_integration_id"`
+	// The namespaces, other than the default Aqueduct namespace, that operators are allowed to run in.
+	AllowedNamespaces ConfigList `json:"allowed_namespaces,omitempty"  yaml:"allowedNamespaces"`
+	// The service accounts that operators are allowed to run as.
+	AllowedServiceAccounts ConfigList `json:"allowed_service_accounts,omitempty"  yaml:"allowedServiceAccounts"`
 }
 
 type LambdaIntegrationConfig struct {
@@ -225,3 +237,24 @@ func (scb *ConfigBool) UnmarshalJSON(data []byte) error {
 	*scb = ConfigBool(b)
 	return nil
 }
+
+// ConfigList is a list that is stored as a comma-separated string, since that is
+// how integration configs store all of their values.
+type ConfigList []string
+
+func (cl *ConfigList) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.Wrapf(err, "Unable to unmarshal %s into ConfigList", string(data))
+	}
+
+	list := ConfigList{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	*cl = list
+	return nil
+}
@@ -0,0 +1,51 @@
+package operator
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+// Condition makes an operator run only if an upstream artifact has a given value.
+// Otherwise, the operator and all of its downstream operators are skipped.
+type Condition struct {
+	// ArtifactID is the artifact that the condition is evaluated on. It must be
+	// the output of an upstream check or parameter operator.
+	ArtifactID uuid.UUID `json:"artifact_id"`
+	// Value is the JSON value that the artifact must have for the operator to run.
+	Value interface{} `json:"value"`
+}
+
+func (c *Condition) Validate() error {
+	if c.ArtifactID == uuid.Nil {
+		return errors.New("Condition must specify an artifact.")
+	}
+
+	if c.Value == nil {
+		return errors.New("Condition must specify a value.")
+	}
+
+	return nil
+}
+
+// Satisfied returns whether the artifact content, serialized with `serializationType`,
+// is equal to the condition's value.
+func (c *Condition) Satisfied(content []byte, serializationType shared.ArtifactSerializationType) (bool, error) {
+	var value interface{}
+	switch serializationType {
+	case shared.JsonSerialization:
+		if err := json.Unmarshal(content, &value); err != nil {
+			return false, errors.Wrap(err, "Unable to parse the condition artifact's content.")
+		}
+	case shared.StringSerialization:
+		value = string(content)
+	default:
+		return false, errors.Newf("Conditions cannot be evaluated on artifacts with %s serialization.", serializationType)
+	}
+
+	// The condition's value is also decoded from JSON, so numbers are float64 on both sides.
+	return reflect.DeepEqual(value, c.Value), nil
+}
@@ -0,0 +1,50 @@
+package operator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionSatisfied(t *testing.T) {
+	type test struct {
+		condition         string
+		content           string
+		serializationType shared.ArtifactSerializationType
+		expected          bool
+	}
+
+	tests := []test{
+		{`{"value": true}`, "true", shared.JsonSerialization, true},
+		{`{"value": true}`, "false", shared.JsonSerialization, false},
+		{`{"value": 5}`, "5.0", shared.JsonSerialization, true},
+		{`{"value": "prod"}`, "prod", shared.StringSerialization, true},
+		{`{"value": "prod"}`, "dev", shared.StringSerialization, false},
+		{`{"value": {"a": [1, 2]}}`, `{"a": [1, 2]}`, shared.JsonSerialization, true},
+	}
+
+	for _, tc := range tests {
+		var condition Condition
+		require.Nil(t, json.Unmarshal([]byte(tc.condition), &condition))
+
+		satisfied, err := condition.Satisfied([]byte(tc.content), tc.serializationType)
+		require.Nil(t, err)
+		require.Equal(t, tc.expected, satisfied, tc.condition)
+	}
+
+	condition := Condition{ArtifactID: uuid.New(), Value: true}
+	_, err := condition.Satisfied([]byte("{"), shared.JsonSerialization)
+	require.NotNil(t, err)
+
+	_, err = condition.Satisfied([]byte("true"), shared.PicklableSerialization)
+	require.NotNil(t, err)
+}
+
+func TestConditionValidate(t *testing.T) {
+	require.Nil(t, (&Condition{ArtifactID: uuid.New(), Value: false}).Validate())
+	require.NotNil(t, (&Condition{Value: true}).Validate())
+	require.NotNil(t, (&Condition{ArtifactID: uuid.New()}).Validate())
+}
@@ -3,6 +3,7 @@ package operator
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/check"
@@ -13,6 +14,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/system_metric"
 	"github.com/aqueducthq/aqueduct/lib/models/utils"
 	"github.com/dropbox/godropbox/errors"
+	corev1 "k8s.io/api/core/v1"
 )
 
 // This file covers all operator specs.
@@ -47,6 +49,25 @@ type ResourceConfig struct {
 	MemoryMB        *int               `json:"memory_mb,omitempty"`
 	GPUResourceName *string            `json:"gpu_resource_name,omitempty"`
 	CudaVersion     *CudaVersionNumber `json:"cuda_version,omitempty"`
+	// The amount of local disk that the operator can use. This is only supported on Kubernetes.
+	EphemeralStorageMB *int `json:"ephemeral_storage_mb,omitempty"`
+	// K8s controls how the operator is scheduled when it runs on Kubernetes.
+	K8s *K8sSchedulingConfig `json:"k8s,omitempty"`
+}
+
+// K8sSchedulingConfig controls where and how the pod of an operator is scheduled on Kubernetes.
+// All of its fields are optional. Tolerations and Affinity follow the Kubernetes API.
+type K8sSchedulingConfig struct {
+	NodeSelector map[string]string   `json:"node_selector,omitempty"`
+	Tolerations  []corev1.Toleration `json:"tolerations,omitempty"`
+	Affinity     *corev1.Affinity    `json:"affinity,omitempty"`
+	// The service account that the pod runs as, eg. one that is bound to a workload identity.
+	ServiceAccountName string `json:"service_account_name,omitempty"`
+	// If set, the operator runs in this namespace instead of the default Aqueduct namespace.
+	Namespace string `json:"namespace,omitempty"`
+	// Added to the job and its pod, along with the labels and annotations that Aqueduct sets.
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 type specUnion struct {
@@ -63,6 +84,12 @@ type specUnion struct {
 	// including function, metric, and check.
 	Resources    *ResourceConfig      `json:"resources,omitempty"`
 	EngineConfig *shared.EngineConfig `json:"engine_config,omitempty"`
+	RetryPolicy  *RetryPolicy         `json:"retry_policy,omitempty"`
+	// If set, the operator's job is killed and the operator fails once it has been
+	// running for longer than this.
+	TimeoutSeconds *int `json:"timeout_seconds,omitempty"`
+	// If set, the operator only runs if its condition is satisfied.
+	Condition *Condition `json:"condition,omitempty"`
 }
 
 type Spec struct {
@@ -124,6 +151,29 @@ func (s Spec) EngineConfig() *shared.EngineConfig {
 	return s.spec.EngineConfig
 }
 
+func (s Spec) RetryPolicy() *RetryPolicy {
+	return s.spec.RetryPolicy
+}
+
+// Timeout returns how long the operator is allowed to run for, or 0 if it has no timeout.
+func (s Spec) Timeout() time.Duration {
+	if s.spec.TimeoutSeconds == nil {
+		return 0
+	}
+	return time.Duration(*s.spec.TimeoutSeconds) * time.Second
+}
+
+func (s Spec) ValidateTimeout() error {
+	if s.spec.TimeoutSeconds != nil && *s.spec.TimeoutSeconds <= 0 {
+		return errors.New("Operator timeout must be positive.")
+	}
+	return nil
+}
+
+func (s Spec) Condition() *Condition {
+	return s.spec.Condition
+}
+
 func (s Spec) Function() *function.Function {
 	if !s.HasFunction() {
 		return nil
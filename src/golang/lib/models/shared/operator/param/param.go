@@ -1,7 +1,26 @@
 package param
 
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+)
+
+// ExecutionTimeParamName is a reserved parameter name. Backfill runs set this parameter
+// to the logical time that the run executes for, formatted as an RFC 3339 string.
+const ExecutionTimeParamName = "aqueduct_execution_time"
+
 // The value of a parameter must be JSON serializable.
 type Param struct {
 	Val               string `json:"val"`
 	SerializationType string `json:"serialization_type"`
 }
+
+// NewExecutionTimeParam returns the value of the reserved execution time parameter for `t`.
+func NewExecutionTimeParam(t time.Time) Param {
+	return Param{
+		Val:               base64.StdEncoding.EncodeToString([]byte(t.UTC().Format(time.RFC3339))),
+		SerializationType: string(shared.StringSerialization),
+	}
+}
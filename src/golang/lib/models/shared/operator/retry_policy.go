@@ -0,0 +1,108 @@
+package operator
+
+import (
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/dropbox/godropbox/errors"
+)
+
+type BackoffType string
+
+const (
+	ConstantBackoff    BackoffType = "constant"
+	LinearBackoff      BackoffType = "linear"
+	ExponentialBackoff BackoffType = "exponential"
+)
+
+// RetryPolicy specifies how the engine should re-launch an operator that failed.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of times the operator can be launched,
+	// including the first attempt.
+	MaxAttempts int         `json:"max_attempts"`
+	Backoff     BackoffType `json:"backoff"`
+	// BackoffSeconds is the base delay between attempts.
+	BackoffSeconds int `json:"backoff_seconds"`
+	// If set, the delay between attempts never exceeds this value.
+	MaxBackoffSeconds *int `json:"max_backoff_seconds,omitempty"`
+	// The failure types that are allowed to trigger another attempt.
+	// Defaults to only system failures if empty.
+	RetryableFailureTypes []shared.FailureType `json:"retryable_failure_types,omitempty"`
+}
+
+func (p *RetryPolicy) Validate() error {
+	if p.MaxAttempts < 1 {
+		return errors.New("Retry policy must allow at least one attempt.")
+	}
+
+	switch p.Backoff {
+	case ConstantBackoff, LinearBackoff, ExponentialBackoff:
+	default:
+		return errors.Newf("Unsupported retry backoff type %s.", p.Backoff)
+	}
+
+	if p.BackoffSeconds < 0 {
+		return errors.New("Retry backoff cannot be negative.")
+	}
+
+	if p.MaxBackoffSeconds != nil && *p.MaxBackoffSeconds < 0 {
+		return errors.New("Maximum retry backoff cannot be negative.")
+	}
+
+	for _, failureType := range p.RetryableFailureTypes {
+		if failureType != shared.SystemFailure && failureType != shared.UserFatalFailure {
+			return errors.Newf("Failure type %d cannot be retried.", failureType)
+		}
+	}
+
+	return nil
+}
+
+// ShouldRetry returns whether an operator that just finished its `attempt`-th
+// attempt (starting from 1) with the given failure type should be launched again.
+func (p *RetryPolicy) ShouldRetry(attempt int, failureType shared.FailureType) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+
+	if len(p.RetryableFailureTypes) == 0 {
+		return failureType == shared.SystemFailure
+	}
+
+	for _, retryableType := range p.RetryableFailureTypes {
+		if retryableType == failureType {
+			return true
+		}
+	}
+	return false
+}
+
+// Delay returns how long to wait after the `attempt`-th attempt (starting from 1)
+// before launching the next one.
+func (p *RetryPolicy) Delay(attempt int) time.Duration {
+	base := time.Duration(p.BackoffSeconds) * time.Second
+
+	var delay time.Duration
+	switch p.Backoff {
+	case LinearBackoff:
+		delay = base * time.Duration(attempt)
+	case ExponentialBackoff:
+		delay = base
+		for i := 1; i < attempt; i++ {
+			delay *= 2
+			if p.MaxBackoffSeconds != nil && delay > time.Duration(*p.MaxBackoffSeconds)*time.Second {
+				break
+			}
+		}
+	default:
+		delay = base
+	}
+
+	if p.MaxBackoffSeconds != nil {
+		maxDelay := time.Duration(*p.MaxBackoffSeconds) * time.Second
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,59 @@
+package operator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	maxBackoffSeconds := 30
+
+	type test struct {
+		policy   RetryPolicy
+		attempt  int
+		expected time.Duration
+	}
+
+	tests := []test{
+		{RetryPolicy{Backoff: ConstantBackoff, BackoffSeconds: 5}, 3, 5 * time.Second},
+		{RetryPolicy{Backoff: LinearBackoff, BackoffSeconds: 5}, 3, 15 * time.Second},
+		{RetryPolicy{Backoff: ExponentialBackoff, BackoffSeconds: 5}, 1, 5 * time.Second},
+		{RetryPolicy{Backoff: ExponentialBackoff, BackoffSeconds: 5}, 3, 20 * time.Second},
+		{RetryPolicy{Backoff: ExponentialBackoff, BackoffSeconds: 5, MaxBackoffSeconds: &maxBackoffSeconds}, 10, 30 * time.Second},
+	}
+
+	for _, tc := range tests {
+		require.Equal(t, tc.expected, tc.policy.Delay(tc.attempt))
+	}
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	defaultPolicy := RetryPolicy{MaxAttempts: 3, Backoff: ConstantBackoff}
+	require.True(t, defaultPolicy.ShouldRetry(1, shared.SystemFailure))
+	require.True(t, defaultPolicy.ShouldRetry(2, shared.SystemFailure))
+	require.False(t, defaultPolicy.ShouldRetry(3, shared.SystemFailure))
+	require.False(t, defaultPolicy.ShouldRetry(1, shared.UserFatalFailure))
+
+	userPolicy := RetryPolicy{
+		MaxAttempts:           2,
+		Backoff:               ConstantBackoff,
+		RetryableFailureTypes: []shared.FailureType{shared.UserFatalFailure},
+	}
+	require.True(t, userPolicy.ShouldRetry(1, shared.UserFatalFailure))
+	require.False(t, userPolicy.ShouldRetry(1, shared.SystemFailure))
+}
+
+func TestRetryPolicyValidate(t *testing.T) {
+	require.Nil(t, (&RetryPolicy{MaxAttempts: 1, Backoff: LinearBackoff}).Validate())
+	require.NotNil(t, (&RetryPolicy{MaxAttempts: 0, Backoff: LinearBackoff}).Validate())
+	require.NotNil(t, (&RetryPolicy{MaxAttempts: 2, Backoff: "random"}).Validate())
+	require.NotNil(t, (&RetryPolicy{MaxAttempts: 2, Backoff: LinearBackoff, BackoffSeconds: -1}).Validate())
+	require.NotNil(t, (&RetryPolicy{
+		MaxAttempts:           2,
+		Backoff:               LinearBackoff,
+		RetryableFailureTypes: []shared.FailureType{shared.UserNonFatalFailure},
+	}).Validate())
+}
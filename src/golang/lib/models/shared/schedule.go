@@ -2,6 +2,7 @@ package shared
 
 import (
 	"database/sql/driver"
+	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/models/utils"
 	"github.com/google/uuid"
@@ -20,6 +21,30 @@ const (
 	CascadingUpdateTrigger UpdateTrigger = "cascade"
 )
 
+// ConcurrencyPolicy specifies what happens when a workflow is triggered while
+// a previous run of it is still in progress.
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrencyPolicy starts the new run right away. This is the default.
+	AllowConcurrencyPolicy ConcurrencyPolicy = "allow"
+	// SkipIfRunningConcurrencyPolicy does not start the new run.
+	SkipIfRunningConcurrencyPolicy ConcurrencyPolicy = "skip_if_running"
+	// QueueConcurrencyPolicy starts the new run once all previous runs have finished.
+	QueueConcurrencyPolicy ConcurrencyPolicy = "queue"
+	// CancelPreviousConcurrencyPolicy cancels all previous runs, and then starts the new run.
+	CancelPreviousConcurrencyPolicy ConcurrencyPolicy = "cancel_previous"
+)
+
+func (p ConcurrencyPolicy) Valid() bool {
+	switch p {
+	case "", AllowConcurrencyPolicy, SkipIfRunningConcurrencyPolicy, QueueConcurrencyPolicy, CancelPreviousConcurrencyPolicy:
+		return true
+	default:
+		return false
+	}
+}
+
 // Schedule defines the frequency for running a workflow.
 type Schedule struct {
 	Trigger              UpdateTrigger `json:"trigger"`
@@ -29,6 +54,26 @@ type Schedule struct {
 	// SourceID is the source Workflow that triggers this
 	// Workflow upon a successful run
 	SourceID uuid.UUID `json:"source_id"`
+	// ConcurrencyPolicy defaults to AllowConcurrencyPolicy if empty.
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrency_policy,omitempty"`
+	// Timezone is the IANA time zone name (e.g. America/New_York) in which
+	// CronSchedule is interpreted. It defaults to UTC if empty.
+	Timezone string `json:"timezone,omitempty"`
+	// JitterSeconds is the size of the window in which each periodic run is randomly
+	// delayed, so that workflows with the same schedule do not all start at once.
+	JitterSeconds int `json:"jitter_seconds,omitempty"`
+}
+
+// Location returns the time zone in which the cron schedule is interpreted.
+func (s *Schedule) Location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+func (s *Schedule) Jitter() time.Duration {
+	return time.Duration(s.JitterSeconds) * time.Second
 }
 
 func (s *Schedule) Value() (driver.Value, error) {
@@ -15,11 +15,40 @@ const (
 	GCSStorageType  StorageType = "gcs"
 )
 
+type CompressionType string
+
+const (
+	GzipCompressionType CompressionType = "gzip"
+	ZstdCompressionType CompressionType = "zstd"
+)
+
 type StorageConfig struct {
 	Type       StorageType `yaml:"type" json:"type"`
 	S3Config   *S3Config   `yaml:"s3Config" json:"s3_config,omitempty"`
 	FileConfig *FileConfig `yaml:"fileConfig" json:"file_config,omitempty"`
 	GCSConfig  *GCSConfig  `yaml:"gcsConfig"  json:"gcs_config,omitempty"`
+
+	// ContentAddressed stores artifact content under the hash of the content, so that
+	// identical content produced by different runs is only stored once.
+	ContentAddressed bool `yaml:"contentAddressed" json:"content_addressed,omitempty"`
+
+	// Compression is the algorithm that stored objects are compressed with.
+	// If not set, objects are stored uncompressed.
+	Compression CompressionType `yaml:"compression" json:"compression,omitempty"`
+	// Encrypt encrypts stored objects with keys that are derived from the server's encryption keys.
+	Encrypt bool `yaml:"encrypt" json:"encrypt,omitempty"`
+	// EncryptionKeys is never persisted. It is only set on the storage config of job specs,
+	// so that jobs which don't have access to the server config can read and write encrypted objects.
+	EncryptionKeys *StorageKeys `yaml:"-" json:"encryption_keys,omitempty"`
+}
+
+// StorageKeys are the keys that stored objects are encrypted with. Each of them is derived from a key
+// in the server's keyring, which is never handed out itself, since it also encrypts the vault.
+type StorageKeys struct {
+	// CurrentKeyID identifies the key that new objects are encrypted with.
+	CurrentKeyID string `json:"current_key_id"`
+	// Keys maps the ID of each key in the server's keyring to the storage key derived from it, base64-encoded.
+	Keys map[string]string `json:"keys"`
 }
 
 type StorageConfigPublic struct {
@@ -28,6 +57,9 @@ type StorageConfigPublic struct {
 	FileConfig      *FileConfig      `json:"fileConfig,omitempty"`
 	GCSConfigPublic *GCSConfigPublic `json:"gcsConfig,omitempty"`
 
+	Compression CompressionType `json:"compression,omitempty"`
+	Encrypt     bool            `json:"encrypt,omitempty"`
+
 	// Empty means that the local filesystem is being used as storage.
 	IntegrationName string `json:"integration_name,omitempty"`
 }
@@ -44,6 +76,16 @@ type S3Config struct {
 	CredentialsProfile string `yaml:"credentialsProfile"  json:"credentials_profile"`
 	AWSAccessKeyID     string `yaml:"awsAccessKeyId"  json:"aws_access_key_id"`
 	AWSSecretAccessKey string `yaml:"awsSecretAccessKey"  json:"aws_secret_access_key"`
+
+	// Endpoint is the URL of an S3-compatible service (e.g. MinIO, Ceph or R2).
+	// If not set, we default to the AWS endpoint of the region.
+	Endpoint string `yaml:"endpoint" json:"endpoint,omitempty"`
+	// ForcePathStyle addresses the bucket as part of the URL path instead of as a subdomain,
+	// which most S3-compatible services require.
+	ForcePathStyle bool `yaml:"forcePathStyle" json:"force_path_style,omitempty"`
+	// CABundlePath is the path to a PEM file of the certificate authorities that are trusted
+	// instead of the system ones, for endpoints with a self-signed certificate.
+	CABundlePath string `yaml:"caBundlePath" json:"ca_bundle_path,omitempty"`
 }
 
 type S3ConfigPublic struct {
@@ -53,6 +95,8 @@ type S3ConfigPublic struct {
 	// Use this directory in the bucket as the root. If not set, we default to the root of the bucket.
 	// Expected to be santizied into the format "path/to/dir/" (without a leading slash, but with a trailing one).
 	RootDir string `yaml:"root_dir" json:"root_dir"`
+
+	Endpoint string `yaml:"endpoint" json:"endpoint,omitempty"`
 }
 
 type FileConfig struct {
@@ -78,7 +122,9 @@ func (s *StorageConfig) Value() (driver.Value, error) {
 
 func (s *StorageConfig) ToPublic() (*StorageConfigPublic, error) {
 	storageConfigPublic := &StorageConfigPublic{
-		Type: s.Type,
+		Type:        s.Type,
+		Compression: s.Compression,
+		Encrypt:     s.Encrypt,
 	}
 
 	switch s.Type {
@@ -86,9 +132,10 @@ func (s *StorageConfig) ToPublic() (*StorageConfigPublic, error) {
 		storageConfigPublic.FileConfig = s.FileConfig
 	case S3StorageType:
 		storageConfigPublic.S3ConfigPublic = &S3ConfigPublic{
-			Region:  s.S3Config.Region,
-			Bucket:  s.S3Config.Bucket,
-			RootDir: s.S3Config.RootDir,
+			Region:   s.S3Config.Region,
+			Bucket:   s.S3Config.Bucket,
+			RootDir:  s.S3Config.RootDir,
+			Endpoint: s.S3Config.Endpoint,
 		}
 	case GCSStorageType:
 		storageConfigPublic.GCSConfigPublic = &GCSConfigPublic{
@@ -0,0 +1,53 @@
+package shared
+
+type VaultType string
+
+const (
+	// StorageVaultType stores secrets encrypted in the storage layer. It is the default.
+	StorageVaultType   VaultType = "storage"
+	HashiCorpVaultType VaultType = "hashicorp"
+	MountedVaultType   VaultType = "mounted"
+	EnvVaultType       VaultType = "env"
+)
+
+// VaultConfig configures where integration credentials are kept.
+type VaultConfig struct {
+	Type            VaultType             `yaml:"type"`
+	HashiCorpConfig *HashiCorpVaultConfig `yaml:"hashicorpConfig,omitempty"`
+	MountedConfig   *MountedVaultConfig   `yaml:"mountedConfig,omitempty"`
+	EnvConfig       *EnvVaultConfig       `yaml:"envConfig,omitempty"`
+}
+
+// IsExternal returns whether secrets are kept outside of the storage layer.
+func (v *VaultConfig) IsExternal() bool {
+	return v != nil && v.Type != "" && v.Type != StorageVaultType
+}
+
+// HashiCorpVaultConfig configures a HashiCorp Vault KV version 2 secrets engine.
+type HashiCorpVaultConfig struct {
+	Address string `yaml:"address"`
+	// Token authenticates with the Vault server. If not set, it is read from TokenPath.
+	Token     string `yaml:"token"`
+	TokenPath string `yaml:"tokenPath"`
+	Namespace string `yaml:"namespace"`
+	// MountPath is where the secrets engine is mounted. Defaults to `secret`.
+	MountPath string `yaml:"mountPath"`
+	// PathPrefix is the path under the mount that secrets are stored at. Defaults to `aqueduct`.
+	PathPrefix string `yaml:"pathPrefix"`
+	// CABundlePath is the path to a PEM file of the certificate authorities that are trusted
+	// instead of the system ones.
+	CABundlePath string `yaml:"caBundlePath"`
+}
+
+// MountedVaultConfig configures secrets that are mounted as files, such as Kubernetes secrets.
+// Each entry is a subdirectory of Directory, with one file per secret.
+type MountedVaultConfig struct {
+	Directory string `yaml:"directory"`
+}
+
+// EnvVaultConfig configures secrets that are read from environment variables.
+// Each entry is a variable that holds a JSON object of the secrets.
+type EnvVaultConfig struct {
+	// Prefix is prepended to the variable names. Defaults to `AQUEDUCT_SECRET_`.
+	Prefix string `yaml:"prefix"`
+}
@@ -14,10 +14,14 @@ func ValueJSONB(value interface{}) (driver.Value, error) {
 
 // ScanJSONB scans value from a SQL Database into dest.
 func ScanJSONB(value interface{}, dest interface{}) error {
-	data, ok := value.([]byte)
-	if !ok {
+	// SQLite returns JSON columns as []byte, but the Postgres driver
+	// returns JSONB columns as a string.
+	switch data := value.(type) {
+	case []byte:
+		return json.Unmarshal(data, dest)
+	case string:
+		return json.Unmarshal([]byte(data), dest)
+	default:
 		return errors.New("Type assertion to []byte failed")
 	}
-
-	return json.Unmarshal(data, dest)
 }
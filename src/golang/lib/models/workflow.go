@@ -13,14 +13,15 @@ const (
 	WorkflowTable = "workflow"
 
 	// Workflow column names
-	WorkflowID                   = "id"
-	WorkflowUserID               = "user_id"
-	WorkflowName                 = "name"
-	WorkflowDescription          = "description"
-	WorkflowSchedule             = "schedule"
-	WorkflowCreatedAt            = "created_at"
-	WorkflowRetentionPolicy      = "retention_policy"
-	WorkflowNotificationSettings = "notification_settings"
+	WorkflowID                     = "id"
+	WorkflowUserID                 = "user_id"
+	WorkflowName                   = "name"
+	WorkflowDescription            = "description"
+	WorkflowSchedule               = "schedule"
+	WorkflowCreatedAt              = "created_at"
+	WorkflowRetentionPolicy        = "retention_policy"
+	WorkflowNotificationSettings   = "notification_settings"
+	WorkflowMaxConcurrentOperators = "max_concurrent_operators"
 )
 
 // A Workflow maps to the workflow table.
@@ -33,6 +34,9 @@ type Workflow struct {
 	CreatedAt            time.Time                   `db:"created_at" json:"created_at"`
 	RetentionPolicy      shared.RetentionPolicy      `db:"retention_policy" json:"retention_policy"`
 	NotificationSettings shared.NotificationSettings `db:"notification_settings" json:"notification_settings"`
+	// MaxConcurrentOperators limits how many operators of a run can execute at once.
+	// If 0, the server-wide default is used.
+	MaxConcurrentOperators int `db:"max_concurrent_operators" json:"max_concurrent_operators"`
 }
 
 // WorkflowCols returns a comma-separated string of all Workflow columns.
@@ -61,5 +65,6 @@ func allWorkflowCols() []string {
 		WorkflowCreatedAt,
 		WorkflowRetentionPolicy,
 		WorkflowNotificationSettings,
+		WorkflowMaxConcurrentOperators,
 	}
 }
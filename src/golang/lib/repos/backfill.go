@@ -0,0 +1,60 @@
+package repos
+
+import (
+	"context"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/google/uuid"
+)
+
+// Backfill defines all of the database operations that can be performed for a Backfill.
+type Backfill interface {
+	backfillReader
+	backfillWriter
+}
+
+type backfillReader interface {
+	// Get returns the Backfill with ID.
+	// It returns a database.ErrNoRows if no rows are found.
+	Get(ctx context.Context, ID uuid.UUID, DB database.Database) (*models.Backfill, error)
+
+	// GetByWorkflow returns all Backfills of the Workflow with workflowID.
+	GetByWorkflow(ctx context.Context, workflowID uuid.UUID, DB database.Database) ([]models.Backfill, error)
+}
+
+type backfillWriter interface {
+	// Create inserts a new pending Backfill with the specified fields.
+	Create(
+		ctx context.Context,
+		workflowID uuid.UUID,
+		cronSchedule string,
+		timezone string,
+		startTime time.Time,
+		endTime time.Time,
+		maxConcurrency int,
+		DB database.Database,
+	) (*models.Backfill, error)
+
+	// DeleteByWorkflow deletes all Backfills of the Workflow with workflowID.
+	DeleteByWorkflow(ctx context.Context, workflowID uuid.UUID, DB database.Database) error
+
+	// Update applies changes to the Backfill with ID. It returns the updated Backfill.
+	Update(
+		ctx context.Context,
+		ID uuid.UUID,
+		changes map[string]interface{},
+		DB database.Database,
+	) (*models.Backfill, error)
+
+	// UpdateBatchStatusByStatus updates all Backfills with status `from` to status `to`.
+	// It returns the updated Backfills.
+	UpdateBatchStatusByStatus(
+		ctx context.Context,
+		from shared.ExecutionStatus,
+		to shared.ExecutionStatus,
+		DB database.Database,
+	) ([]models.Backfill, error)
+}
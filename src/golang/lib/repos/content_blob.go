@@ -0,0 +1,36 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+)
+
+// ContentBlob defines all of the database operations that can be performed for a ContentBlob.
+type ContentBlob interface {
+	contentBlobReader
+	contentBlobWriter
+}
+
+type contentBlobReader interface {
+	// Get returns the ContentBlob with hash.
+	// It returns a database.ErrNoRows if no rows are found.
+	Get(ctx context.Context, hash string, DB database.Database) (*models.ContentBlob, error)
+
+	// List returns all ContentBlobs.
+	List(ctx context.Context, DB database.Database) ([]models.ContentBlob, error)
+}
+
+type contentBlobWriter interface {
+	// IncrementRefCount adds a reference to the ContentBlob with hash,
+	// creating it with a single reference if it does not exist yet. It returns the updated ContentBlob.
+	IncrementRefCount(ctx context.Context, hash string, DB database.Database) (*models.ContentBlob, error)
+
+	// DecrementRefCount removes a reference from the ContentBlob with hash. It returns the updated ContentBlob.
+	// It returns a database.ErrNoRows if no rows are found.
+	DecrementRefCount(ctx context.Context, hash string, DB database.Database) (*models.ContentBlob, error)
+
+	// Delete deletes the ContentBlob with hash.
+	Delete(ctx context.Context, hash string, DB database.Database) error
+}
@@ -69,6 +69,7 @@ type dagWriter interface {
 		workflowID uuid.UUID,
 		storageConfig *shared.StorageConfig,
 		engineConfig *shared.EngineConfig,
+		storageIntegrationID *uuid.UUID,
 		DB database.Database,
 	) (*models.DAG, error)
 
@@ -24,9 +24,16 @@ type dagResultReader interface {
 	// GetBatch returns the DAGResults with ID in IDs.
 	GetBatch(ctx context.Context, IDs []uuid.UUID, DB database.Database) ([]models.DAGResult, error)
 
+	// GetByBackfill returns all DAGResults triggered by the Backfill with backfillID.
+	GetByBackfill(ctx context.Context, backfillID uuid.UUID, DB database.Database) ([]models.DAGResult, error)
+
 	// GetByWorkflow returns the DAGResults of all DAGs associated with the Workflow with workflowID.
 	GetByWorkflow(ctx context.Context, workflowID uuid.UUID, DB database.Database) ([]models.DAGResult, error)
 
+	// GetInProgressByWorkflow returns the DAGResults of the Workflow with workflowID that are
+	// pending or running, ordered by DAGResult.CreatedAt.
+	GetInProgressByWorkflow(ctx context.Context, workflowID uuid.UUID, DB database.Database) ([]models.DAGResult, error)
+
 	// GetKOffsetByWorkflow returns the DAGResults of all DAGs associated with the Workflow with workflowID
 	// except for the last k DAGResults ordered by DAGResult.CreatedAt.
 	GetKOffsetByWorkflow(ctx context.Context, workflowID uuid.UUID, k int, DB database.Database) ([]models.DAGResult, error)
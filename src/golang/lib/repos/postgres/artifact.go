@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/database/stmt_preparers"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator"
+	"github.com/aqueducthq/aqueduct/lib/models/views"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+const artifactNodeViewSubQuery = `
+	WITH artf_with_outputs AS ( -- Aggregate outputs
+		SELECT
+			artifact.id AS id,
+			workflow_dag.id AS dag_id,
+			artifact.name AS name,
+			artifact.description AS description,
+			artifact.type as type,
+			jsonb_agg( -- Group to_ids and idx into one array
+				jsonb_build_object(
+					'value', workflow_dag_edge.to_id,
+					'idx', workflow_dag_edge.idx
+				)
+			) AS outputs
+		FROM
+			artifact, workflow_dag, workflow_dag_edge
+		WHERE
+			workflow_dag.id = workflow_dag_edge.workflow_dag_id
+			AND artifact.id = workflow_dag_edge.from_id
+		GROUP BY
+			workflow_dag.id, artifact.id
+	),
+	artf_with_input AS ( -- No need to group as input is unique
+		SELECT
+			artifact.id AS id,
+			workflow_dag.id AS dag_id,
+			artifact.name AS name,
+			artifact.description AS description,
+			artifact.type as type,
+			workflow_dag_edge.from_id AS input
+		FROM
+			artifact, workflow_dag, workflow_dag_edge
+		WHERE
+			workflow_dag.id = workflow_dag_edge.workflow_dag_id
+			AND artifact.id = workflow_dag_edge.to_id
+	)
+	SELECT -- just do input LEFT JOIN outputs as all artifacts have inputs
+		artf_with_input.id AS id,
+		artf_with_input.dag_id AS dag_id,
+		artf_with_input.name AS name,
+		artf_with_input.description AS description,
+		artf_with_input.type AS type,
+		artf_with_outputs.outputs AS outputs,
+		artf_with_input.input AS input
+	FROM
+		artf_with_input LEFT JOIN artf_with_outputs
+	ON
+		artf_with_outputs.id = artf_with_input.id
+		AND artf_with_outputs.dag_id = artf_with_input.dag_id
+`
+
+type artifactRepo struct {
+	repos.Artifact
+}
+
+func NewArtifactRepo() repos.Artifact {
+	return &artifactRepo{
+		Artifact: sqlite.NewArtifactRepo(),
+	}
+}
+
+func (*artifactRepo) GetNode(ctx context.Context, ID uuid.UUID, DB database.Database) (*views.ArtifactNode, error) {
+	query := fmt.Sprintf(
+		"WITH %s AS (%s) SELECT %s FROM %s WHERE %s = $1",
+		views.ArtifactNodeView,
+		artifactNodeViewSubQuery,
+		views.ArtifactNodeCols(),
+		views.ArtifactNodeView,
+		models.ArtifactID,
+	)
+	args := []interface{}{ID}
+	return getArtifactNode(ctx, DB, query, args...)
+}
+
+func (*artifactRepo) GetMetricsByUpstreamArtifactBatch(
+	ctx context.Context,
+	artifactIDs []uuid.UUID,
+	DB database.Database,
+) (map[uuid.UUID][]models.Artifact, error) {
+	query := fmt.Sprintf(
+		`SELECT DISTINCT
+			%s,
+			edge_artf_to_metrics_op.from_id as upstream_id
+		FROM
+			workflow_dag_edge edge_artf_to_metrics_op,
+			workflow_dag_edge edge_metrics_op_to_artf,
+			operator,
+			artifact 
+		WHERE 
+			artifact.id = edge_metrics_op_to_artf.to_id
+			AND edge_artf_to_metrics_op.to_id = operator.id 
+			AND edge_metrics_op_to_artf.from_id = operator.id
+			AND operator.spec->>'type' = '%s'
+			AND edge_artf_to_metrics_op.from_id IN (%s);`,
+		models.ArtifactColsWithPrefix(),
+		operator.MetricType,
+		stmt_preparers.GenerateArgsList(len(artifactIDs), 1),
+	)
+
+	args := stmt_preparers.CastIdsListToInterfaceList(artifactIDs)
+
+	type artifactWithUpstreamID struct {
+		// copy of artifact
+		ID          uuid.UUID           `db:"id"`
+		Name        string              `db:"name"`
+		Description string              `db:"description"`
+		Type        shared.ArtifactType `db:"type"`
+		UpstreamID  uuid.UUID           `db:"upstream_id"`
+	}
+
+	var queryRows []artifactWithUpstreamID
+	err := DB.Query(ctx, &queryRows, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[uuid.UUID][]models.Artifact, len(queryRows))
+	for _, queryRow := range queryRows {
+		results[queryRow.UpstreamID] = append(results[queryRow.UpstreamID], models.Artifact{
+			ID:          queryRow.ID,
+			Name:        queryRow.Name,
+			Description: queryRow.Description,
+			Type:        queryRow.Type,
+		})
+	}
+
+	return results, nil
+}
+
+func (*artifactRepo) GetNodesByDAG(
+	ctx context.Context,
+	dagID uuid.UUID,
+	DB database.Database,
+) ([]views.ArtifactNode, error) {
+	query := fmt.Sprintf(
+		"WITH %s AS (%s) SELECT %s FROM %s WHERE %s = $1",
+		views.ArtifactNodeView,
+		artifactNodeViewSubQuery,
+		views.ArtifactNodeCols(),
+		views.ArtifactNodeView,
+		views.ArtifactNodeDagID,
+	)
+	args := []interface{}{dagID}
+	return getArtifactNodes(ctx, DB, query, args...)
+}
+
+func getArtifactNode(ctx context.Context, DB database.Database, query string, args ...interface{}) (*views.ArtifactNode, error) {
+	nodes, err := getArtifactNodes(ctx, DB, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodes) == 0 {
+		return nil, database.ErrNoRows()
+	}
+
+	if len(nodes) != 1 {
+		return nil, errors.Newf("Expected 1 Artifact but got %v", len(nodes))
+	}
+
+	return &nodes[0], nil
+}
+
+func getArtifactNodes(ctx context.Context, DB database.Database, query string, args ...interface{}) ([]views.ArtifactNode, error) {
+	var artifactNodes []views.ArtifactNode
+	err := DB.Query(ctx, &artifactNodes, query, args...)
+	return artifactNodes, err
+}
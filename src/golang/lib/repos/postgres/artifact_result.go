@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/database/stmt_preparers"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator"
+	"github.com/aqueducthq/aqueduct/lib/models/views"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
+	"github.com/google/uuid"
+)
+
+type artifactResultRepo struct {
+	repos.ArtifactResult
+}
+
+func NewArtifactResultRepo() repos.ArtifactResult {
+	return &artifactResultRepo{
+		ArtifactResult: sqlite.NewArtifactResultRepo(),
+	}
+}
+
+func (*artifactResultRepo) GetWithArtifactOfMetricsByDAGResultBatch(
+	ctx context.Context,
+	dagResultIDs []uuid.UUID,
+	DB database.Database,
+) ([]views.ArtifactWithResult, error) {
+	query := fmt.Sprintf(
+		`SELECT DISTINCT
+			artifact.id as id,
+			artifact.name as name,
+			artifact.description as description,
+			artifact.type as type,
+			artifact_result.id as result_id,
+			artifact_result.workflow_dag_result_id as dag_result_id,
+			artifact_result.content_path as content_path,
+			artifact_result.execution_state as execution_state,
+			artifact_result.metadata as metadata,
+			workflow_dag.storage_config as storage_config
+		FROM
+			workflow_dag,
+			workflow_dag_edge,
+			operator,
+			artifact,
+			artifact_result
+		WHERE 
+			workflow_dag_edge.to_id = artifact.id
+			AND workflow_dag_edge.from_id = operator.id
+			AND workflow_dag_edge.workflow_dag_id = workflow_dag.id
+			AND operator.spec->>'type' = '%s'
+			AND artifact_result.artifact_id = artifact.id
+			AND artifact_result.workflow_dag_result_id IN (%s);`,
+		operator.MetricType,
+		stmt_preparers.GenerateArgsList(len(dagResultIDs), 1),
+	)
+
+	args := stmt_preparers.CastIdsListToInterfaceList(dagResultIDs)
+	var results []views.ArtifactWithResult
+
+	err := DB.Query(ctx, &results, query, args...)
+	return results, err
+}
+
+func (*artifactResultRepo) UpdateBatchStatusByStatus(
+	ctx context.Context,
+	from shared.ExecutionStatus,
+	to shared.ExecutionStatus,
+	DB database.Database,
+) ([]models.ArtifactResult, error) {
+	setExecStateFragment, args, err := generateUpdateExecStateSnippet(
+		models.ArtifactResultExecState,
+		to,
+		time.Now(),
+		0, /* offset */
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s SET
+			%s,
+			status = $%d
+		WHERE
+			%s->>'status' = $%d
+		RETURNING %s;`,
+		models.ArtifactResultTable,
+		setExecStateFragment,
+		len(args)+1,
+		models.ArtifactResultExecState,
+		len(args)+2,
+		models.ArtifactResultCols(),
+	)
+
+	args = append(args, to)
+	args = append(args, from)
+	var results []models.ArtifactResult
+	err = DB.Query(ctx, &results, query, args...)
+	return results, err
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
+)
+
+type backfillRepo struct {
+	repos.Backfill
+}
+
+func NewBackfillRepo() repos.Backfill {
+	return &backfillRepo{
+		Backfill: sqlite.NewBackfillRepo(),
+	}
+}
+
+func (*backfillRepo) UpdateBatchStatusByStatus(
+	ctx context.Context,
+	from shared.ExecutionStatus,
+	to shared.ExecutionStatus,
+	DB database.Database,
+) ([]models.Backfill, error) {
+	setExecStateFragment, args, err := generateUpdateExecStateSnippet(
+		models.BackfillExecState,
+		to,
+		time.Now(),
+		0, /* offset */
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s SET
+			%s,
+			status = $%d
+		WHERE
+			status = $%d
+		RETURNING %s;`,
+		models.BackfillTable,
+		setExecStateFragment,
+		len(args)+1,
+		len(args)+2,
+		models.BackfillCols(),
+	)
+
+	args = append(args, to)
+	args = append(args, from)
+	var backfills []models.Backfill
+	err = DB.Query(ctx, &backfills, query, args...)
+	return backfills, err
+}
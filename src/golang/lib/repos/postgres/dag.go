@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/views"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
+	"github.com/google/uuid"
+)
+
+type dagRepo struct {
+	repos.DAG
+}
+
+func NewDAGRepo() repos.DAG {
+	return &dagRepo{
+		DAG: sqlite.NewDAGRepo(),
+	}
+}
+
+func (*dagRepo) GetLatestIDsByOrgAndEngine(
+	ctx context.Context,
+	orgID string,
+	engine shared.EngineType,
+	DB database.Database,
+) ([]uuid.UUID, error) {
+	orgIDQuerySnippet := ""
+	if orgID != "" {
+		orgIDQuerySnippet = `
+			AND app_user.organization_id = $2
+			AND app_user.id = workflow.user_id
+		`
+	}
+	query := fmt.Sprintf(`
+		SELECT workflow_dag.id 
+		FROM workflow_dag 
+		WHERE created_at IN 
+		(
+			SELECT MAX(workflow_dag.created_at) 
+			FROM app_user, workflow, workflow_dag 
+			WHERE
+				workflow.id = workflow_dag.workflow_id
+				AND workflow_dag.engine_config->>'type' = $1
+				%s
+		 	GROUP BY workflow.id
+		);`,
+		orgIDQuerySnippet,
+	)
+	args := []interface{}{engine}
+	if orgID != "" {
+		args = append(args, orgID)
+	}
+
+	var objectIDs []views.ObjectID
+	err := DB.Query(ctx, &objectIDs, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	IDs := make([]uuid.UUID, 0, len(objectIDs))
+	for _, objectID := range objectIDs {
+		IDs = append(IDs, objectID.ID)
+	}
+
+	return IDs, nil
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
+	"github.com/google/uuid"
+)
+
+type dagResultRepo struct {
+	repos.DAGResult
+}
+
+func NewDAGResultRepo() repos.DAGResult {
+	return &dagResultRepo{
+		DAGResult: sqlite.NewDAGResultRepo(),
+	}
+}
+
+func (*dagResultRepo) GetKOffsetByWorkflow(ctx context.Context, workflowID uuid.UUID, k int, DB database.Database) ([]models.DAGResult, error) {
+	query := fmt.Sprintf(
+		`SELECT %s 
+		FROM workflow_dag_result, workflow_dag 
+		WHERE 
+			workflow_dag_result.workflow_dag_id = workflow_dag.id 
+			AND workflow_dag.workflow_id = $1
+		ORDER BY workflow_dag_result.created_at DESC
+		OFFSET $2;`,
+		models.DAGResultColsWithPrefix(),
+	)
+	args := []interface{}{workflowID, k}
+
+	var dagResults []models.DAGResult
+	err := DB.Query(ctx, &dagResults, query, args...)
+	return dagResults, err
+}
+
+func (*dagResultRepo) UpdateBatchStatusByStatus(
+	ctx context.Context,
+	from shared.ExecutionStatus,
+	to shared.ExecutionStatus,
+	DB database.Database,
+) ([]models.DAGResult, error) {
+	setExecStateFragment, args, err := generateUpdateExecStateSnippet(
+		models.DAGResultExecState,
+		to,
+		time.Now(),
+		0, /* offset */
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s SET
+			%s,
+			status = $%d
+		WHERE
+			%s->>'status' = $%d
+		RETURNING %s;`,
+		models.DAGResultTable,
+		setExecStateFragment,
+		len(args)+1,
+		models.DAGResultExecState,
+		len(args)+2,
+		models.DAGResultCols(),
+	)
+
+	args = append(args, to)
+	args = append(args, from)
+	var results []models.DAGResult
+	err = DB.Query(ctx, &results, query, args...)
+	return results, err
+}
@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
+)
+
+type integrationRepo struct {
+	repos.Integration
+}
+
+func NewIntegrationRepo() repos.Integration {
+	return &integrationRepo{
+		Integration: sqlite.NewIntegrationRepo(),
+	}
+}
+
+func (*integrationRepo) GetByConfigField(ctx context.Context, fieldName string, fieldValue string, DB database.Database) ([]models.Integration, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM integration WHERE config->>$1 = $2;",
+		models.IntegrationCols(),
+	)
+
+	// The full 'where' condition becomes `config->>'field_name' = 'field_value'`.
+	// We parametrize the field_name and field_value to prevent injection.
+	args := []interface{}{fieldName, fieldValue}
+
+	var integrations []models.Integration
+	err := DB.Query(ctx, &integrations, query, args...)
+	return integrations, err
+}
@@ -0,0 +1,493 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/database/stmt_preparers"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator"
+	"github.com/aqueducthq/aqueduct/lib/models/views"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+const operatorNodeViewSubQuery = `
+	WITH op_with_outputs AS ( -- Aggregate outputs
+		SELECT
+			operator.id AS id,
+			workflow_dag.id AS dag_id,
+			operator.name AS name,
+			operator.description AS description,
+			operator.spec AS spec,
+			operator.execution_environment_id AS execution_environment_id,
+			jsonb_agg( -- Group to_ids and idx into one array
+				jsonb_build_object(
+					'value', workflow_dag_edge.to_id,
+					'idx', workflow_dag_edge.idx
+				)
+			) AS outputs
+		FROM
+			operator, workflow_dag, workflow_dag_edge
+		WHERE
+			workflow_dag.id = workflow_dag_edge.workflow_dag_id
+			AND operator.id = workflow_dag_edge.from_id
+		GROUP BY
+			workflow_dag.id, operator.id
+	),
+	op_with_inputs AS ( -- Aggregate inputs
+		SELECT
+			operator.id AS id,
+			workflow_dag.id AS dag_id,
+			operator.name AS name,
+			operator.description AS description,
+			operator.spec AS spec,
+			operator.execution_environment_id AS execution_environment_id,
+			jsonb_agg( -- Group from_ids and idx into one array
+				jsonb_build_object(
+					'value', workflow_dag_edge.from_id,
+					'idx', workflow_dag_edge.idx
+				)
+			) AS inputs
+		FROM
+			operator, workflow_dag, workflow_dag_edge
+		WHERE
+			workflow_dag.id = workflow_dag_edge.workflow_dag_id
+			AND operator.id = workflow_dag_edge.to_id
+		GROUP BY
+			workflow_dag.id, operator.id
+	)
+	SELECT -- A full outer join to include operators without inputs / outputs.
+		op_with_outputs.id AS id,
+		op_with_outputs.dag_id AS dag_id,
+		op_with_outputs.name AS name,
+		op_with_outputs.description AS description,
+		op_with_outputs.spec AS spec,
+		op_with_outputs.execution_environment_id AS execution_environment_id,
+		op_with_outputs.outputs AS outputs,
+		op_with_inputs.inputs AS inputs
+	FROM
+		op_with_outputs LEFT JOIN op_with_inputs
+	ON
+		op_with_outputs.id = op_with_inputs.id
+		AND op_with_outputs.dag_id = op_with_inputs.dag_id
+	UNION ALL
+	SELECT
+		op_with_inputs.id AS id,
+		op_with_inputs.dag_id AS dag_id,
+		op_with_inputs.name AS name,
+		op_with_inputs.description AS description,
+		op_with_inputs.spec AS spec,
+		op_with_inputs.execution_environment_id AS execution_environment_id,
+		op_with_outputs.outputs AS outputs,
+		op_with_inputs.inputs AS inputs
+	FROM
+		op_with_inputs LEFT JOIN op_with_outputs
+	ON
+		op_with_outputs.id = op_with_inputs.id
+		AND op_with_outputs.dag_id = op_with_inputs.dag_id
+	WHERE op_with_outputs.outputs IS NULL
+`
+
+type operatorRepo struct {
+	repos.Operator
+}
+
+func NewOperatorRepo() repos.Operator {
+	return &operatorRepo{
+		Operator: sqlite.NewOperatorRepo(),
+	}
+}
+
+func (*operatorRepo) GetNode(ctx context.Context, ID uuid.UUID, DB database.Database) (*views.OperatorNode, error) {
+	query := fmt.Sprintf(
+		"WITH %s AS (%s) SELECT %s FROM %s WHERE %s = $1",
+		views.OperatorNodeView,
+		operatorNodeViewSubQuery,
+		views.OperatorNodeCols(),
+		views.OperatorNodeView,
+		models.OperatorID,
+	)
+	args := []interface{}{ID}
+	return getOperatorNode(ctx, DB, query, args...)
+}
+
+func (*operatorRepo) GetNodesByDAG(
+	ctx context.Context,
+	dagID uuid.UUID,
+	DB database.Database,
+) ([]views.OperatorNode, error) {
+	query := fmt.Sprintf(
+		"WITH %s AS (%s) SELECT %s FROM %s WHERE %s = $1",
+		views.OperatorNodeView,
+		operatorNodeViewSubQuery,
+		views.OperatorNodeCols(),
+		views.OperatorNodeView,
+		views.OperatorNodeDagID,
+	)
+	args := []interface{}{dagID}
+	return getOperatorNodes(ctx, DB, query, args...)
+}
+
+func (*operatorRepo) GetDistinctLoadOPsByWorkflow(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	DB database.Database,
+) ([]views.LoadOperator, error) {
+	// Get all unique load operator (defined as a unique combination of operator name, integration,
+	// and operator spec) that has an edge (in `from_id` or `to_id`) in a DAG
+	// belonging to the specified workflow in order of when the operator was last modified.
+	query := `
+	SELECT
+		operator.name AS operator_name, 
+		MAX(workflow_dag.created_at) AS modified_at,
+		integration.name AS integration_name,
+		operator.spec->'load' AS spec 	
+	FROM 
+		operator, integration, workflow_dag_edge, workflow_dag
+	WHERE (
+		operator.spec->>'type' = 'load' AND 
+		integration.id::text = operator.spec#>>'{load,integration_id}' AND
+		( 
+			workflow_dag_edge.from_id = operator.id OR 
+			workflow_dag_edge.to_id = operator.id 
+		) AND 
+		workflow_dag_edge.workflow_dag_id = workflow_dag.id AND 
+		workflow_dag.workflow_id = $1
+	)
+	GROUP BY
+		operator.name,
+		integration.name,
+		operator.spec->'load'
+	ORDER BY modified_at DESC;
+	`
+	args := []interface{}{workflowID}
+
+	var operators []views.LoadOperator
+	err := DB.Query(ctx, &operators, query, args...)
+	return operators, err
+}
+
+func (*operatorRepo) GetExtractAndLoadOPsByIntegration(
+	ctx context.Context,
+	integrationID uuid.UUID,
+	DB database.Database,
+) ([]models.Operator, error) {
+	query := fmt.Sprintf(
+		`SELECT %s 
+		FROM operator
+		WHERE 
+			spec#>>'{load,integration_id}' = $1
+			OR spec#>>'{extract,integration_id}' = $2`,
+		models.OperatorCols(),
+	)
+	args := []interface{}{integrationID, integrationID}
+
+	return getOperators(ctx, DB, query, args...)
+}
+
+// This currently only works with relational and S3 loads!
+func (*operatorRepo) GetLoadOPsByWorkflowAndIntegration(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	integrationID uuid.UUID,
+	objectName string,
+	DB database.Database,
+) ([]models.Operator, error) {
+	// Get all load operators where table=objectName & integration_id=integrationId
+	// and has an edge (in `from_id` or `to_id`) in a DAG belonging to the specified
+	// workflow.
+	query := fmt.Sprintf(`
+	SELECT %s
+	FROM operator
+	WHERE
+		spec->>'type' = '%s' AND 
+		(
+			spec#>>'{load,parameters,table}' = $1 OR
+			spec#>>'{load,parameters,filepath}' = $1
+		) AND
+		spec#>>'{load,integration_id}' = $2 AND
+		EXISTS 
+		(
+			SELECT 1 
+			FROM 
+				workflow_dag_edge, workflow_dag 
+			WHERE 
+			( 
+				workflow_dag_edge.from_id = operator.id OR 
+				workflow_dag_edge.to_id = operator.id 
+			) AND 
+			workflow_dag_edge.workflow_dag_id = workflow_dag.id AND 
+			workflow_dag.workflow_id = $3
+		);`,
+		models.OperatorCols(),
+		operator.LoadType,
+	)
+	args := []interface{}{objectName, integrationID, workflowID}
+
+	return getOperators(ctx, DB, query, args...)
+}
+
+func (*operatorRepo) GetLoadOPsByIntegration(
+	ctx context.Context,
+	integrationID uuid.UUID,
+	objectName string,
+	DB database.Database,
+) ([]models.Operator, error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM operator
+		WHERE 
+			spec#>>'{load,integration_id}' = $1
+			OR spec#>>'{extract,integration_id}' = $2`,
+		models.OperatorCols(),
+	)
+	args := []interface{}{integrationID, integrationID}
+
+	return getOperators(ctx, DB, query, args...)
+}
+
+func (*operatorRepo) GetLoadOPSpecsByOrg(ctx context.Context, orgID string, DB database.Database) ([]views.LoadOperatorSpec, error) {
+	// Get the artifact id, artifact name, operator id, workflow name, workflow id,
+	// and operator spec of all load operators (`to_id`s) and the artifact(s) going to
+	// that operator (`from_id`s; these artifacts are the objects that will be saved
+	// by the operator to the integration) in the workflows owned by the specified
+	// organization.
+	query := fmt.Sprintf(
+		`SELECT DISTINCT 
+			workflow_dag_edge.from_id AS artifact_id, 
+			artifact.name AS artifact_name, 
+		 	operator.id AS load_operator_id, 
+			workflow.name AS workflow_name, 
+			workflow.id AS workflow_id, operator.spec 
+		 FROM 
+		 	app_user, workflow, workflow_dag, 
+			workflow_dag_edge, operator, artifact
+		 WHERE 
+		 	app_user.id = workflow.user_id 
+			AND workflow.id = workflow_dag.workflow_id 
+			AND workflow_dag.id = workflow_dag_edge.workflow_dag_id 
+			AND workflow_dag_edge.to_id = operator.id 
+			AND artifact.id = workflow_dag_edge.from_id 
+			AND operator.spec->>'type' = '%s' 
+			AND app_user.organization_id = $1;`,
+		operator.LoadType,
+	)
+	args := []interface{}{orgID}
+
+	var specs []views.LoadOperatorSpec
+	err := DB.Query(ctx, &specs, query, args...)
+	return specs, err
+}
+
+func (*operatorRepo) GetByEngineIntegrationID(
+	ctx context.Context,
+	integrationID uuid.UUID,
+	DB database.Database,
+) ([]models.Operator, error) {
+	workflow_condition_fragments := make([]string, 0, len(shared.ServiceToEngineConfigField))
+	operator_condition_fragments := make([]string, 0, len(shared.ServiceToEngineConfigField))
+	for _, field := range shared.ServiceToEngineConfigField {
+		workflow_condition_fragments = append(
+			workflow_condition_fragments,
+			fmt.Sprintf(
+				`workflow_dag.engine_config#>>'{%s,integration_id}' = $1`,
+				field),
+		)
+
+		operator_condition_fragments = append(
+			operator_condition_fragments,
+			fmt.Sprintf(
+				`operator.spec#>>'{engine_config,%s,integration_id}' = $1`,
+				field),
+		)
+	}
+
+	workflow_condition := strings.Join(workflow_condition_fragments, " OR ")
+	operator_condition := strings.Join(operator_condition_fragments, " OR ")
+
+	query := fmt.Sprintf(`
+		SELECT DISTINCT %s FROM
+		operator, workflow_dag, workflow_dag_edge
+		WHERE
+		workflow_dag_edge.workflow_dag_id = workflow_dag.id
+		AND (
+			workflow_dag_edge.from_id = operator.id
+			OR workflow_dag_edge.to_id = operator.id
+		)
+		AND (
+			(
+				operator.spec->>'engine_config' IS NULL
+				AND (%s)
+			)
+			OR (%s)
+		);`,
+		models.OperatorColsWithPrefix(),
+		workflow_condition,
+		operator_condition,
+	)
+	args := []interface{}{integrationID}
+
+	var results []models.Operator
+	err := DB.Query(ctx, &results, query, args...)
+	return results, err
+}
+
+func (*operatorRepo) GetUnusedCondaEnvNames(ctx context.Context, DB database.Database) ([]string, error) {
+	// Note that we use `OperatorToArtifactType` as the filtering condition because an operator
+	// is guaranteed to generate at least one artifact, so this filter is guaranteed to capture
+	// all operators involved in a workflow DAG.
+	query := fmt.Sprintf(`
+	WITH latest_workflow_dag AS
+	(
+		SELECT 
+			workflow_dag.id 
+		FROM
+			workflow_dag 
+		WHERE 
+			created_at IN (
+				SELECT 
+					MAX(workflow_dag.created_at) 
+				FROM 
+					workflow, workflow_dag 
+				WHERE 
+					workflow.id = workflow_dag.workflow_id 
+				GROUP BY 
+					workflow.id
+			)
+	),
+	all_env_names AS
+	(
+		SELECT DISTINCT
+			operator.spec#>>'{engine_config,aqueduct_conda_config,env}' AS name,
+			operator.id as op_id
+		FROM 
+			workflow_dag_edge, operator
+		WHERE
+			workflow_dag_edge.type = '%s' 
+			AND 
+			workflow_dag_edge.from_id = operator.id
+			AND
+			operator.spec#>>'{engine_config,aqueduct_conda_config,env}' IS NOT NULL
+	),
+	active_env_names AS
+	(
+		SELECT DISTINCT
+			all_env_names.name AS name
+		FROM 
+			all_env_names, latest_workflow_dag, workflow_dag_edge
+		WHERE
+			latest_workflow_dag.id = workflow_dag_edge.workflow_dag_id 
+			AND 
+			workflow_dag_edge.type = '%s' 
+			AND 
+			workflow_dag_edge.from_id = all_env_names.op_id
+	)
+	SELECT 
+		all_env_names.name AS name
+	FROM 
+		all_env_names LEFT JOIN active_env_names 
+		ON all_env_names.name = active_env_names.name
+	WHERE 
+		active_env_names.name IS NULL;`,
+		shared.OperatorToArtifactDAGEdge,
+		shared.OperatorToArtifactDAGEdge,
+	)
+
+	type resultStruct struct {
+		Name string `db:"name"`
+	}
+
+	var resultRows []resultStruct
+	err := DB.Query(ctx, &resultRows, query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]string, 0, len(resultRows))
+	for _, row := range resultRows {
+		results = append(results, row.Name)
+	}
+
+	return results, nil
+}
+
+func (*operatorRepo) GetByEngineType(ctx context.Context, engineType shared.EngineType, DB database.Database) ([]models.Operator, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM operator WHERE operator.spec#>>'{engine_config,type}' = $1;",
+		models.OperatorCols(),
+	)
+
+	return getOperators(ctx, DB, query, engineType)
+}
+
+func (*operatorRepo) GetEngineTypesMapByDagIDs(
+	ctx context.Context,
+	DagIDs []uuid.UUID,
+	DB database.Database,
+) (map[uuid.UUID][]shared.EngineType, error) {
+	query := fmt.Sprintf(`
+		SELECT DISTINCT
+			workflow_dag_edge.workflow_dag_id as dag_id,
+			COALESCE(
+				operator.spec#>>'{engine_config,type}',
+				''
+			) as engine_type
+		FROM operator, workflow_dag_edge
+		WHERE
+			(workflow_dag_edge.from_id = operator.id
+			OR workflow_dag_edge.to_id = operator.id)
+			AND workflow_dag_edge.workflow_dag_id IN (%s);`,
+		stmt_preparers.GenerateArgsList(len(DagIDs), 1),
+	)
+	args := stmt_preparers.CastIdsListToInterfaceList(DagIDs)
+	var resultRows []struct {
+		DagID      uuid.UUID         `db:"dag_id"`
+		EngineType shared.EngineType `db:"engine_type"`
+	}
+
+	err := DB.Query(ctx, &resultRows, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[uuid.UUID][]shared.EngineType, len(resultRows))
+	for _, row := range resultRows {
+		results[row.DagID] = append(results[row.DagID], row.EngineType)
+	}
+
+	return results, nil
+}
+
+func getOperators(ctx context.Context, DB database.Database, query string, args ...interface{}) ([]models.Operator, error) {
+	var operators []models.Operator
+	err := DB.Query(ctx, &operators, query, args...)
+	return operators, err
+}
+
+func getOperatorNode(ctx context.Context, DB database.Database, query string, args ...interface{}) (*views.OperatorNode, error) {
+	nodes, err := getOperatorNodes(ctx, DB, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodes) == 0 {
+		return nil, database.ErrNoRows()
+	}
+
+	if len(nodes) != 1 {
+		return nil, errors.Newf("Expected 1 Operator but got %v", len(nodes))
+	}
+
+	return &nodes[0], nil
+}
+
+func getOperatorNodes(ctx context.Context, DB database.Database, query string, args ...interface{}) ([]views.OperatorNode, error) {
+	var operatorNodes []views.OperatorNode
+	err := DB.Query(ctx, &operatorNodes, query, args...)
+	return operatorNodes, err
+}
@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/database/stmt_preparers"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator"
+	"github.com/aqueducthq/aqueduct/lib/models/views"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
+	"github.com/google/uuid"
+)
+
+type operatorResultRepo struct {
+	repos.OperatorResult
+}
+
+func NewOperatorResultRepo() repos.OperatorResult {
+	return &operatorResultRepo{
+		OperatorResult: sqlite.NewOperatorResultRepo(),
+	}
+}
+
+func (*operatorResultRepo) GetWithOperatorByDAGResultBatch(
+	ctx context.Context,
+	dagResultIDs []uuid.UUID,
+	types []operator.Type,
+	DB database.Database,
+) ([]views.OperatorWithResult, error) {
+	query := fmt.Sprintf(
+		`SELECT
+			operator.id as id,
+			operator.name as name,
+			operator.description as description,
+			operator.spec as spec,
+			operator.execution_environment_id as execution_environment_id,
+			operator_result.id as result_id,
+			operator_result.workflow_dag_result_id as dag_result_id,
+			operator_result.status as status,
+			operator_result.execution_state as execution_state
+		FROM operator, operator_result 
+		WHERE operator_result.workflow_dag_result_id IN (%s)
+		AND operator.spec->>'type' IN (%s)
+		AND operator.id = operator_result.operator_id`,
+		stmt_preparers.GenerateArgsList(len(dagResultIDs), 1),
+		stmt_preparers.GenerateArgsList(len(types), 1+len(dagResultIDs)),
+	)
+
+	args := stmt_preparers.CastIdsListToInterfaceList(dagResultIDs)
+	for _, tp := range types {
+		args = append(args, tp)
+	}
+
+	var results []views.OperatorWithResult
+	err := DB.Query(ctx, &results, query, args...)
+	return results, err
+}
+
+func (*operatorResultRepo) GetCheckStatusByArtifactBatch(
+	ctx context.Context,
+	artifactIDs []uuid.UUID,
+	DB database.Database,
+) ([]views.OperatorResultStatus, error) {
+	// Get all unique combinations of artifact id, operator name,
+	// operator status, operator execution state, and workflow dag
+	// result id of all check operators of artifacts in the
+	// `artifactIds` list (`from_id` in `artifactIds`).
+	query := fmt.Sprintf(
+		`SELECT DISTINCT
+			workflow_dag_edge.from_id AS artifact_id,
+			operator.name AS operator_name,
+		 	operator_result.execution_state as metadata,
+			operator_result.workflow_dag_result_id 
+		FROM workflow_dag_edge, operator, operator_result 
+		WHERE 
+			workflow_dag_edge.to_id = operator.id 
+			AND operator.id = operator_result.operator_id 
+			AND workflow_dag_edge.from_id IN (%s) 
+			AND operator.spec->>'type' = '%s';`,
+		stmt_preparers.GenerateArgsList(len(artifactIDs), 1),
+		operator.CheckType,
+	)
+	args := stmt_preparers.CastIdsListToInterfaceList(artifactIDs)
+
+	var statuses []views.OperatorResultStatus
+	err := DB.Query(ctx, &statuses, query, args...)
+	return statuses, err
+}
+
+func (*operatorResultRepo) UpdateBatchStatusByStatus(
+	ctx context.Context,
+	from shared.ExecutionStatus,
+	to shared.ExecutionStatus,
+	DB database.Database,
+) ([]models.OperatorResult, error) {
+	setExecStateFragment, args, err := generateUpdateExecStateSnippet(
+		models.OperatorResultExecState,
+		to,
+		time.Now(),
+		0, /* offset */
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s SET
+			%s,
+			status = $%d
+		WHERE
+			%s->>'status' = $%d
+		RETURNING %s;`,
+		models.OperatorResultTable,
+		setExecStateFragment,
+		len(args)+1,
+		models.OperatorResultExecState,
+		len(args)+2,
+		models.OperatorResultCols(),
+	)
+
+	args = append(args, to)
+	args = append(args, from)
+	var results []models.OperatorResult
+	err = DB.Query(ctx, &results, query, args...)
+	return results, err
+}
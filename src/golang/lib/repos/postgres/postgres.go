@@ -0,0 +1,43 @@
+// Package postgres implements the repos interfaces for a Postgres database.
+//
+// Most queries issued by the SQLite repos are portable, so each repo here embeds
+// its SQLite counterpart and only overrides the methods whose queries rely on
+// SQLite-specific JSON functions or syntax.
+package postgres
+
+import (
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
+)
+
+func NewContentBlobRepo() repos.ContentBlob {
+	return sqlite.NewContentBlobRepo()
+}
+
+func NewDAGEdgeRepo() repos.DAGEdge {
+	return sqlite.NewDAGEdgeRepo()
+}
+
+func NewExecutionEnvironmentRepo() repos.ExecutionEnvironment {
+	return sqlite.NewExecutionEnvironmentRepo()
+}
+
+func NewNotificationRepo() repos.Notification {
+	return sqlite.NewNotificationRepo()
+}
+
+func NewPreviewCacheEntryRepo() repos.PreviewCacheEntry {
+	return sqlite.NewPreviewCacheEntryRepo()
+}
+
+func NewSchemaVersionRepo() repos.SchemaVersion {
+	return sqlite.NewSchemaVersionRepo()
+}
+
+func NewUserRepo() repos.User {
+	return sqlite.NewUserRepo()
+}
+
+func NewWatcherRepo() repos.Watcher {
+	return sqlite.NewWatcherRepo()
+}
@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
+)
+
+type storageMigrationRepo struct {
+	repos.StorageMigration
+}
+
+func NewStorageMigrationRepo() repos.StorageMigration {
+	return &storageMigrationRepo{
+		StorageMigration: sqlite.NewStorageMigrationRepo(),
+	}
+}
+
+// List returns all the storage migration entries in reverse chronological order, by creation time.
+func (*storageMigrationRepo) List(
+	ctx context.Context,
+	DB database.Database,
+) ([]models.StorageMigration, error) {
+	query := `SELECT * FROM storage_migration ORDER BY execution_state#>>'{timestamps,registered_at}' DESC;`
+
+	var storageMigrations []models.StorageMigration
+	err := DB.Query(ctx, &storageMigrations, query)
+	return storageMigrations, err
+}
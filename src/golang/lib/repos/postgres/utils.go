@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+)
+
+// generateUpdateExecStateSnippet returns a query fragment that updates exec state JSONB
+// with the given status and timestamp.
+// This is useful to update the state without deserializing the content.
+// Example: generateUpdateExecStateSnippet('integration.execution_state', 'succeeded', time.Now())
+// -> '`integration.execution_state = jsonb_set(
+//
+//	jsonb_set(integration.execution_state, '{status}', to_jsonb('succeeded')),
+//	  '{timestamps,finished_at}', to_jsonb('2023-03-27 14:13PM')
+//	)`
+func generateUpdateExecStateSnippet(
+	columnAccessPath string,
+	status shared.ExecutionStatus,
+	timestamp time.Time,
+	offset int,
+) (fragment string, args []interface{}, err error) {
+	timestampField, err := shared.ExecutionTimestampsJsonFieldByStatus(status)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// The timestamp is pre-serialized for the same reason as in the SQLite implementation,
+	// so that both backends store it in the same format.
+	timestampValue, err := timestamp.MarshalText()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return fmt.Sprintf(`%s = jsonb_set(
+			jsonb_set(%s, '{status}', to_jsonb($%d::text)),
+			'{timestamps,%s}',
+			to_jsonb($%d::text)
+		)`,
+		columnAccessPath,
+		columnAccessPath,
+		offset+1,
+		timestampField,
+		offset+2,
+	), []interface{}{
+		status, string(timestampValue),
+	}, nil
+}
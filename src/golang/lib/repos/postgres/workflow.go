@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/views"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
+	"github.com/google/uuid"
+)
+
+type workflowRepo struct {
+	repos.Workflow
+}
+
+func NewWorkflowRepo() repos.Workflow {
+	return &workflowRepo{
+		Workflow: sqlite.NewWorklowRepo(),
+	}
+}
+
+func (*workflowRepo) GetByScheduleTrigger(
+	ctx context.Context,
+	trigger shared.UpdateTrigger,
+	DB database.Database,
+) ([]models.Workflow, error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM workflow WHERE
+			schedule->>'trigger' = $1;
+		`,
+		models.WorkflowCols(),
+	)
+	args := []interface{}{trigger}
+
+	var workflows []models.Workflow
+	err := DB.Query(ctx, &workflows, query, args...)
+	return workflows, err
+}
+
+func (*workflowRepo) GetTargets(ctx context.Context, ID uuid.UUID, DB database.Database) ([]uuid.UUID, error) {
+	query := `
+		SELECT id FROM workflow
+		WHERE
+			schedule->>'trigger' = $1
+			AND schedule->>'source_id' = $2
+		;`
+	args := []interface{}{shared.CascadingUpdateTrigger, ID}
+
+	var objectIDs []views.ObjectID
+	err := DB.Query(ctx, &objectIDs, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	IDs := make([]uuid.UUID, 0, len(objectIDs))
+	for _, objectID := range objectIDs {
+		IDs = append(IDs, objectID.ID)
+	}
+
+	return IDs, nil
+}
+
+func (*workflowRepo) GetLastRunByEngine(
+	ctx context.Context,
+	engine shared.EngineType,
+	DB database.Database,
+) ([]views.WorkflowLastRun, error) {
+	query := `
+		SELECT 
+			workflow.id AS workflow_id, 
+			workflow.schedule, 
+			workflow_dag_result.created_at AS last_run_at 
+		FROM 
+			workflow, 
+			workflow_dag, 
+			workflow_dag_result, 
+			(
+				SELECT 
+					workflow.id, 
+					MAX(workflow_dag_result.created_at) AS created_at 
+				FROM 
+					workflow, 
+					workflow_dag, 
+					workflow_dag_result 
+				WHERE 
+					workflow.id = workflow_dag.workflow_id 
+					AND workflow_dag.id = workflow_dag_result.workflow_dag_id 
+				GROUP BY workflow.id
+			) AS workflow_latest_run 
+		WHERE 
+			workflow.id = workflow_dag.workflow_id 
+			AND workflow_dag.id = workflow_dag_result.workflow_dag_id 
+			AND workflow.id = workflow_latest_run.id 
+			AND workflow_dag_result.created_at = workflow_latest_run.created_at
+			AND workflow_dag.engine_config->>'type' = $1;`
+
+	var lastRuns []views.WorkflowLastRun
+	args := []interface{}{engine}
+
+	err := DB.Query(ctx, &lastRuns, query, args...)
+	return lastRuns, err
+}
+
+func (*workflowRepo) GetLatestStatusesByOrg(ctx context.Context, orgID string, DB database.Database) ([]views.LatestWorkflowStatus, error) {
+	// This is the same query as the SQLite implementation, except for how the
+	// engine type is extracted from the DAG's engine config.
+	query := `
+		WITH workflow_results AS
+		(
+			SELECT 
+				wf.id AS id, wf.name AS name,
+		 		wf.description AS description, wf.created_at AS created_at,
+		 		wfdr.created_at AS run_at, wfdr.status as status,
+				wfdr.id as result_id, wfd.id AS dag_id,
+				wfd.engine_config->>'type' as engine
+			FROM 
+				workflow AS wf
+				INNER JOIN app_user ON wf.user_id = app_user.id
+				INNER JOIN workflow_dag AS wfd ON wf.id = wfd.workflow_id
+				LEFT JOIN workflow_dag_result AS wfdr ON wfd.id = wfdr.workflow_dag_id
+			WHERE 
+				app_user.organization_id = $1
+		),
+		latest_result AS
+		(
+			SELECT 
+				id, MAX(run_at) AS last_run_at
+	  		FROM 
+				workflow_results
+	  		GROUP BY 
+				id
+		)
+		SELECT 
+			wfr.id,
+			wfr.name,
+			wfr.description,
+			wfr.created_at,
+			wfr.result_id,
+			wfr.dag_id, 
+			wfr.run_at AS last_run_at,
+			wfr.status,
+			wfr.engine
+		FROM 
+			workflow_results AS wfr, latest_result AS lr
+		WHERE 
+			wfr.id = lr.id
+			AND 
+			(	
+				wfr.run_at = lr.last_run_at
+				OR 
+				(
+					wfr.run_at IS NULL 
+					AND lr.last_run_at IS NULL
+				)
+			)
+		ORDER BY 
+			created_at DESC;`
+	args := []interface{}{orgID}
+
+	var latestWorkflowResponse []views.LatestWorkflowStatus
+	err := DB.Query(ctx, &latestWorkflowResponse, query, args...)
+	return latestWorkflowResponse, err
+}
+
+func (*workflowRepo) RemoveNotificationFromSettings(ctx context.Context, notificationIntegrationID uuid.UUID, DB database.Database) error {
+	query := `
+	UPDATE workflow
+	SET
+		notification_settings = notification_settings #- ARRAY['settings', $1::text]
+	WHERE
+		notification_settings IS NOT NULL
+		AND notification_settings #> ARRAY['settings', $1::text] IS NOT NULL;`
+	return DB.Execute(ctx, query, notificationIntegrationID.String())
+}
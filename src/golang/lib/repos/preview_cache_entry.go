@@ -0,0 +1,43 @@
+package repos
+
+import (
+	"context"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/google/uuid"
+)
+
+// PreviewCacheEntry defines all of the database operations that can be performed for a PreviewCacheEntry.
+type PreviewCacheEntry interface {
+	previewCacheEntryReader
+	previewCacheEntryWriter
+}
+
+type previewCacheEntryReader interface {
+	// GetBatch returns the PreviewCacheEntries with signatures.
+	GetBatch(ctx context.Context, signatures []uuid.UUID, DB database.Database) ([]models.PreviewCacheEntry, error)
+
+	// List returns all PreviewCacheEntries, ordered from least to most recently accessed.
+	List(ctx context.Context, DB database.Database) ([]models.PreviewCacheEntry, error)
+}
+
+type previewCacheEntryWriter interface {
+	// Create inserts a new PreviewCacheEntry with the specified fields.
+	Create(
+		ctx context.Context,
+		signature uuid.UUID,
+		artifactContentPath string,
+		artifactMetadataPath string,
+		opMetadataPath string,
+		size int64,
+		DB database.Database,
+	) (*models.PreviewCacheEntry, error)
+
+	// UpdateLastAccessedAt sets the last access time of the PreviewCacheEntries with signatures.
+	UpdateLastAccessedAt(ctx context.Context, signatures []uuid.UUID, lastAccessedAt time.Time, DB database.Database) error
+
+	// Delete deletes the PreviewCacheEntry with signature.
+	Delete(ctx context.Context, signature uuid.UUID, DB database.Database) error
+}
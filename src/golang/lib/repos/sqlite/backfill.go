@@ -0,0 +1,177 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+type backfillRepo struct {
+	backfillReader
+	backfillWriter
+}
+
+type backfillReader struct{}
+
+type backfillWriter struct{}
+
+func NewBackfillRepo() repos.Backfill {
+	return &backfillRepo{
+		backfillReader: backfillReader{},
+		backfillWriter: backfillWriter{},
+	}
+}
+
+func (*backfillReader) Get(ctx context.Context, ID uuid.UUID, DB database.Database) (*models.Backfill, error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM workflow_backfill WHERE id = $1;`,
+		models.BackfillCols(),
+	)
+	args := []interface{}{ID}
+
+	return getBackfill(ctx, DB, query, args...)
+}
+
+func (*backfillReader) GetByWorkflow(ctx context.Context, workflowID uuid.UUID, DB database.Database) ([]models.Backfill, error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM workflow_backfill WHERE workflow_id = $1 ORDER BY start_time;`,
+		models.BackfillCols(),
+	)
+	args := []interface{}{workflowID}
+
+	return getBackfills(ctx, DB, query, args...)
+}
+
+func (*backfillWriter) Create(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	cronSchedule string,
+	timezone string,
+	startTime time.Time,
+	endTime time.Time,
+	maxConcurrency int,
+	DB database.Database,
+) (*models.Backfill, error) {
+	cols := []string{
+		models.BackfillID,
+		models.BackfillWorkflowID,
+		models.BackfillCronSchedule,
+		models.BackfillTimezone,
+		models.BackfillStartTime,
+		models.BackfillEndTime,
+		models.BackfillMaxConcurrency,
+		models.BackfillStatus,
+		models.BackfillExecState,
+	}
+	query := DB.PrepareInsertWithReturnAllStmt(models.BackfillTable, cols, models.BackfillCols())
+
+	ID, err := GenerateUniqueUUID(ctx, models.BackfillTable, DB)
+	if err != nil {
+		return nil, err
+	}
+
+	execState := createPendingExecState()
+	args := []interface{}{
+		ID,
+		workflowID,
+		cronSchedule,
+		timezone,
+		startTime,
+		endTime,
+		maxConcurrency,
+		execState.Status,
+		execState,
+	}
+
+	return getBackfill(ctx, DB, query, args...)
+}
+
+func (*backfillWriter) DeleteByWorkflow(ctx context.Context, workflowID uuid.UUID, DB database.Database) error {
+	query := `DELETE FROM workflow_backfill WHERE workflow_id = $1;`
+	args := []interface{}{workflowID}
+
+	return DB.Execute(ctx, query, args...)
+}
+
+func (*backfillWriter) Update(ctx context.Context, ID uuid.UUID, changes map[string]interface{}, DB database.Database) (*models.Backfill, error) {
+	var backfill models.Backfill
+	err := repos.UpdateRecordToDest(
+		ctx,
+		&backfill,
+		changes,
+		models.BackfillTable,
+		models.BackfillID,
+		ID,
+		models.BackfillCols(),
+		DB,
+	)
+
+	return &backfill, err
+}
+
+func (*backfillWriter) UpdateBatchStatusByStatus(
+	ctx context.Context,
+	from shared.ExecutionStatus,
+	to shared.ExecutionStatus,
+	DB database.Database,
+) ([]models.Backfill, error) {
+	setExecStateFragment, args, err := generateUpdateExecStateSnippet(
+		models.BackfillExecState,
+		to,
+		time.Now(),
+		0, /* offset */
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s SET
+			%s,
+			status = $%d
+		WHERE
+			status = $%d
+		RETURNING %s;`,
+		models.BackfillTable,
+		setExecStateFragment,
+		len(args)+1,
+		len(args)+2,
+		models.BackfillCols(),
+	)
+
+	args = append(args, to)
+	args = append(args, from)
+	var backfills []models.Backfill
+	err = DB.Query(ctx, &backfills, query, args...)
+	return backfills, err
+}
+
+func getBackfills(ctx context.Context, DB database.Database, query string, args ...interface{}) ([]models.Backfill, error) {
+	var backfills []models.Backfill
+	err := DB.Query(ctx, &backfills, query, args...)
+	return backfills, err
+}
+
+func getBackfill(ctx context.Context, DB database.Database, query string, args ...interface{}) (*models.Backfill, error) {
+	backfills, err := getBackfills(ctx, DB, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(backfills) == 0 {
+		return nil, database.ErrNoRows()
+	}
+
+	if len(backfills) != 1 {
+		return nil, errors.Newf("Expected 1 Backfill but got %v", len(backfills))
+	}
+
+	return &backfills[0], nil
+}
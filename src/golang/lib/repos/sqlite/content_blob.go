@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/dropbox/godropbox/errors"
+)
+
+type contentBlobRepo struct {
+	contentBlobReader
+	contentBlobWriter
+}
+
+type contentBlobReader struct{}
+
+type contentBlobWriter struct{}
+
+func NewContentBlobRepo() repos.ContentBlob {
+	return &contentBlobRepo{
+		contentBlobReader: contentBlobReader{},
+		contentBlobWriter: contentBlobWriter{},
+	}
+}
+
+func (*contentBlobReader) Get(ctx context.Context, hash string, DB database.Database) (*models.ContentBlob, error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM content_blob WHERE hash = $1;`,
+		models.ContentBlobCols(),
+	)
+	args := []interface{}{hash}
+
+	return getContentBlob(ctx, DB, query, args...)
+}
+
+func (*contentBlobReader) List(ctx context.Context, DB database.Database) ([]models.ContentBlob, error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM content_blob;`,
+		models.ContentBlobCols(),
+	)
+
+	return getContentBlobs(ctx, DB, query)
+}
+
+func (*contentBlobWriter) IncrementRefCount(ctx context.Context, hash string, DB database.Database) (*models.ContentBlob, error) {
+	query := fmt.Sprintf(
+		`INSERT INTO content_blob (%s) VALUES ($1, 1, $2)
+		ON CONFLICT (hash) DO UPDATE SET ref_count = content_blob.ref_count + 1
+		RETURNING %s;`,
+		models.ContentBlobCols(),
+		models.ContentBlobCols(),
+	)
+	args := []interface{}{hash, time.Now()}
+
+	return getContentBlob(ctx, DB, query, args...)
+}
+
+func (*contentBlobWriter) DecrementRefCount(ctx context.Context, hash string, DB database.Database) (*models.ContentBlob, error) {
+	query := fmt.Sprintf(
+		`UPDATE content_blob SET ref_count = ref_count - 1 WHERE hash = $1 RETURNING %s;`,
+		models.ContentBlobCols(),
+	)
+	args := []interface{}{hash}
+
+	return getContentBlob(ctx, DB, query, args...)
+}
+
+func (*contentBlobWriter) Delete(ctx context.Context, hash string, DB database.Database) error {
+	query := `DELETE FROM content_blob WHERE hash = $1;`
+	args := []interface{}{hash}
+
+	return DB.Execute(ctx, query, args...)
+}
+
+func getContentBlobs(ctx context.Context, DB database.Database, query string, args ...interface{}) ([]models.ContentBlob, error) {
+	var contentBlobs []models.ContentBlob
+	err := DB.Query(ctx, &contentBlobs, query, args...)
+	return contentBlobs, err
+}
+
+func getContentBlob(ctx context.Context, DB database.Database, query string, args ...interface{}) (*models.ContentBlob, error) {
+	contentBlobs, err := getContentBlobs(ctx, DB, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(contentBlobs) == 0 {
+		return nil, database.ErrNoRows()
+	}
+
+	if len(contentBlobs) != 1 {
+		return nil, errors.Newf("Expected 1 ContentBlob but got %v", len(contentBlobs))
+	}
+
+	return &contentBlobs[0], nil
+}
@@ -296,6 +296,7 @@ func (*dagWriter) Create(
 	workflowID uuid.UUID,
 	storageConfig *shared.StorageConfig,
 	engineConfig *shared.EngineConfig,
+	storageIntegrationID *uuid.UUID,
 	DB database.Database,
 ) (*models.DAG, error) {
 	cols := []string{
@@ -304,6 +305,7 @@ func (*dagWriter) Create(
 		models.DagCreatedAt,
 		models.DagStorageConfig,
 		models.DagEngineConfig,
+		models.DagStorageIntegrationID,
 	}
 	query := DB.PrepareInsertWithReturnAllStmt(models.DagTable, cols, models.DAGCols())
 
@@ -318,6 +320,7 @@ func (*dagWriter) Create(
 		time.Now(),
 		storageConfig,
 		engineConfig,
+		storageIntegrationID,
 	}
 
 	return getDAG(ctx, DB, query, args...)
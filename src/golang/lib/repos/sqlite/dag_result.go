@@ -52,6 +52,16 @@ func (*dagResultReader) GetBatch(ctx context.Context, IDs []uuid.UUID, DB databa
 	return getDAGResults(ctx, DB, query, args...)
 }
 
+func (*dagResultReader) GetByBackfill(ctx context.Context, backfillID uuid.UUID, DB database.Database) ([]models.DAGResult, error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM workflow_dag_result WHERE backfill_id = $1 ORDER BY execution_time;`,
+		models.DAGResultCols(),
+	)
+	args := []interface{}{backfillID}
+
+	return getDAGResults(ctx, DB, query, args...)
+}
+
 func (*dagResultReader) GetByWorkflow(ctx context.Context, workflowID uuid.UUID, DB database.Database) ([]models.DAGResult, error) {
 	query := fmt.Sprintf(
 		`SELECT %s 
@@ -66,6 +76,22 @@ func (*dagResultReader) GetByWorkflow(ctx context.Context, workflowID uuid.UUID,
 	return getDAGResults(ctx, DB, query, args...)
 }
 
+func (*dagResultReader) GetInProgressByWorkflow(ctx context.Context, workflowID uuid.UUID, DB database.Database) ([]models.DAGResult, error) {
+	query := fmt.Sprintf(
+		`SELECT %s 
+		FROM workflow_dag_result, workflow_dag 
+		WHERE 
+			workflow_dag_result.workflow_dag_id = workflow_dag.id 
+			AND workflow_dag.workflow_id = $1
+			AND workflow_dag_result.status IN ($2, $3)
+		ORDER BY workflow_dag_result.created_at;`,
+		models.DAGResultColsWithPrefix(),
+	)
+	args := []interface{}{workflowID, shared.PendingExecutionStatus, shared.RunningExecutionStatus}
+
+	return getDAGResults(ctx, DB, query, args...)
+}
+
 func (*dagResultReader) GetKOffsetByWorkflow(ctx context.Context, workflowID uuid.UUID, k int, DB database.Database) ([]models.DAGResult, error) {
 	// https://itecnote.com/tecnote/sqlite-limit-offset-query/
 	// `LIMIT <skip>, <count>` is equivalent to `LIMIT <count> OFFSET <skip>`
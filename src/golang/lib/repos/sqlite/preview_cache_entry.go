@@ -0,0 +1,133 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/database/stmt_preparers"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+type previewCacheEntryRepo struct {
+	previewCacheEntryReader
+	previewCacheEntryWriter
+}
+
+type previewCacheEntryReader struct{}
+
+type previewCacheEntryWriter struct{}
+
+func NewPreviewCacheEntryRepo() repos.PreviewCacheEntry {
+	return &previewCacheEntryRepo{
+		previewCacheEntryReader: previewCacheEntryReader{},
+		previewCacheEntryWriter: previewCacheEntryWriter{},
+	}
+}
+
+func (*previewCacheEntryReader) GetBatch(ctx context.Context, signatures []uuid.UUID, DB database.Database) ([]models.PreviewCacheEntry, error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM preview_cache_entry WHERE signature IN (%s);`,
+		models.PreviewCacheEntryCols(),
+		stmt_preparers.GenerateArgsList(len(signatures), 1),
+	)
+	args := stmt_preparers.CastIdsListToInterfaceList(signatures)
+
+	return getPreviewCacheEntries(ctx, DB, query, args...)
+}
+
+func (*previewCacheEntryReader) List(ctx context.Context, DB database.Database) ([]models.PreviewCacheEntry, error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM preview_cache_entry ORDER BY last_accessed_at ASC;`,
+		models.PreviewCacheEntryCols(),
+	)
+
+	return getPreviewCacheEntries(ctx, DB, query)
+}
+
+func (*previewCacheEntryWriter) Create(
+	ctx context.Context,
+	signature uuid.UUID,
+	artifactContentPath string,
+	artifactMetadataPath string,
+	opMetadataPath string,
+	size int64,
+	DB database.Database,
+) (*models.PreviewCacheEntry, error) {
+	cols := []string{
+		models.PreviewCacheEntrySignature,
+		models.PreviewCacheEntryArtifactContentPath,
+		models.PreviewCacheEntryArtifactMetadataPath,
+		models.PreviewCacheEntryOpMetadataPath,
+		models.PreviewCacheEntrySize,
+		models.PreviewCacheEntryCreatedAt,
+		models.PreviewCacheEntryLastAccessedAt,
+	}
+	query := DB.PrepareInsertWithReturnAllStmt(models.PreviewCacheEntryTable, cols, models.PreviewCacheEntryCols())
+
+	now := time.Now()
+	args := []interface{}{
+		signature,
+		artifactContentPath,
+		artifactMetadataPath,
+		opMetadataPath,
+		size,
+		now,
+		now,
+	}
+
+	return getPreviewCacheEntry(ctx, DB, query, args...)
+}
+
+func (*previewCacheEntryWriter) UpdateLastAccessedAt(
+	ctx context.Context,
+	signatures []uuid.UUID,
+	lastAccessedAt time.Time,
+	DB database.Database,
+) error {
+	if len(signatures) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf(
+		`UPDATE preview_cache_entry SET last_accessed_at = $1 WHERE signature IN (%s);`,
+		stmt_preparers.GenerateArgsList(len(signatures), 2),
+	)
+	args := append([]interface{}{lastAccessedAt}, stmt_preparers.CastIdsListToInterfaceList(signatures)...)
+
+	return DB.Execute(ctx, query, args...)
+}
+
+func (*previewCacheEntryWriter) Delete(ctx context.Context, signature uuid.UUID, DB database.Database) error {
+	query := `DELETE FROM preview_cache_entry WHERE signature = $1;`
+	args := []interface{}{signature}
+
+	return DB.Execute(ctx, query, args...)
+}
+
+func getPreviewCacheEntries(ctx context.Context, DB database.Database, query string, args ...interface{}) ([]models.PreviewCacheEntry, error) {
+	var entries []models.PreviewCacheEntry
+	err := DB.Query(ctx, &entries, query, args...)
+	return entries, err
+}
+
+func getPreviewCacheEntry(ctx context.Context, DB database.Database, query string, args ...interface{}) (*models.PreviewCacheEntry, error) {
+	entries, err := getPreviewCacheEntries(ctx, DB, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, database.ErrNoRows()
+	}
+
+	if len(entries) != 1 {
+		return nil, errors.Newf("Expected 1 PreviewCacheEntry but got %v", len(entries))
+	}
+
+	return &entries[0], nil
+}
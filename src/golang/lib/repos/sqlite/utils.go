@@ -107,14 +107,14 @@ func generateUpdateExecStateSnippet(
 			'$.timestamps.%s',
 			$%d
 		) AS BLOB)`,
-			columnAccessPath,
-			columnAccessPath,
-			offset+1,
-			timestampField,
-			offset+2,
-		), []interface{}{
-			status, string(timestampValue),
-		}, nil
+		columnAccessPath,
+		columnAccessPath,
+		offset+1,
+		timestampField,
+		offset+2,
+	), []interface{}{
+		status, string(timestampValue),
+	}, nil
 }
 
 // Creates the initial execution state for when we initially kick off some process (eg. storage migration).
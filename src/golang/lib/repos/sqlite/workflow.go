@@ -252,6 +252,7 @@ func (*workflowWriter) Create(
 	schedule *shared.Schedule,
 	retentionPolicy *shared.RetentionPolicy,
 	notificationSettings *shared.NotificationSettings,
+	maxConcurrentOperators int,
 	DB database.Database,
 ) (*models.Workflow, error) {
 	cols := []string{
@@ -263,6 +264,7 @@ func (*workflowWriter) Create(
 		models.WorkflowCreatedAt,
 		models.WorkflowRetentionPolicy,
 		models.WorkflowNotificationSettings,
+		models.WorkflowMaxConcurrentOperators,
 	}
 	query := DB.PrepareInsertWithReturnAllStmt(models.WorkflowTable, cols, models.WorkflowCols())
 
@@ -271,7 +273,7 @@ func (*workflowWriter) Create(
 		return nil, err
 	}
 
-	args := []interface{}{ID, userID, name, description, schedule, time.Now(), retentionPolicy, notificationSettings}
+	args := []interface{}{ID, userID, name, description, schedule, time.Now(), retentionPolicy, notificationSettings, maxConcurrentOperators}
 	return getWorkflow(ctx, DB, query, args...)
 }
 
@@ -301,6 +303,13 @@ func (*workflowWriter) Update(
 	return &workflow, err
 }
 
+func (*workflowWriter) Lock(ctx context.Context, ID uuid.UUID, DB database.Database) error {
+	// Updating the row locks it in Postgres, and locks the whole database in SQLite.
+	query := fmt.Sprintf(`UPDATE workflow SET id = id WHERE id = $1 RETURNING %s;`, models.WorkflowCols())
+	_, err := getWorkflow(ctx, DB, query, ID)
+	return err
+}
+
 func (*workflowWriter) RemoveNotificationFromSettings(ctx context.Context, notificationIntegrationID uuid.UUID, DB database.Database) error {
 	query := `
 	UPDATE workflow
@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func (ts *TestSuite) TestBackfill_Get() {
+	backfills := ts.seedBackfill(1)
+	expectedBackfill := backfills[0]
+
+	actualBackfill, err := ts.backfill.Get(ts.ctx, expectedBackfill.ID, ts.DB)
+	require.Nil(ts.T(), err)
+	requireDeepEqual(ts.T(), expectedBackfill, *actualBackfill)
+}
+
+func (ts *TestSuite) TestBackfill_GetByWorkflow() {
+	expectedBackfills := ts.seedBackfill(3)
+
+	actualBackfills, err := ts.backfill.GetByWorkflow(ts.ctx, expectedBackfills[0].WorkflowID, ts.DB)
+	require.Nil(ts.T(), err)
+	requireDeepEqual(ts.T(), expectedBackfills, actualBackfills)
+}
+
+func (ts *TestSuite) TestBackfill_Create() {
+	workflows := ts.seedWorkflow(1)
+	workflow := workflows[0]
+
+	startTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	expectedBackfill := &models.Backfill{
+		WorkflowID:     workflow.ID,
+		CronSchedule:   "0 * * * *",
+		Timezone:       "America/New_York",
+		StartTime:      startTime,
+		EndTime:        startTime.Add(time.Hour),
+		MaxConcurrency: 2,
+		Status:         shared.PendingExecutionStatus,
+	}
+
+	actualBackfill, err := ts.backfill.Create(
+		ts.ctx,
+		expectedBackfill.WorkflowID,
+		expectedBackfill.CronSchedule,
+		expectedBackfill.Timezone,
+		expectedBackfill.StartTime,
+		expectedBackfill.EndTime,
+		expectedBackfill.MaxConcurrency,
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+	require.NotEqual(ts.T(), uuid.Nil, actualBackfill.ID)
+	require.Equal(ts.T(), shared.PendingExecutionStatus, actualBackfill.ExecState.Status)
+
+	expectedBackfill.ID = actualBackfill.ID
+	// ExecState is set equal since the timestamps are generated by the repo.
+	expectedBackfill.ExecState = actualBackfill.ExecState
+	require.True(ts.T(), expectedBackfill.StartTime.Equal(actualBackfill.StartTime))
+	require.True(ts.T(), expectedBackfill.EndTime.Equal(actualBackfill.EndTime))
+	expectedBackfill.StartTime = actualBackfill.StartTime
+	expectedBackfill.EndTime = actualBackfill.EndTime
+
+	requireDeepEqual(ts.T(), expectedBackfill, actualBackfill)
+}
+
+func (ts *TestSuite) TestBackfill_UpdateBatchStatusByStatus() {
+	backfills := ts.seedBackfill(2)
+
+	updatedBackfills, err := ts.backfill.UpdateBatchStatusByStatus(
+		ts.ctx,
+		shared.PendingExecutionStatus,
+		shared.CanceledExecutionStatus,
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+	require.Len(ts.T(), updatedBackfills, len(backfills))
+
+	for _, backfill := range updatedBackfills {
+		require.Equal(ts.T(), shared.CanceledExecutionStatus, backfill.Status)
+		require.Equal(ts.T(), shared.CanceledExecutionStatus, backfill.ExecState.Status)
+	}
+}
+
+func (ts *TestSuite) TestBackfill_DeleteByWorkflow() {
+	backfills := ts.seedBackfill(2)
+
+	err := ts.backfill.DeleteByWorkflow(ts.ctx, backfills[0].WorkflowID, ts.DB)
+	require.Nil(ts.T(), err)
+
+	_, err = ts.backfill.Get(ts.ctx, backfills[0].ID, ts.DB)
+	require.True(ts.T(), aq_errors.Is(err, database.ErrNoRows()))
+}
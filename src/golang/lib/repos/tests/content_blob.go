@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"github.com/aqueducthq/aqueduct/lib/database"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func (ts *TestSuite) TestContentBlob_IncrementRefCount() {
+	hash := randString(64)
+
+	contentBlob, err := ts.contentBlob.IncrementRefCount(ts.ctx, hash, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), hash, contentBlob.Hash)
+	require.Equal(ts.T(), 1, contentBlob.RefCount)
+
+	contentBlob, err = ts.contentBlob.IncrementRefCount(ts.ctx, hash, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), 2, contentBlob.RefCount)
+
+	actualContentBlob, err := ts.contentBlob.Get(ts.ctx, hash, ts.DB)
+	require.Nil(ts.T(), err)
+	requireDeepEqual(ts.T(), *contentBlob, *actualContentBlob)
+}
+
+func (ts *TestSuite) TestContentBlob_List() {
+	hashes := []string{randString(64), randString(64)}
+	for _, hash := range hashes {
+		_, err := ts.contentBlob.IncrementRefCount(ts.ctx, hash, ts.DB)
+		require.Nil(ts.T(), err)
+	}
+
+	contentBlobs, err := ts.contentBlob.List(ts.ctx, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Len(ts.T(), contentBlobs, len(hashes))
+
+	actualHashes := make([]string, 0, len(contentBlobs))
+	for _, contentBlob := range contentBlobs {
+		actualHashes = append(actualHashes, contentBlob.Hash)
+	}
+	require.ElementsMatch(ts.T(), hashes, actualHashes)
+}
+
+func (ts *TestSuite) TestContentBlob_DecrementRefCount() {
+	hash := randString(64)
+
+	_, err := ts.contentBlob.IncrementRefCount(ts.ctx, hash, ts.DB)
+	require.Nil(ts.T(), err)
+	_, err = ts.contentBlob.IncrementRefCount(ts.ctx, hash, ts.DB)
+	require.Nil(ts.T(), err)
+
+	contentBlob, err := ts.contentBlob.DecrementRefCount(ts.ctx, hash, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), 1, contentBlob.RefCount)
+
+	_, err = ts.contentBlob.DecrementRefCount(ts.ctx, randString(64), ts.DB)
+	require.True(ts.T(), aq_errors.Is(err, database.ErrNoRows()))
+}
+
+func (ts *TestSuite) TestContentBlob_Delete() {
+	hash := randString(64)
+
+	_, err := ts.contentBlob.IncrementRefCount(ts.ctx, hash, ts.DB)
+	require.Nil(ts.T(), err)
+
+	err = ts.contentBlob.Delete(ts.ctx, hash, ts.DB)
+	require.Nil(ts.T(), err)
+
+	_, err = ts.contentBlob.Get(ts.ctx, hash, ts.DB)
+	require.True(ts.T(), aq_errors.Is(err, database.ErrNoRows()))
+}
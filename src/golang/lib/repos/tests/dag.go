@@ -3,6 +3,7 @@ package tests
 import (
 	"github.com/aqueducthq/aqueduct/lib/models"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/utils"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
@@ -132,6 +133,7 @@ func (ts *TestSuite) TestDAG_Create() {
 			Type:           shared.AqueductEngineType,
 			AqueductConfig: &shared.AqueductConfig{},
 		},
+		StorageIntegrationID: utils.NullUUID{IsNull: true},
 	}
 
 	actualDAG, err := ts.dag.Create(
@@ -139,6 +141,7 @@ func (ts *TestSuite) TestDAG_Create() {
 		expectedDAG.WorkflowID,
 		&expectedDAG.StorageConfig,
 		&expectedDAG.EngineConfig,
+		nil, /* storageIntegrationID */
 		ts.DB,
 	)
 	require.Nil(ts.T(), err)
@@ -5,6 +5,7 @@ import (
 
 	"github.com/aqueducthq/aqueduct/lib/models"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/utils"
 	"github.com/aqueducthq/aqueduct/lib/models/views"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -43,6 +44,70 @@ func (ts *TestSuite) TestDAGResult_GetByWorkflow() {
 	requireDeepEqualDAGResults(ts.T(), expectedDAGResults, actualDAGResults)
 }
 
+func (ts *TestSuite) TestDAGResult_GetInProgressByWorkflow() {
+	dags := ts.seedDAG(1)
+	dag := dags[0]
+
+	dagResults := ts.seedDAGResultWithDAG(3, []uuid.UUID{dag.ID, dag.ID, dag.ID})
+
+	// Only the pending and running DAGResults are in progress.
+	execState := dagResults[0].ExecState.ExecutionState
+	execState.Status = shared.SucceededExecutionStatus
+	_, err := ts.dagResult.Update(
+		ts.ctx,
+		dagResults[0].ID,
+		map[string]interface{}{
+			models.DAGResultStatus:    execState.Status,
+			models.DAGResultExecState: &execState,
+		},
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+
+	runningDAGResult, err := ts.dagResult.Update(
+		ts.ctx,
+		dagResults[1].ID,
+		map[string]interface{}{
+			models.DAGResultStatus: shared.RunningExecutionStatus,
+		},
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+
+	actualDAGResults, err := ts.dagResult.GetInProgressByWorkflow(ts.ctx, dag.WorkflowID, ts.DB)
+	require.Nil(ts.T(), err)
+	requireDeepEqualDAGResults(ts.T(), []models.DAGResult{*runningDAGResult, dagResults[2]}, actualDAGResults)
+}
+
+func (ts *TestSuite) TestDAGResult_GetByBackfill() {
+	dags := ts.seedDAG(1)
+	dag := dags[0]
+
+	backfills := ts.seedBackfillWithWorkflow(1, dag.WorkflowID)
+	backfill := backfills[0]
+
+	dagResults := ts.seedDAGResultWithDAG(3, []uuid.UUID{dag.ID, dag.ID, dag.ID})
+
+	expectedDAGResults := make([]models.DAGResult, 0, 2)
+	for i, dagResult := range dagResults[:2] {
+		updatedDAGResult, err := ts.dagResult.Update(
+			ts.ctx,
+			dagResult.ID,
+			map[string]interface{}{
+				models.DAGResultBackfillID:    backfill.ID,
+				models.DAGResultExecutionTime: backfill.StartTime.Add(time.Duration(i) * time.Minute),
+			},
+			ts.DB,
+		)
+		require.Nil(ts.T(), err)
+		expectedDAGResults = append(expectedDAGResults, *updatedDAGResult)
+	}
+
+	actualDAGResults, err := ts.dagResult.GetByBackfill(ts.ctx, backfill.ID, ts.DB)
+	require.Nil(ts.T(), err)
+	requireDeepEqualDAGResults(ts.T(), expectedDAGResults, actualDAGResults)
+}
+
 func (ts *TestSuite) TestDAGResult_GetKOffsetByWorkflow() {
 	dags := ts.seedDAG(1)
 	dag := dags[0]
@@ -120,6 +185,9 @@ func (ts *TestSuite) TestDAGResult_Create() {
 				},
 			},
 		},
+		SourceDAGResultID: utils.NullUUID{IsNull: true},
+		BackfillID:        utils.NullUUID{IsNull: true},
+		ExecutionTime:     utils.NullTime{IsNull: true},
 	}
 
 	actualDAGResult, err := ts.dagResult.Create(
@@ -4,18 +4,48 @@ import (
 	"flag"
 	"testing"
 
+	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/stretchr/testify/suite"
 )
 
-var runTests = flag.Bool("database", false, "If this flag is set, the database integration tests will be run.")
+var (
+	runTests = flag.Bool("database", false, "If this flag is set, the database integration tests will be run.")
+	dbType   = flag.String("type", database.SqliteType, "The type of database to run the integration tests against: sqlite or postgres.")
+
+	// Postgres Config
+	pgHost     = flag.String("host", "localhost", "The host of the Postgres server to connect to.")
+	pgPort     = flag.String("port", "5432", "The port number to connect to the Postgres database.")
+	pgUser     = flag.String("username", "postgres", "The username for connecting to the Postgres database.")
+	pgPassword = flag.String("password", "", "The password for connecting to the Postgres database.")
+	pgDatabase = flag.String("postgres-database", "aqueduct_test", "The Postgres database to run the tests against. It is cleared after each test.")
+)
 
 // TestDatabaseSuite is the entrypoint for all database integration tests
-// in this package.
+// in this package. They are run against an in-memory SQLite database, or against
+// a Postgres database if `-type postgres` is specified.
 func TestDatabaseSuite(t *testing.T) {
 	flag.Parse()
 	if !*runTests {
 		t.Skip("Skipping database integration tests.")
 	}
 
-	suite.Run(t, new(TestSuite))
+	ts := new(TestSuite)
+	switch database.Type(*dbType) {
+	case database.SqliteType:
+	case database.PostgresType:
+		ts.dbConfig = &database.DatabaseConfig{
+			Type: database.PostgresType,
+			Postgres: &database.PostgresConfig{
+				Address:  *pgHost,
+				Port:     *pgPort,
+				UserName: *pgUser,
+				Password: *pgPassword,
+				Database: *pgDatabase,
+			},
+		}
+	default:
+		t.Fatalf("Unknown database type specified: %v", *dbType)
+	}
+
+	suite.Run(t, ts)
 }
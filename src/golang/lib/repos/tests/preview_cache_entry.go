@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func (ts *TestSuite) seedPreviewCacheEntry(count int) []models.PreviewCacheEntry {
+	entries := make([]models.PreviewCacheEntry, 0, count)
+	for i := 0; i < count; i++ {
+		entry, err := ts.previewCacheEntry.Create(
+			ts.ctx,
+			uuid.New(),
+			randString(10),
+			randString(10),
+			randString(10),
+			int64(i+1),
+			ts.DB,
+		)
+		require.Nil(ts.T(), err)
+		entries = append(entries, *entry)
+	}
+	return entries
+}
+
+func (ts *TestSuite) TestPreviewCacheEntry_GetBatch() {
+	entries := ts.seedPreviewCacheEntry(3)
+
+	actualEntries, err := ts.previewCacheEntry.GetBatch(
+		ts.ctx,
+		[]uuid.UUID{entries[0].Signature, entries[2].Signature, uuid.New()},
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+	require.Len(ts.T(), actualEntries, 2)
+
+	actualSignatures := []uuid.UUID{actualEntries[0].Signature, actualEntries[1].Signature}
+	require.ElementsMatch(ts.T(), []uuid.UUID{entries[0].Signature, entries[2].Signature}, actualSignatures)
+}
+
+func (ts *TestSuite) TestPreviewCacheEntry_UpdateLastAccessedAt() {
+	entries := ts.seedPreviewCacheEntry(2)
+
+	// The first entry becomes the most recently accessed one.
+	lastAccessedAt := time.Now().Add(time.Hour)
+	err := ts.previewCacheEntry.UpdateLastAccessedAt(ts.ctx, []uuid.UUID{entries[0].Signature}, lastAccessedAt, ts.DB)
+	require.Nil(ts.T(), err)
+
+	actualEntries, err := ts.previewCacheEntry.List(ts.ctx, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Len(ts.T(), actualEntries, 2)
+	require.Equal(ts.T(), entries[1].Signature, actualEntries[0].Signature)
+	require.Equal(ts.T(), entries[0].Signature, actualEntries[1].Signature)
+	require.True(ts.T(), lastAccessedAt.Equal(actualEntries[1].LastAccessedAt))
+}
+
+func (ts *TestSuite) TestPreviewCacheEntry_Delete() {
+	entries := ts.seedPreviewCacheEntry(2)
+
+	err := ts.previewCacheEntry.Delete(ts.ctx, entries[0].Signature, ts.DB)
+	require.Nil(ts.T(), err)
+
+	actualEntries, err := ts.previewCacheEntry.List(ts.ctx, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Len(ts.T(), actualEntries, 1)
+	requireDeepEqual(ts.T(), entries[1], actualEntries[0])
+}
@@ -220,6 +220,7 @@ func (ts *TestSuite) seedWorkflowWithUser(count int, userIDs []uuid.UUID) []mode
 			schedule,
 			retentionPolicy,
 			&shared.NotificationSettings{},
+			0, /* maxConcurrentOperators */
 			ts.DB,
 		)
 		require.Nil(ts.T(), err)
@@ -273,6 +274,7 @@ func (ts *TestSuite) seedDAGWithWorkflow(count int, workflowIDs []uuid.UUID) []m
 			workflowID,
 			storageConfig,
 			engineConfig,
+			nil, /* storageIntegrationID */
 			ts.DB,
 		)
 		require.Nil(ts.T(), err)
@@ -321,6 +323,37 @@ func (ts *TestSuite) seedDAGResultWithDAG(count int, dagIDs []uuid.UUID) []model
 	return dagResults
 }
 
+// seedBackfill creates count Backfill records. It creates a new Workflow
+// to associate with the Backfills.
+func (ts *TestSuite) seedBackfill(count int) []models.Backfill {
+	workflows := ts.seedWorkflow(1)
+	return ts.seedBackfillWithWorkflow(count, workflows[0].ID)
+}
+
+// seedBackfillWithWorkflow creates count Backfill records for the Workflow with workflowID.
+func (ts *TestSuite) seedBackfillWithWorkflow(count int, workflowID uuid.UUID) []models.Backfill {
+	backfills := make([]models.Backfill, 0, count)
+
+	startTime := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	for i := 0; i < count; i++ {
+		backfill, err := ts.backfill.Create(
+			ts.ctx,
+			workflowID,
+			"0 * * * *",
+			"", /* timezone */
+			startTime.Add(time.Duration(i)*time.Hour),
+			startTime.Add(time.Duration(i+1)*time.Hour),
+			i+1,
+			ts.DB,
+		)
+		require.Nil(ts.T(), err)
+
+		backfills = append(backfills, *backfill)
+	}
+
+	return backfills
+}
+
 // seedDAGEdgeWith creates count DAGEdge records.
 // It creates a new DAG to associate with the DAGEdges.
 // For each DAGEdge, it randomly chooses the fromID, toID, and
@@ -8,6 +8,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/models"
 	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/repos/postgres"
 	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/suite"
@@ -22,6 +23,8 @@ type TestSuite struct {
 	// List of all repos
 	artifact             repos.Artifact
 	artifactResult       repos.ArtifactResult
+	backfill             repos.Backfill
+	contentBlob          repos.ContentBlob
 	dag                  repos.DAG
 	dagEdge              repos.DAGEdge
 	dagResult            repos.DAGResult
@@ -30,43 +33,71 @@ type TestSuite struct {
 	notification         repos.Notification
 	operator             repos.Operator
 	operatorResult       repos.OperatorResult
+	previewCacheEntry    repos.PreviewCacheEntry
 	schemaVersion        repos.SchemaVersion
 	storageMigration     repos.StorageMigration
 	user                 repos.User
 	watcher              repos.Watcher
 	workflow             repos.Workflow
 
-	DB database.Database
+	// The database that the tests are run against. If nil, an in-memory
+	// SQLite database is used.
+	dbConfig *database.DatabaseConfig
+	DB       database.Database
 }
 
 // SetupSuite is run only once before all tests. It initializes the database
 // connection and creates the repos. It initializes the database schema
 // to the latest version.
 func (ts *TestSuite) SetupSuite() {
-	DB, err := database.NewSqliteInMemoryDatabase(&database.SqliteConfig{})
+	DB, err := newDatabase(ts.dbConfig)
 	if err != nil {
-		ts.T().Errorf("Unable to create SQLite client: %v", err)
+		ts.T().Fatalf("Unable to create database client: %v", err)
 	}
 
 	ts.ctx = context.Background()
 	ts.DB = DB
 
 	// Initialize repos
-	ts.artifact = sqlite.NewArtifactRepo()
-	ts.artifactResult = sqlite.NewArtifactResultRepo()
-	ts.dag = sqlite.NewDAGRepo()
-	ts.dagEdge = sqlite.NewDAGEdgeRepo()
-	ts.dagResult = sqlite.NewDAGResultRepo()
-	ts.executionEnvironment = sqlite.NewExecutionEnvironmentRepo()
-	ts.integration = sqlite.NewIntegrationRepo()
-	ts.notification = sqlite.NewNotificationRepo()
-	ts.operator = sqlite.NewOperatorRepo()
-	ts.operatorResult = sqlite.NewOperatorResultRepo()
-	ts.schemaVersion = sqlite.NewSchemaVersionRepo()
-	ts.storageMigration = sqlite.NewStorageMigrationRepo()
-	ts.user = sqlite.NewUserRepo()
-	ts.watcher = sqlite.NewWatcherRepo()
-	ts.workflow = sqlite.NewWorklowRepo()
+	if DB.Type() == database.PostgresType {
+		ts.artifact = postgres.NewArtifactRepo()
+		ts.artifactResult = postgres.NewArtifactResultRepo()
+		ts.backfill = postgres.NewBackfillRepo()
+		ts.contentBlob = postgres.NewContentBlobRepo()
+		ts.dag = postgres.NewDAGRepo()
+		ts.dagEdge = postgres.NewDAGEdgeRepo()
+		ts.dagResult = postgres.NewDAGResultRepo()
+		ts.executionEnvironment = postgres.NewExecutionEnvironmentRepo()
+		ts.integration = postgres.NewIntegrationRepo()
+		ts.notification = postgres.NewNotificationRepo()
+		ts.operator = postgres.NewOperatorRepo()
+		ts.operatorResult = postgres.NewOperatorResultRepo()
+		ts.previewCacheEntry = postgres.NewPreviewCacheEntryRepo()
+		ts.schemaVersion = postgres.NewSchemaVersionRepo()
+		ts.storageMigration = postgres.NewStorageMigrationRepo()
+		ts.user = postgres.NewUserRepo()
+		ts.watcher = postgres.NewWatcherRepo()
+		ts.workflow = postgres.NewWorkflowRepo()
+	} else {
+		ts.artifact = sqlite.NewArtifactRepo()
+		ts.artifactResult = sqlite.NewArtifactResultRepo()
+		ts.backfill = sqlite.NewBackfillRepo()
+		ts.contentBlob = sqlite.NewContentBlobRepo()
+		ts.dag = sqlite.NewDAGRepo()
+		ts.dagEdge = sqlite.NewDAGEdgeRepo()
+		ts.dagResult = sqlite.NewDAGResultRepo()
+		ts.executionEnvironment = sqlite.NewExecutionEnvironmentRepo()
+		ts.integration = sqlite.NewIntegrationRepo()
+		ts.notification = sqlite.NewNotificationRepo()
+		ts.operator = sqlite.NewOperatorRepo()
+		ts.operatorResult = sqlite.NewOperatorResultRepo()
+		ts.previewCacheEntry = sqlite.NewPreviewCacheEntryRepo()
+		ts.schemaVersion = sqlite.NewSchemaVersionRepo()
+		ts.storageMigration = sqlite.NewStorageMigrationRepo()
+		ts.user = sqlite.NewUserRepo()
+		ts.watcher = sqlite.NewWatcherRepo()
+		ts.workflow = sqlite.NewWorklowRepo()
+	}
 
 	// Init database schema
 	if err := initDBSchema(DB); err != nil {
@@ -75,6 +106,15 @@ func (ts *TestSuite) SetupSuite() {
 	}
 }
 
+// newDatabase connects to the database specified by conf.
+func newDatabase(conf *database.DatabaseConfig) (database.Database, error) {
+	if conf == nil {
+		return database.NewSqliteInMemoryDatabase(&database.SqliteConfig{})
+	}
+
+	return database.NewDatabase(conf)
+}
+
 func initDBSchema(DB database.Database) error {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -107,19 +147,48 @@ func (ts *TestSuite) TearDownTest() {
 	DELETE FROM app_user;
 	DELETE FROM artifact;
 	DELETE FROM artifact_result;
+	DELETE FROM content_blob;
 	DELETE FROM execution_environment;
 	DELETE FROM integration;
 	DELETE FROM notification;
 	DELETE FROM operator;
 	DELETE FROM operator_result;
+	DELETE FROM preview_cache_entry;
 	DELETE FROM schema_version;
 	DELETE FROM storage_migration;
 	DELETE FROM workflow;
+	DELETE FROM workflow_backfill;
 	DELETE FROM workflow_dag;
 	DELETE FROM workflow_dag_edge;
 	DELETE FROM workflow_dag_result;
 	;
 	`
+	if ts.DB.Type() == database.PostgresType {
+		// Postgres enforces foreign keys, so the tables are truncated together.
+		// The schema version is kept, so that the suite can be run against the
+		// same database again.
+		query = `
+		TRUNCATE
+			app_user,
+			artifact,
+			artifact_result,
+			content_blob,
+			execution_environment,
+			integration,
+			notification,
+			operator,
+			operator_result,
+			preview_cache_entry,
+			storage_migration,
+			workflow,
+			workflow_backfill,
+			workflow_dag,
+			workflow_dag_edge,
+			workflow_dag_result
+		CASCADE;
+		`
+	}
+
 	if err := ts.DB.Execute(ts.ctx, query); err != nil {
 		ts.T().Errorf("Unable to clear database: %v", err)
 	}
@@ -1,6 +1,8 @@
 package tests
 
 import (
+	"github.com/aqueducthq/aqueduct/lib/database"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
 	"github.com/aqueducthq/aqueduct/lib/models"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/google/uuid"
@@ -60,6 +62,7 @@ func (ts *TestSuite) TestWorkflow_GetByScheduleTrigger() {
 				KLatestRuns: 5,
 			},
 			&shared.NotificationSettings{},
+			0, /* maxConcurrentOperators */
 			ts.DB,
 		)
 		require.Nil(ts.T(), err)
@@ -92,6 +95,7 @@ func (ts *TestSuite) TestWorkflow_GetTargets() {
 				KLatestRuns: 5,
 			},
 			&shared.NotificationSettings{},
+			0, /* maxConcurrentOperators */
 			ts.DB,
 		)
 		require.Nil(ts.T(), err)
@@ -151,6 +155,7 @@ func (ts *TestSuite) TestWorkflow_Create() {
 				notificationIntegrationID: shared.ErrorNotificationLevel,
 			},
 		},
+		MaxConcurrentOperators: 4,
 	}
 
 	actualWorkflow, err := ts.workflow.Create(
@@ -161,6 +166,7 @@ func (ts *TestSuite) TestWorkflow_Create() {
 		&expectedWorkflow.Schedule,
 		&expectedWorkflow.RetentionPolicy,
 		&expectedWorkflow.NotificationSettings,
+		expectedWorkflow.MaxConcurrentOperators,
 		ts.DB,
 	)
 	require.Nil(ts.T(), err)
@@ -180,6 +186,27 @@ func (ts *TestSuite) TestWorkflow_Delete() {
 	require.Nil(ts.T(), err)
 }
 
+func (ts *TestSuite) TestWorkflow_Lock() {
+	workflows := ts.seedWorkflow(1)
+	workflow := workflows[0]
+
+	txn, err := ts.DB.BeginTx(ts.ctx)
+	require.Nil(ts.T(), err)
+	defer database.TxnRollbackIgnoreErr(ts.ctx, txn)
+
+	err = ts.workflow.Lock(ts.ctx, workflow.ID, txn)
+	require.Nil(ts.T(), err)
+
+	err = ts.workflow.Lock(ts.ctx, uuid.New(), txn)
+	require.True(ts.T(), aq_errors.Is(err, database.ErrNoRows()))
+
+	require.Nil(ts.T(), txn.Commit(ts.ctx))
+
+	actualWorkflow, err := ts.workflow.Get(ts.ctx, workflow.ID, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), workflow.ID, actualWorkflow.ID)
+}
+
 func (ts *TestSuite) TestWorkflow_Update() {
 	workflows := ts.seedWorkflow(1)
 	oldWorkflow := workflows[0]
@@ -237,6 +264,7 @@ func (ts *TestSuite) TestWorkflow_RemoveNotificationFromSettings() {
 		&workflow.Schedule,
 		&workflow.RetentionPolicy,
 		&workflow.NotificationSettings,
+		workflow.MaxConcurrentOperators,
 		ts.DB,
 	)
 	require.Nil(ts.T(), err)
@@ -63,6 +63,7 @@ type workflowWriter interface {
 		schedule *shared.Schedule,
 		retentionPolicy *shared.RetentionPolicy,
 		notificationSettings *shared.NotificationSettings,
+		maxConcurrentOperators int,
 		DB database.Database,
 	) (*models.Workflow, error)
 
@@ -72,6 +73,10 @@ type workflowWriter interface {
 	// Update applies changes to the Workflow with ID. It returns the updated Workflow.
 	Update(ctx context.Context, ID uuid.UUID, changes map[string]interface{}, DB database.Database) (*models.Workflow, error)
 
+	// Lock locks the Workflow with ID until the transaction DB ends, so that the transactions
+	// which lock the same Workflow are serialized. It returns a database.ErrNoRows if no rows are found.
+	Lock(ctx context.Context, ID uuid.UUID, DB database.Database) error
+
 	// RemoveNotificationFromSettings removes `notificationIntegrationID` from notification_settings
 	// field when possible.
 	// If the ID does not appear in any notification_settings field,
@@ -0,0 +1,79 @@
+package response
+
+import (
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/google/uuid"
+)
+
+// This file should map exactly to
+// `src/ui/common/src/handlers/responses/backfill.ts`
+type Backfill struct {
+	ID             uuid.UUID              `json:"id"`
+	WorkflowID     uuid.UUID              `json:"workflow_id"`
+	CronSchedule   string                 `json:"cron_schedule"`
+	Timezone       string                 `json:"timezone"`
+	StartTime      time.Time              `json:"start_time"`
+	EndTime        time.Time              `json:"end_time"`
+	MaxConcurrency int                    `json:"max_concurrency"`
+	ExecState      *shared.ExecutionState `json:"exec_state"`
+
+	// The number of runs in each status, and the status of the run for each tick in the range.
+	RunsByStatus map[shared.ExecutionStatus]int `json:"runs_by_status"`
+	Runs         []BackfillRun                  `json:"runs"`
+}
+
+type BackfillRun struct {
+	ExecutionTime time.Time `json:"execution_time"`
+	// Not set if the run has not started yet.
+	DAGResultID *uuid.UUID             `json:"dag_result_id,omitempty"`
+	Status      shared.ExecutionStatus `json:"status"`
+}
+
+// NewBackfillFromDBObject builds the progress of `dbBackfill` given every tick in its range
+// and the DAG results of the runs that have started so far.
+func NewBackfillFromDBObject(
+	dbBackfill *models.Backfill,
+	ticks []time.Time,
+	dbDAGResults []models.DAGResult,
+) *Backfill {
+	dagResultByTime := make(map[time.Time]*models.DAGResult, len(dbDAGResults))
+	for i, dagResult := range dbDAGResults {
+		if dagResult.ExecutionTime.IsNull {
+			continue
+		}
+		dagResultByTime[dagResult.ExecutionTime.Time.UTC()] = &dbDAGResults[i]
+	}
+
+	runsByStatus := map[shared.ExecutionStatus]int{}
+	runs := make([]BackfillRun, 0, len(ticks))
+	for _, tick := range ticks {
+		run := BackfillRun{
+			ExecutionTime: tick,
+			Status:        shared.PendingExecutionStatus,
+		}
+
+		if dagResult, ok := dagResultByTime[tick.UTC()]; ok {
+			run.DAGResultID = &dagResult.ID
+			run.Status = dagResult.Status
+		}
+
+		runsByStatus[run.Status]++
+		runs = append(runs, run)
+	}
+
+	return &Backfill{
+		ID:             dbBackfill.ID,
+		WorkflowID:     dbBackfill.WorkflowID,
+		CronSchedule:   dbBackfill.CronSchedule,
+		Timezone:       dbBackfill.Timezone,
+		StartTime:      dbBackfill.StartTime,
+		EndTime:        dbBackfill.EndTime,
+		MaxConcurrency: dbBackfill.MaxConcurrency,
+		ExecState:      &dbBackfill.ExecState,
+		RunsByStatus:   runsByStatus,
+		Runs:           runs,
+	}
+}
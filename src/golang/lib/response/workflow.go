@@ -11,26 +11,28 @@ import (
 // This file should map exactly to
 // `src/ui/common/src/handlers/responses/workflow.ts`
 type Workflow struct {
-	ID                   uuid.UUID                   `json:"id"`
-	UserID               uuid.UUID                   `json:"user_id"`
-	Name                 string                      `json:"name"`
-	Description          string                      `json:"description"`
-	Schedule             shared.Schedule             `json:"schedule"`
-	CreatedAt            time.Time                   `json:"created_at"`
-	RetentionPolicy      shared.RetentionPolicy      `json:"retention_policy"`
-	NotificationSettings shared.NotificationSettings `json:"notification_settings"`
+	ID                     uuid.UUID                   `json:"id"`
+	UserID                 uuid.UUID                   `json:"user_id"`
+	Name                   string                      `json:"name"`
+	Description            string                      `json:"description"`
+	Schedule               shared.Schedule             `json:"schedule"`
+	CreatedAt              time.Time                   `json:"created_at"`
+	RetentionPolicy        shared.RetentionPolicy      `json:"retention_policy"`
+	NotificationSettings   shared.NotificationSettings `json:"notification_settings"`
+	MaxConcurrentOperators int                         `json:"max_concurrent_operators"`
 }
 
 func NewWorkflowFromDBObject(dbWorkflow *models.Workflow) *Workflow {
 	return &Workflow{
-		ID:                   dbWorkflow.ID,
-		UserID:               dbWorkflow.UserID,
-		Name:                 dbWorkflow.Name,
-		Description:          dbWorkflow.Description,
-		Schedule:             dbWorkflow.Schedule,
-		CreatedAt:            dbWorkflow.CreatedAt,
-		RetentionPolicy:      dbWorkflow.RetentionPolicy,
-		NotificationSettings: dbWorkflow.NotificationSettings,
+		ID:                     dbWorkflow.ID,
+		UserID:                 dbWorkflow.UserID,
+		Name:                   dbWorkflow.Name,
+		Description:            dbWorkflow.Description,
+		Schedule:               dbWorkflow.Schedule,
+		CreatedAt:              dbWorkflow.CreatedAt,
+		RetentionPolicy:        dbWorkflow.RetentionPolicy,
+		NotificationSettings:   dbWorkflow.NotificationSettings,
+		MaxConcurrentOperators: dbWorkflow.MaxConcurrentOperators,
 	}
 }
 
@@ -56,6 +58,8 @@ type DAGResult struct {
 	ID        uuid.UUID              `json:"id"`
 	DagID     uuid.UUID              `json:"dag_id"`
 	ExecState *shared.ExecutionState `json:"exec_state"`
+	// Set if this run was resumed from a previous, unsuccessful run.
+	SourceDAGResultID *uuid.UUID `json:"source_dag_result_id,omitempty"`
 }
 
 func NewDAGResultFromDBObject(dbDAGResult *models.DAGResult) *DAGResult {
@@ -64,9 +68,15 @@ func NewDAGResultFromDBObject(dbDAGResult *models.DAGResult) *DAGResult {
 		execStatePtr = &dbDAGResult.ExecState.ExecutionState
 	}
 
+	var sourceDAGResultIDPtr *uuid.UUID
+	if !dbDAGResult.SourceDAGResultID.IsNull {
+		sourceDAGResultIDPtr = &dbDAGResult.SourceDAGResultID.UUID
+	}
+
 	return &DAGResult{
-		ID:        dbDAGResult.ID,
-		DagID:     dbDAGResult.DagID,
-		ExecState: execStatePtr,
+		ID:                dbDAGResult.ID,
+		DagID:             dbDAGResult.DagID,
+		ExecState:         execStatePtr,
+		SourceDAGResultID: sourceDAGResultIDPtr,
 	}
 }
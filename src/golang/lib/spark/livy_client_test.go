@@ -171,3 +171,20 @@ func TestWaitForSession(t *testing.T) {
 	assert.NotEmpty(t, err)
 	assert.Containsf(t, err.Error(), expectedErrorMsg, "expected error containing %q, got %s", expectedErrorMsg, err)
 }
+
+func TestCancelStatement(t *testing.T) {
+	cleanup := setup()
+	defer cleanup()
+
+	mux.HandleFunc("/sessions/1/statements/1/cancel", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"msg": "canceled"}`))
+	})
+
+	// Call CancelStatement with session ID 1 and statement ID 1
+	err := client.CancelStatement(1, 1)
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+
+	"github.com/aqueducthq/aqueduct/lib/errors"
+)
+
+// ErrContentCorrupted is thrown when stored content does not match the checksum that was
+// recorded when it was written, e.g. because the object was truncated.
+// NOTE: Callers should use NewContentCorruptedError, so that the error includes the offending path.
+func ErrContentCorrupted() error {
+	return errors.New("Stored content does not match its checksum.")
+}
+
+// NewContentCorruptedError returns an `ErrContentCorrupted` for the content at path.
+func NewContentCorruptedError(path string, expected string, actual string) error {
+	return errors.Wrapf(
+		ErrContentCorrupted(),
+		"The content at %s has checksum %s, but checksum %s was recorded when it was written.",
+		path,
+		actual,
+		expected,
+	)
+}
+
+// Checksum returns the hex-encoded SHA-256 checksum of content. This is the same as the hash
+// that content is stored by if it is content-addressed.
+func Checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyChecksum checks that the content read from path matches checksum.
+// Content without a recorded checksum, i.e. if checksum is empty, is not verified.
+func VerifyChecksum(path string, content []byte, checksum string) error {
+	if checksum == "" {
+		return nil
+	}
+
+	if actual := Checksum(content); actual != checksum {
+		return NewContentCorruptedError(path, checksum, actual)
+	}
+	return nil
+}
+
+// NewVerifyingReader returns a reader over r that checks the content read from path against
+// checksum once r is exhausted. If the content does not match, the final read returns an
+// `ErrContentCorrupted` instead of io.EOF. Content without a recorded checksum is not verified.
+func NewVerifyingReader(r io.ReadCloser, path string, checksum string) io.ReadCloser {
+	if checksum == "" {
+		return r
+	}
+
+	return &verifyingReader{
+		r:        r,
+		path:     path,
+		checksum: checksum,
+		h:        sha256.New(),
+	}
+}
+
+type verifyingReader struct {
+	r        io.ReadCloser
+	path     string
+	checksum string
+	h        hash.Hash
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	v.h.Write(p[:n])
+
+	if err == io.EOF {
+		if actual := hex.EncodeToString(v.h.Sum(nil)); actual != v.checksum {
+			return n, NewContentCorruptedError(v.path, v.checksum, actual)
+		}
+	}
+	return n, err
+}
+
+func (v *verifyingReader) Close() error {
+	return v.r.Close()
+}
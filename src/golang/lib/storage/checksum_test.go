@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyChecksum(t *testing.T) {
+	content := []byte(`{"schema":{},"data":[]}`)
+	checksum := Checksum(content)
+
+	require.Nil(t, VerifyChecksum("path", content, checksum))
+	// Content written before checksums were recorded is not verified.
+	require.Nil(t, VerifyChecksum("path", content[:5], ""))
+
+	err := VerifyChecksum("path", content[:5], checksum)
+	require.True(t, errors.Is(err, ErrContentCorrupted()))
+	require.Contains(t, err.Error(), "path")
+}
+
+func TestVerifyingReader(t *testing.T) {
+	content := []byte("some artifact content")
+	checksum := Checksum(content)
+
+	r := NewVerifyingReader(io.NopCloser(bytes.NewReader(content)), "path", checksum)
+	read, err := io.ReadAll(r)
+	require.Nil(t, err)
+	require.Equal(t, content, read)
+
+	// A truncated object is only detected once it is read to the end.
+	r = NewVerifyingReader(io.NopCloser(bytes.NewReader(content[:4])), "path", checksum)
+	_, err = io.ReadAll(r)
+	require.True(t, errors.Is(err, ErrContentCorrupted()))
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+)
+
+// Content-addressed objects are stored under the SHA-256 hash of their content.
+const contentAddressedPrefix = "content/sha256/"
+
+// ContentAddressedPath returns the key of the content with the given hash.
+func ContentAddressedPath(hash string) string {
+	return contentAddressedPrefix + hash
+}
+
+// ParseContentAddressedPath returns the hash of the content stored at key,
+// and whether key is a content-addressed path at all.
+func ParseContentAddressedPath(key string) (string, bool) {
+	if !strings.HasPrefix(key, contentAddressedPrefix) {
+		return "", false
+	}
+
+	hash := strings.TrimPrefix(key, contentAddressedPrefix)
+	if len(hash) != sha256.Size*2 {
+		return "", false
+	}
+	if _, err := hex.DecodeString(hash); err != nil {
+		return "", false
+	}
+	return hash, true
+}
+
+// HashContent returns the hex-encoded SHA-256 hash of the object at key.
+// The content is streamed, so that large objects don't need to be buffered in memory.
+// Throws `ErrObjectDoesNotExist` if the path does not exist.
+func HashContent(ctx context.Context, store Storage, key string) (string, error) {
+	r, err := store.GetReader(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// CopyKey streams the object at srcKey to dstKey in the same storage.
+// Throws `ErrObjectDoesNotExist` if srcKey does not exist.
+func CopyKey(ctx context.Context, store Storage, srcKey string, dstKey string) error {
+	r, err := store.GetReader(ctx, srcKey)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	return store.PutReader(ctx, dstKey, r)
+}
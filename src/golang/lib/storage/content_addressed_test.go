@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentAddressedPath(t *testing.T) {
+	ctx := context.Background()
+	store := newTestFileStorage(t)
+
+	require.Nil(t, store.Put(ctx, "run-1", []byte("content")))
+	require.Nil(t, store.Put(ctx, "run-2", []byte("content")))
+	require.Nil(t, store.Put(ctx, "run-3", []byte("other content")))
+
+	hash, err := HashContent(ctx, store, "run-1")
+	require.Nil(t, err)
+
+	otherHash, err := HashContent(ctx, store, "run-2")
+	require.Nil(t, err)
+	require.Equal(t, hash, otherHash)
+
+	otherHash, err = HashContent(ctx, store, "run-3")
+	require.Nil(t, err)
+	require.NotEqual(t, hash, otherHash)
+
+	path := ContentAddressedPath(hash)
+	require.Nil(t, CopyKey(ctx, store, "run-1", path))
+
+	content, err := store.Get(ctx, path)
+	require.Nil(t, err)
+	require.Equal(t, []byte("content"), content)
+
+	parsedHash, ok := ParseContentAddressedPath(path)
+	require.True(t, ok)
+	require.Equal(t, hash, parsedHash)
+
+	_, ok = ParseContentAddressedPath("run-1")
+	require.False(t, ok)
+
+	_, ok = ParseContentAddressedPath(ContentAddressedPath("not-a-hash"))
+	require.False(t, ok)
+}
@@ -23,11 +23,24 @@ func convertS3IntegrationtoStorageConfig(c *shared.S3IntegrationConfig) (*shared
 	storageConfig := &shared.StorageConfig{
 		Type: shared.S3StorageType,
 		S3Config: &shared.S3Config{
-			Bucket:  fmt.Sprintf("s3://%s", c.Bucket),
-			Region:  c.Region,
-			RootDir: c.RootDir,
+			Bucket:         fmt.Sprintf("s3://%s", c.Bucket),
+			Region:         c.Region,
+			RootDir:        c.RootDir,
+			Endpoint:       c.Endpoint,
+			ForcePathStyle: bool(c.ForcePathStyle),
 		},
 	}
+
+	if c.CACertificate != "" {
+		// The S3 Storage implementation expects the CA certificate to be specified via a filepath.
+		path := filepath.Join(config.AqueductPath(), "storage", uuid.NewString())
+		if err := os.WriteFile(path, []byte(c.CACertificate), 0o600); err != nil {
+			return nil, err
+		}
+
+		storageConfig.S3Config.CABundlePath = path
+	}
+
 	switch c.Type {
 	case shared.AccessKeyS3ConfigType:
 		// AWS access and secret keys need to be written to a credentials file
@@ -0,0 +1,599 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+
+	aq_config "github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Objects that are compressed or encrypted start with a header that records how they were encoded,
+// so that objects are always readable regardless of how the storage layer is currently configured.
+// Objects without the header are stored as is, which is also how all objects were stored before
+// encoding was supported.
+//
+// The header consists of the magic bytes, the format version, the compression algorithm, the
+// encryption algorithm, the length of the key ID and the ID of the keyring key that the storage key
+// the content is encrypted with is derived from (see `DeriveKeys`). Objects of the first version were
+// encrypted with the keyring key itself, and have no key ID. Encrypted content is split into chunks that are sealed separately with
+// AES-GCM, so that it can be streamed. Each chunk is stored as a flag that marks the final chunk,
+// the length of the sealed chunk, the nonce and the sealed chunk. Every chunk is authenticated along
+// with the header, its index and its flag, so that chunks cannot be reordered, dropped or truncated.
+// The same format is implemented by the Python executor, in `aqueduct_executor/operators/utils/storage/encoding.py`.
+var encodingMagic = []byte{0x89, 'A', 'Q', 'S'}
+
+const (
+	encodingVersion byte = 2
+	// Objects of this version are encrypted with the server's encryption key, so only the server can read them.
+	legacyEncodingVersion byte = 1
+	// The size of the part of the header that every version has.
+	encodingHeaderSize = 7
+
+	noCompression   byte = 0
+	gzipCompression byte = 1
+	zstdCompression byte = 2
+
+	noEncryption     byte = 0
+	aesGCMEncryption byte = 1
+
+	encryptedChunkSize = 64 * 1024
+	// The maximum length of a sealed chunk, which includes the GCM tag.
+	maxSealedChunkSize = encryptedChunkSize + 16
+)
+
+// encodedStorage compresses and encrypts objects before they are written to the underlying storage
+// layer, according to the storage config, and decodes them when they are read.
+type encodedStorage struct {
+	Storage
+	config *shared.StorageConfig
+}
+
+func newEncodedStorage(store Storage, config *shared.StorageConfig) *encodedStorage {
+	return &encodedStorage{
+		Storage: store,
+		config:  config,
+	}
+}
+
+func (e *encodedStorage) encodes() bool {
+	return e.config.Compression != "" || e.config.Encrypt
+}
+
+// encryptionKeys are only resolved once they are needed, since most processes never encrypt anything.
+// Jobs get them from their spec or their environment, while the server derives them from its keyring.
+func (e *encodedStorage) encryptionKeys() (*shared.StorageKeys, error) {
+	if e.config.EncryptionKeys != nil {
+		return e.config.EncryptionKeys, nil
+	}
+
+	keys, err := CurrentKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys.Keys) == 0 {
+		return nil, errors.New("No encryption key is configured for encrypted storage.")
+	}
+	return keys, nil
+}
+
+// header returns the header of objects that are encoded according to the storage config.
+// keyID is only recorded if the objects are encrypted.
+func (e *encodedStorage) header(keyID string) ([]byte, error) {
+	if len(keyID) > 255 {
+		return nil, errors.Newf("Encryption key ID %s is too long.", keyID)
+	}
+
+	header := make([]byte, 0, encodingHeaderSize+1+len(keyID))
+	header = append(header, encodingMagic...)
+	header = append(header, encodingVersion)
+
+	switch e.config.Compression {
+	case "":
+		header = append(header, noCompression)
+	case shared.GzipCompressionType:
+		header = append(header, gzipCompression)
+	case shared.ZstdCompressionType:
+		header = append(header, zstdCompression)
+	default:
+		return nil, errors.Newf("Unsupported compression type %s.", e.config.Compression)
+	}
+
+	if e.config.Encrypt {
+		header = append(header, aesGCMEncryption)
+	} else {
+		header = append(header, noEncryption)
+	}
+
+	header = append(header, byte(len(keyID)))
+	return append(header, keyID...), nil
+}
+
+func (e *encodedStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := e.Storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isEncoded(value) {
+		return value, nil
+	}
+
+	r, err := e.decode(bytes.NewReader(value))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to decode object %s.", key)
+	}
+	defer r.Close()
+
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to decode object %s.", key)
+	}
+	return decoded, nil
+}
+
+func (e *encodedStorage) Put(ctx context.Context, key string, value []byte) error {
+	if !e.encodes() {
+		return e.Storage.Put(ctx, key, value)
+	}
+
+	return e.PutReader(ctx, key, bytes.NewReader(value))
+}
+
+func (e *encodedStorage) GetReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	rc, err := e.Storage.GetReader(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(rc)
+	// A short object cannot have a header, and is returned as is.
+	header, _ := br.Peek(encodingHeaderSize)
+	if !isEncoded(header) {
+		return &readCloser{Reader: br, closers: []io.Closer{rc}}, nil
+	}
+
+	decoded, err := e.decode(br)
+	if err != nil {
+		rc.Close()
+		return nil, errors.Wrapf(err, "Unable to decode object %s.", key)
+	}
+	return &readCloser{Reader: decoded, closers: []io.Closer{decoded, rc}}, nil
+}
+
+func (e *encodedStorage) PutReader(ctx context.Context, key string, r io.Reader) error {
+	if !e.encodes() {
+		return e.Storage.PutReader(ctx, key, r)
+	}
+
+	var keyID string
+	var encryptionKey []byte
+	if e.config.Encrypt {
+		keys, err := e.encryptionKeys()
+		if err != nil {
+			return err
+		}
+
+		keyID = keys.CurrentKeyID
+		encryptionKey, err = lookupKey(keys, keyID)
+		if err != nil {
+			return err
+		}
+	}
+
+	header, err := e.header(keyID)
+	if err != nil {
+		return err
+	}
+
+	// The content is encoded while it is written, so that it never needs to be buffered in memory.
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pw.CloseWithError(encode(pw, r, header, encryptionKey))
+	}()
+
+	err = e.Storage.PutReader(ctx, key, pr)
+	// Unblocks the encoding if the write stopped early, so that r is no longer read once this returns.
+	pr.Close()
+	<-done
+	return err
+}
+
+// decode returns a reader over the decoded content of an encoded object.
+// The header of the object must not have been consumed from r yet.
+func (e *encodedStorage) decode(r io.Reader) (io.ReadCloser, error) {
+	header := make([]byte, encodingHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	var keyID string
+	switch header[4] {
+	case legacyEncodingVersion:
+	case encodingVersion:
+		keyIDLen := make([]byte, 1)
+		if _, err := io.ReadFull(r, keyIDLen); err != nil {
+			return nil, err
+		}
+
+		keyIDBytes := make([]byte, keyIDLen[0])
+		if _, err := io.ReadFull(r, keyIDBytes); err != nil {
+			return nil, err
+		}
+
+		keyID = string(keyIDBytes)
+		header = append(append(header, keyIDLen...), keyIDBytes...)
+	default:
+		return nil, errors.Newf("Unsupported encoding version %d.", header[4])
+	}
+
+	switch header[6] {
+	case noEncryption:
+	case aesGCMEncryption:
+		key, err := e.decryptionKey(header[4], keyID)
+		if err != nil {
+			return nil, err
+		}
+
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		r = &decryptReader{r: r, aead: aead, header: header}
+	default:
+		return nil, errors.Newf("Unsupported encryption algorithm %d.", header[6])
+	}
+
+	switch header[5] {
+	case noCompression:
+		return io.NopCloser(r), nil
+	case gzipCompression:
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return gr, nil
+	case zstdCompression:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return nil, errors.Newf("Unsupported compression algorithm %d.", header[5])
+	}
+}
+
+// decryptionKey returns the key that objects of the given encoding version were encrypted with.
+func (e *encodedStorage) decryptionKey(version byte, keyID string) ([]byte, error) {
+	if version == legacyEncodingVersion {
+		if e.config.EncryptionKeys != nil || aq_config.EncryptionKey() == "" {
+			return nil, errors.New("Objects of encoding version 1 can only be decrypted by the server.")
+		}
+		return []byte(aq_config.EncryptionKey()), nil
+	}
+
+	keys, err := e.encryptionKeys()
+	if err != nil {
+		return nil, err
+	}
+	return lookupKey(keys, keyID)
+}
+
+// encryptionKeyID returns the ID of the key that the object at key is encrypted with, and false if it
+// is not encrypted. Objects of the legacy version have the empty key ID, since they are encrypted with
+// the original key of the keyring.
+func (e *encodedStorage) encryptionKeyID(ctx context.Context, key string) (string, bool, error) {
+	rc, err := e.Storage.GetReader(ctx, key)
+	if err != nil {
+		return "", false, err
+	}
+	defer rc.Close()
+
+	header := make([]byte, encodingHeaderSize)
+	if _, err := io.ReadFull(rc, header); err != nil {
+		// A short object cannot have a header.
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	if !isEncoded(header) || header[6] != aesGCMEncryption {
+		return "", false, nil
+	}
+
+	switch header[4] {
+	case legacyEncodingVersion:
+		return "", true, nil
+	case encodingVersion:
+		keyIDLen := make([]byte, 1)
+		if _, err := io.ReadFull(rc, keyIDLen); err != nil {
+			return "", false, truncatedErr(err)
+		}
+
+		keyID := make([]byte, keyIDLen[0])
+		if _, err := io.ReadFull(rc, keyID); err != nil {
+			return "", false, truncatedErr(err)
+		}
+		return string(keyID), true, nil
+	default:
+		return "", false, errors.Newf("Unsupported encoding version %d.", header[4])
+	}
+}
+
+// Reencrypt re-encodes the objects in the storage layer of config that are encrypted with the key
+// keyID, so that they are encrypted with the current key instead. This must be done before keyID is
+// retired from the keyring. It returns the number of objects that were re-encrypted.
+func Reencrypt(ctx context.Context, config *shared.StorageConfig, keyID string) (int, error) {
+	store, ok := NewStorage(config).(*encodedStorage)
+	if !ok {
+		return 0, errors.New("The storage layer does not support encryption.")
+	}
+
+	objects, err := store.List(ctx, "" /* prefix */)
+	if err != nil {
+		return 0, errors.Wrap(err, "Unable to list objects.")
+	}
+
+	numReencrypted := 0
+	for _, object := range objects {
+		objectKeyID, encrypted, err := store.encryptionKeyID(ctx, object.Key)
+		if err != nil {
+			return numReencrypted, errors.Wrapf(err, "Unable to read the header of object %s.", object.Key)
+		}
+
+		if !encrypted || objectKeyID != keyID {
+			continue
+		}
+
+		value, err := store.Get(ctx, object.Key)
+		if err != nil {
+			return numReencrypted, err
+		}
+
+		// The object is encoded according to the current storage config, so it is not
+		// encrypted at all anymore if the storage layer no longer encrypts objects.
+		if err := store.Put(ctx, object.Key, value); err != nil {
+			return numReencrypted, errors.Wrapf(err, "Unable to re-encrypt object %s.", object.Key)
+		}
+		numReencrypted++
+	}
+
+	return numReencrypted, nil
+}
+
+// encode writes the header, followed by the content read from r, compressed and encrypted as
+// specified by the header. The content is only encrypted if encryptionKey is set.
+func encode(w io.Writer, r io.Reader, header []byte, encryptionKey []byte) error {
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	var ew *encryptWriter
+	if encryptionKey != nil {
+		aead, err := newAEAD(encryptionKey)
+		if err != nil {
+			return err
+		}
+		ew = newEncryptWriter(w, aead, header)
+		w = ew
+	}
+
+	var compressWriter io.WriteCloser
+	switch header[5] {
+	case gzipCompression:
+		compressWriter = gzip.NewWriter(w)
+	case zstdCompression:
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return err
+		}
+		compressWriter = zw
+	}
+
+	if compressWriter != nil {
+		w = compressWriter
+	}
+
+	if _, err := io.Copy(w, r); err != nil {
+		return err
+	}
+
+	if compressWriter != nil {
+		if err := compressWriter.Close(); err != nil {
+			return err
+		}
+	}
+
+	if ew != nil {
+		return ew.Close()
+	}
+	return nil
+}
+
+func isEncoded(value []byte) bool {
+	return len(value) >= encodingHeaderSize && bytes.Equal(value[:len(encodingMagic)], encodingMagic)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	c, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid encryption key.")
+	}
+	return cipher.NewGCM(c)
+}
+
+// chunkAdditionalData returns the data that the chunk at index is authenticated with.
+func chunkAdditionalData(header []byte, index uint64, final bool) []byte {
+	data := make([]byte, 0, len(header)+9)
+	data = append(data, header...)
+	data = binary.BigEndian.AppendUint64(data, index)
+	if final {
+		return append(data, 1)
+	}
+	return append(data, 0)
+}
+
+// encryptWriter seals everything written to it in chunks of encryptedChunkSize.
+// Close must be called to write the final chunk.
+type encryptWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	header []byte
+
+	buf   []byte
+	index uint64
+}
+
+func newEncryptWriter(w io.Writer, aead cipher.AEAD, header []byte) *encryptWriter {
+	return &encryptWriter{
+		w:      w,
+		aead:   aead,
+		header: header,
+		buf:    make([]byte, 0, encryptedChunkSize),
+	}
+}
+
+func (ew *encryptWriter) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		// A full chunk is only sealed once more content follows, since the final chunk is marked as such.
+		if len(ew.buf) == encryptedChunkSize {
+			if err := ew.flush(false /* final */); err != nil {
+				return n, err
+			}
+		}
+
+		written := copy(ew.buf[len(ew.buf):encryptedChunkSize], p)
+		ew.buf = ew.buf[:len(ew.buf)+written]
+		p = p[written:]
+		n += written
+	}
+	return n, nil
+}
+
+func (ew *encryptWriter) Close() error {
+	return ew.flush(true /* final */)
+}
+
+func (ew *encryptWriter) flush(final bool) error {
+	nonce := make([]byte, ew.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+
+	sealed := ew.aead.Seal(nil, nonce, ew.buf, chunkAdditionalData(ew.header, ew.index, final))
+
+	prefix := make([]byte, 5)
+	if final {
+		prefix[0] = 1
+	}
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(sealed)))
+
+	for _, part := range [][]byte{prefix, nonce, sealed} {
+		if _, err := ew.w.Write(part); err != nil {
+			return err
+		}
+	}
+
+	ew.buf = ew.buf[:0]
+	ew.index++
+	return nil
+}
+
+// decryptReader opens the chunks written by encryptWriter.
+type decryptReader struct {
+	r      io.Reader
+	aead   cipher.AEAD
+	header []byte
+
+	chunk []byte
+	index uint64
+	done  bool
+}
+
+func (dr *decryptReader) Read(p []byte) (int, error) {
+	for len(dr.chunk) == 0 {
+		if dr.done {
+			return 0, io.EOF
+		}
+
+		if err := dr.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, dr.chunk)
+	dr.chunk = dr.chunk[n:]
+	return n, nil
+}
+
+func (dr *decryptReader) next() error {
+	prefix := make([]byte, 5)
+	if _, err := io.ReadFull(dr.r, prefix); err != nil {
+		return errors.Wrap(truncatedErr(err), "Unable to read encrypted chunk.")
+	}
+
+	final := prefix[0] == 1
+	sealedSize := binary.BigEndian.Uint32(prefix[1:])
+	if sealedSize > maxSealedChunkSize {
+		return errors.Newf("Encrypted chunk of size %d is too large.", sealedSize)
+	}
+
+	nonceAndSealed := make([]byte, dr.aead.NonceSize()+int(sealedSize))
+	if _, err := io.ReadFull(dr.r, nonceAndSealed); err != nil {
+		return errors.Wrap(truncatedErr(err), "Unable to read encrypted chunk.")
+	}
+
+	nonce, sealed := nonceAndSealed[:dr.aead.NonceSize()], nonceAndSealed[dr.aead.NonceSize():]
+	chunk, err := dr.aead.Open(nil, nonce, sealed, chunkAdditionalData(dr.header, dr.index, final))
+	if err != nil {
+		return errors.Wrap(err, "Unable to decrypt chunk. The object may have been encrypted with a different key.")
+	}
+
+	dr.chunk = chunk
+	dr.index++
+	if final {
+		dr.done = true
+		if n, _ := dr.r.Read(make([]byte, 1)); n > 0 {
+			return errors.New("Unexpected data after the final encrypted chunk.")
+		}
+	}
+	return nil
+}
+
+func truncatedErr(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// readCloser reads from a decoding reader, and closes it along with the underlying object reader.
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (rc *readCloser) Close() error {
+	var firstErr error
+	for _, closer := range rc.closers {
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,294 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/stretchr/testify/require"
+)
+
+const testEncryptionKey = "0123456789abcdef0123456789abcdef"
+
+// testStorageKeys returns the storage keys of a keyring that only has key, which may be empty.
+func testStorageKeys(t *testing.T, key string) *shared.StorageKeys {
+	if key == "" {
+		return nil
+	}
+
+	keys, err := DeriveKeys(config.Keyring{Keys: map[string]string{"": key}})
+	require.Nil(t, err)
+	return keys
+}
+
+func newTestEncodedStorage(
+	t *testing.T,
+	dir string,
+	compression shared.CompressionType,
+	encrypt bool,
+	key string,
+) Storage {
+	return NewStorage(&shared.StorageConfig{
+		Type:           shared.FileStorageType,
+		FileConfig:     &shared.FileConfig{Directory: dir},
+		Compression:    compression,
+		Encrypt:        encrypt,
+		EncryptionKeys: testStorageKeys(t, key),
+	})
+}
+
+func TestEncodedStorageRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	// Spans several encrypted chunks.
+	content := bytes.Repeat([]byte("aqueduct"), encryptedChunkSize/3)
+
+	for _, compression := range []shared.CompressionType{"", shared.GzipCompressionType, shared.ZstdCompressionType} {
+		for _, encrypt := range []bool{false, true} {
+			dir := t.TempDir()
+			store := newTestEncodedStorage(t, dir, compression, encrypt, testEncryptionKey)
+
+			require.Nil(t, store.Put(ctx, "key", content))
+
+			value, err := store.Get(ctx, "key")
+			require.Nil(t, err)
+			require.Equal(t, content, value)
+
+			r, err := store.GetReader(ctx, "key")
+			require.Nil(t, err)
+			value, err = io.ReadAll(r)
+			require.Nil(t, err)
+			require.Nil(t, r.Close())
+			require.Equal(t, content, value)
+
+			stored, err := os.ReadFile(filepath.Join(dir, "key"))
+			require.Nil(t, err)
+			if compression == "" && !encrypt {
+				require.Equal(t, content, stored)
+			} else {
+				require.True(t, isEncoded(stored))
+			}
+
+			if compression != "" {
+				require.Less(t, len(stored), len(content))
+			}
+
+			if encrypt {
+				require.False(t, bytes.Contains(stored, []byte("aqueduct")))
+			}
+		}
+	}
+}
+
+func TestEncodedStorageReadsPlainObjects(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	plain := newTestEncodedStorage(t, dir, "", false, "")
+	require.Nil(t, plain.Put(ctx, "old", []byte("old content")))
+	require.Nil(t, plain.Put(ctx, "short", []byte("abc")))
+
+	encoded := newTestEncodedStorage(t, dir, shared.ZstdCompressionType, true, testEncryptionKey)
+	value, err := encoded.Get(ctx, "old")
+	require.Nil(t, err)
+	require.Equal(t, []byte("old content"), value)
+
+	r, err := encoded.GetReader(ctx, "short")
+	require.Nil(t, err)
+	defer r.Close()
+	value, err = io.ReadAll(r)
+	require.Nil(t, err)
+	require.Equal(t, []byte("abc"), value)
+
+	// Objects stay readable once the storage layer no longer encodes them.
+	require.Nil(t, encoded.Put(ctx, "new", []byte("new content")))
+	plainWithKey := newTestEncodedStorage(t, dir, "", false, testEncryptionKey)
+	value, err = plainWithKey.Get(ctx, "new")
+	require.Nil(t, err)
+	require.Equal(t, []byte("new content"), value)
+}
+
+func TestEncodedStorageRejectsInvalidObjects(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	content := bytes.Repeat([]byte("aqueduct"), encryptedChunkSize/3)
+
+	store := newTestEncodedStorage(t, dir, "", true, testEncryptionKey)
+	require.Nil(t, store.Put(ctx, "key", content))
+
+	otherKeyStore := newTestEncodedStorage(t, dir, "", true, "fedcba9876543210fedcba9876543210")
+	_, err := otherKeyStore.Get(ctx, "key")
+	require.NotNil(t, err)
+
+	stored, err := os.ReadFile(filepath.Join(dir, "key"))
+	require.Nil(t, err)
+
+	// Dropping the final chunk is detected.
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "truncated"), stored[:len(stored)/2], 0o644))
+	_, err = store.Get(ctx, "truncated")
+	require.NotNil(t, err)
+
+	tampered := append([]byte{}, stored...)
+	tampered[len(tampered)-1] ^= 1
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "tampered"), tampered, 0o644))
+	_, err = store.Get(ctx, "tampered")
+	require.NotNil(t, err)
+}
+
+func TestEncodedStorageKeyIDs(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	keyring := config.Keyring{
+		CurrentKeyID: "",
+		Keys: map[string]string{
+			"":    testEncryptionKey,
+			"new": "fedcba9876543210fedcba9876543210",
+		},
+	}
+	keys, err := DeriveKeys(keyring)
+	require.Nil(t, err)
+	require.Len(t, keys.Keys, 2)
+	require.NotEqual(t, keys.Keys[""], keys.Keys["new"])
+
+	newStore := func(keys *shared.StorageKeys) Storage {
+		return NewStorage(&shared.StorageConfig{
+			Type:           shared.FileStorageType,
+			FileConfig:     &shared.FileConfig{Directory: dir},
+			Encrypt:        true,
+			EncryptionKeys: keys,
+		})
+	}
+
+	require.Nil(t, newStore(keys).Put(ctx, "old", []byte("old content")))
+
+	rotated := &shared.StorageKeys{CurrentKeyID: "new", Keys: keys.Keys}
+	require.Nil(t, newStore(rotated).Put(ctx, "new", []byte("new content")))
+
+	// Objects are decrypted with the key that they were encrypted with.
+	for key, content := range map[string]string{"old": "old content", "new": "new content"} {
+		value, err := newStore(rotated).Get(ctx, key)
+		require.Nil(t, err)
+		require.Equal(t, []byte(content), value)
+	}
+
+	// Objects cannot be read once their key is gone.
+	retired := &shared.StorageKeys{CurrentKeyID: "new", Keys: map[string]string{"new": keys.Keys["new"]}}
+	_, err = newStore(retired).Get(ctx, "old")
+	require.NotNil(t, err)
+	value, err := newStore(retired).Get(ctx, "new")
+	require.Nil(t, err)
+	require.Equal(t, []byte("new content"), value)
+}
+
+func TestEncodedStorageKeysFromEnv(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	keys := testStorageKeys(t, testEncryptionKey)
+	serializedKeys, err := json.Marshal(keys)
+	require.Nil(t, err)
+	t.Setenv(KeysEnvVar, string(serializedKeys))
+
+	// Jobs whose spec has no storage keys read them from their environment.
+	envStore := newTestEncodedStorage(t, dir, "" /* compression */, true /* encrypt */, "" /* key */)
+	require.Nil(t, envStore.Put(ctx, "key", []byte("content")))
+
+	value, err := newTestEncodedStorage(t, dir, "" /* compression */, true /* encrypt */, testEncryptionKey).Get(ctx, "key")
+	require.Nil(t, err)
+	require.Equal(t, []byte("content"), value)
+}
+
+func TestEncodedStorageLegacyObjects(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	configPath := filepath.Join(dir, "config.yml")
+	require.Nil(t, os.WriteFile(configPath, []byte("aqPath: "+dir+"\nencryptionKey: "+testEncryptionKey+"\n"), 0o644))
+	require.Nil(t, config.Init(configPath))
+
+	// Objects of the first version are encrypted with the server's encryption key itself.
+	var legacy bytes.Buffer
+	header := append(append([]byte{}, encodingMagic...), legacyEncodingVersion, noCompression, aesGCMEncryption)
+	require.Nil(t, encode(&legacy, bytes.NewReader([]byte("legacy content")), header, []byte(testEncryptionKey)))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "legacy"), legacy.Bytes(), 0o644))
+
+	// The server reads them, and encrypts new objects with the derived key.
+	server := NewStorage(&shared.StorageConfig{
+		Type:       shared.FileStorageType,
+		FileConfig: &shared.FileConfig{Directory: dir},
+		Encrypt:    true,
+	})
+	value, err := server.Get(ctx, "legacy")
+	require.Nil(t, err)
+	require.Equal(t, []byte("legacy content"), value)
+
+	require.Nil(t, server.Put(ctx, "new", []byte("new content")))
+	stored, err := os.ReadFile(filepath.Join(dir, "new"))
+	require.Nil(t, err)
+	require.Equal(t, encodingVersion, stored[len(encodingMagic)])
+
+	// Jobs only have the derived keys, which they can read new objects with, but not the legacy ones.
+	job := newTestEncodedStorage(t, dir, "", true, testEncryptionKey)
+	value, err = job.Get(ctx, "new")
+	require.Nil(t, err)
+	require.Equal(t, []byte("new content"), value)
+
+	_, err = job.Get(ctx, "legacy")
+	require.NotNil(t, err)
+}
+
+func TestReencrypt(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	keys, err := DeriveKeys(config.Keyring{
+		CurrentKeyID: "",
+		Keys: map[string]string{
+			"":    testEncryptionKey,
+			"new": "fedcba9876543210fedcba9876543210",
+		},
+	})
+	require.Nil(t, err)
+
+	storageConfig := func(keys *shared.StorageKeys) *shared.StorageConfig {
+		return &shared.StorageConfig{
+			Type:           shared.FileStorageType,
+			FileConfig:     &shared.FileConfig{Directory: dir},
+			Compression:    shared.GzipCompressionType,
+			Encrypt:        true,
+			EncryptionKeys: keys,
+		}
+	}
+
+	require.Nil(t, NewStorage(storageConfig(keys)).Put(ctx, "old", []byte("old content")))
+	require.Nil(t, newTestEncodedStorage(t, dir, "", false, "").Put(ctx, "plain", []byte("plain content")))
+
+	rotated := &shared.StorageKeys{CurrentKeyID: "new", Keys: keys.Keys}
+	require.Nil(t, NewStorage(storageConfig(rotated)).Put(ctx, "new", []byte("new content")))
+
+	numReencrypted, err := Reencrypt(ctx, storageConfig(rotated), "" /* keyID */)
+	require.Nil(t, err)
+	require.Equal(t, 1, numReencrypted)
+
+	// Everything is readable once the old key is gone.
+	retired := NewStorage(storageConfig(&shared.StorageKeys{
+		CurrentKeyID: "new",
+		Keys:         map[string]string{"new": keys.Keys["new"]},
+	}))
+	for key, content := range map[string]string{"old": "old content", "new": "new content", "plain": "plain content"} {
+		value, err := retired.Get(ctx, key)
+		require.Nil(t, err)
+		require.Equal(t, []byte(content), value)
+	}
+
+	// Re-encrypting is a no-op once no object is encrypted with the key anymore.
+	numReencrypted, err = Reencrypt(ctx, storageConfig(rotated), "" /* keyID */)
+	require.Nil(t, err)
+	require.Equal(t, 0, numReencrypted)
+}
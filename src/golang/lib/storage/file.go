@@ -1,11 +1,16 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
+	"strings"
 
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 )
@@ -34,19 +39,105 @@ func (f *fileStorage) Get(ctx context.Context, key string) ([]byte, error) {
 }
 
 func (f *fileStorage) Put(ctx context.Context, key string, value []byte) error {
+	return f.PutReader(ctx, key, bytes.NewReader(value))
+}
+
+func (f *fileStorage) GetReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	file, err := os.Open(f.getFullPath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrObjectDoesNotExist()
+	}
+	return file, err
+}
+
+func (f *fileStorage) PutReader(ctx context.Context, key string, r io.Reader) error {
 	filePath := f.getFullPath(key)
 	dir := path.Dir(filePath)
+	if err := createDir(dir); err != nil {
+		return err
+	}
 
-	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
-		// Directory does not exist, so we need to create it
-		if err := os.MkdirAll(dir, dirPermissionCode); err != nil {
-			return err
-		}
-	} else if err != nil {
+	// The content is written to a temporary file first, so that a failed write
+	// never leaves a partial object behind.
+	tmpFile, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
 		return err
 	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := io.Copy(tmpFile, r); err != nil {
+		tmpFile.Close()
+		return err
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmpFile.Name(), filePermissionCode); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), filePath)
+}
+
+func (f *fileStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	info, err := os.Stat(f.getFullPath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrObjectDoesNotExist()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ObjectInfo{Key: key, Size: info.Size(), LastModified: info.ModTime()}, nil
+}
+
+func (f *fileStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	// Only the directory that all keys with the prefix are in needs to be walked.
+	root := f.fileConfig.Directory
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		root = f.getFullPath(prefix[:i])
+	}
+
+	objects := []ObjectInfo{}
+	err := filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(f.fileConfig.Directory, filePath)
+		if err != nil {
+			return err
+		}
 
-	return os.WriteFile(filePath, value, filePermissionCode)
+		key := filepath.ToSlash(relPath)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				// The object was deleted while the directory was walked.
+				return nil
+			}
+			return err
+		}
+
+		objects = append(objects, ObjectInfo{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
 }
 
 func (f *fileStorage) Delete(ctx context.Context, key string) error {
@@ -64,3 +155,14 @@ func (f *fileStorage) Exists(ctx context.Context, key string) bool {
 func (f *fileStorage) getFullPath(key string) string {
 	return fmt.Sprintf("%s/%s", f.fileConfig.Directory, key)
 }
+
+// createDir creates the directory, along with any missing parents, if it does not exist yet.
+func createDir(dir string) error {
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		// Directory does not exist, so we need to create it
+		return os.MkdirAll(dir, dirPermissionCode)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFileStorage(t *testing.T) *fileStorage {
+	return newFileStorage(&shared.FileConfig{Directory: t.TempDir()})
+}
+
+func TestFileStorageReaderRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store := newTestFileStorage(t)
+
+	content := bytes.Repeat([]byte("aqueduct"), 1<<16)
+	require.Nil(t, store.PutReader(ctx, "dir/key", bytes.NewReader(content)))
+
+	info, err := store.Stat(ctx, "dir/key")
+	require.Nil(t, err)
+	require.Equal(t, int64(len(content)), info.Size)
+
+	r, err := store.GetReader(ctx, "dir/key")
+	require.Nil(t, err)
+	defer r.Close()
+
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(r)
+	require.Nil(t, err)
+	require.Equal(t, content, buf.Bytes())
+
+	// No temporary files should be left behind.
+	entries, err := os.ReadDir(filepath.Join(store.fileConfig.Directory, "dir"))
+	require.Nil(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestFileStorageMissingObject(t *testing.T) {
+	ctx := context.Background()
+	store := newTestFileStorage(t)
+
+	_, err := store.GetReader(ctx, "missing")
+	require.True(t, errors.Is(err, ErrObjectDoesNotExist()))
+
+	_, err = store.Stat(ctx, "missing")
+	require.True(t, errors.Is(err, ErrObjectDoesNotExist()))
+}
+
+func TestFileStorageList(t *testing.T) {
+	ctx := context.Background()
+	store := newTestFileStorage(t)
+
+	require.Nil(t, store.Put(ctx, "dir/a", []byte("a")))
+	require.Nil(t, store.Put(ctx, "dir/nested/b", []byte("bb")))
+	require.Nil(t, store.Put(ctx, "directory", []byte("c")))
+	require.Nil(t, store.Put(ctx, "other", []byte("d")))
+
+	objects, err := store.List(ctx, "dir/")
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{"dir/a", "dir/nested/b"}, objectKeys(objects))
+
+	objects, err = store.List(ctx, "dir")
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{"dir/a", "dir/nested/b", "directory"}, objectKeys(objects))
+
+	objects, err = store.List(ctx, "")
+	require.Nil(t, err)
+	require.Len(t, objects, 4)
+	for _, object := range objects {
+		if object.Key == "dir/nested/b" {
+			require.Equal(t, int64(2), object.Size)
+			require.False(t, object.LastModified.IsZero())
+		}
+	}
+
+	objects, err = store.List(ctx, "missing/")
+	require.Nil(t, err)
+	require.Empty(t, objects)
+}
+
+func objectKeys(objects []ObjectInfo) []string {
+	keys := make([]string, 0, len(objects))
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+	return keys
+}
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+	src := newTestFileStorage(t)
+	dst := newTestFileStorage(t)
+
+	require.Nil(t, src.Put(ctx, "key", []byte("content")))
+	require.Nil(t, Copy(ctx, src, dst, "key"))
+
+	content, err := dst.Get(ctx, "key")
+	require.Nil(t, err)
+	require.Equal(t, []byte("content"), content)
+
+	require.True(t, errors.Is(Copy(ctx, src, dst, "missing"), ErrObjectDoesNotExist()))
+}
+
+func TestSameLocation(t *testing.T) {
+	s3Config := func(bucket string, accessKeyID string) *shared.StorageConfig {
+		return &shared.StorageConfig{
+			Type: shared.S3StorageType,
+			S3Config: &shared.S3Config{
+				Region:         "us-east-2",
+				Bucket:         bucket,
+				AWSAccessKeyID: accessKeyID,
+			},
+		}
+	}
+
+	// Credentials and encoding do not change where objects are stored.
+	encrypted := s3Config("bucket", "other-key")
+	encrypted.Encrypt = true
+	encrypted.Compression = shared.GzipCompressionType
+	require.True(t, SameLocation(s3Config("bucket", "key"), encrypted))
+
+	require.False(t, SameLocation(s3Config("bucket", "key"), s3Config("other-bucket", "key")))
+	require.False(t, SameLocation(s3Config("bucket", "key"), &shared.StorageConfig{
+		Type:       shared.FileStorageType,
+		FileConfig: &shared.FileConfig{Directory: "bucket"},
+	}))
+}
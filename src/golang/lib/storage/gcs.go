@@ -9,6 +9,7 @@ import (
 
 	"cloud.google.com/go/storage"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
@@ -92,6 +93,102 @@ func (g *gcsStorage) Put(ctx context.Context, key string, value []byte) error {
 	return wc.Close()
 }
 
+func (g *gcsStorage) GetReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	client, err := g.newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket, key := g.parseBucketAndKey(key)
+
+	rc, err := client.Bucket(bucket).Object(key).NewReader(ctx)
+	if err != nil {
+		client.Close()
+		if err == storage.ErrObjectNotExist {
+			return nil, ErrObjectDoesNotExist()
+		}
+		return nil, err
+	}
+
+	return &gcsReader{Reader: rc, client: client}, nil
+}
+
+func (g *gcsStorage) PutReader(ctx context.Context, key string, r io.Reader) error {
+	client, err := g.newClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	bucket, key := g.parseBucketAndKey(key)
+
+	// Canceling the context aborts the upload, so that a failed write does not
+	// leave a partial object behind.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	wc := client.Bucket(bucket).Object(key).NewWriter(ctx)
+	if _, err = io.Copy(wc, r); err != nil {
+		return err
+	}
+
+	return wc.Close()
+}
+
+func (g *gcsStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	client, err := g.newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	bucket, fullKey := g.parseBucketAndKey(key)
+
+	attrs, err := client.Bucket(bucket).Object(fullKey).Attrs(ctx)
+	if err != nil {
+		if err == storage.ErrObjectNotExist {
+			return nil, ErrObjectDoesNotExist()
+		}
+		return nil, err
+	}
+
+	return &ObjectInfo{Key: key, Size: attrs.Size, LastModified: attrs.Updated}, nil
+}
+
+func (g *gcsStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	client, err := g.newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	bucket, rootKey := g.parseBucketAndKey("")
+
+	// The keys in the bucket are relative to the subpath, which is not part of the returned keys.
+	if rootKey != "" {
+		rootKey += "/"
+	}
+
+	objects := []ObjectInfo{}
+	it := client.Bucket(bucket).Objects(ctx, &storage.Query{Prefix: rootKey + prefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, ObjectInfo{
+			Key:          strings.TrimPrefix(attrs.Name, rootKey),
+			Size:         attrs.Size,
+			LastModified: attrs.Updated,
+		})
+	}
+	return objects, nil
+}
+
 func (g *gcsStorage) Delete(ctx context.Context, key string) error {
 	client, err := g.newClient(ctx)
 	if err != nil {
@@ -125,3 +222,14 @@ func (g *gcsStorage) Exists(ctx context.Context, key string) bool {
 func (g *gcsStorage) newClient(ctx context.Context) (*storage.Client, error) {
 	return storage.NewClient(ctx, option.WithCredentialsJSON([]byte(g.gcsConfig.ServiceAccountCredentials)))
 }
+
+// gcsReader closes the GCS client along with the object reader.
+type gcsReader struct {
+	*storage.Reader
+	client *storage.Client
+}
+
+func (r *gcsReader) Close() error {
+	defer r.client.Close()
+	return r.Reader.Close()
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"os"
+
+	aq_config "github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// KeysEnvVar is the environment variable that jobs which don't have access to the server config
+	// read the JSON serialized storage keys from, if their storage config does not have them.
+	KeysEnvVar = "AQUEDUCT_STORAGE_KEYS"
+
+	// storageKeyInfo separates the storage keys from any other key that is derived from the same keyring key.
+	storageKeyInfo = "storage"
+	storageKeySize = 32
+)
+
+// DeriveKeys derives a storage key from each key in keyring, so that objects are never encrypted
+// with the keys that encrypt the vault.
+func DeriveKeys(keyring aq_config.Keyring) (*shared.StorageKeys, error) {
+	keys := &shared.StorageKeys{
+		CurrentKeyID: keyring.CurrentKeyID,
+		Keys:         make(map[string]string, len(keyring.Keys)),
+	}
+
+	for keyID, key := range keyring.Keys {
+		if key == "" {
+			continue
+		}
+
+		storageKey, err := deriveKey(key)
+		if err != nil {
+			return nil, err
+		}
+		keys.Keys[keyID] = base64.StdEncoding.EncodeToString(storageKey)
+	}
+	return keys, nil
+}
+
+// CurrentKeys returns the storage keys of this process. Jobs get them from their environment,
+// while the server, and the jobs that run with the server config, derive them from its keyring.
+func CurrentKeys() (*shared.StorageKeys, error) {
+	if serializedKeys := os.Getenv(KeysEnvVar); serializedKeys != "" {
+		var keys shared.StorageKeys
+		if err := json.Unmarshal([]byte(serializedKeys), &keys); err != nil {
+			return nil, errors.Wrap(err, "Unable to parse storage keys.")
+		}
+		return &keys, nil
+	}
+
+	return DeriveKeys(aq_config.EncryptionKeyring())
+}
+
+func deriveKey(key string) ([]byte, error) {
+	storageKey := make([]byte, storageKeySize)
+	r := hkdf.New(sha256.New, []byte(key), nil /* salt */, []byte(storageKeyInfo))
+	if _, err := io.ReadFull(r, storageKey); err != nil {
+		return nil, errors.Wrap(err, "Unable to derive storage key.")
+	}
+	return storageKey, nil
+}
+
+// lookupKey returns the storage key with keyID.
+func lookupKey(keys *shared.StorageKeys, keyID string) ([]byte, error) {
+	encoded, ok := keys.Keys[keyID]
+	if !ok {
+		return nil, errors.Newf("Storage key %s is not available. The key it is derived from may have been removed from the keyring.", keyID)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Storage key %s is malformed.", keyID)
+	}
+	return key, nil
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"net/http"
 	"net/url"
+	"os"
 	"path"
 	"strings"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/dropbox/godropbox/errors"
 )
 
@@ -102,6 +105,121 @@ func (s *s3Storage) Put(ctx context.Context, key string, value []byte) error {
 	return err
 }
 
+func (s *s3Storage) GetReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	sess, err := CreateS3Session(s.s3Config)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket, key, err := s.parseBucketAndKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s3.New(sess).GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, errors.Wrapf(ErrObjectDoesNotExist(), "Unable to fetch key `%s` from bucket `%s`.", key, bucket)
+		}
+		return nil, err
+	}
+
+	return result.Body, nil
+}
+
+func (s *s3Storage) PutReader(ctx context.Context, key string, r io.Reader) error {
+	sess, err := CreateS3Session(s.s3Config)
+	if err != nil {
+		return err
+	}
+
+	bucket, key, err := s.parseBucketAndKey(key)
+	if err != nil {
+		return err
+	}
+
+	// The uploader splits the content into a multipart upload, so only a few parts
+	// are held in memory at a time.
+	_, err = s3manager.NewUploader(sess).UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   r,
+	})
+	return err
+}
+
+func (s *s3Storage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	sess, err := CreateS3Session(s.s3Config)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket, fullKey, err := s.parseBucketAndKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s3.New(sess).HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(fullKey),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, errors.Wrapf(ErrObjectDoesNotExist(), "Unable to fetch key `%s` from bucket `%s`.", fullKey, bucket)
+		}
+		return nil, err
+	}
+
+	return &ObjectInfo{
+		Key:          key,
+		Size:         aws.Int64Value(result.ContentLength),
+		LastModified: aws.TimeValue(result.LastModified),
+	}, nil
+}
+
+func (s *s3Storage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	sess, err := CreateS3Session(s.s3Config)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket, rootKey, err := s.parseBucketAndKey("")
+	if err != nil {
+		return nil, err
+	}
+
+	// The keys in the bucket are relative to the root directory, which is not part of the returned keys.
+	if rootKey != "" {
+		rootKey += "/"
+	}
+
+	objects := []ObjectInfo{}
+	err = s3.New(sess).ListObjectsV2PagesWithContext(
+		ctx,
+		&s3.ListObjectsV2Input{
+			Bucket: aws.String(bucket),
+			Prefix: aws.String(rootKey + prefix),
+		},
+		func(page *s3.ListObjectsV2Output, _ bool) bool {
+			for _, object := range page.Contents {
+				objects = append(objects, ObjectInfo{
+					Key:          strings.TrimPrefix(aws.StringValue(object.Key), rootKey),
+					Size:         aws.Int64Value(object.Size),
+					LastModified: aws.TimeValue(object.LastModified),
+				})
+			}
+			return true
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
 func (s *s3Storage) Delete(ctx context.Context, key string) error {
 	sess, err := CreateS3Session(s.s3Config)
 	if err != nil {
@@ -158,14 +276,45 @@ func (s *s3Storage) Exists(ctx context.Context, key string) bool {
 	return true
 }
 
+// isS3NotFound returns whether err means that the requested object does not exist.
+// HEAD requests have no response body, so they report a generic `NotFound` code instead of `NoSuchKey`.
+func isS3NotFound(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok {
+		return aerr.Code() == s3.ErrCodeNoSuchKey || aerr.Code() == "NotFound"
+	}
+	return false
+}
+
 func CreateS3Session(s3Config *shared.S3Config) (*session.Session, error) {
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(s3Config.Region),
-		Credentials: credentials.NewSharedCredentials(
-			s3Config.CredentialsPath,
-			s3Config.CredentialsProfile,
-		),
-	})
+	opts := session.Options{
+		Config: aws.Config{
+			Region: aws.String(s3Config.Region),
+			Credentials: credentials.NewSharedCredentials(
+				s3Config.CredentialsPath,
+				s3Config.CredentialsProfile,
+			),
+			S3ForcePathStyle: aws.Bool(s3Config.ForcePathStyle),
+		},
+	}
+
+	if s3Config.Endpoint != "" {
+		opts.Config.Endpoint = aws.String(s3Config.Endpoint)
+	}
+
+	if s3Config.CABundlePath != "" {
+		caBundle, err := os.Open(s3Config.CABundlePath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Unable to open CA bundle %s.", s3Config.CABundlePath)
+		}
+		defer caBundle.Close()
+
+		// The bundle is read when the session is created. The SDK installs it on the transport
+		// of the session's HTTP client, so a dedicated client is needed to not affect `http.DefaultClient`.
+		opts.CustomCABundle = caBundle
+		opts.Config.HTTPClient = &http.Client{}
+	}
+
+	sess, err := session.NewSessionWithOptions(opts)
 	if err != nil {
 		return nil, err
 	}
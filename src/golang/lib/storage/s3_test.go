@@ -1,8 +1,20 @@
 package storage
 
 import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/aqueducthq/aqueduct/lib/errors"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/stretchr/testify/require"
 )
@@ -43,3 +55,188 @@ func TestParseBucketAndKey(t *testing.T) {
 		require.Equal(t, tc.expectedKey, key)
 	}
 }
+
+// fakeS3Server is a minimal stand-in for an S3-compatible service like MinIO, which only
+// supports path-style addressing of the objects in a single bucket.
+type fakeS3Server struct {
+	bucket string
+
+	mutex   sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2" {
+		f.list(w, r.URL.Query().Get("prefix"))
+		return
+	}
+
+	prefix := fmt.Sprintf("/%s/", f.bucket)
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, "<Error><Code>NoSuchBucket</Code></Error>")
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, prefix)
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		content, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		f.objects[key] = content
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodGet, http.MethodHead:
+		content, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method == http.MethodGet {
+				fmt.Fprint(w, "<Error><Code>NoSuchKey</Code></Error>")
+			}
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		if r.Method == http.MethodGet {
+			w.Write(content)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// list responds with all objects whose key starts with prefix, in a single page.
+func (f *fakeS3Server) list(w http.ResponseWriter, prefix string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var contents strings.Builder
+	for key, content := range f.objects {
+		if strings.HasPrefix(key, prefix) {
+			fmt.Fprintf(
+				&contents,
+				"<Contents><Key>%s</Key><Size>%d</Size><LastModified>2023-01-02T03:04:05.000Z</LastModified></Contents>",
+				key,
+				len(content),
+			)
+		}
+	}
+
+	fmt.Fprintf(
+		w,
+		"<ListBucketResult><Name>%s</Name><Prefix>%s</Prefix><IsTruncated>false</IsTruncated>%s</ListBucketResult>",
+		f.bucket,
+		prefix,
+		contents.String(),
+	)
+}
+
+// newFakeS3Config starts a fakeS3Server with a self-signed certificate, and returns
+// the config to connect to it along with the server.
+func newFakeS3Config(t *testing.T) (*shared.S3Config, *fakeS3Server) {
+	fakeServer := &fakeS3Server{bucket: "aqueduct", objects: map[string][]byte{}}
+	server := httptest.NewTLSServer(fakeServer)
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	credentialsPath := filepath.Join(dir, "credentials")
+	credentials := "[default]\naws_access_key_id=key\naws_secret_access_key=secret\n"
+	require.Nil(t, os.WriteFile(credentialsPath, []byte(credentials), 0o600))
+
+	caBundlePath := filepath.Join(dir, "ca.pem")
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.Nil(t, os.WriteFile(caBundlePath, caBundle, 0o600))
+
+	return &shared.S3Config{
+		Region:             "us-east-1",
+		Bucket:             "s3://aqueduct",
+		RootDir:            "root/",
+		CredentialsPath:    credentialsPath,
+		CredentialsProfile: "default",
+		Endpoint:           server.URL,
+		ForcePathStyle:     true,
+		CABundlePath:       caBundlePath,
+	}, fakeServer
+}
+
+func TestS3StorageCustomEndpoint(t *testing.T) {
+	ctx := context.Background()
+	s3Config, fakeServer := newFakeS3Config(t)
+	store := newS3Storage(s3Config)
+
+	require.Nil(t, store.Put(ctx, "key", []byte("content")))
+	require.Equal(t, []byte("content"), fakeServer.objects["root/key"])
+	require.True(t, store.Exists(ctx, "key"))
+
+	content, err := store.Get(ctx, "key")
+	require.Nil(t, err)
+	require.Equal(t, []byte("content"), content)
+
+	require.Nil(t, store.PutReader(ctx, "streamed", strings.NewReader("streamed content")))
+	info, err := store.Stat(ctx, "streamed")
+	require.Nil(t, err)
+	require.Equal(t, int64(len("streamed content")), info.Size)
+
+	r, err := store.GetReader(ctx, "streamed")
+	require.Nil(t, err)
+	content, err = io.ReadAll(r)
+	require.Nil(t, err)
+	require.Nil(t, r.Close())
+	require.Equal(t, []byte("streamed content"), content)
+
+	_, err = store.Get(ctx, "missing")
+	require.True(t, errors.Is(err, ErrObjectDoesNotExist()))
+	_, err = store.Stat(ctx, "missing")
+	require.True(t, errors.Is(err, ErrObjectDoesNotExist()))
+	require.False(t, store.Exists(ctx, "missing"))
+
+	require.Nil(t, store.Delete(ctx, "key"))
+	require.False(t, store.Exists(ctx, "key"))
+}
+
+func TestS3StorageList(t *testing.T) {
+	ctx := context.Background()
+	s3Config, fakeServer := newFakeS3Config(t)
+	store := newS3Storage(s3Config)
+
+	require.Nil(t, store.Put(ctx, "dir/a", []byte("a")))
+	require.Nil(t, store.Put(ctx, "dir/bb", []byte("bb")))
+	require.Nil(t, store.Put(ctx, "other", []byte("other")))
+	// Objects outside of the root directory are not part of the storage layer.
+	fakeServer.objects["outside"] = []byte("outside")
+
+	objects, err := store.List(ctx, "dir/")
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{"dir/a", "dir/bb"}, objectKeys(objects))
+
+	objects, err = store.List(ctx, "")
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{"dir/a", "dir/bb", "other"}, objectKeys(objects))
+	for _, object := range objects {
+		if object.Key == "dir/bb" {
+			require.Equal(t, int64(2), object.Size)
+			require.False(t, object.LastModified.IsZero())
+		}
+	}
+}
+
+func TestValidateS3CustomEndpoint(t *testing.T) {
+	ctx := context.Background()
+	s3Config, fakeServer := newFakeS3Config(t)
+
+	storageConfig := &shared.StorageConfig{Type: shared.S3StorageType, S3Config: s3Config}
+	require.Nil(t, Validate(ctx, storageConfig))
+	// The object used for the check is cleaned up.
+	require.Empty(t, fakeServer.objects)
+
+	// The self-signed certificate of the endpoint is not trusted without the CA bundle.
+	s3Config.CABundlePath = ""
+	require.NotNil(t, Validate(ctx, storageConfig))
+}
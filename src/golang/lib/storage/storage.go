@@ -1,11 +1,17 @@
 package storage
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"log"
+	"reflect"
+	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/errors"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/google/uuid"
 )
 
 // NOTE: Callers that use ErrObjectDoesNotExist need to wrap this error with more detail about what
@@ -14,12 +20,103 @@ func ErrObjectDoesNotExist() error {
 	return errors.New("Object does not exist in storage.")
 }
 
+// ObjectInfo describes an object in storage.
+type ObjectInfo struct {
+	Key string
+	// Size is the size of the stored object in bytes,
+	// which is smaller than the size of its content if it is compressed.
+	Size         int64
+	LastModified time.Time
+}
+
 type Storage interface {
 	// Throws `ErrObjectDoesNotExist` if the path does not exist.
 	Get(ctx context.Context, key string) ([]byte, error)
 	Put(ctx context.Context, key string, value []byte) error
 	Delete(ctx context.Context, key string) error
 	Exists(ctx context.Context, key string) bool
+
+	// GetReader returns a reader over the object's content, so that large objects
+	// don't need to be buffered in memory. The caller must close the returned reader.
+	// Throws `ErrObjectDoesNotExist` if the path does not exist.
+	GetReader(ctx context.Context, key string) (io.ReadCloser, error)
+	// PutReader writes everything read from r to the object, until r returns io.EOF.
+	PutReader(ctx context.Context, key string, r io.Reader) error
+	// Throws `ErrObjectDoesNotExist` if the path does not exist.
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// List returns all objects whose key starts with prefix.
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+// Copy streams the object at key from src to dst.
+// Throws `ErrObjectDoesNotExist` if the path does not exist in src.
+func Copy(ctx context.Context, src Storage, dst Storage, key string) error {
+	r, err := src.GetReader(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	return dst.PutReader(ctx, key, r)
+}
+
+// SameLocation returns whether both storage configs store objects in the same place,
+// regardless of how the objects are encoded and which credentials are used to access them.
+func SameLocation(a *shared.StorageConfig, b *shared.StorageConfig) bool {
+	return reflect.DeepEqual(location(a), location(b))
+}
+
+// location returns a copy of config without the fields that do not affect where objects are stored.
+func location(config *shared.StorageConfig) shared.StorageConfig {
+	location := shared.StorageConfig{
+		Type:       config.Type,
+		FileConfig: config.FileConfig,
+	}
+
+	if config.S3Config != nil {
+		s3Config := *config.S3Config
+		s3Config.CredentialsPath = ""
+		s3Config.CredentialsProfile = ""
+		s3Config.AWSAccessKeyID = ""
+		s3Config.AWSSecretAccessKey = ""
+		s3Config.CABundlePath = ""
+		location.S3Config = &s3Config
+	}
+
+	if config.GCSConfig != nil {
+		gcsConfig := *config.GCSConfig
+		gcsConfig.ServiceAccountCredentials = ""
+		location.GCSConfig = &gcsConfig
+	}
+
+	return location
+}
+
+// Validate checks that the storage layer specified by config can be written to and read from,
+// by storing a small object in it and then deleting it.
+func Validate(ctx context.Context, config *shared.StorageConfig) error {
+	store := NewStorage(config)
+
+	key := fmt.Sprintf("connectivity-check-%s", uuid.NewString())
+	value := []byte(key)
+
+	if err := store.Put(ctx, key, value); err != nil {
+		return errors.Wrap(err, "Unable to write to the storage layer.")
+	}
+
+	content, err := store.Get(ctx, key)
+	if err != nil {
+		return errors.Wrap(err, "Unable to read from the storage layer.")
+	}
+
+	if !bytes.Equal(content, value) {
+		return errors.New("The content read from the storage layer does not match the content written to it.")
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		return errors.Wrap(err, "Unable to delete from the storage layer.")
+	}
+	return nil
 }
 
 func NewStorage(config *shared.StorageConfig) Storage {
@@ -27,15 +124,20 @@ func NewStorage(config *shared.StorageConfig) Storage {
 		log.Fatalf("Nil storage config.")
 	}
 
+	var store Storage
 	switch config.Type {
 	case shared.S3StorageType:
-		return newS3Storage(config.S3Config)
+		store = newS3Storage(config.S3Config)
 	case shared.FileStorageType:
-		return newFileStorage(config.FileConfig)
+		store = newFileStorage(config.FileConfig)
 	case shared.GCSStorageType:
-		return newGCSStorage(config.GCSConfig)
+		store = newGCSStorage(config.GCSConfig)
 	default:
 		log.Fatalf("Unsupported storage type: %s", config.Type)
 		return nil
 	}
+
+	// Objects are always decoded when they are read, even if the storage layer
+	// is no longer configured to encode them.
+	return newEncodedStorage(store, config)
 }
@@ -0,0 +1,394 @@
+package storage_gc
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultGracePeriod is how old an unreferenced object must be before it is deleted, if no grace period is configured.
+const DefaultGracePeriod = 24 * time.Hour
+
+// Options configures a garbage collection of the storage layer.
+type Options struct {
+	// Objects that were modified less than GracePeriod ago are never deleted, since they can
+	// belong to a workflow run or preview that has not recorded them in the database yet.
+	GracePeriod time.Duration
+	// If DryRun is set, the orphaned objects are only reported, and nothing is deleted.
+	DryRun bool
+	// PreviewPaths are the paths referenced by the preview cache. If PreviewPaths is nil, the
+	// preview cache is unknown, so the objects in the preview directory are not collected.
+	PreviewPaths []string
+}
+
+// Orphan is an object that is not referenced by anything.
+type Orphan struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+	// The storage integration whose storage layer the object is in.
+	// This is nil if the object is in the server's storage layer.
+	StorageIntegrationID *uuid.UUID `json:"storage_integration_id,omitempty"`
+}
+
+// Report describes the outcome of a garbage collection.
+type Report struct {
+	DryRun bool `json:"dry_run"`
+	// The number of objects in the storage layers.
+	NumObjects int `json:"num_objects"`
+	// The objects that are older than the grace period and are not referenced by anything.
+	Orphans []Orphan `json:"orphans"`
+	// The number of orphans that were deleted, and their total size.
+	NumDeleted     int   `json:"num_deleted"`
+	ReclaimedBytes int64 `json:"reclaimed_bytes"`
+}
+
+// storageLayer is a storage layer that is collected, along with the objects that were listed in it.
+type storageLayer struct {
+	config *shared.StorageConfig
+	// This is nil for the server's storage layer.
+	integrationID *uuid.UUID
+	objects       []storage.ObjectInfo
+}
+
+// Collect deletes the objects that are not referenced by any operator, artifact result, content blob,
+// vault entry, preview cache entry or storage config, and that are older than the grace period. The
+// server's storage layer `storageConfig` is collected, along with the storage layer of every storage
+// integration that workflows store their content in. Each storage layer is only checked against the
+// references of the workflows that store their content in it.
+//
+// The storage layers are listed before the references are read from the database, so an object that
+// becomes referenced while this runs is never deleted, unless it was already an orphan. Content that is
+// stored by its hash can be referenced again by a new artifact result at any time, so its reference
+// count is checked again right before it is deleted.
+func Collect(
+	ctx context.Context,
+	storageConfig *shared.StorageConfig,
+	opts *Options,
+	dagRepo repos.DAG,
+	artifactRepo repos.Artifact,
+	artifactResultRepo repos.ArtifactResult,
+	operatorRepo repos.Operator,
+	integrationRepo repos.Integration,
+	contentBlobRepo repos.ContentBlob,
+	DB database.Database,
+) (*Report, error) {
+	startedAt := time.Now()
+
+	layers, err := listStorageLayers(ctx, storageConfig, dagRepo, DB)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		DryRun:  opts.DryRun,
+		Orphans: []Orphan{},
+	}
+	// The storage layer that each orphan is in.
+	orphanStores := []storage.Storage{}
+
+	for _, layer := range layers {
+		store := storage.NewStorage(layer.config)
+		referenced, err := referencedPaths(
+			ctx,
+			layer,
+			storageConfig,
+			opts.PreviewPaths,
+			dagRepo,
+			artifactRepo,
+			artifactResultRepo,
+			operatorRepo,
+			integrationRepo,
+			contentBlobRepo,
+			DB,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		report.NumObjects += len(layer.objects)
+		for _, object := range layer.objects {
+			if referenced[object.Key] || startedAt.Sub(object.LastModified) < opts.GracePeriod {
+				continue
+			}
+
+			if opts.PreviewPaths == nil && isPreviewPath(object.Key) {
+				continue
+			}
+
+			report.Orphans = append(report.Orphans, Orphan{
+				Key:                  object.Key,
+				Size:                 object.Size,
+				LastModified:         object.LastModified,
+				StorageIntegrationID: layer.integrationID,
+			})
+			orphanStores = append(orphanStores, store)
+		}
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	for i, orphan := range report.Orphans {
+		if hash, ok := storage.ParseContentAddressedPath(orphan.Key); ok {
+			_, err := contentBlobRepo.Get(ctx, hash, DB)
+			if err == nil {
+				// The content was referenced by an artifact result since the references were read.
+				continue
+			}
+			if !aq_errors.Is(err, database.ErrNoRows()) {
+				return nil, errors.Wrapf(err, "Unable to check references to content %s.", hash)
+			}
+		}
+
+		// Deletion is best-effort, since the object is deleted again by the next garbage collection.
+		if err := orphanStores[i].Delete(ctx, orphan.Key); err != nil {
+			log.Errorf("Unable to delete orphaned object %s: %v", orphan.Key, err)
+			continue
+		}
+
+		report.NumDeleted++
+		report.ReclaimedBytes += orphan.Size
+	}
+
+	return report, nil
+}
+
+// listStorageLayers lists the objects in the server's storage layer, and in each distinct storage layer
+// that workflows store their content in. A storage layer that cannot be listed is skipped, so that it
+// does not prevent the other storage layers from being collected.
+func listStorageLayers(
+	ctx context.Context,
+	storageConfig *shared.StorageConfig,
+	dagRepo repos.DAG,
+	DB database.Database,
+) ([]*storageLayer, error) {
+	objects, err := storage.NewStorage(storageConfig).List(ctx, "" /* prefix */)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to list objects in the storage layer.")
+	}
+
+	layers := []*storageLayer{{config: storageConfig, objects: objects}}
+	locations := map[string]bool{storageLocation(storageConfig): true}
+
+	dags, err := dagRepo.List(ctx, DB)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to retrieve workflow dags.")
+	}
+
+	for _, dag := range dags {
+		// Workflows that share a storage layer, including the server's, share its references.
+		if dag.StorageIntegrationID.IsNull || locations[storageLocation(&dag.StorageConfig)] {
+			continue
+		}
+
+		layerConfig := dag.StorageConfig
+		integrationID := dag.StorageIntegrationID.UUID
+		locations[storageLocation(&layerConfig)] = true
+
+		switch layerConfig.Type {
+		case shared.S3StorageType, shared.GCSStorageType, shared.FileStorageType:
+		default:
+			log.Errorf("Unable to collect the storage layer of integration %s of type %s.", integrationID, layerConfig.Type)
+			continue
+		}
+
+		objects, err := storage.NewStorage(&layerConfig).List(ctx, "" /* prefix */)
+		if err != nil {
+			log.Errorf("Unable to list objects in the storage layer of integration %s: %v", integrationID, err)
+			continue
+		}
+
+		layers = append(layers, &storageLayer{
+			config:        &layerConfig,
+			integrationID: &integrationID,
+			objects:       objects,
+		})
+	}
+
+	return layers, nil
+}
+
+// storageLocation identifies where a storage layer stores its objects. Storage configs with the same
+// location store their objects in the same place, even if their credentials are different.
+func storageLocation(storageConfig *shared.StorageConfig) string {
+	switch storageConfig.Type {
+	case shared.FileStorageType:
+		return path.Join("file:", filepath.Clean(storageConfig.FileConfig.Directory))
+	case shared.S3StorageType:
+		return path.Join("s3:", storageConfig.S3Config.Endpoint, storageConfig.S3Config.Bucket, storageConfig.S3Config.RootDir)
+	case shared.GCSStorageType:
+		return path.Join("gcs:", storageConfig.GCSConfig.Bucket)
+	default:
+		return string(storageConfig.Type)
+	}
+}
+
+// dagStorageLocation returns the location of the storage layer that a dag stores its content in.
+func dagStorageLocation(dag *models.DAG, storageConfig *shared.StorageConfig) string {
+	if dag.StorageIntegrationID.IsNull {
+		return storageLocation(storageConfig)
+	}
+	return storageLocation(&dag.StorageConfig)
+}
+
+// referencedPaths returns the set of paths in the storage layer `layer` that are referenced by anything.
+// Only the server's storage layer `storageConfig` has content that is stored by its hash, vault entries
+// and preview cache entries. Vault entries are referenced by their integration, so the vault entries among
+// the objects of the layer are checked against the integrations that exist.
+func referencedPaths(
+	ctx context.Context,
+	layer *storageLayer,
+	storageConfig *shared.StorageConfig,
+	previewPaths []string,
+	dagRepo repos.DAG,
+	artifactRepo repos.Artifact,
+	artifactResultRepo repos.ArtifactResult,
+	operatorRepo repos.Operator,
+	integrationRepo repos.Integration,
+	contentBlobRepo repos.ContentBlob,
+	DB database.Database,
+) (map[string]bool, error) {
+	location := storageLocation(layer.config)
+	isServerLayer := location == storageLocation(storageConfig)
+
+	referenced := map[string]bool{}
+	referenceLocalFiles(referenced, layer.config, storageConfig)
+
+	dags, err := dagRepo.List(ctx, DB)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to retrieve workflow dags.")
+	}
+
+	for _, dag := range dags {
+		// The local files of a workflow's storage config can be in the server's storage layer.
+		referenceLocalFiles(referenced, layer.config, &dag.StorageConfig)
+
+		if dagStorageLocation(&dag, storageConfig) != location {
+			continue
+		}
+
+		operators, err := operatorRepo.GetByDAG(ctx, dag.ID, DB)
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to retrieve operators.")
+		}
+
+		for _, operator := range operators {
+			switch {
+			case operator.Spec.IsFunction():
+				referenced[operator.Spec.Function().StoragePath] = true
+			case operator.Spec.IsCheck():
+				referenced[operator.Spec.Check().Function.StoragePath] = true
+			case operator.Spec.IsMetric():
+				referenced[operator.Spec.Metric().Function.StoragePath] = true
+			}
+		}
+
+		artifacts, err := artifactRepo.GetByDAG(ctx, dag.ID, DB)
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to retrieve artifacts.")
+		}
+
+		if len(artifacts) == 0 {
+			continue
+		}
+
+		artifactIDs := make([]uuid.UUID, 0, len(artifacts))
+		for _, artifact := range artifacts {
+			artifactIDs = append(artifactIDs, artifact.ID)
+		}
+
+		artifactResults, err := artifactResultRepo.GetByArtifactBatch(ctx, artifactIDs, DB)
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to retrieve artifact results.")
+		}
+
+		for _, artifactResult := range artifactResults {
+			referenced[artifactResult.ContentPath] = true
+		}
+	}
+
+	if !isServerLayer {
+		return referenced, nil
+	}
+
+	for _, previewPath := range previewPaths {
+		referenced[previewPath] = true
+	}
+
+	contentBlobs, err := contentBlobRepo.List(ctx, DB)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to retrieve content blobs.")
+	}
+
+	for _, contentBlob := range contentBlobs {
+		referenced[storage.ContentAddressedPath(contentBlob.Hash)] = true
+	}
+
+	// The vault entry of an integration is named after the integration's ID.
+	integrationIDs := []uuid.UUID{}
+	for _, object := range layer.objects {
+		name := strings.TrimPrefix(object.Key, vault.StorageKey(""))
+		if name == object.Key {
+			continue
+		}
+
+		if integrationID, err := uuid.Parse(name); err == nil {
+			integrationIDs = append(integrationIDs, integrationID)
+		}
+	}
+
+	if len(integrationIDs) > 0 {
+		integrations, err := integrationRepo.GetBatch(ctx, integrationIDs, DB)
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to retrieve integrations.")
+		}
+
+		for _, integration := range integrations {
+			referenced[vault.StorageKey(integration.ID.String())] = true
+		}
+	}
+
+	return referenced, nil
+}
+
+// referenceLocalFiles marks the local files that the storage config `conf` reads, such as S3
+// credentials, as referenced if they are in the file storage layer `storageConfig`. These files
+// are written to the server's storage directory when a storage integration is converted to a
+// storage config.
+func referenceLocalFiles(referenced map[string]bool, storageConfig *shared.StorageConfig, conf *shared.StorageConfig) {
+	if storageConfig.Type != shared.FileStorageType || conf.Type != shared.S3StorageType || conf.S3Config == nil {
+		return
+	}
+
+	for _, localPath := range []string{conf.S3Config.CredentialsPath, conf.S3Config.CABundlePath} {
+		if localPath == "" {
+			continue
+		}
+
+		relPath, err := filepath.Rel(storageConfig.FileConfig.Directory, localPath)
+		if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			continue
+		}
+		referenced[filepath.ToSlash(relPath)] = true
+	}
+}
+
+func isPreviewPath(key string) bool {
+	return strings.HasPrefix(key, utils.PreviewDir+"/")
+}
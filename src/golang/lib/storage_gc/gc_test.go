@@ -0,0 +1,340 @@
+package storage_gc
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/function"
+	mdl_utils "github.com/aqueducthq/aqueduct/lib/models/utils"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// The fake repos only implement the methods that are used to find the referenced paths.
+type fakeDAGRepo struct {
+	repos.DAG
+	dags []models.DAG
+}
+
+func (r *fakeDAGRepo) List(ctx context.Context, DB database.Database) ([]models.DAG, error) {
+	return r.dags, nil
+}
+
+type fakeOperatorRepo struct {
+	repos.Operator
+	operators map[uuid.UUID][]models.Operator
+}
+
+func (r *fakeOperatorRepo) GetByDAG(ctx context.Context, dagID uuid.UUID, DB database.Database) ([]models.Operator, error) {
+	return r.operators[dagID], nil
+}
+
+type fakeArtifactRepo struct {
+	repos.Artifact
+	artifacts map[uuid.UUID][]models.Artifact
+}
+
+func (r *fakeArtifactRepo) GetByDAG(ctx context.Context, dagID uuid.UUID, DB database.Database) ([]models.Artifact, error) {
+	return r.artifacts[dagID], nil
+}
+
+type fakeArtifactResultRepo struct {
+	repos.ArtifactResult
+	artifactResults []models.ArtifactResult
+}
+
+func (r *fakeArtifactResultRepo) GetByArtifactBatch(
+	ctx context.Context,
+	artifactIDs []uuid.UUID,
+	DB database.Database,
+) ([]models.ArtifactResult, error) {
+	artifactResults := []models.ArtifactResult{}
+	for _, artifactResult := range r.artifactResults {
+		for _, artifactID := range artifactIDs {
+			if artifactResult.ArtifactID == artifactID {
+				artifactResults = append(artifactResults, artifactResult)
+			}
+		}
+	}
+	return artifactResults, nil
+}
+
+type fakeIntegrationRepo struct {
+	repos.Integration
+	integrations []models.Integration
+}
+
+func (r *fakeIntegrationRepo) GetBatch(ctx context.Context, IDs []uuid.UUID, DB database.Database) ([]models.Integration, error) {
+	return r.integrations, nil
+}
+
+type fakeContentBlobRepo struct {
+	repos.ContentBlob
+	contentBlobs []models.ContentBlob
+}
+
+func (r *fakeContentBlobRepo) List(ctx context.Context, DB database.Database) ([]models.ContentBlob, error) {
+	return r.contentBlobs, nil
+}
+
+func (r *fakeContentBlobRepo) Get(ctx context.Context, hash string, DB database.Database) (*models.ContentBlob, error) {
+	for _, contentBlob := range r.contentBlobs {
+		if contentBlob.Hash == hash {
+			return &contentBlob, nil
+		}
+	}
+	return nil, database.ErrNoRows()
+}
+
+func TestCollect(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	storageConfig := &shared.StorageConfig{
+		Type:       shared.FileStorageType,
+		FileConfig: &shared.FileConfig{Directory: dir},
+	}
+	store := storage.NewStorage(storageConfig)
+
+	integrationID := uuid.New()
+	referencedHash := strings.Repeat("a", 64)
+
+	referenced := []string{
+		"operator-code",
+		"artifact-content",
+		vault.StorageKey(integrationID.String()),
+		storage.ContentAddressedPath(referencedHash),
+	}
+	orphans := []string{
+		"leaked",
+		vault.StorageKey(uuid.NewString()),
+		storage.ContentAddressedPath(strings.Repeat("b", 64)),
+	}
+	preview := "preview/cached"
+
+	old := time.Now().Add(-2 * DefaultGracePeriod)
+	for _, key := range append(append(referenced, orphans...), preview) {
+		require.Nil(t, store.Put(ctx, key, []byte(key)))
+		require.Nil(t, os.Chtimes(filepath.Join(dir, key), old, old))
+	}
+	// Objects within the grace period are never collected.
+	require.Nil(t, store.Put(ctx, "recent", []byte("recent")))
+
+	dagID := uuid.New()
+	artifactID := uuid.New()
+
+	collect := func(opts *Options) *Report {
+		report, err := Collect(
+			ctx,
+			storageConfig,
+			opts,
+			&fakeDAGRepo{dags: []models.DAG{{ID: dagID, StorageIntegrationID: mdl_utils.NullUUID{IsNull: true}}}},
+			&fakeArtifactRepo{artifacts: map[uuid.UUID][]models.Artifact{dagID: {{ID: artifactID}}}},
+			&fakeArtifactResultRepo{artifactResults: []models.ArtifactResult{
+				{ArtifactID: artifactID, ContentPath: "artifact-content"},
+			}},
+			&fakeOperatorRepo{operators: map[uuid.UUID][]models.Operator{dagID: {{
+				Spec: *operator.NewSpecFromFunction(function.Function{StoragePath: "operator-code"}),
+			}}}},
+			&fakeIntegrationRepo{integrations: []models.Integration{{ID: integrationID}}},
+			&fakeContentBlobRepo{contentBlobs: []models.ContentBlob{{Hash: referencedHash, RefCount: 1}}},
+			nil, /* DB */
+		)
+		require.Nil(t, err)
+		return report
+	}
+
+	orphanKeys := func(report *Report) []string {
+		keys := make([]string, 0, len(report.Orphans))
+		for _, orphan := range report.Orphans {
+			keys = append(keys, orphan.Key)
+		}
+		return keys
+	}
+
+	report := collect(&Options{GracePeriod: DefaultGracePeriod, DryRun: true})
+	require.Equal(t, 9, report.NumObjects)
+	require.ElementsMatch(t, orphans, orphanKeys(report))
+	require.Equal(t, 0, report.NumDeleted)
+	for _, key := range orphans {
+		require.True(t, store.Exists(ctx, key))
+	}
+
+	// The preview directory is only collected if the preview cache is known.
+	report = collect(&Options{GracePeriod: DefaultGracePeriod})
+	require.ElementsMatch(t, orphans, orphanKeys(report))
+	require.Equal(t, len(orphans), report.NumDeleted)
+	for _, key := range orphans {
+		require.False(t, store.Exists(ctx, key))
+	}
+	for _, key := range append(referenced, preview, "recent") {
+		require.True(t, store.Exists(ctx, key))
+	}
+
+	report = collect(&Options{GracePeriod: DefaultGracePeriod, PreviewPaths: []string{}})
+	require.Equal(t, []string{preview}, orphanKeys(report))
+	require.False(t, store.Exists(ctx, preview))
+}
+
+func TestCollectKeepsStorageCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	// S3 credentials and CA certificates are written to the server's storage directory.
+	aqPath := t.TempDir()
+	dir := filepath.Join(aqPath, "storage")
+	require.Nil(t, os.MkdirAll(dir, 0o700))
+
+	configPath := filepath.Join(aqPath, "config.yml")
+	require.Nil(t, os.WriteFile(configPath, []byte("aqPath: "+aqPath+"\n"), 0o600))
+	require.Nil(t, config.Init(configPath))
+
+	storageConfig := &shared.StorageConfig{
+		Type:       shared.FileStorageType,
+		FileConfig: &shared.FileConfig{Directory: dir},
+	}
+	store := storage.NewStorage(storageConfig)
+
+	confData, err := json.Marshal(map[string]string{
+		"type":              string(shared.AccessKeyS3ConfigType),
+		"bucket":            "bucket",
+		"region":            "us-east-2",
+		"access_key_id":     "access-key-id",
+		"secret_access_key": "secret-access-key",
+		"endpoint":          "http://127.0.0.1:1",
+		"ca_certificate":    "certificate",
+	})
+	require.Nil(t, err)
+
+	workflowStorageConfig, err := storage.ConvertIntegrationConfigToStorageConfig(shared.S3, confData)
+	require.Nil(t, err)
+
+	require.Nil(t, store.Put(ctx, "leaked", []byte("leaked")))
+
+	old := time.Now().Add(-2 * DefaultGracePeriod)
+	for _, localPath := range []string{
+		workflowStorageConfig.S3Config.CredentialsPath,
+		workflowStorageConfig.S3Config.CABundlePath,
+		filepath.Join(dir, "leaked"),
+	} {
+		require.Nil(t, os.Chtimes(localPath, old, old))
+	}
+
+	report, err := Collect(
+		ctx,
+		storageConfig,
+		&Options{GracePeriod: DefaultGracePeriod, PreviewPaths: []string{}},
+		&fakeDAGRepo{dags: []models.DAG{{
+			ID:                   uuid.New(),
+			StorageConfig:        *workflowStorageConfig,
+			StorageIntegrationID: mdl_utils.NullUUID{UUID: uuid.New()},
+		}}},
+		&fakeArtifactRepo{},
+		&fakeArtifactResultRepo{},
+		&fakeOperatorRepo{},
+		&fakeIntegrationRepo{},
+		&fakeContentBlobRepo{},
+		nil, /* DB */
+	)
+	require.Nil(t, err)
+	require.Equal(t, 3, report.NumObjects)
+	require.Equal(t, 1, report.NumDeleted)
+	require.False(t, store.Exists(ctx, "leaked"))
+
+	for _, localPath := range []string{
+		workflowStorageConfig.S3Config.CredentialsPath,
+		workflowStorageConfig.S3Config.CABundlePath,
+	} {
+		_, err := os.Stat(localPath)
+		require.Nil(t, err)
+	}
+}
+
+func TestCollectWorkflowStorage(t *testing.T) {
+	ctx := context.Background()
+
+	newFileStorageConfig := func() *shared.StorageConfig {
+		return &shared.StorageConfig{
+			Type:       shared.FileStorageType,
+			FileConfig: &shared.FileConfig{Directory: t.TempDir()},
+		}
+	}
+	serverStorageConfig := newFileStorageConfig()
+	workflowStorageConfig := newFileStorageConfig()
+	serverStore := storage.NewStorage(serverStorageConfig)
+	workflowStore := storage.NewStorage(workflowStorageConfig)
+
+	// Each storage layer has the content of the other one's workflow.
+	old := time.Now().Add(-2 * DefaultGracePeriod)
+	for _, storageConfig := range []*shared.StorageConfig{serverStorageConfig, workflowStorageConfig} {
+		store := storage.NewStorage(storageConfig)
+		for _, key := range []string{"server-content", "workflow-content"} {
+			require.Nil(t, store.Put(ctx, key, []byte(key)))
+			require.Nil(t, os.Chtimes(filepath.Join(storageConfig.FileConfig.Directory, key), old, old))
+		}
+	}
+
+	serverDAGID, workflowDAGID := uuid.New(), uuid.New()
+	serverArtifactID, workflowArtifactID := uuid.New(), uuid.New()
+	integrationID := uuid.New()
+
+	report, err := Collect(
+		ctx,
+		serverStorageConfig,
+		&Options{GracePeriod: DefaultGracePeriod, PreviewPaths: []string{}},
+		&fakeDAGRepo{dags: []models.DAG{
+			{
+				ID:                   serverDAGID,
+				StorageConfig:        *serverStorageConfig,
+				StorageIntegrationID: mdl_utils.NullUUID{IsNull: true},
+			},
+			{
+				ID:                   workflowDAGID,
+				StorageConfig:        *workflowStorageConfig,
+				StorageIntegrationID: mdl_utils.NullUUID{UUID: integrationID},
+			},
+		}},
+		&fakeArtifactRepo{artifacts: map[uuid.UUID][]models.Artifact{
+			serverDAGID:   {{ID: serverArtifactID}},
+			workflowDAGID: {{ID: workflowArtifactID}},
+		}},
+		&fakeArtifactResultRepo{artifactResults: []models.ArtifactResult{
+			{ArtifactID: serverArtifactID, ContentPath: "server-content"},
+			{ArtifactID: workflowArtifactID, ContentPath: "workflow-content"},
+		}},
+		&fakeOperatorRepo{},
+		&fakeIntegrationRepo{},
+		&fakeContentBlobRepo{},
+		nil, /* DB */
+	)
+	require.Nil(t, err)
+	require.Equal(t, 4, report.NumObjects)
+	require.Equal(t, 2, report.NumDeleted)
+
+	require.Equal(t, 2, len(report.Orphans))
+	for _, orphan := range report.Orphans {
+		if orphan.Key == "workflow-content" {
+			require.Nil(t, orphan.StorageIntegrationID)
+		} else {
+			require.Equal(t, "server-content", orphan.Key)
+			require.Equal(t, integrationID, *orphan.StorageIntegrationID)
+		}
+	}
+
+	require.True(t, serverStore.Exists(ctx, "server-content"))
+	require.False(t, serverStore.Exists(ctx, "workflow-content"))
+	require.True(t, workflowStore.Exists(ctx, "workflow-content"))
+	require.False(t, workflowStore.Exists(ctx, "server-content"))
+}
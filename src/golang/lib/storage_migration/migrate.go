@@ -113,6 +113,8 @@ func Perform(
 
 		// Migrate all storage content to the new storage config
 		currentStorageConfig := config.Storage()
+		// Whether content is stored by its hash is independent of where it is stored.
+		newStorageConfig.ContentAddressed = currentStorageConfig.ContentAddressed
 		storageCleanupConfig, err := MigrateStorageAndVault(
 			context.Background(),
 			&currentStorageConfig,
@@ -242,10 +244,13 @@ type StorageCleanupConfig struct {
 // This includes:
 //   - artifact result content
 //   - operator (function, check) code
-//   - vault content (integration credentials)
+//   - vault content (integration credentials), unless it is kept in an external vault
 //
 // The keys to all the contents that were copied are also returned, so that the caller can perform best-effort
 // cleanup the old storage layer.
+//
+// If both configs store objects in the same place and only differ in how the objects are compressed or encrypted,
+// the storage content is re-encoded in place instead, and there is nothing to clean up.
 func MigrateStorageAndVault(
 	ctx context.Context,
 	oldConf *shared.StorageConfig,
@@ -260,17 +265,26 @@ func MigrateStorageAndVault(
 ) (*StorageCleanupConfig, error) {
 	log.Infof("Migrating from %v to %v", *oldConf, *newConf)
 
+	reencode := storage.SameLocation(oldConf, newConf)
+
 	oldStore := storage.NewStorage(oldConf)
 	newStore := storage.NewStorage(newConf)
 
-	oldVault, err := vault.NewVault(oldConf, config.EncryptionKey())
-	if err != nil {
-		return nil, err
-	}
+	// Secrets that are kept in an external vault are not affected by the storage layer.
+	migrateVault := !reencode && !config.Vault().IsExternal()
 
-	newVault, err := vault.NewVault(newConf, config.EncryptionKey())
-	if err != nil {
-		return nil, err
+	var oldVault, newVault vault.Vault
+	if migrateVault {
+		var err error
+		oldVault, err = vault.NewVault(nil /* vaultConf */, oldConf, config.EncryptionKeyring())
+		if err != nil {
+			return nil, err
+		}
+
+		newVault, err = vault.NewVault(nil /* vaultConf */, newConf, config.EncryptionKeyring())
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	txn, err := DB.BeginTx(ctx)
@@ -286,6 +300,10 @@ func MigrateStorageAndVault(
 
 	toDelete := []string{}
 
+	// Artifact results can share their content if it is stored by its hash,
+	// in which case the content only needs to be migrated once.
+	migratedContentPaths := map[string]bool{}
+
 	log.Infof("There are %v DAGs to migrate", len(dags))
 
 	for _, dag := range dags {
@@ -297,6 +315,12 @@ func MigrateStorageAndVault(
 			continue
 		}
 
+		if !dag.StorageIntegrationID.IsNull {
+			// The DAG's workflow has its own storage layer, which is migrated with `MigrateWorkflow`.
+			log.Info("This DAG has its own storage layer, so its migration will be skipped.")
+			continue
+		}
+
 		// Migrate all of the artifact result content for this DAG
 		artifacts, err := artifactRepo.GetByDAG(ctx, dag.ID, txn)
 		if err != nil {
@@ -319,7 +343,12 @@ func MigrateStorageAndVault(
 			for _, artifactResult := range artifactResults {
 				log.Infof("Starting migration for artifact result %v of artifact %v", artifactResult.ID, artifact.ID)
 
-				val, err := oldStore.Get(ctx, artifactResult.ContentPath)
+				if migratedContentPaths[artifactResult.ContentPath] {
+					continue
+				}
+
+				// The content is streamed, since artifact results can be too large to fit in memory.
+				val, err := oldStore.GetReader(ctx, artifactResult.ContentPath)
 				if err != nil &&
 					!artifactResult.ExecState.IsNull &&
 					artifactResult.ExecState.Status == shared.SucceededExecutionStatus {
@@ -332,12 +361,15 @@ func MigrateStorageAndVault(
 				if err == nil {
 					// Only try to migrate artifact result if there was no issue reading
 					// it from the `oldStore`
-					if err := newStore.Put(ctx, artifactResult.ContentPath, val); err != nil {
+					err := newStore.PutReader(ctx, artifactResult.ContentPath, val)
+					val.Close()
+					if err != nil {
 						log.Errorf("Unable to write artifact result %v to new store: %v", artifactResult.ID, err)
 						return nil, err
 					}
 				}
 
+				migratedContentPaths[artifactResult.ContentPath] = true
 				toDelete = append(toDelete, artifactResult.ContentPath)
 			}
 		}
@@ -366,14 +398,8 @@ func MigrateStorageAndVault(
 				continue
 			}
 
-			val, err := oldStore.Get(ctx, operatorCodePath)
-			if err != nil {
-				log.Errorf("Unable to get operator code %v from old store: %v", operator.ID, err)
-				return nil, err
-			}
-
-			if err := newStore.Put(ctx, operatorCodePath, val); err != nil {
-				log.Errorf("Unable to write operator code %v to new store: %v", operator.ID, err)
+			if err := storage.Copy(ctx, oldStore, newStore, operatorCodePath); err != nil {
+				log.Errorf("Unable to migrate operator code %v to new store: %v", operator.ID, err)
 				return nil, err
 			}
 
@@ -393,23 +419,32 @@ func MigrateStorageAndVault(
 		}
 	}
 
-	// Migrate the vault portion of storage
-	toDeleteFromVault, err := utils.MigrateVault(
-		ctx,
-		oldVault,
-		newVault,
-		orgID,
-		integrationRepo,
-		txn,
-	)
-	if err != nil {
-		return nil, err
+	// The vault is not affected by how storage content is encoded.
+	var toDeleteFromVault []string
+	if migrateVault {
+		// Migrate the vault portion of storage
+		toDeleteFromVault, err = utils.MigrateVault(
+			ctx,
+			oldVault,
+			newVault,
+			orgID,
+			integrationRepo,
+			txn,
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if err := txn.Commit(ctx); err != nil {
 		return nil, err
 	}
 
+	if reencode {
+		// The re-encoded content replaced the old content, so it must not be deleted.
+		toDelete = nil
+	}
+
 	return &StorageCleanupConfig{
 		StoreKeys: toDelete,
 		VaultKeys: toDeleteFromVault,
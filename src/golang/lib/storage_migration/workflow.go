@@ -0,0 +1,298 @@
+package storage_migration
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/auth"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// NewWorkflowStorageConfig returns the storage layer of the storage integration `integrationObj`,
+// for a workflow to store its content in. Objects are compressed and encrypted the same way as in
+// the server's storage layer. An error is returned if the storage layer is not usable.
+func NewWorkflowStorageConfig(
+	ctx context.Context,
+	integrationObj *models.Integration,
+	vaultObject vault.Vault,
+) (*shared.StorageConfig, error) {
+	conf, err := auth.ReadConfigFromSecret(ctx, integrationObj.ID, vaultObject)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to read storage integration config.")
+	}
+
+	confData, err := conf.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	storageConfig, err := storage.ConvertIntegrationConfigToStorageConfig(integrationObj.Service, confData)
+	if err != nil {
+		return nil, err
+	}
+
+	// Content that is stored by its hash is reference counted for the server's storage layer
+	// only, so a workflow's own storage layer never stores content by its hash.
+	serverStorageConfig := config.Storage()
+	storageConfig.Compression = serverStorageConfig.Compression
+	storageConfig.Encrypt = serverStorageConfig.Encrypt
+	storageConfig.ContentAddressed = false
+
+	if err := storage.Validate(ctx, storageConfig); err != nil {
+		return nil, errors.Wrap(err, "Unable to connect to the storage layer.")
+	}
+
+	return storageConfig, nil
+}
+
+// MigrateWorkflow moves the artifact result content and operator code of all of the dags of
+// the workflow with workflowID to the storage layer `newConf`. If `storageIntegrationID` is set,
+// `newConf` is the storage layer of that integration, otherwise it is the server's storage layer.
+//
+// Content that is stored by its hash belongs to the server's storage layer, where it can be shared
+// with other workflows. It is copied to a path of its own in the new storage layer, and the
+// workflow's references to the shared content are released.
+//
+// The migration waits until no workflow is running. The old content is deleted on a best-effort
+// basis once the migration has been committed.
+func MigrateWorkflow(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	storageIntegrationID *uuid.UUID,
+	newConf *shared.StorageConfig,
+	artifactResultRepo repos.ArtifactResult,
+	contentBlobRepo repos.ContentBlob,
+	dagRepo repos.DAG,
+	dagResultRepo repos.DAGResult,
+	operatorRepo repos.Operator,
+	DB database.Database,
+) error {
+	// Wait until there are no more workflow runs in progress
+	lock := utils.NewExecutionLock()
+	if err := lock.Lock(); err != nil {
+		return errors.Wrap(err, "Unexpected error when acquiring workflow execution lock.")
+	}
+	defer func() {
+		if lockErr := lock.Unlock(); lockErr != nil {
+			log.Errorf("Unexpected error when unlocking workflow execution lock: %v", lockErr)
+		}
+	}()
+
+	txn, err := DB.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer database.TxnRollbackIgnoreErr(ctx, txn)
+
+	dags, err := dagRepo.GetByWorkflow(ctx, workflowID, txn)
+	if err != nil {
+		return errors.Wrap(err, "Unable to retrieve workflow dags.")
+	}
+
+	dagByID := make(map[uuid.UUID]*models.DAG, len(dags))
+	for i, dag := range dags {
+		if dag.EngineConfig.Type == shared.AirflowEngineType {
+			return errors.New("The content of Airflow workflows cannot be migrated.")
+		}
+		dagByID[dag.ID] = &dags[i]
+	}
+
+	newStore := storage.NewStorage(newConf)
+
+	// The old content of each dag is deleted from the dag's old storage layer after the migration.
+	toDeleteByDAG := make(map[uuid.UUID][]string, len(dags))
+	migratedPaths := map[string]bool{}
+
+	// deleteOldContent marks the object at path in the dag's storage layer to be deleted after the migration,
+	// unless the object was re-encoded in place.
+	deleteOldContent := func(dag *models.DAG, path string) {
+		if !storage.SameLocation(&dag.StorageConfig, newConf) {
+			toDeleteByDAG[dag.ID] = append(toDeleteByDAG[dag.ID], path)
+		}
+	}
+
+	// copyContent copies the object at oldPath in the dag's storage layer to newPath in the new one.
+	// It returns whether the object exists.
+	copyContent := func(dag *models.DAG, oldPath string, newPath string) (bool, error) {
+		if oldPath == newPath && reflect.DeepEqual(dag.StorageConfig, *newConf) {
+			return true, nil
+		}
+
+		r, err := storage.NewStorage(&dag.StorageConfig).GetReader(ctx, oldPath)
+		if err != nil {
+			if aq_errors.Is(err, storage.ErrObjectDoesNotExist()) {
+				return false, nil
+			}
+			return false, err
+		}
+		defer r.Close()
+
+		// The content is streamed, since artifact results can be too large to fit in memory.
+		return true, newStore.PutReader(ctx, newPath, r)
+	}
+
+	dagResults, err := dagResultRepo.GetByWorkflow(ctx, workflowID, txn)
+	if err != nil {
+		return errors.Wrap(err, "Unable to retrieve workflow dag results.")
+	}
+
+	dagResultIDs := make([]uuid.UUID, 0, len(dagResults))
+	dagIDByDAGResultID := make(map[uuid.UUID]uuid.UUID, len(dagResults))
+	for _, dagResult := range dagResults {
+		dagResultIDs = append(dagResultIDs, dagResult.ID)
+		dagIDByDAGResultID[dagResult.ID] = dagResult.DagID
+	}
+
+	artifactResults := []models.ArtifactResult{}
+	if len(dagResultIDs) > 0 {
+		artifactResults, err = artifactResultRepo.GetByDAGResults(ctx, dagResultIDs, txn)
+		if err != nil {
+			return errors.Wrap(err, "Unable to retrieve artifact results.")
+		}
+	}
+
+	log.Infof("There are %v artifact results to migrate for workflow %v", len(artifactResults), workflowID)
+
+	// Each artifact result holds its own reference to shared content, so all of them are released.
+	// Shared content is only copied once, to the same new path.
+	newPathBySharedPath := map[string]string{}
+	sharedPathsToRelease := map[uuid.UUID][]string{}
+	for _, artifactResult := range artifactResults {
+		dag := dagByID[dagIDByDAGResultID[artifactResult.DAGResultID]]
+		if dag == nil {
+			return errors.Newf("Unable to find the workflow dag of artifact result %v.", artifactResult.ID)
+		}
+
+		if _, ok := storage.ParseContentAddressedPath(artifactResult.ContentPath); !ok {
+			if migratedPaths[artifactResult.ContentPath] {
+				continue
+			}
+
+			exists, err := copyContent(dag, artifactResult.ContentPath, artifactResult.ContentPath)
+			if err != nil {
+				return errors.Wrapf(err, "Unable to migrate artifact result %v.", artifactResult.ID)
+			}
+
+			if exists {
+				deleteOldContent(dag, artifactResult.ContentPath)
+			} else if artifactResult.Status == shared.SucceededExecutionStatus {
+				return errors.Newf("The content of artifact result %v is missing from the storage layer.", artifactResult.ID)
+			}
+
+			migratedPaths[artifactResult.ContentPath] = true
+			continue
+		}
+
+		newPath, ok := newPathBySharedPath[artifactResult.ContentPath]
+		if !ok {
+			newPath = utils.InitializePath(false /* isPreview */)
+			exists, err := copyContent(dag, artifactResult.ContentPath, newPath)
+			if err != nil {
+				return errors.Wrapf(err, "Unable to migrate artifact result %v.", artifactResult.ID)
+			}
+			if !exists {
+				return errors.Newf("The content of artifact result %v is missing from the storage layer.", artifactResult.ID)
+			}
+
+			newPathBySharedPath[artifactResult.ContentPath] = newPath
+		}
+
+		if _, err := artifactResultRepo.Update(
+			ctx,
+			artifactResult.ID,
+			map[string]interface{}{
+				models.ArtifactResultContentPath: newPath,
+			},
+			txn,
+		); err != nil {
+			return errors.Wrapf(err, "Unable to update artifact result %v.", artifactResult.ID)
+		}
+
+		sharedPathsToRelease[dag.ID] = append(sharedPathsToRelease[dag.ID], artifactResult.ContentPath)
+	}
+
+	for _, dag := range dags {
+		operators, err := operatorRepo.GetByDAG(ctx, dag.ID, txn)
+		if err != nil {
+			return errors.Wrap(err, "Unable to retrieve operators.")
+		}
+
+		for _, operator := range operators {
+			var operatorCodePath string
+			switch {
+			case operator.Spec.IsFunction():
+				operatorCodePath = operator.Spec.Function().StoragePath
+			case operator.Spec.IsCheck():
+				operatorCodePath = operator.Spec.Check().Function.StoragePath
+			case operator.Spec.IsMetric():
+				operatorCodePath = operator.Spec.Metric().Function.StoragePath
+			default:
+				// There is no operator code to migrate for this operator
+				continue
+			}
+
+			// Operators are shared by the dags of a workflow.
+			if migratedPaths[operatorCodePath] {
+				continue
+			}
+
+			exists, err := copyContent(dagByID[dag.ID], operatorCodePath, operatorCodePath)
+			if err != nil {
+				return errors.Wrapf(err, "Unable to migrate the code of operator %v.", operator.ID)
+			}
+			if !exists {
+				return errors.Newf("The code of operator %v is missing from the storage layer.", operator.ID)
+			}
+
+			deleteOldContent(dagByID[dag.ID], operatorCodePath)
+			migratedPaths[operatorCodePath] = true
+		}
+
+		if _, err := dagRepo.Update(
+			ctx,
+			dag.ID,
+			map[string]interface{}{
+				models.DagStorageConfig:        newConf,
+				models.DagStorageIntegrationID: storageIntegrationID,
+			},
+			txn,
+		); err != nil {
+			return errors.Wrap(err, "Unable to update the storage layer of the workflow dag.")
+		}
+	}
+
+	releasedPathsByDAG := make(map[uuid.UUID][]string, len(sharedPathsToRelease))
+	for dagID, sharedPaths := range sharedPathsToRelease {
+		_, releasedPaths, err := utils.ReleaseContent(ctx, sharedPaths, contentBlobRepo, txn)
+		if err != nil {
+			return errors.Wrap(err, "Unable to release shared artifact content.")
+		}
+		releasedPathsByDAG[dagID] = releasedPaths
+	}
+
+	if err := txn.Commit(ctx); err != nil {
+		return err
+	}
+
+	for dagID, toDelete := range toDeleteByDAG {
+		utils.CleanupStorageFiles(ctx, &dagByID[dagID].StorageConfig, toDelete)
+	}
+
+	// The old copies of shared content are deleted from the old storage layer.
+	for dagID, releasedPaths := range releasedPathsByDAG {
+		utils.DeleteReleasedContent(ctx, &dagByID[dagID].StorageConfig, releasedPaths, contentBlobRepo, DB)
+	}
+
+	return nil
+}
@@ -1,15 +1,31 @@
 package vault
 
 import (
+	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
 	"encoding/json"
 	"io"
+
+	"github.com/aqueducthq/aqueduct/config"
+	"github.com/dropbox/godropbox/errors"
 )
 
-// encrypt uses `key` to encrypt `secrets`
-func encrypt(secrets map[string]string, key string) ([]byte, error) {
+// Entries are stored in an envelope that records the ID of the key they were encrypted with,
+// so that the key can be rotated. The envelope consists of the magic bytes, the envelope version,
+// the length of the key ID, the key ID, the nonce and the ciphertext.
+// Entries that were written before keys could be rotated only consist of the nonce and the ciphertext.
+var envelopeMagic = []byte{0x89, 'A', 'Q', 'V'}
+
+const envelopeVersion byte = 1
+
+// encrypt uses the key with `keyID` to encrypt `secrets`
+func encrypt(secrets map[string]string, keyID string, key string) ([]byte, error) {
+	if len(keyID) > 255 {
+		return nil, errors.Newf("Encryption key ID %s is too long.", keyID)
+	}
+
 	// generate an aes cipher using our 32 byte encryption key
 	c, err := aes.NewCipher([]byte(key))
 	if err != nil {
@@ -34,12 +50,74 @@ func encrypt(secrets map[string]string, key string) ([]byte, error) {
 		return nil, err
 	}
 
+	envelope := make([]byte, 0, len(envelopeMagic)+2+len(keyID))
+	envelope = append(envelope, envelopeMagic...)
+	envelope = append(envelope, envelopeVersion, byte(len(keyID)))
+	envelope = append(envelope, keyID...)
+
 	encrypted := gcm.Seal(nonce, nonce, serialized, nil /* additionalData */)
-	return encrypted, nil
+	return append(envelope, encrypted...), nil
 }
 
-// decrypt uses `key` to decrypt `data`
-func decrypt(data []byte, key string) (map[string]string, error) {
+// decrypt uses the key in `keyring` that `data` was encrypted with to decrypt `data`
+func decrypt(data []byte, keyring config.Keyring) (map[string]string, error) {
+	keyID, encrypted, ok, err := parseEnvelope(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		// The key that entries without an envelope were encrypted with is unknown,
+		// so every key is tried, starting with the original one.
+		if result, err := decryptWithKey(data, keyring.Keys[""]); err == nil {
+			return result, nil
+		}
+
+		for keyID, key := range keyring.Keys {
+			if keyID == "" {
+				continue
+			}
+
+			if result, err := decryptWithKey(data, key); err == nil {
+				return result, nil
+			}
+		}
+		return nil, errors.New("Unable to decrypt vault entry with any key in the keyring.")
+	}
+
+	key, ok := keyring.Keys[keyID]
+	if !ok {
+		return nil, errors.Newf("Vault entry was encrypted with key %s, which is not in the keyring.", keyID)
+	}
+	return decryptWithKey(encrypted, key)
+}
+
+// parseEnvelope returns the ID of the key that `data` was encrypted with and the encrypted data.
+// It returns false if `data` has no envelope.
+func parseEnvelope(data []byte) (string, []byte, bool, error) {
+	if !bytes.HasPrefix(data, envelopeMagic) {
+		return "", nil, false, nil
+	}
+
+	data = data[len(envelopeMagic):]
+	if len(data) < 2 {
+		return "", nil, false, errors.New("Vault entry is truncated.")
+	}
+
+	if data[0] != envelopeVersion {
+		return "", nil, false, errors.Newf("Unsupported vault entry version %d.", data[0])
+	}
+
+	keyIDLen := int(data[1])
+	data = data[2:]
+	if len(data) < keyIDLen {
+		return "", nil, false, errors.New("Vault entry is truncated.")
+	}
+	return string(data[:keyIDLen]), data[keyIDLen:], true, nil
+}
+
+// decryptWithKey uses `key` to decrypt `data`
+func decryptWithKey(data []byte, key string) (map[string]string, error) {
 	c, err := aes.NewCipher([]byte(key))
 	if err != nil {
 		return nil, err
@@ -52,7 +130,7 @@ func decrypt(data []byte, key string) (map[string]string, error) {
 
 	nonceSize := gcm.NonceSize()
 	if len(data) < nonceSize {
-		return nil, err
+		return nil, errors.New("Vault entry is truncated.")
 	}
 
 	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
@@ -0,0 +1,69 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/dropbox/godropbox/errors"
+)
+
+const defaultEnvVaultPrefix = "AQUEDUCT_SECRET_"
+
+// envVault reads secrets from environment variables. Each entry is a variable that holds a JSON object
+// of the secrets. The variables cannot be changed by the server, so the entries must be provisioned
+// before they are used.
+type envVault struct {
+	prefix string
+}
+
+func newEnvVault(conf shared.EnvVaultConfig) Vault {
+	prefix := conf.Prefix
+	if prefix == "" {
+		prefix = defaultEnvVaultPrefix
+	}
+
+	return &envVault{prefix: prefix}
+}
+
+// variable returns the name of the environment variable that holds the entry `name`.
+func (v *envVault) variable(name string) string {
+	return v.prefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+func (v *envVault) Put(ctx context.Context, name string, secrets map[string]string) error {
+	existing, err := v.Get(ctx, name)
+	if err == nil && reflect.DeepEqual(existing, secrets) {
+		return nil
+	}
+
+	return errors.Newf(
+		"Secrets are read from the environment, so the environment variable %s must be set to a JSON object of the secrets.",
+		v.variable(name),
+	)
+}
+
+func (v *envVault) Get(ctx context.Context, name string) (map[string]string, error) {
+	value, ok := os.LookupEnv(v.variable(name))
+	if !ok {
+		return nil, errors.Newf("Environment variable %s is not set.", v.variable(name))
+	}
+
+	var secrets map[string]string
+	if err := json.Unmarshal([]byte(value), &secrets); err != nil {
+		return nil, errors.Wrapf(err, "Environment variable %s is not a JSON object of secrets.", v.variable(name))
+	}
+	return secrets, nil
+}
+
+func (*envVault) Delete(ctx context.Context, name string) error {
+	// The variables are owned by the environment, so there is nothing to delete.
+	return nil
+}
+
+func (*envVault) Reencrypt(ctx context.Context, name string) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,186 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/stretchr/testify/require"
+)
+
+const testHashiCorpToken = "dev-only-token"
+
+// newHashiCorpDevServer starts a stand-in for a dev-mode HashiCorp Vault server,
+// which serves the KV version 2 secrets engine mounted at `secret`.
+func newHashiCorpDevServer(t *testing.T) *httptest.Server {
+	var mu sync.Mutex
+	secrets := map[string]map[string]string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(hashiCorpTokenHeader) != testHashiCorpToken {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string][]string{"errors": {"permission denied"}})
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v1/secret/data/"):
+			path := strings.TrimPrefix(r.URL.Path, "/v1/secret/data/")
+			switch r.Method {
+			case http.MethodPost:
+				var secret hashiCorpSecret
+				if err := json.NewDecoder(r.Body).Decode(&secret); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				secrets[path] = secret.Data
+				w.WriteHeader(http.StatusOK)
+			case http.MethodGet:
+				secret, ok := secrets[path]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(hashiCorpReadResponse{Data: hashiCorpSecret{Data: secret}})
+			default:
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		case strings.HasPrefix(r.URL.Path, "/v1/secret/metadata/") && r.Method == http.MethodDelete:
+			delete(secrets, strings.TrimPrefix(r.URL.Path, "/v1/secret/metadata/"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHashiCorpVault(t *testing.T) {
+	ctx := context.Background()
+	server := newHashiCorpDevServer(t)
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.Nil(t, os.WriteFile(tokenPath, []byte(testHashiCorpToken+"\n"), 0o600))
+
+	v, err := NewVault(&shared.VaultConfig{
+		Type: shared.HashiCorpVaultType,
+		HashiCorpConfig: &shared.HashiCorpVaultConfig{
+			Address:   server.URL,
+			TokenPath: tokenPath,
+		},
+	}, nil /* storageConf */, config.Keyring{})
+	require.Nil(t, err)
+
+	require.Nil(t, v.Put(ctx, "integration", testSecrets))
+
+	secrets, err := v.Get(ctx, "integration")
+	require.Nil(t, err)
+	require.Equal(t, testSecrets, secrets)
+
+	reencrypted, err := v.Reencrypt(ctx, "integration")
+	require.Nil(t, err)
+	require.False(t, reencrypted)
+
+	require.Nil(t, v.Delete(ctx, "integration"))
+	_, err = v.Get(ctx, "integration")
+	require.NotNil(t, err)
+
+	// Deleting a missing secret is not an error.
+	require.Nil(t, v.Delete(ctx, "integration"))
+
+	unauthorized, err := NewVault(&shared.VaultConfig{
+		Type: shared.HashiCorpVaultType,
+		HashiCorpConfig: &shared.HashiCorpVaultConfig{
+			Address: server.URL,
+			Token:   "wrong-token",
+		},
+	}, nil /* storageConf */, config.Keyring{})
+	require.Nil(t, err)
+
+	err = unauthorized.Put(ctx, "integration", testSecrets)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "permission denied")
+}
+
+func TestMountedVault(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// Provision an entry the way Kubernetes mounts a secret: the files link into a hidden directory.
+	dataDir := filepath.Join(dir, "provisioned", "..data")
+	require.Nil(t, os.MkdirAll(dataDir, 0o755))
+	for key, value := range testSecrets {
+		require.Nil(t, os.WriteFile(filepath.Join(dataDir, key), []byte(value), 0o600))
+		require.Nil(t, os.Symlink(filepath.Join("..data", key), filepath.Join(dir, "provisioned", key)))
+	}
+
+	v, err := NewVault(&shared.VaultConfig{
+		Type:          shared.MountedVaultType,
+		MountedConfig: &shared.MountedVaultConfig{Directory: dir},
+	}, nil /* storageConf */, config.Keyring{})
+	require.Nil(t, err)
+
+	secrets, err := v.Get(ctx, "provisioned")
+	require.Nil(t, err)
+	require.Equal(t, testSecrets, secrets)
+
+	// Putting the same secrets leaves the provisioned entry as is.
+	require.Nil(t, v.Put(ctx, "provisioned", testSecrets))
+	_, err = os.Lstat(filepath.Join(dir, "provisioned", "..data"))
+	require.Nil(t, err)
+
+	require.Nil(t, v.Put(ctx, "written", testSecrets))
+	secrets, err = v.Get(ctx, "written")
+	require.Nil(t, err)
+	require.Equal(t, testSecrets, secrets)
+
+	require.Nil(t, v.Delete(ctx, "written"))
+	_, err = v.Get(ctx, "written")
+	require.NotNil(t, err)
+
+	// Names that would escape the mount directory are rejected.
+	require.NotNil(t, v.Put(ctx, "escaped", map[string]string{"../../escaped": "value"}))
+	require.NotNil(t, v.Put(ctx, "escaped", map[string]string{"nested/key": "value"}))
+	require.NotNil(t, v.Put(ctx, "..", testSecrets))
+	require.NotNil(t, v.Delete(ctx, ".."))
+	_, err = os.Stat(filepath.Join(dir, "escaped"))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(filepath.Dir(dir), "escaped"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestEnvVault(t *testing.T) {
+	ctx := context.Background()
+
+	v, err := NewVault(&shared.VaultConfig{Type: shared.EnvVaultType}, nil /* storageConf */, config.Keyring{})
+	require.Nil(t, err)
+
+	_, err = v.Get(ctx, "my-integration")
+	require.NotNil(t, err)
+
+	// Secrets cannot be written to the environment.
+	require.NotNil(t, v.Put(ctx, "my-integration", testSecrets))
+
+	serialized, err := json.Marshal(testSecrets)
+	require.Nil(t, err)
+	t.Setenv("AQUEDUCT_SECRET_MY_INTEGRATION", string(serialized))
+
+	secrets, err := v.Get(ctx, "my-integration")
+	require.Nil(t, err)
+	require.Equal(t, testSecrets, secrets)
+
+	// Putting the secrets that are already set is a no-op.
+	require.Nil(t, v.Put(ctx, "my-integration", testSecrets))
+}
@@ -3,6 +3,7 @@ package vault
 import (
 	"path/filepath"
 
+	"github.com/aqueducthq/aqueduct/config"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/storage"
 )
@@ -11,7 +12,7 @@ const (
 	FileVaultDir = "vault/"
 )
 
-func newFileVault(fileStoreConf shared.FileConfig, key string) Vault {
+func newFileVault(fileStoreConf shared.FileConfig, keyring config.Keyring) Vault {
 	// The file vault stores secrets under the ../vault subdirectory
 	fileStoreConf.Directory = filepath.Join(fileStoreConf.Directory, FileVaultDir)
 
@@ -21,7 +22,7 @@ func newFileVault(fileStoreConf shared.FileConfig, key string) Vault {
 	})
 
 	return &vault{
-		store: store,
-		key:   key,
+		store:   store,
+		keyring: keyring,
 	}
 }
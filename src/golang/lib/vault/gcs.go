@@ -3,6 +3,7 @@ package vault
 import (
 	"path"
 
+	"github.com/aqueducthq/aqueduct/config"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/storage"
 )
@@ -11,7 +12,7 @@ const (
 	gcsVaultDir = "vault"
 )
 
-func newGCSVault(gcsStoreConf shared.GCSConfig, key string) Vault {
+func newGCSVault(gcsStoreConf shared.GCSConfig, keyring config.Keyring) Vault {
 	// The GCS vault stores secrets under the ../vault path
 	gcsStoreConf.Bucket = path.Join(gcsStoreConf.Bucket, gcsVaultDir)
 
@@ -21,7 +22,7 @@ func newGCSVault(gcsStoreConf shared.GCSConfig, key string) Vault {
 	})
 
 	return &vault{
-		store: store,
-		key:   key,
+		store:   store,
+		keyring: keyring,
 	}
 }
@@ -0,0 +1,201 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/dropbox/godropbox/errors"
+)
+
+const (
+	defaultHashiCorpMountPath  = "secret"
+	defaultHashiCorpPathPrefix = "aqueduct"
+
+	hashiCorpTokenHeader     = "X-Vault-Token"
+	hashiCorpNamespaceHeader = "X-Vault-Namespace"
+
+	hashiCorpRequestTimeout = 30 * time.Second
+)
+
+// hashiCorpVault keeps secrets in a HashiCorp Vault KV version 2 secrets engine,
+// through its HTTP API.
+type hashiCorpVault struct {
+	address    string
+	token      string
+	namespace  string
+	mountPath  string
+	pathPrefix string
+	client     *http.Client
+}
+
+// The payload of the KV version 2 read and write APIs.
+type hashiCorpSecret struct {
+	Data map[string]string `json:"data"`
+}
+
+type hashiCorpReadResponse struct {
+	Data hashiCorpSecret `json:"data"`
+}
+
+func newHashiCorpVault(conf shared.HashiCorpVaultConfig) (Vault, error) {
+	if conf.Address == "" {
+		return nil, errors.New("HashiCorp vault address is missing.")
+	}
+
+	token := conf.Token
+	if token == "" && conf.TokenPath != "" {
+		tokenBytes, err := os.ReadFile(conf.TokenPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Unable to read HashiCorp vault token from %s.", conf.TokenPath)
+		}
+		token = strings.TrimSpace(string(tokenBytes))
+	}
+
+	if token == "" {
+		return nil, errors.New("HashiCorp vault token is missing.")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if conf.CABundlePath != "" {
+		caBundle, err := os.ReadFile(conf.CABundlePath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Unable to read CA bundle %s.", conf.CABundlePath)
+		}
+
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(caBundle) {
+			return nil, errors.Newf("No certificates were found in CA bundle %s.", conf.CABundlePath)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: certPool, MinVersion: tls.VersionTLS12}
+	}
+
+	mountPath := strings.Trim(conf.MountPath, "/")
+	if mountPath == "" {
+		mountPath = defaultHashiCorpMountPath
+	}
+
+	pathPrefix := strings.Trim(conf.PathPrefix, "/")
+	if pathPrefix == "" {
+		pathPrefix = defaultHashiCorpPathPrefix
+	}
+
+	return &hashiCorpVault{
+		address:    strings.TrimRight(conf.Address, "/"),
+		token:      token,
+		namespace:  conf.Namespace,
+		mountPath:  mountPath,
+		pathPrefix: pathPrefix,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   hashiCorpRequestTimeout,
+		},
+	}, nil
+}
+
+// url returns the URL of the secret `name` under the `api` path of the secrets engine,
+// which is either `data` or `metadata`.
+func (v *hashiCorpVault) url(api string, name string) string {
+	return fmt.Sprintf("%s/v1/%s/%s/%s/%s", v.address, v.mountPath, api, v.pathPrefix, name)
+}
+
+func (v *hashiCorpVault) do(ctx context.Context, method string, url string, body []byte) (*http.Response, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set(hashiCorpTokenHeader, v.token)
+	if v.namespace != "" {
+		req.Header.Set(hashiCorpNamespaceHeader, v.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return v.client.Do(req)
+}
+
+func (v *hashiCorpVault) Put(ctx context.Context, name string, secrets map[string]string) error {
+	body, err := json.Marshal(hashiCorpSecret{Data: secrets})
+	if err != nil {
+		return err
+	}
+
+	resp, err := v.do(ctx, http.MethodPost, v.url("data", name), body)
+	if err != nil {
+		return errors.Wrap(err, "Unable to write secret to HashiCorp vault.")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return hashiCorpError(resp, "Unable to write secret to HashiCorp vault.")
+	}
+	return nil
+}
+
+func (v *hashiCorpVault) Get(ctx context.Context, name string) (map[string]string, error) {
+	resp, err := v.do(ctx, http.MethodGet, v.url("data", name), nil /* body */)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to read secret from HashiCorp vault.")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.Newf("Secret %s does not exist in HashiCorp vault.", name)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, hashiCorpError(resp, "Unable to read secret from HashiCorp vault.")
+	}
+
+	var readResp hashiCorpReadResponse
+	if err := json.NewDecoder(resp.Body).Decode(&readResp); err != nil {
+		return nil, errors.Wrap(err, "Unable to parse secret from HashiCorp vault.")
+	}
+	return readResp.Data.Data, nil
+}
+
+func (v *hashiCorpVault) Delete(ctx context.Context, name string) error {
+	// Deleting the metadata removes all versions of the secret.
+	resp, err := v.do(ctx, http.MethodDelete, v.url("metadata", name), nil /* body */)
+	if err != nil {
+		return errors.Wrap(err, "Unable to delete secret from HashiCorp vault.")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return hashiCorpError(resp, "Unable to delete secret from HashiCorp vault.")
+	}
+	return nil
+}
+
+func (*hashiCorpVault) Reencrypt(ctx context.Context, name string) (bool, error) {
+	// HashiCorp Vault manages the encryption of secrets itself.
+	return false, nil
+}
+
+// hashiCorpError returns an error that includes the errors reported by the Vault server.
+func hashiCorpError(resp *http.Response, msg string) error {
+	var errResp struct {
+		Errors []string `json:"errors"`
+	}
+	// The response body is best-effort, since not every error response has one.
+	_ = json.NewDecoder(resp.Body).Decode(&errResp)
+
+	return errors.Newf("%s Status %d: %s", msg, resp.StatusCode, strings.Join(errResp.Errors, "; "))
+}
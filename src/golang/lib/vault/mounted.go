@@ -0,0 +1,122 @@
+package vault
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/dropbox/godropbox/errors"
+)
+
+const mountedSecretPermissionCode = 0o600
+
+// mountedVault reads secrets that are mounted as files. Each entry is a directory with one file
+// per secret, which is how Kubernetes mounts secrets. If the directory is writable, new entries
+// are written to it as well. Otherwise, the entries must be provisioned before they are used.
+type mountedVault struct {
+	directory string
+}
+
+func newMountedVault(conf shared.MountedVaultConfig) Vault {
+	return &mountedVault{directory: conf.Directory}
+}
+
+// validateFileName returns an error if `name` cannot be used as the name of a file in the mount,
+// because it would escape its directory or be hidden.
+func validateFileName(name string) error {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) {
+		return errors.Newf("%q is not a valid secret name.", name)
+	}
+	return nil
+}
+
+func (v *mountedVault) Put(ctx context.Context, name string, secrets map[string]string) error {
+	if err := validateFileName(name); err != nil {
+		return err
+	}
+	for key := range secrets {
+		if err := validateFileName(key); err != nil {
+			return errors.Wrapf(err, "Unable to write secret %s to %s.", name, v.directory)
+		}
+	}
+
+	// Provisioned entries are left as is, so that read-only mounts can be used.
+	if existing, err := v.Get(ctx, name); err == nil && reflect.DeepEqual(existing, secrets) {
+		return nil
+	}
+
+	// The entry is written to a temporary directory first, and then swapped in,
+	// so that a failed write never leaves a partial entry behind.
+	tmpDir, err := os.MkdirTemp(v.directory, ".tmp-*")
+	if err != nil {
+		return errors.Wrapf(err, "Unable to write secret %s to %s.", name, v.directory)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for key, value := range secrets {
+		if err := os.WriteFile(filepath.Join(tmpDir, key), []byte(value), mountedSecretPermissionCode); err != nil {
+			return errors.Wrapf(err, "Unable to write secret %s to %s.", name, v.directory)
+		}
+	}
+
+	entryDir := filepath.Join(v.directory, name)
+	if err := os.RemoveAll(entryDir); err != nil {
+		return err
+	}
+	return os.Rename(tmpDir, entryDir)
+}
+
+func (v *mountedVault) Get(ctx context.Context, name string) (map[string]string, error) {
+	if err := validateFileName(name); err != nil {
+		return nil, err
+	}
+
+	entryDir := filepath.Join(v.directory, name)
+	files, err := os.ReadDir(entryDir)
+	if os.IsNotExist(err) {
+		return nil, errors.Newf("Secret %s does not exist in %s.", name, v.directory)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make(map[string]string, len(files))
+	for _, file := range files {
+		// Kubernetes keeps the actual files in hidden directories, and links to them.
+		if strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(entryDir, file.Name())
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		value, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		secrets[file.Name()] = string(value)
+	}
+
+	return secrets, nil
+}
+
+func (v *mountedVault) Delete(ctx context.Context, name string) error {
+	if err := validateFileName(name); err != nil {
+		return err
+	}
+	return os.RemoveAll(filepath.Join(v.directory, name))
+}
+
+func (*mountedVault) Reencrypt(ctx context.Context, name string) (bool, error) {
+	// Mounted secrets are protected by the system that mounts them.
+	return false, nil
+}
@@ -1,6 +1,7 @@
 package vault
 
 import (
+	"github.com/aqueducthq/aqueduct/config"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/storage"
 )
@@ -9,7 +10,7 @@ const (
 	s3VaultDir = "vault"
 )
 
-func newS3Vault(s3StoreConf shared.S3Config, key string) Vault {
+func newS3Vault(s3StoreConf shared.S3Config, keyring config.Keyring) Vault {
 	// The S3 vault stores secrets under the [root_dir]/vault path
 	// NOTE: The existing root directory is expected to always end with a slash.
 	s3StoreConf.RootDir += s3VaultDir + "/"
@@ -20,7 +21,7 @@ func newS3Vault(s3StoreConf shared.S3Config, key string) Vault {
 	})
 
 	return &vault{
-		store: store,
-		key:   key,
+		store:   store,
+		keyring: keyring,
 	}
 }
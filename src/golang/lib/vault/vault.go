@@ -3,6 +3,7 @@ package vault
 import (
 	"context"
 
+	"github.com/aqueducthq/aqueduct/config"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/storage"
 	"github.com/dropbox/godropbox/errors"
@@ -10,31 +11,79 @@ import (
 
 type Vault interface {
 	Put(ctx context.Context, name string, secrets map[string]string) error
+	// Get decrypts the entry with any key in the keyring.
 	Get(ctx context.Context, name string) (map[string]string, error)
 	Delete(ctx context.Context, name string) error
+	// Reencrypt encrypts the entry with the current key of the keyring, unless it already is.
+	// It returns whether the entry was re-encrypted.
+	Reencrypt(ctx context.Context, name string) (bool, error)
 }
 
-// NewVault constructs a Vault from the storage config and encryption key provided.
-func NewVault(storageConf *shared.StorageConfig, key string) (Vault, error) {
+// NewVault constructs a Vault from the vault config provided. If `vaultConf` is nil,
+// secrets are stored in the storage layer of `storageConf`, encrypted with `keyring`.
+func NewVault(
+	vaultConf *shared.VaultConfig,
+	storageConf *shared.StorageConfig,
+	keyring config.Keyring,
+) (Vault, error) {
+	if vaultConf.IsExternal() {
+		return newExternalVault(vaultConf)
+	}
+
+	if _, ok := keyring.Keys[keyring.CurrentKeyID]; !ok {
+		return nil, errors.Newf("The current encryption key %s is not in the keyring.", keyring.CurrentKeyID)
+	}
+
 	switch storageConf.Type {
 	case shared.FileStorageType:
-		return newFileVault(*storageConf.FileConfig, key), nil
+		return newFileVault(*storageConf.FileConfig, keyring), nil
 	case shared.S3StorageType:
-		return newS3Vault(*storageConf.S3Config, key), nil
+		return newS3Vault(*storageConf.S3Config, keyring), nil
 	case shared.GCSStorageType:
-		return newGCSVault(*storageConf.GCSConfig, key), nil
+		return newGCSVault(*storageConf.GCSConfig, keyring), nil
 	default:
 		return nil, errors.Newf("Unsupported vault type: %v", storageConf.Type)
 	}
 }
 
+// StorageKey returns the key that the entry `name` is stored at in the storage layer,
+// if secrets are kept in it.
+func StorageKey(name string) string {
+	return FileVaultDir + name
+}
+
 type vault struct {
-	store storage.Storage
-	key   string
+	store   storage.Storage
+	keyring config.Keyring
+}
+
+// newExternalVault constructs a Vault that keeps secrets outside of the storage layer.
+// The external system is responsible for protecting them, so they are not encrypted by Aqueduct.
+func newExternalVault(vaultConf *shared.VaultConfig) (Vault, error) {
+	switch vaultConf.Type {
+	case shared.HashiCorpVaultType:
+		if vaultConf.HashiCorpConfig == nil {
+			return nil, errors.New("HashiCorp vault config is missing.")
+		}
+		return newHashiCorpVault(*vaultConf.HashiCorpConfig)
+	case shared.MountedVaultType:
+		if vaultConf.MountedConfig == nil || vaultConf.MountedConfig.Directory == "" {
+			return nil, errors.New("Mounted vault directory is missing.")
+		}
+		return newMountedVault(*vaultConf.MountedConfig), nil
+	case shared.EnvVaultType:
+		envConf := shared.EnvVaultConfig{}
+		if vaultConf.EnvConfig != nil {
+			envConf = *vaultConf.EnvConfig
+		}
+		return newEnvVault(envConf), nil
+	default:
+		return nil, errors.Newf("Unsupported vault type: %v", vaultConf.Type)
+	}
 }
 
 func (v *vault) Put(ctx context.Context, name string, secrets map[string]string) error {
-	encrypted, err := encrypt(secrets, v.key)
+	encrypted, err := encrypt(secrets, v.keyring.CurrentKeyID, v.keyring.CurrentKey())
 	if err != nil {
 		return err
 	}
@@ -48,7 +97,34 @@ func (v *vault) Get(ctx context.Context, name string) (map[string]string, error)
 		return nil, err
 	}
 
-	return decrypt(ciphertext, v.key)
+	return decrypt(ciphertext, v.keyring)
+}
+
+func (v *vault) Reencrypt(ctx context.Context, name string) (bool, error) {
+	ciphertext, err := v.store.Get(ctx, name)
+	if err != nil {
+		return false, err
+	}
+
+	keyID, _, ok, err := parseEnvelope(ciphertext)
+	if err != nil {
+		return false, err
+	}
+
+	if ok && keyID == v.keyring.CurrentKeyID {
+		return false, nil
+	}
+
+	secrets, err := decrypt(ciphertext, v.keyring)
+	if err != nil {
+		return false, err
+	}
+
+	// The entry is replaced atomically, so it is never lost if this is interrupted.
+	if err := v.Put(ctx, name, secrets); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 func (v *vault) Delete(ctx context.Context, name string) error {
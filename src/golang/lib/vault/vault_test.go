@@ -0,0 +1,107 @@
+package vault
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	originalKey = "0123456789abcdef0123456789abcdef"
+	rotatedKey  = "fedcba9876543210fedcba9876543210"
+)
+
+var testSecrets = map[string]string{"username": "aqueduct", "password": "secret"}
+
+func newTestVault(t *testing.T, dir string, keyring config.Keyring) Vault {
+	v, err := NewVault(nil /* vaultConf */, &shared.StorageConfig{
+		Type:       shared.FileStorageType,
+		FileConfig: &shared.FileConfig{Directory: dir},
+	}, keyring)
+	require.Nil(t, err)
+	return v
+}
+
+// encryptWithoutEnvelope encrypts `secrets` the way entries were encrypted before keys could be rotated.
+func encryptWithoutEnvelope(t *testing.T, secrets map[string]string, key string) []byte {
+	c, err := aes.NewCipher([]byte(key))
+	require.Nil(t, err)
+	gcm, err := cipher.NewGCM(c)
+	require.Nil(t, err)
+
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	require.Nil(t, err)
+
+	serialized, err := json.Marshal(secrets)
+	require.Nil(t, err)
+	return gcm.Seal(nonce, nonce, serialized, nil)
+}
+
+func TestNewVaultRequiresCurrentKey(t *testing.T) {
+	_, err := NewVault(nil /* vaultConf */, &shared.StorageConfig{
+		Type:       shared.FileStorageType,
+		FileConfig: &shared.FileConfig{Directory: t.TempDir()},
+	}, config.Keyring{CurrentKeyID: "missing", Keys: map[string]string{"": originalKey}})
+	require.NotNil(t, err)
+}
+
+func TestVaultKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	oldKeyring := config.Keyring{Keys: map[string]string{"": originalKey}}
+	newKeyring := config.Keyring{
+		CurrentKeyID: "rotated",
+		Keys:         map[string]string{"": originalKey, "rotated": rotatedKey},
+	}
+
+	oldVault := newTestVault(t, dir, oldKeyring)
+	require.Nil(t, oldVault.Put(ctx, "current", testSecrets))
+
+	// Entries written before keys could be rotated have no envelope.
+	legacyPath := filepath.Join(dir, FileVaultDir, "legacy")
+	require.Nil(t, os.WriteFile(legacyPath, encryptWithoutEnvelope(t, testSecrets, originalKey), 0o664))
+
+	newVault := newTestVault(t, dir, newKeyring)
+	for _, name := range []string{"current", "legacy"} {
+		secrets, err := newVault.Get(ctx, name)
+		require.Nil(t, err)
+		require.Equal(t, testSecrets, secrets)
+
+		reencrypted, err := newVault.Reencrypt(ctx, name)
+		require.Nil(t, err)
+		require.True(t, reencrypted)
+
+		// Re-encrypting again is a no-op, so an interrupted rotation can be resumed.
+		reencrypted, err = newVault.Reencrypt(ctx, name)
+		require.Nil(t, err)
+		require.False(t, reencrypted)
+
+		secrets, err = newVault.Get(ctx, name)
+		require.Nil(t, err)
+		require.Equal(t, testSecrets, secrets)
+
+		// The original key alone can no longer decrypt the entry.
+		_, err = oldVault.Get(ctx, name)
+		require.NotNil(t, err)
+	}
+
+	// Entries can still be read after the original key is removed from the keyring.
+	rotatedOnly := newTestVault(t, dir, config.Keyring{
+		CurrentKeyID: "rotated",
+		Keys:         map[string]string{"rotated": rotatedKey},
+	})
+	secrets, err := rotatedOnly.Get(ctx, "legacy")
+	require.Nil(t, err)
+	require.Equal(t, testSecrets, secrets)
+}
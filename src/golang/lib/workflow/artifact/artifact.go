@@ -3,6 +3,7 @@ package artifact
 import (
 	"context"
 	"encoding/json"
+	"io"
 
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/models"
@@ -48,7 +49,8 @@ type Artifact interface {
 	GetMetadata(ctx context.Context) (*shared.ArtifactResultMetadata, error)
 
 	// GetContent fetches the content of this artifact.
-	// Errors if the artifact has not yet been computed.
+	// Errors if the artifact has not yet been computed, or with `storage.ErrContentCorrupted`
+	// if the content does not match the checksum recorded in its metadata.
 	GetContent(ctx context.Context) ([]byte, error)
 
 	// SampleContent works similar to GetContent but takes only
@@ -80,6 +82,9 @@ type ArtifactImpl struct {
 	resultID       uuid.UUID
 	resultMetadata *shared.ArtifactResultMetadata
 
+	// Only used if the content is stored by its hash.
+	contentBlobRepo repos.ContentBlob
+
 	// If this is not nil, this artifact should be written to the cache.
 	// An artifact cannot be both cache-aware and persisted.
 	previewCacheManager preview_cache.CacheManager
@@ -95,6 +100,7 @@ func NewArtifact(
 	execPaths *utils.ExecPaths,
 	artifactRepo repos.Artifact,
 	artifactResultRepo repos.ArtifactResult,
+	contentBlobRepo repos.ContentBlob,
 	storageConfig *shared.StorageConfig,
 	previewCacheManager preview_cache.CacheManager,
 	db database.Database,
@@ -114,6 +120,7 @@ func NewArtifact(
 		resultRepo:          artifactResultRepo,
 		resultID:            uuid.Nil,
 		resultMetadata:      nil,
+		contentBlobRepo:     contentBlobRepo,
 		previewCacheManager: previewCacheManager,
 		resultsPersisted:    false,
 		storageConfig:       storageConfig,
@@ -210,6 +217,9 @@ func (a *ArtifactImpl) updateArtifactResultAfterComputation(
 		models.ArtifactResultStatus:    execState.Status,
 		models.ArtifactResultExecState: execState,
 		models.ArtifactResultMetadata:  nil,
+		// The content path can change after the result is initialized if the content
+		// is reused from a previous run.
+		models.ArtifactResultContentPath: a.execPaths.ArtifactContentPath,
 	}
 
 	if a.Computed(ctx) {
@@ -225,6 +235,15 @@ func (a *ArtifactImpl) updateArtifactResultAfterComputation(
 			return
 		}
 		changes[models.ArtifactResultMetadata] = &artifactResultMetadata
+
+		if a.storageConfig.ContentAddressed && a.contentBlobRepo != nil {
+			err = a.updateArtifactResultWithContentBlob(ctx, changes)
+			if err == nil {
+				return
+			}
+			// The content is still valid at the path it was written to.
+			log.Errorf("Unable to store the content of artifact %s by its hash: %v", a.ID(), err)
+		}
 	}
 
 	_, err := a.resultRepo.Update(
@@ -242,6 +261,72 @@ func (a *ArtifactImpl) updateArtifactResultAfterComputation(
 	}
 }
 
+// updateArtifactResultWithContentBlob moves the computed content to the path of its hash, unless
+// content with the same hash is already stored there, and applies changes to the artifact result
+// along with pointing it at that path.
+func (a *ArtifactImpl) updateArtifactResultWithContentBlob(
+	ctx context.Context,
+	changes map[string]interface{},
+) error {
+	store := storage.NewStorage(a.storageConfig)
+	contentPath := a.execPaths.ArtifactContentPath
+
+	// The content is already stored by its hash if it was reused from a previous run.
+	hash, ok := storage.ParseContentAddressedPath(contentPath)
+	if !ok {
+		var err error
+		hash, err = storage.HashContent(ctx, store, contentPath)
+		if err != nil {
+			return errors.Wrap(err, "Unable to hash artifact content.")
+		}
+	}
+
+	blobPath := storage.ContentAddressedPath(hash)
+	if blobPath != contentPath && !store.Exists(ctx, blobPath) {
+		if err := storage.CopyKey(ctx, store, contentPath, blobPath); err != nil {
+			return errors.Wrap(err, "Unable to write artifact content.")
+		}
+	}
+
+	txn, err := a.db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer database.TxnRollbackIgnoreErr(ctx, txn)
+
+	if _, err := a.contentBlobRepo.IncrementRefCount(ctx, hash, txn); err != nil {
+		return errors.Wrap(err, "Unable to reference artifact content.")
+	}
+
+	changes[models.ArtifactResultContentPath] = blobPath
+	if _, err := a.resultRepo.Update(ctx, a.resultID, changes, txn); err != nil {
+		return errors.Wrap(err, "Unable to update artifact result.")
+	}
+
+	if err := txn.Commit(ctx); err != nil {
+		return err
+	}
+
+	if blobPath == contentPath {
+		return nil
+	}
+
+	// The content may have been deleted in the meantime if its last reference was released.
+	if !store.Exists(ctx, blobPath) {
+		if err := storage.CopyKey(ctx, store, contentPath, blobPath); err != nil {
+			return errors.Wrap(err, "Unable to write artifact content.")
+		}
+	}
+
+	if err := store.Delete(ctx, contentPath); err != nil {
+		log.Errorf("Unable to clean up artifact content at %s: %v", contentPath, err)
+	}
+
+	// Downstream operators read the content from its new path.
+	a.execPaths.ArtifactContentPath = blobPath
+	return nil
+}
+
 // For lazily published workflows, we will need to update the artifact type in the database
 // to something more specific than UNTYPED, so that we can enforce types in the future.
 // Errors are ignored, since this update is meant to be best-effort.
@@ -341,6 +426,17 @@ func (a *ArtifactImpl) GetContent(ctx context.Context) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	metadata, err := a.GetMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata != nil {
+		if err := storage.VerifyChecksum(a.execPaths.ArtifactContentPath, content, metadata.Checksum); err != nil {
+			return nil, err
+		}
+	}
 	return content, nil
 }
 
@@ -362,62 +458,200 @@ func (a *ArtifactImpl) SampleContent(ctx context.Context) ([]byte, bool, error)
 		return nil, false, nil
 	}
 
+	// Tables can be arbitrarily large, so their content is streamed from storage
+	// and only the sampled rows are kept in memory.
+	if metadata.SerializationType == shared.TableSerialization ||
+		metadata.SerializationType == shared.BsonTableSerialization {
+		r, err := storage.NewStorage(a.storageConfig).GetReader(ctx, a.execPaths.ArtifactContentPath)
+		if err != nil {
+			return nil, false, err
+		}
+		r = storage.NewVerifyingReader(r, a.execPaths.ArtifactContentPath, metadata.Checksum)
+		defer r.Close()
+
+		sample := sampleTable
+		if metadata.SerializationType == shared.BsonTableSerialization {
+			sample = sampleRecords
+		}
+
+		content, isDownsampled, err := sample(r)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if metadata.Checksum != "" {
+			// The rest of the table is read so that the whole content is verified.
+			if _, err := io.Copy(io.Discard, r); err != nil {
+				return nil, false, err
+			}
+		}
+		return content, isDownsampled, nil
+	}
+
 	content, err := a.GetContent(ctx)
 	if err != nil {
 		return nil, false, err
 	}
 
-	// For table types, we returns a down-sampled table when possible
-	if metadata.SerializationType == shared.TableSerialization {
-		// The over-simplified type for table orient.
-		type table struct {
-			Schema map[string]interface{} `json:"schema"`
-			Data   []interface{}          `json:"data"`
-		}
+	return content, false, nil
+}
+
+// sampleTable returns the first `sampleTableRow` rows of a table serialized in the `table` orient,
+// which is an object of the form {"schema": ..., "data": [...]}.
+func sampleTable(r io.Reader) ([]byte, bool, error) {
+	// The over-simplified type for table orient.
+	type table struct {
+		Schema json.RawMessage   `json:"schema"`
+		Data   []json.RawMessage `json:"data"`
+	}
 
-		var t table
+	var t table
+	isDownsampled := false
+	hasSchema := false
 
-		err := json.Unmarshal(content, &t)
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, false, err
+	}
+
+	for dec.More() {
+		key, err := dec.Token()
 		if err != nil {
 			return nil, false, err
 		}
 
-		if len(t.Data) <= sampleTableRow {
-			// If the table is small, return the original content
-			return content, false, nil
+		switch key {
+		case "schema":
+			if err := dec.Decode(&t.Schema); err != nil {
+				return nil, false, err
+			}
+			hasSchema = true
+		case "data":
+			t.Data, isDownsampled, err = sampleArray(dec)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if isDownsampled && !hasSchema {
+				// The schema comes after the data, so the remaining rows need to be skipped.
+				if err := skipArray(dec); err != nil {
+					return nil, false, err
+				}
+			}
+		default:
+			if err := skipValue(dec); err != nil {
+				return nil, false, err
+			}
 		}
 
-		t.Data = t.Data[:sampleTableRow]
-		downsampledContent, err := json.Marshal(t)
-		if err != nil {
-			return nil, false, err
+		if isDownsampled && hasSchema {
+			// Stop reading, since the remaining content is not needed.
+			break
 		}
+	}
 
-		return downsampledContent, true, nil
+	content, err := json.Marshal(t)
+	if err != nil {
+		return nil, false, err
+	}
+	return content, isDownsampled, nil
+}
+
+// sampleRecords returns the first `sampleTableRow` records of a table serialized in the `records` orient,
+// which is an array of the rows.
+func sampleRecords(r io.Reader) ([]byte, bool, error) {
+	dec := json.NewDecoder(r)
+	records, isDownsampled, err := sampleArray(dec)
+	if err != nil {
+		return nil, false, err
 	}
 
-	if metadata.SerializationType == shared.BsonTableSerialization {
-		// The over-simplified type for record orient.
-		var t []interface{}
+	content, err := json.Marshal(records)
+	if err != nil {
+		return nil, false, err
+	}
+	return content, isDownsampled, nil
+}
 
-		err := json.Unmarshal(content, &t)
-		if err != nil {
+// sampleArray decodes at most `sampleTableRow` elements of the array at the current position of dec.
+// If the array has more elements, the decoder is left at the first element that was not decoded
+// and the returned bool is true. Otherwise, the whole array is consumed.
+func sampleArray(dec *json.Decoder) ([]json.RawMessage, bool, error) {
+	if err := expectDelim(dec, '['); err != nil {
+		return nil, false, err
+	}
+
+	elements := []json.RawMessage{}
+	for dec.More() {
+		if len(elements) == sampleTableRow {
+			return elements, true, nil
+		}
+
+		var element json.RawMessage
+		if err := dec.Decode(&element); err != nil {
 			return nil, false, err
 		}
+		elements = append(elements, element)
+	}
 
-		if len(t) <= sampleTableRow {
-			// If the table is small, return the original content
-			return content, false, nil
+	if err := expectDelim(dec, ']'); err != nil {
+		return nil, false, err
+	}
+	return elements, false, nil
+}
+
+// skipArray consumes the remaining elements of the array that dec is in, including the closing bracket.
+func skipArray(dec *json.Decoder) error {
+	for dec.More() {
+		if err := skipValue(dec); err != nil {
+			return err
 		}
+	}
+	return expectDelim(dec, ']')
+}
 
-		t = t[:sampleTableRow]
-		downsampledContent, err := json.Marshal(t)
-		if err != nil {
-			return nil, false, err
+// skipValue consumes the next value of dec without keeping it in memory.
+func skipValue(dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		// This is a scalar value.
+		return nil
+	}
+
+	switch delim {
+	case '[', '{':
+		for dec.More() {
+			if delim == '{' {
+				// Skip the key of the object member.
+				if _, err := dec.Token(); err != nil {
+					return err
+				}
+			}
+			if err := skipValue(dec); err != nil {
+				return err
+			}
 		}
+		// Consume the closing delimiter.
+		_, err = dec.Token()
+		return err
+	default:
+		return errors.Newf("Unexpected delimiter %v in JSON content.", delim)
+	}
+}
 
-		return downsampledContent, true, nil
+func expectDelim(dec *json.Decoder, expected json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
 	}
 
-	return content, false, nil
+	if delim, ok := tok.(json.Delim); !ok || delim != expected {
+		return errors.Newf("Unexpected JSON token %v, expected %v.", tok, expected)
+	}
+	return nil
 }
@@ -0,0 +1,84 @@
+package artifact
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func generateRows(n int) []string {
+	rows := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		rows = append(rows, fmt.Sprintf(`{"index":%d,"name":"row %d","nested":{"a":[1,2]}}`, i, i))
+	}
+	return rows
+}
+
+func TestSampleTable(t *testing.T) {
+	schema := `{"fields":[{"name":"index","type":"integer"}]}`
+
+	type test struct {
+		name                  string
+		content               string
+		expectedRows          int
+		expectedIsDownsampled bool
+	}
+
+	tests := []test{
+		{
+			name:         "small table",
+			content:      fmt.Sprintf(`{"schema":%s,"data":[%s]}`, schema, strings.Join(generateRows(10), ",")),
+			expectedRows: 10,
+		},
+		{
+			name:                  "large table",
+			content:               fmt.Sprintf(`{"schema":%s,"data":[%s]}`, schema, strings.Join(generateRows(1000), ",")),
+			expectedRows:          sampleTableRow,
+			expectedIsDownsampled: true,
+		},
+		{
+			name:                  "schema after data",
+			content:               fmt.Sprintf(`{"data":[%s],"extra":[{"a":1}],"schema":%s}`, strings.Join(generateRows(1000), ","), schema),
+			expectedRows:          sampleTableRow,
+			expectedIsDownsampled: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			content, isDownsampled, err := sampleTable(strings.NewReader(tc.content))
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedIsDownsampled, isDownsampled)
+
+			var table struct {
+				Schema map[string]interface{}   `json:"schema"`
+				Data   []map[string]interface{} `json:"data"`
+			}
+			require.Nil(t, json.Unmarshal(content, &table))
+			require.Len(t, table.Data, tc.expectedRows)
+			require.NotNil(t, table.Schema)
+			require.Equal(t, float64(0), table.Data[0]["index"])
+		})
+	}
+}
+
+func TestSampleRecords(t *testing.T) {
+	content, isDownsampled, err := sampleRecords(strings.NewReader("[" + strings.Join(generateRows(1000), ",") + "]"))
+	require.Nil(t, err)
+	require.True(t, isDownsampled)
+
+	var records []map[string]interface{}
+	require.Nil(t, json.Unmarshal(content, &records))
+	require.Len(t, records, sampleTableRow)
+
+	content, isDownsampled, err = sampleRecords(strings.NewReader("[]"))
+	require.Nil(t, err)
+	require.False(t, isDownsampled)
+	require.Equal(t, "[]", string(content))
+
+	_, _, err = sampleRecords(strings.NewReader(`{"data":[]}`))
+	require.NotNil(t, err)
+}
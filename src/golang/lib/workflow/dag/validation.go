@@ -4,8 +4,13 @@ import (
 	"context"
 
 	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/k8s"
+	"github.com/aqueducthq/aqueduct/lib/lib_utils"
 	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/auth"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/google/uuid"
 )
@@ -16,6 +21,13 @@ var (
 	ErrUnreachableArtifact     = errors.New("The DAG has an unreachable artifact")
 	ErrUnDefinedArtifact       = errors.New("The DAG's operator edge contains an undefined artifact.")
 	ErrUnexecutableOperator    = errors.New("The DAG contains an operator whose dependencies will never be met.")
+	ErrInvalidRetryPolicy      = errors.New("The DAG contains an operator with an invalid retry policy.")
+	ErrInvalidTimeout          = errors.New("The DAG contains an operator with an invalid timeout.")
+	ErrUnsupportedTimeout      = errors.New("Operator timeouts are not supported on Airflow, since it cannot stop a running operator.")
+	ErrInvalidConcurrencyLimit = errors.New("The maximum number of concurrent operators cannot be negative.")
+	ErrInvalidCondition        = errors.New("The DAG contains an operator whose condition is not on an upstream check or parameter.")
+	ErrUnsupportedCondition    = errors.New("Conditional operators are not supported on Airflow or Databricks.")
+	ErrDisallowedK8sScheduling = errors.New("The DAG contains an operator that runs in a Kubernetes namespace or as a service account that its Kubernetes integration does not allow.")
 
 	ValidationErrors = map[error]bool{
 		ErrNoOperator:              true,
@@ -23,6 +35,13 @@ var (
 		ErrUnreachableArtifact:     true,
 		ErrUnDefinedArtifact:       true,
 		ErrUnexecutableOperator:    true,
+		ErrInvalidRetryPolicy:      true,
+		ErrInvalidTimeout:          true,
+		ErrUnsupportedTimeout:      true,
+		ErrInvalidConcurrencyLimit: true,
+		ErrInvalidCondition:        true,
+		ErrUnsupportedCondition:    true,
+		ErrDisallowedK8sScheduling: true,
 	}
 )
 
@@ -33,6 +52,10 @@ func Validate(
 		return ErrNoOperator
 	}
 
+	if dag.Metadata != nil && dag.Metadata.MaxConcurrentOperators < 0 {
+		return ErrInvalidConcurrencyLimit
+	}
+
 	// In this map, the keys are all artifact IDs that appear in the
 	// dag's edge definition, and the value is a boolean indicating
 	// whether each artifact is defined in `dag.Artifacts`.
@@ -44,6 +67,20 @@ func Validate(
 	artifactParents := make(map[uuid.UUID]bool)
 
 	for _, op := range dag.Operators {
+		if retryPolicy := op.Spec.RetryPolicy(); retryPolicy != nil {
+			if err := retryPolicy.Validate(); err != nil {
+				return ErrInvalidRetryPolicy
+			}
+		}
+
+		if err := op.Spec.ValidateTimeout(); err != nil {
+			return ErrInvalidTimeout
+		}
+
+		if op.Spec.Timeout() > 0 && operatorEngineConfig(dag, op).Type == shared.AirflowEngineType {
+			return ErrUnsupportedTimeout
+		}
+
 		for _, inputArtifactId := range op.Inputs {
 			artifactIdsInEdges[inputArtifactId] = false
 		}
@@ -78,7 +115,71 @@ func Validate(
 		}
 	}
 
-	return checkUnexecutableOperator(dag)
+	if err := checkUnexecutableOperator(dag); err != nil {
+		return err
+	}
+
+	return checkConditions(dag)
+}
+
+// operatorEngineConfig returns the engine config that `op` runs with. The operator's engine takes
+// precedence over the DAG's engine.
+func operatorEngineConfig(dag *models.DAG, op models.Operator) shared.EngineConfig {
+	if op.Spec.EngineConfig() != nil {
+		return *op.Spec.EngineConfig()
+	}
+	return dag.EngineConfig
+}
+
+// ValidateK8sScheduling verifies that the operators that run on Kubernetes only override the namespace
+// that they run in and the service account that they run as with ones that their Kubernetes integration
+// allows, since the server sets up the storage credentials in that namespace for them.
+func ValidateK8sScheduling(
+	ctx context.Context,
+	dag *models.DAG,
+	vaultObject vault.Vault,
+) error {
+	k8sConfigs := map[uuid.UUID]*shared.K8sIntegrationConfig{}
+	for _, op := range dag.Operators {
+		resources := op.Spec.Resources()
+		if resources == nil || resources.K8s == nil {
+			continue
+		}
+
+		engineConfig := operatorEngineConfig(dag, op)
+		if engineConfig.Type != shared.K8sEngineType || engineConfig.K8sConfig == nil {
+			continue
+		}
+
+		integrationID := engineConfig.K8sConfig.IntegrationID
+		k8sConfig, ok := k8sConfigs[integrationID]
+		if !ok {
+			config, err := auth.ReadConfigFromSecret(ctx, integrationID, vaultObject)
+			if err != nil {
+				return errors.Wrap(err, "Unable to read k8s config from vault.")
+			}
+
+			k8sConfig, err = lib_utils.ParseK8sConfig(config)
+			if err != nil {
+				return errors.Wrap(err, "Unable to parse k8s config.")
+			}
+			k8sConfigs[integrationID] = k8sConfig
+		}
+
+		err := k8s.CheckSchedulingAllowed(
+			&k8s.SchedulingConfig{
+				Namespace:          resources.K8s.Namespace,
+				ServiceAccountName: resources.K8s.ServiceAccountName,
+			},
+			k8sConfig.AllowedNamespaces,
+			k8sConfig.AllowedServiceAccounts,
+		)
+		if err != nil {
+			return ErrDisallowedK8sScheduling
+		}
+	}
+
+	return nil
 }
 
 func ValidateDagOperatorIntegrationOwnership(
@@ -186,3 +287,81 @@ func checkUnexecutableOperator(dag *models.DAG) error {
 
 	return nil
 }
+
+// checkConditions verifies that every operator condition is on the output of a check or parameter
+// operator that is upstream of the conditional operator, so that the condition can be evaluated
+// by the time the operator is ready to run. It assumes that the DAG is acyclic.
+func checkConditions(dag *models.DAG) error {
+	artifactToParentOp := make(map[uuid.UUID]models.Operator, len(dag.Artifacts))
+	for _, op := range dag.Operators {
+		for _, artifactID := range op.Outputs {
+			artifactToParentOp[artifactID] = op
+		}
+	}
+
+	for _, op := range dag.Operators {
+		condition := op.Spec.Condition()
+		if condition == nil {
+			continue
+		}
+
+		// Airflow and Databricks run the whole DAG themselves, so they cannot skip an operator.
+		if dag.EngineConfig.Type == shared.AirflowEngineType || dag.EngineConfig.Type == shared.DatabricksEngineType {
+			return ErrUnsupportedCondition
+		}
+
+		if err := condition.Validate(); err != nil {
+			return ErrInvalidCondition
+		}
+
+		parentOp, ok := artifactToParentOp[condition.ArtifactID]
+		if !ok {
+			return ErrInvalidCondition
+		}
+
+		if parentOp.Spec.IsCheck() {
+			// Checks always output a bool.
+			if _, ok := condition.Value.(bool); !ok {
+				return ErrInvalidCondition
+			}
+		} else if !parentOp.Spec.IsParam() {
+			return ErrInvalidCondition
+		}
+
+		if !isUpstreamArtifact(dag, artifactToParentOp, condition.ArtifactID, op) {
+			return ErrInvalidCondition
+		}
+	}
+
+	return nil
+}
+
+// isUpstreamArtifact returns whether the artifact is an input of `op`, or of any operator that `op` depends on.
+func isUpstreamArtifact(
+	dag *models.DAG,
+	artifactToParentOp map[uuid.UUID]models.Operator,
+	artifactID uuid.UUID,
+	op models.Operator,
+) bool {
+	visited := map[uuid.UUID]bool{}
+	toVisit := append([]uuid.UUID{}, op.Inputs...)
+	for len(toVisit) > 0 {
+		inputID := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+
+		if inputID == artifactID {
+			return true
+		}
+
+		if visited[inputID] {
+			continue
+		}
+		visited[inputID] = true
+
+		if parentOp, ok := artifactToParentOp[inputID]; ok {
+			toVisit = append(toVisit, parentOp.Inputs...)
+		}
+	}
+
+	return false
+}
@@ -1,9 +1,15 @@
 package dag
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/dropbox/godropbox/errors"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
@@ -232,3 +238,171 @@ func TestValidate(t *testing.T) {
 	)
 	require.Equal(t, err, ErrUnDefinedArtifact)
 }
+
+// This manually creates a DAG as follows, where func_1 only runs if
+// artifact_<conditionArtifact> has the value `condition`:
+//
+//	param_0 ---> artifact_p --|
+//	                          v
+//	func_2 ----> artifact_0 -----> func_0 -> artifact_1 -> func_1
+//
+//	check_0 ---> artifact_c
+func generateConditionalDag(t *testing.T, conditionArtifact string, condition string) *models.DAG {
+	artifacts := map[string]models.Artifact{}
+	for _, name := range []string{"p", "c", "0", "1"} {
+		artifacts[name] = models.Artifact{ID: uuid.New()}
+	}
+
+	newOperator := func(spec string, inputs []string, outputs []string) models.Operator {
+		op := models.Operator{ID: uuid.New()}
+		require.Nil(t, json.Unmarshal([]byte(spec), &op.Spec))
+		for _, name := range inputs {
+			op.Inputs = append(op.Inputs, artifacts[name].ID)
+		}
+		for _, name := range outputs {
+			op.Outputs = append(op.Outputs, artifacts[name].ID)
+		}
+		return op
+	}
+
+	conditionSpec := fmt.Sprintf(
+		`{"function": {}, "condition": {"artifact_id": "%s", "value": %s}}`,
+		artifacts[conditionArtifact].ID,
+		condition,
+	)
+
+	operators := []models.Operator{
+		newOperator(`{"param": {}}`, nil, []string{"p"}),
+		newOperator(`{"check": {}}`, nil, []string{"c"}),
+		newOperator(`{"function": {}}`, nil, []string{"0"}),
+		newOperator(`{"function": {}}`, []string{"0", "p"}, []string{"1"}),
+		newOperator(conditionSpec, []string{"1"}, nil),
+	}
+
+	dag := &models.DAG{
+		Operators: map[uuid.UUID]models.Operator{},
+		Artifacts: map[uuid.UUID]models.Artifact{},
+	}
+	for _, op := range operators {
+		dag.Operators[op.ID] = op
+	}
+	for _, artifact := range artifacts {
+		dag.Artifacts[artifact.ID] = artifact
+	}
+	return dag
+}
+
+func TestValidateConditions(t *testing.T) {
+	// The condition is on an upstream parameter.
+	err := Validate(generateConditionalDag(t, "p", `"prod"`))
+	require.Nil(t, err)
+
+	// The condition is on the output of a function.
+	err = Validate(generateConditionalDag(t, "1", "true"))
+	require.Equal(t, ErrInvalidCondition, err)
+
+	// The condition is on a check that is not upstream of the operator.
+	err = Validate(generateConditionalDag(t, "c", "true"))
+	require.Equal(t, ErrInvalidCondition, err)
+
+	// The condition does not have a value.
+	err = Validate(generateConditionalDag(t, "p", "null"))
+	require.Equal(t, ErrInvalidCondition, err)
+
+	for _, engineType := range []shared.EngineType{shared.AirflowEngineType, shared.DatabricksEngineType} {
+		unsupportedDag := generateConditionalDag(t, "p", `"prod"`)
+		unsupportedDag.EngineConfig.Type = engineType
+		err = Validate(unsupportedDag)
+		require.Equal(t, ErrUnsupportedCondition, err)
+	}
+}
+
+type fakeVault struct {
+	vault.Vault
+	secrets map[string]map[string]string
+}
+
+func (v *fakeVault) Get(_ context.Context, name string) (map[string]string, error) {
+	secrets, ok := v.secrets[name]
+	if !ok {
+		return nil, errors.Newf("Secret %s does not exist.", name)
+	}
+	return secrets, nil
+}
+
+func TestValidateK8sScheduling(t *testing.T) {
+	ctx := context.Background()
+	integrationID := uuid.New()
+	vaultObject := &fakeVault{secrets: map[string]map[string]string{
+		integrationID.String(): {
+			"kubeconfig_path":          "/home/kubeconfig",
+			"cluster_name":             "cluster",
+			"allowed_namespaces":       "ml, ml-staging",
+			"allowed_service_accounts": "workload-identity",
+		},
+	}}
+
+	k8sDag := func(k8sSpec string) *models.DAG {
+		op := models.Operator{ID: uuid.New()}
+		require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"function": {}, "resources": {"k8s": %s}}`, k8sSpec)), &op.Spec))
+		return &models.DAG{
+			Operators: map[uuid.UUID]models.Operator{op.ID: op},
+			EngineConfig: shared.EngineConfig{
+				Type:      shared.K8sEngineType,
+				K8sConfig: &shared.K8sConfig{IntegrationID: integrationID},
+			},
+		}
+	}
+
+	for _, k8sSpec := range []string{
+		`{}`,
+		`{"namespace": "ml-staging"}`,
+		`{"namespace": "aqueduct", "service_account_name": "workload-identity"}`,
+	} {
+		require.Nil(t, ValidateK8sScheduling(ctx, k8sDag(k8sSpec), vaultObject))
+	}
+
+	for _, k8sSpec := range []string{
+		`{"namespace": "kube-system"}`,
+		`{"namespace": "ml", "service_account_name": "cluster-admin"}`,
+	} {
+		require.Equal(t, ErrDisallowedK8sScheduling, ValidateK8sScheduling(ctx, k8sDag(k8sSpec), vaultObject))
+	}
+
+	// The scheduling config only matters on Kubernetes.
+	aqueductDag := k8sDag(`{"namespace": "kube-system"}`)
+	aqueductDag.EngineConfig = shared.EngineConfig{Type: shared.AqueductEngineType}
+	require.Nil(t, ValidateK8sScheduling(ctx, aqueductDag, vaultObject))
+}
+
+func TestValidateTimeout(t *testing.T) {
+	newDag := func(spec string, engineType shared.EngineType) *models.DAG {
+		op := models.Operator{ID: uuid.New()}
+		require.Nil(t, json.Unmarshal([]byte(spec), &op.Spec))
+		return &models.DAG{
+			Operators:    map[uuid.UUID]models.Operator{op.ID: op},
+			Artifacts:    map[uuid.UUID]models.Artifact{},
+			EngineConfig: shared.EngineConfig{Type: engineType},
+		}
+	}
+
+	// Lambda caps the timeout of the function that runs the operator.
+	for _, engineType := range []shared.EngineType{
+		shared.AqueductEngineType,
+		shared.K8sEngineType,
+		shared.DatabricksEngineType,
+		shared.LambdaEngineType,
+	} {
+		require.Nil(t, Validate(newDag(`{"function": {}, "timeout_seconds": 60}`, engineType)))
+	}
+
+	require.Equal(t, ErrInvalidTimeout, Validate(newDag(`{"function": {}, "timeout_seconds": 0}`, shared.AqueductEngineType)))
+
+	// Airflow cannot stop a running operator, so it does not support timeouts.
+	require.Equal(t, ErrUnsupportedTimeout, Validate(newDag(`{"function": {}, "timeout_seconds": 60}`, shared.AirflowEngineType)))
+	require.Nil(t, Validate(newDag(`{"function": {}}`, shared.AirflowEngineType)))
+
+	// The operator's engine takes precedence over the DAG's.
+	airflowOpDag := newDag(`{"function": {}, "timeout_seconds": 60, "engine_config": {"type": "airflow"}}`, shared.AqueductEngineType)
+	require.Equal(t, ErrUnsupportedTimeout, Validate(airflowOpDag))
+}
@@ -26,6 +26,9 @@ type WorkflowDag interface {
 	ResultID() uuid.UUID
 	Name() string
 	NotificationSettings() shared.NotificationSettings
+	// MaxConcurrentOperators returns the workflow's limit on how many operators can execute at once.
+	// It is 0 if the workflow does not set one.
+	MaxConcurrentOperators() int
 
 	Link() string
 	ResultLink() string
@@ -120,6 +123,14 @@ func (dag *workflowDagImpl) NotificationSettings() shared.NotificationSettings {
 	return dag.dbDAG.Metadata.NotificationSettings
 }
 
+func (dag *workflowDagImpl) MaxConcurrentOperators() int {
+	if dag.dbDAG.Metadata == nil {
+		return 0
+	}
+
+	return dag.dbDAG.Metadata.MaxConcurrentOperators
+}
+
 func (dag *workflowDagImpl) Link() string {
 	return fmt.Sprintf("%s/workflow/%s", dag.displayIP, dag.ID())
 }
@@ -134,16 +145,24 @@ func (dag *workflowDagImpl) ResultLink() string {
 }
 
 // Assumption: all dag's start with operators.
-// computeArtifactSignatures traverses over the entire dag structure from beginning to end,
-// computing the signatures for each artifact. These signatures are returned in a map keyed
-// by the artifact's original ID.
+// computeSignatures traverses over the entire dag structure from beginning to end,
+// computing the signatures for each artifact and operator. These signatures are returned
+// in maps keyed by the artifact's and operator's original ID respectively.
 // `opIDsByInputArtifact` does not contain entries for terminal artifacts.
-func computeArtifactSignatures(
+func computeSignatures(
 	dbOperators map[uuid.UUID]models.Operator,
 	opIDsByInputArtifact map[uuid.UUID][]uuid.UUID,
 	numArtifacts int,
-) (map[uuid.UUID]uuid.UUID, error) {
+) (map[uuid.UUID]uuid.UUID, map[uuid.UUID]uuid.UUID, error) {
 	artifactIDToSignature := make(map[uuid.UUID]uuid.UUID, numArtifacts)
+	opIDToSignature := make(map[uuid.UUID]uuid.UUID, len(dbOperators))
+
+	artifactIDToOpID := make(map[uuid.UUID]uuid.UUID, numArtifacts)
+	for _, dbOperator := range dbOperators {
+		for _, outputArtifactID := range dbOperator.Outputs {
+			artifactIDToOpID[outputArtifactID] = dbOperator.ID
+		}
+	}
 
 	// Queue that stores the frontier of operators as we perform a BFS over the dag.
 	q := make([]uuid.UUID, 0, 1)
@@ -159,11 +178,6 @@ func computeArtifactSignatures(
 		currOp := dbOperators[q[0]]
 		q = q[1:]
 
-		// Skip operators with no output artifacts.
-		if len(currOp.Outputs) == 0 {
-			continue
-		}
-
 		// Represents the bytes prefix that we want to hash for each output artifact.
 		// Is computed to be the concatenation of the operator's input signatures, along with
 		// the parameter value of the operator (if the operator is a parameter).
@@ -173,7 +187,7 @@ func computeArtifactSignatures(
 		for _, inputArtifactID := range currOp.Inputs {
 			inputArtifactSignature, ok := artifactIDToSignature[inputArtifactID]
 			if !ok {
-				return nil, errors.Newf("Unable to find signature for input artifact %s", inputArtifactID)
+				return nil, nil, errors.Newf("Unable to find signature for input artifact %s", inputArtifactID)
 			}
 			inputBytesToHash = append(inputBytesToHash, []byte(inputArtifactSignature.String())...)
 		}
@@ -183,6 +197,21 @@ func computeArtifactSignatures(
 			inputBytesToHash = append(inputBytesToHash, []byte(currOp.Spec.Param().Val)...)
 		}
 
+		// The operator's signature also covers the signatures of the operators that produced its inputs,
+		// and is hashed against its own id instead. Operators are never modified once created, so the id
+		// also identifies the operator's spec.
+		opBytesToHash := append([]byte{}, inputBytesToHash...)
+		for _, inputArtifactID := range currOp.Inputs {
+			opBytesToHash = append(opBytesToHash, []byte(opIDToSignature[artifactIDToOpID[inputArtifactID]].String())...)
+		}
+		opBytesToHash = append(opBytesToHash, []byte(currOp.ID.String())...)
+		opIDToSignature[currOp.ID] = uuid.NewSHA1(uuid.NameSpaceOID, opBytesToHash)
+
+		// Skip operators with no output artifacts.
+		if len(currOp.Outputs) == 0 {
+			continue
+		}
+
 		// Compute that signature for each output artifact.
 		for _, outputArtifactID := range currOp.Outputs {
 			// NOTE: is it important for correctness that we do not allocate additional capacity for `inputBytesToHash`.
@@ -215,7 +244,29 @@ func computeArtifactSignatures(
 			}
 		}
 	}
-	return artifactIDToSignature, nil
+	return artifactIDToSignature, opIDToSignature, nil
+}
+
+// ComputeOperatorSignatures returns the signature of each operator of the DAG, keyed by the operator's ID.
+// Two operators with the same signature perform the same computation over the same inputs.
+func ComputeOperatorSignatures(dag *models.DAG) (map[uuid.UUID]uuid.UUID, error) {
+	_, opIDToSignature, err := computeSignatures(
+		dag.Operators,
+		opIDsByInputArtifact(dag.Operators),
+		len(dag.Artifacts),
+	)
+	return opIDToSignature, err
+}
+
+// opIDsByInputArtifact returns the IDs of the operators that consume each artifact.
+func opIDsByInputArtifact(dbOperators map[uuid.UUID]models.Operator) map[uuid.UUID][]uuid.UUID {
+	opIDs := make(map[uuid.UUID][]uuid.UUID, len(dbOperators))
+	for _, dbOperator := range dbOperators {
+		for _, inputArtifactID := range dbOperator.Inputs {
+			opIDs[inputArtifactID] = append(opIDs[inputArtifactID], dbOperator.ID)
+		}
+	}
+	return opIDs
 }
 
 func NewWorkflowDag(
@@ -225,6 +276,7 @@ func NewWorkflowDag(
 	opResultRepo repos.OperatorResult,
 	artifactRepo repos.Artifact,
 	artifactResultRepo repos.ArtifactResult,
+	contentBlobRepo repos.ContentBlob,
 	vaultObject vault.Vault,
 	artifactCacheManager preview_cache.CacheManager,
 	execEnvs map[uuid.UUID]exec_env.ExecutionEnvironment,
@@ -239,20 +291,11 @@ func NewWorkflowDag(
 
 	artifactIDToInputOpID := make(map[uuid.UUID]uuid.UUID, len(dbArtifacts))
 	opIDToMetadataPath := make(map[uuid.UUID]string, len(dbOperators))
-	opIDsByInputArtifact := make(map[uuid.UUID][]uuid.UUID, len(dbArtifacts))
 	for _, dbOperator := range dbOperators {
 		for _, outputArtifactID := range dbOperator.Outputs {
 			artifactIDToInputOpID[outputArtifactID] = dbOperator.ID
 		}
 		opIDToMetadataPath[dbOperator.ID] = utils.InitializePath(opExecMode == operator.Preview)
-
-		for _, inputArtifactID := range dbOperator.Inputs {
-			opIDs, ok := opIDsByInputArtifact[inputArtifactID]
-			if !ok {
-				opIDs = make([]uuid.UUID, 0, 1)
-			}
-			opIDsByInputArtifact[inputArtifactID] = append(opIDs, dbOperator.ID)
-		}
 	}
 
 	// Allocate all execution paths for the workflowlib/workflow/operator/base.go.
@@ -270,8 +313,12 @@ func NewWorkflowDag(
 		)
 	}
 
-	// Compute signatures for each artifact.
-	artifactIDToSignatures, err := computeArtifactSignatures(dbOperators, opIDsByInputArtifact, len(dbArtifacts))
+	// Compute signatures for each artifact and operator.
+	artifactIDToSignatures, opIDToSignatures, err := computeSignatures(
+		dbOperators,
+		opIDsByInputArtifact(dbOperators),
+		len(dbArtifacts),
+	)
 	if err != nil {
 		return nil, errors.Wrap(err, "Internal error: unable to set up workflow execution.")
 	}
@@ -287,6 +334,7 @@ func NewWorkflowDag(
 			artifactIDToExecPaths[artifactID],
 			artifactRepo,
 			artifactResultRepo,
+			contentBlobRepo,
 			&dag.StorageConfig,
 			artifactCacheManager,
 			DB,
@@ -341,6 +389,7 @@ func NewWorkflowDag(
 
 		newOp, err := operator.NewOperator(
 			ctx,
+			opIDToSignatures[opID],
 			dbOperator,
 			inputArtifacts,
 			outputArtifacts,
@@ -2,6 +2,7 @@ package operator
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/check"
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/function"
 	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage"
 	"github.com/aqueducthq/aqueduct/lib/vault"
 	"github.com/aqueducthq/aqueduct/lib/workflow/artifact"
 	"github.com/aqueducthq/aqueduct/lib/workflow/preview_cache"
@@ -25,6 +27,7 @@ import (
 
 type baseOperator struct {
 	dbOperator *models.Operator
+	signature  uuid.UUID
 
 	// These fields are set to nil in the preview case.
 	resultRepo repos.OperatorResult
@@ -32,6 +35,8 @@ type baseOperator struct {
 
 	metadataPath string
 	jobName      string
+	// The job name of the first attempt. Retries derive their job names from it.
+	initialJobName string
 
 	inputs          []artifact.Artifact
 	outputs         []artifact.Artifact
@@ -71,6 +76,10 @@ func (bo *baseOperator) ID() uuid.UUID {
 	return bo.dbOperator.ID
 }
 
+func (bo *baseOperator) Signature() uuid.UUID {
+	return bo.signature
+}
+
 func (bo *baseOperator) Dynamic() bool {
 	return bo.dynamicProperties != nil
 }
@@ -113,6 +122,19 @@ func unknownSystemFailureExecState(err error, logMsg string) *shared.ExecutionSt
 	}
 }
 
+func timeoutExecState(timeout time.Duration) *shared.ExecutionState {
+	failureType := shared.UserFatalFailure
+	return &shared.ExecutionState{
+		Status:      shared.FailedExecutionStatus,
+		FailureType: &failureType,
+		Error: &shared.Error{
+			Context: "",
+			Tip:     fmt.Sprintf("Operator exceeded its timeout of %s and was stopped.", timeout),
+			Code:    shared.TimeoutErrorCode,
+		},
+	}
+}
+
 func jobManagerUserFailureExecState(err job.JobError, logMsg string) *shared.ExecutionState {
 	log.Errorf("Job execution had a user-facing issue: %s %v", logMsg, err)
 
@@ -135,7 +157,12 @@ func (bo *baseOperator) launch(ctx context.Context, spec job.Spec) error {
 		return errors.Newf("Cannot launch operator with state %s", bo.execState.Status)
 	}
 
+	wasQueued := bo.execState.Reason == shared.QueuedExecutionReason
 	bo.UpdateExecState(&shared.ExecutionState{Status: shared.RunningExecutionStatus})
+	if wasQueued {
+		// Otherwise, the operator would show as queued until it terminates.
+		bo.persistInProgressExecState(ctx)
+	}
 
 	// Check if this operator can use previously cached results instead of computing for scratch.
 	if bo.previewCacheManager != nil {
@@ -165,8 +192,22 @@ func (bo *baseOperator) launch(ctx context.Context, spec job.Spec) error {
 		}
 	}
 
-	err := bo.jobManager.Launch(ctx, spec.JobName(), spec)
+	// Some job managers run the job as part of launching it, so the launch is bounded by the timeout too.
+	launchCtx := ctx
+	if timeout := bo.Timeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		launchCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err := bo.jobManager.Launch(launchCtx, spec.JobName(), spec)
 	if err != nil {
+		if launchCtx.Err() == context.DeadlineExceeded {
+			// This is not an error in launching the job, so the engine handles it like any other failed operator.
+			bo.UpdateExecState(timeoutExecState(bo.Timeout()))
+			return nil
+		}
+
 		if err.Code() == job.User {
 			bo.UpdateExecState(
 				jobManagerUserFailureExecState(err, "Job manager's Launch API failed due to user error"),
@@ -221,6 +262,9 @@ func (bo *baseOperator) UpdateExecState(execState *shared.ExecutionState) {
 	}
 
 	execState.Timestamps = execTimestamps
+	if execState.Attempts == nil {
+		execState.Attempts = bo.execState.Attempts
+	}
 	bo.execState = *execState
 }
 
@@ -282,7 +326,7 @@ func (bo *baseOperator) Poll(ctx context.Context) (*shared.ExecutionState, error
 		return bo.ExecState(), nil
 	}
 
-	status, err := bo.jobManager.Poll(ctx, bo.jobName)
+	status, err := bo.pollJob(ctx)
 	if err != nil {
 		// If the job does not exist, this could mean that
 		// 1) it is hasn't been run yet (pending),
@@ -328,6 +372,75 @@ func (bo *baseOperator) Poll(ctx context.Context) (*shared.ExecutionState, error
 	}
 }
 
+// jobResources returns the resource config that the operator's job is launched with.
+// Only function-based operators pass theirs to the job manager.
+func (bo *baseOperator) jobResources() *operator.ResourceConfig {
+	spec := bo.dbOperator.Spec
+	if spec.IsFunction() || spec.IsMetric() || spec.IsCheck() {
+		return spec.Resources()
+	}
+	return nil
+}
+
+// pollJob polls the operator's job. If the job manager runs the job in a place that depends on the
+// operator's resources, the job is looked up there, since the job manager may have been restarted
+// since it launched the job.
+func (bo *baseOperator) pollJob(ctx context.Context) (shared.ExecutionStatus, job.JobError) {
+	if jobManager, ok := bo.jobManager.(job.ResourceAwareJobManager); ok {
+		return jobManager.PollWithResources(ctx, bo.jobName, bo.jobResources())
+	}
+	return bo.jobManager.Poll(ctx, bo.jobName)
+}
+
+func (bo *baseOperator) Completion(ctx context.Context) <-chan struct{} {
+	watcher, ok := bo.jobManager.(job.CompletionWatcher)
+	if !ok || bo.jobName == "" {
+		return nil
+	}
+
+	var done <-chan struct{}
+	var err job.JobError
+	if jobManager, ok := bo.jobManager.(job.ResourceAwareJobManager); ok {
+		done, err = jobManager.WatchWithResources(ctx, bo.jobName, bo.jobResources())
+	} else {
+		done, err = watcher.Watch(ctx, bo.jobName)
+	}
+	if err != nil {
+		// The job may not have been launched, eg. if its results were found in the preview cache.
+		if err.Code() != job.JobMissing {
+			log.Errorf("Unable to watch job %s, falling back to polling: %v", bo.jobName, err)
+		}
+		return nil
+	}
+
+	return done
+}
+
+func (bo *baseOperator) Queue(ctx context.Context) {
+	if bo.execState.Status != shared.PendingExecutionStatus || bo.execState.Reason == shared.QueuedExecutionReason {
+		return
+	}
+
+	bo.execState.Reason = shared.QueuedExecutionReason
+	bo.persistInProgressExecState(ctx)
+}
+
+// persistInProgressExecState writes the exec state of an operator that has not terminated yet
+// to its operator result. This is a noop outside of publish mode.
+func (bo *baseOperator) persistInProgressExecState(ctx context.Context) {
+	if bo.execMode != Publish || bo.resultRepo == nil || bo.resultID == uuid.Nil {
+		return
+	}
+
+	updateOperatorResultAfterComputation(
+		ctx,
+		&bo.execState,
+		bo.resultRepo,
+		bo.resultID,
+		bo.db,
+	)
+}
+
 func (bo *baseOperator) ExecState() *shared.ExecutionState {
 	return &bo.execState
 }
@@ -367,8 +480,9 @@ func (bo *baseOperator) PersistResult(ctx context.Context) error {
 		// still generate downstream artifacts, so those will continue to be marked as "failed".
 		// Invariant: if an artifact is marked as failed, it's operator must also be marked failed,
 		// with the same error message and context.
+		// The artifacts of a skipped operator are marked as skipped as well.
 		artifactExecState := *execState
-		if !outputArtifact.Computed(ctx) {
+		if !outputArtifact.Computed(ctx) && execState.Status != shared.SkippedExecutionStatus {
 			artifactExecState.Status = shared.CanceledExecutionStatus
 		}
 
@@ -399,6 +513,155 @@ func (bo *baseOperator) Cancel() {
 	})
 }
 
+func (bo *baseOperator) Skip() {
+	bo.UpdateExecState(&shared.ExecutionState{
+		Status: shared.SkippedExecutionStatus,
+	})
+}
+
+func (bo *baseOperator) Kill(ctx context.Context) error {
+	err := bo.cancelJob(ctx)
+	if err != nil {
+		return err
+	}
+
+	bo.Cancel()
+	return nil
+}
+
+// cancelJob stops the operator's job if it is running.
+func (bo *baseOperator) cancelJob(ctx context.Context) error {
+	if bo.execState.Status == shared.RunningExecutionStatus && bo.jobName != "" {
+		var err job.JobError
+		if jobManager, ok := bo.jobManager.(job.ResourceAwareJobManager); ok {
+			err = jobManager.CancelWithResources(ctx, bo.jobName, bo.jobResources())
+		} else {
+			err = bo.jobManager.Cancel(ctx, bo.jobName)
+		}
+		// A missing job has already finished or was never launched, and a noop means the
+		// job manager is unable to stop it. Either way there is nothing left to do.
+		if err != nil && err.Code() != job.JobMissing && err.Code() != job.Noop {
+			return errors.Wrapf(err, "Unable to kill job for operator %s.", bo.Name())
+		}
+	}
+	return nil
+}
+
+func (bo *baseOperator) Timeout() time.Duration {
+	return bo.dbOperator.Spec.Timeout()
+}
+
+func (bo *baseOperator) TimeOut(ctx context.Context) error {
+	if bo.execState.Status != shared.RunningExecutionStatus {
+		return errors.Newf("Cannot time out operator %s with state %s", bo.Name(), bo.execState.Status)
+	}
+
+	err := bo.cancelJob(ctx)
+	if err != nil {
+		return err
+	}
+
+	bo.UpdateExecState(timeoutExecState(bo.Timeout()))
+	return nil
+}
+
+func (bo *baseOperator) Condition() *operator.Condition {
+	return bo.dbOperator.Spec.Condition()
+}
+
+func (bo *baseOperator) RetryPolicy() *operator.RetryPolicy {
+	return bo.dbOperator.Spec.RetryPolicy()
+}
+
+func (bo *baseOperator) Retry(ctx context.Context) error {
+	if !bo.execState.Terminated() {
+		return errors.Newf("Cannot retry operator %s with state %s", bo.Name(), bo.execState.Status)
+	}
+
+	if bo.resultsPersisted {
+		return errors.Newf("Operator %s cannot be retried, as its results were already persisted.", bo.Name())
+	}
+
+	// The timestamps are copied since `UpdateExecState()` mutates them in place.
+	var timestamps *shared.ExecutionTimestamps
+	if bo.execState.Timestamps != nil {
+		timestampsCopy := *bo.execState.Timestamps
+		timestamps = &timestampsCopy
+	}
+
+	attempts := append(bo.execState.Attempts, shared.ExecutionAttempt{
+		Attempt:     bo.execState.NumAttempts(),
+		Status:      bo.execState.Status,
+		FailureType: bo.execState.FailureType,
+		Error:       bo.execState.Error,
+		UserLogs:    bo.execState.UserLogs,
+		Timestamps:  timestamps,
+	})
+
+	// The previous attempt's metadata must be removed, otherwise polling the next attempt
+	// can pick it up as the result.
+	utils.CleanupStorageFile(ctx, bo.storageConfig, bo.metadataPath)
+
+	if bo.initialJobName == "" {
+		bo.initialJobName = bo.jobName
+	}
+	bo.jobName = fmt.Sprintf("%s-%d", bo.initialJobName, len(attempts)+1)
+
+	now := time.Now()
+	bo.execState = shared.ExecutionState{
+		Status: shared.PendingExecutionStatus,
+		Timestamps: &shared.ExecutionTimestamps{
+			PendingAt: &now,
+		},
+		Attempts: attempts,
+	}
+
+	bo.persistInProgressExecState(ctx)
+	return nil
+}
+
+func (bo *baseOperator) Reuse(
+	ctx context.Context,
+	execState *shared.ExecutionState,
+	outputResults map[uuid.UUID]*models.ArtifactResult,
+) error {
+	if bo.execState.Status != shared.PendingExecutionStatus {
+		return errors.Newf("Cannot reuse results for operator %s with state %s", bo.Name(), bo.execState.Status)
+	}
+
+	if !execState.Terminated() {
+		return errors.Newf("Cannot reuse non-terminated results for operator %s.", bo.Name())
+	}
+
+	for i, outputArtifact := range bo.outputs {
+		outputResult, ok := outputResults[outputArtifact.ID()]
+		if !ok || outputResult.Metadata.IsNull {
+			return errors.Newf("No reusable result was found for artifact %s.", outputArtifact.Name())
+		}
+
+		// The output artifact now points to the content written by the previous run. Its metadata
+		// is only stored in the database, so it is written back to storage for downstream operators.
+		serializedMetadata, err := json.Marshal(&outputResult.Metadata.ArtifactResultMetadata)
+		if err != nil {
+			return errors.Wrapf(err, "Unable to serialize metadata for artifact %s.", outputArtifact.Name())
+		}
+
+		err = storage.NewStorage(bo.storageConfig).Put(
+			ctx,
+			bo.outputExecPaths[i].ArtifactMetadataPath,
+			serializedMetadata,
+		)
+		if err != nil {
+			return errors.Wrapf(err, "Unable to write metadata for artifact %s.", outputArtifact.Name())
+		}
+
+		bo.outputExecPaths[i].ArtifactContentPath = outputResult.ContentPath
+	}
+
+	bo.UpdateExecState(execState)
+	return nil
+}
+
 func (bfo *baseOperator) FetchExecutionEnvironment(ctx context.Context) *exec_env.ExecutionEnvironment {
 	return bfo.execEnv
 }
@@ -28,6 +28,9 @@ type Operator interface {
 	Type() operator.Type
 	Name() string
 	ID() uuid.UUID
+	// Signature identifies the computation that the operator performs: its spec, along with
+	// the signatures of its inputs. Operators with the same signature produce the same results.
+	Signature() uuid.UUID
 	JobSpec() job.Spec
 	// ExecState returns the operators ExecState since the last `Poll()` or `Launch()`
 	ExecState() *shared.ExecutionState
@@ -45,10 +48,57 @@ type Operator interface {
 	// Returns the execState updated. This does not persist the exec state to DB.
 	Poll(ctx context.Context) (*shared.ExecutionState, error)
 
+	// Queue marks this pending operator as ready to run, but waiting for an execution slot.
+	// In publish mode, this is also written to the operator result, so that it is visible
+	// while the workflow run is in progress.
+	Queue(ctx context.Context)
+
+	// Completion returns a channel that is closed once the operator's launched job terminates.
+	// It returns nil if the operator's job manager cannot push completion events, or if no job
+	// is running, in which case `Poll()` must be used to determine when the operator has completed.
+	Completion(ctx context.Context) <-chan struct{}
+
 	// Cancel updates the status of this operator execution if the result of the
 	// execution will not be generated. This does not persist the exec state to DB.
 	Cancel()
 
+	// Skip marks this pending operator as skipped, since its condition is not satisfied
+	// or an upstream operator was skipped. This does not persist the exec state to DB.
+	Skip()
+
+	// Kill stops the operator's job if it has been launched and has not completed yet,
+	// and then marks the operator as canceled. This does not persist the exec state to DB.
+	Kill(ctx context.Context) error
+
+	// Retry records the current, terminated execution state as a previous attempt and
+	// resets the operator back to pending, so that it can be launched again.
+	// In publish mode, the attempt history is also written to the operator result.
+	Retry(ctx context.Context) error
+
+	// Condition returns the condition that must be satisfied for this operator to run,
+	// or nil if it always runs.
+	Condition() *operator.Condition
+
+	// RetryPolicy returns the retry policy of this operator, or nil if it has none.
+	RetryPolicy() *operator.RetryPolicy
+
+	// Timeout returns how long this operator is allowed to run for, or 0 if it has no timeout.
+	Timeout() time.Duration
+
+	// TimeOut stops the operator's running job, and marks the operator as failed with
+	// a timeout error. This does not persist the exec state to DB.
+	TimeOut(ctx context.Context) error
+
+	// Reuse marks this pending operator as completed with the given execution state, using
+	// the output artifact results of a previous run instead of launching a job.
+	// `outputResults` must contain a result for each of the operator's outputs, keyed by artifact ID.
+	// This does not persist the exec state to DB.
+	Reuse(
+		ctx context.Context,
+		execState *shared.ExecutionState,
+		outputResults map[uuid.UUID]*models.ArtifactResult,
+	) error
+
 	// Finish is an end-of-lifecycle hook meant to do any final cleanup work.
 	// Also calls Finish() on all the operator's output artifacts.
 	Finish(ctx context.Context)
@@ -95,6 +145,7 @@ const (
 
 func NewOperator(
 	ctx context.Context,
+	signature uuid.UUID,
 	dbOperator models.Operator,
 	inputs []artifact.Artifact,
 	outputs []artifact.Artifact,
@@ -168,6 +219,7 @@ func NewOperator(
 
 	baseOp := baseOperator{
 		dbOperator: &dbOperator,
+		signature:  signature,
 		resultRepo: opResultRepo,
 		resultID:   uuid.Nil,
 
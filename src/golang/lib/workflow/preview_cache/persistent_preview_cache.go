@@ -0,0 +1,255 @@
+package preview_cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// persistentPreviewCacheManagerImpl keeps its entries in the metadata database, so unlike the
+// in-memory cache manager, the cache survives server restarts. Once the cached data exceeds the byte budget, the least recently
+// used entries are evicted.
+type persistentPreviewCacheManagerImpl struct {
+	storageConfig *shared.StorageConfig
+	// If 0, the cache is not bounded by size.
+	maxBytes int64
+
+	previewCacheEntryRepo repos.PreviewCacheEntry
+	DB                    database.Database
+
+	// putMutex serializes writes, so that concurrent evictions do not delete the same entries twice.
+	putMutex sync.Mutex
+
+	// Updated atomically, since the cache can be used by multiple workflow runs at once.
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+func NewPersistentPreviewCacheManager(
+	storageConfig *shared.StorageConfig,
+	maxBytes int64,
+	previewCacheEntryRepo repos.PreviewCacheEntry,
+	DB database.Database,
+) CacheManager {
+	return &persistentPreviewCacheManagerImpl{
+		storageConfig:         storageConfig,
+		maxBytes:              maxBytes,
+		previewCacheEntryRepo: previewCacheEntryRepo,
+		DB:                    DB,
+	}
+}
+
+func (c *persistentPreviewCacheManagerImpl) Get(ctx context.Context, artifactSignature uuid.UUID) (bool, Entry, error) {
+	allFound, entryByID, err := c.GetMulti(ctx, []uuid.UUID{artifactSignature})
+	if err != nil {
+		return false, Entry{}, err
+	}
+
+	if !allFound {
+		return false, Entry{}, nil
+	}
+	return true, entryByID[artifactSignature], nil
+}
+
+func (c *persistentPreviewCacheManagerImpl) GetMulti(ctx context.Context, artifactSignatures []uuid.UUID) (bool, map[uuid.UUID]Entry, error) {
+	cachedEntries := make(map[uuid.UUID]Entry, len(artifactSignatures))
+	if len(artifactSignatures) == 0 {
+		return true, cachedEntries, nil
+	}
+
+	dbEntries, err := c.previewCacheEntryRepo.GetBatch(ctx, artifactSignatures, c.DB)
+	if err != nil {
+		return false, nil, errors.Wrap(err, "Unable to read preview cache entries.")
+	}
+
+	store := storage.NewStorage(c.storageConfig)
+	foundSignatures := make([]uuid.UUID, 0, len(dbEntries))
+	for _, dbEntry := range dbEntries {
+		// The cached data can be gone if the storage layer was migrated or cleaned up
+		// since the entry was written, in which case the entry is useless.
+		if !store.Exists(ctx, dbEntry.ArtifactContentPath) {
+			if err := c.previewCacheEntryRepo.Delete(ctx, dbEntry.Signature, c.DB); err != nil {
+				log.Errorf("Unable to delete preview cache entry %s with missing data: %v", dbEntry.Signature, err)
+			}
+			continue
+		}
+
+		cachedEntries[dbEntry.Signature] = entryFromModel(&dbEntry)
+		foundSignatures = append(foundSignatures, dbEntry.Signature)
+	}
+
+	atomic.AddInt64(&c.hits, int64(len(foundSignatures)))
+	atomic.AddInt64(&c.misses, int64(len(artifactSignatures)-len(foundSignatures)))
+
+	if err := c.previewCacheEntryRepo.UpdateLastAccessedAt(ctx, foundSignatures, time.Now(), c.DB); err != nil {
+		return false, nil, errors.Wrap(err, "Unable to update preview cache entries.")
+	}
+
+	for _, signature := range artifactSignatures {
+		if _, ok := cachedEntries[signature]; !ok {
+			return false, cachedEntries, nil
+		}
+	}
+	return true, cachedEntries, nil
+}
+
+func (c *persistentPreviewCacheManagerImpl) Put(ctx context.Context, artifactSignature uuid.UUID, execPaths *utils.ExecPaths) error {
+	c.putMutex.Lock()
+	defer c.putMutex.Unlock()
+
+	existingEntries, err := c.previewCacheEntryRepo.GetBatch(ctx, []uuid.UUID{artifactSignature}, c.DB)
+	if err != nil {
+		return errors.Wrap(err, "Unable to read preview cache entries.")
+	}
+
+	if len(existingEntries) > 0 {
+		existingEntry := entryFromModel(&existingEntries[0])
+
+		// If the entry already exists, then it must have the same data.
+		if !isEqual(execPaths, &existingEntry) {
+			return errors.New("When updating an existing entry in the preview cache, we expect the entry to be the same.")
+		}
+		return c.previewCacheEntryRepo.UpdateLastAccessedAt(ctx, []uuid.UUID{artifactSignature}, time.Now(), c.DB)
+	}
+
+	_, err = c.previewCacheEntryRepo.Create(
+		ctx,
+		artifactSignature,
+		execPaths.ArtifactContentPath,
+		execPaths.ArtifactMetadataPath,
+		execPaths.OpMetadataPath,
+		c.sizeOf(ctx, execPaths),
+		c.DB,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Unable to create preview cache entry.")
+	}
+
+	return c.evict(ctx)
+}
+
+func (c *persistentPreviewCacheManagerImpl) List(ctx context.Context) ([]Entry, error) {
+	dbEntries, err := c.previewCacheEntryRepo.List(ctx, c.DB)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to list preview cache entries.")
+	}
+
+	entries := make([]Entry, 0, len(dbEntries))
+	for _, dbEntry := range dbEntries {
+		entries = append(entries, entryFromModel(&dbEntry))
+	}
+	return entries, nil
+}
+
+func (c *persistentPreviewCacheManagerImpl) Stats(ctx context.Context) (*Stats, error) {
+	dbEntries, err := c.previewCacheEntryRepo.List(ctx, c.DB)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to list preview cache entries.")
+	}
+
+	var sizeBytes int64
+	for _, dbEntry := range dbEntries {
+		sizeBytes += dbEntry.Size
+	}
+
+	return &Stats{
+		Hits:         atomic.LoadInt64(&c.hits),
+		Misses:       atomic.LoadInt64(&c.misses),
+		Evictions:    atomic.LoadInt64(&c.evictions),
+		NumEntries:   len(dbEntries),
+		SizeBytes:    sizeBytes,
+		MaxSizeBytes: c.maxBytes,
+	}, nil
+}
+
+// sizeOf returns the total size of the data an entry points to. Objects that cannot be
+// found are not counted.
+func (c *persistentPreviewCacheManagerImpl) sizeOf(ctx context.Context, execPaths *utils.ExecPaths) int64 {
+	store := storage.NewStorage(c.storageConfig)
+
+	var size int64
+	for _, path := range []string{execPaths.ArtifactContentPath, execPaths.ArtifactMetadataPath, execPaths.OpMetadataPath} {
+		info, err := store.Stat(ctx, path)
+		if err != nil {
+			continue
+		}
+		size += info.Size
+	}
+	return size
+}
+
+// evict deletes the least recently used entries, and the data they point to, until the cache
+// fits in its byte budget. The caller must hold putMutex.
+func (c *persistentPreviewCacheManagerImpl) evict(ctx context.Context) error {
+	if c.maxBytes <= 0 {
+		return nil
+	}
+
+	dbEntries, err := c.previewCacheEntryRepo.List(ctx, c.DB)
+	if err != nil {
+		return errors.Wrap(err, "Unable to list preview cache entries.")
+	}
+
+	var totalBytes int64
+	for _, dbEntry := range dbEntries {
+		totalBytes += dbEntry.Size
+	}
+
+	numEvicted := 0
+	for numEvicted < len(dbEntries) && totalBytes > c.maxBytes {
+		dbEntry := dbEntries[numEvicted]
+		if err := c.previewCacheEntryRepo.Delete(ctx, dbEntry.Signature, c.DB); err != nil {
+			return errors.Wrapf(err, "Unable to evict preview cache entry %s.", dbEntry.Signature)
+		}
+
+		totalBytes -= dbEntry.Size
+		numEvicted++
+	}
+
+	if numEvicted == 0 {
+		return nil
+	}
+	atomic.AddInt64(&c.evictions, int64(numEvicted))
+
+	// The outputs of the same operator share its metadata, so data that is still
+	// referenced by a remaining entry is kept.
+	remainingPaths := map[string]bool{}
+	for _, dbEntry := range dbEntries[numEvicted:] {
+		remainingPaths[dbEntry.ArtifactContentPath] = true
+		remainingPaths[dbEntry.ArtifactMetadataPath] = true
+		remainingPaths[dbEntry.OpMetadataPath] = true
+	}
+
+	pathsToDelete := []string{}
+	for _, dbEntry := range dbEntries[:numEvicted] {
+		for _, path := range []string{dbEntry.ArtifactContentPath, dbEntry.ArtifactMetadataPath, dbEntry.OpMetadataPath} {
+			if !remainingPaths[path] {
+				remainingPaths[path] = true // Avoids deleting the same path twice.
+				pathsToDelete = append(pathsToDelete, path)
+			}
+		}
+	}
+	utils.CleanupStorageFiles(ctx, c.storageConfig, pathsToDelete)
+
+	return nil
+}
+
+func entryFromModel(dbEntry *models.PreviewCacheEntry) Entry {
+	return Entry{
+		ArtifactContentPath:  dbEntry.ArtifactContentPath,
+		ArtifactMetadataPath: dbEntry.ArtifactMetadataPath,
+		OpMetadataPath:       dbEntry.OpMetadataPath,
+	}
+}
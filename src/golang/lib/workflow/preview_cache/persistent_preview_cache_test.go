@@ -0,0 +1,156 @@
+package preview_cache
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePreviewCacheEntryRepo keeps the entries in memory, in the order they were created.
+type fakePreviewCacheEntryRepo struct {
+	repos.PreviewCacheEntry
+	entries []models.PreviewCacheEntry
+}
+
+func (r *fakePreviewCacheEntryRepo) GetBatch(ctx context.Context, signatures []uuid.UUID, DB database.Database) ([]models.PreviewCacheEntry, error) {
+	entries := []models.PreviewCacheEntry{}
+	for _, entry := range r.entries {
+		for _, signature := range signatures {
+			if entry.Signature == signature {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries, nil
+}
+
+func (r *fakePreviewCacheEntryRepo) List(ctx context.Context, DB database.Database) ([]models.PreviewCacheEntry, error) {
+	entries := append([]models.PreviewCacheEntry{}, r.entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].LastAccessedAt.Before(entries[j].LastAccessedAt)
+	})
+	return entries, nil
+}
+
+func (r *fakePreviewCacheEntryRepo) Create(
+	ctx context.Context,
+	signature uuid.UUID,
+	artifactContentPath string,
+	artifactMetadataPath string,
+	opMetadataPath string,
+	size int64,
+	DB database.Database,
+) (*models.PreviewCacheEntry, error) {
+	now := time.Now()
+	r.entries = append(r.entries, models.PreviewCacheEntry{
+		Signature:            signature,
+		ArtifactContentPath:  artifactContentPath,
+		ArtifactMetadataPath: artifactMetadataPath,
+		OpMetadataPath:       opMetadataPath,
+		Size:                 size,
+		CreatedAt:            now,
+		LastAccessedAt:       now,
+	})
+	return &r.entries[len(r.entries)-1], nil
+}
+
+func (r *fakePreviewCacheEntryRepo) UpdateLastAccessedAt(ctx context.Context, signatures []uuid.UUID, lastAccessedAt time.Time, DB database.Database) error {
+	for i := range r.entries {
+		for _, signature := range signatures {
+			if r.entries[i].Signature == signature {
+				r.entries[i].LastAccessedAt = lastAccessedAt
+			}
+		}
+	}
+	return nil
+}
+
+func (r *fakePreviewCacheEntryRepo) Delete(ctx context.Context, signature uuid.UUID, DB database.Database) error {
+	for i, entry := range r.entries {
+		if entry.Signature == signature {
+			r.entries = append(r.entries[:i], r.entries[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func TestPersistentPreviewCache(t *testing.T) {
+	ctx := context.Background()
+
+	storageConfig := &shared.StorageConfig{
+		Type:       shared.FileStorageType,
+		FileConfig: &shared.FileConfig{Directory: t.TempDir()},
+	}
+	store := storage.NewStorage(storageConfig)
+	repo := &fakePreviewCacheEntryRepo{}
+
+	// Each entry takes 10 bytes, so the cache fits two of them.
+	cache := NewPersistentPreviewCacheManager(storageConfig, 25, repo, nil /* DB */)
+
+	putEntry := func(name string) (uuid.UUID, *utils.ExecPaths) {
+		execPaths := &utils.ExecPaths{
+			OpMetadataPath:       name + "_op_metadata",
+			ArtifactContentPath:  name + "_content",
+			ArtifactMetadataPath: name + "_metadata",
+		}
+		require.Nil(t, store.Put(ctx, execPaths.ArtifactContentPath, []byte("content")))
+		require.Nil(t, store.Put(ctx, execPaths.ArtifactMetadataPath, []byte("{}")))
+		require.Nil(t, store.Put(ctx, execPaths.OpMetadataPath, []byte("-")))
+
+		signature := uuid.New()
+		require.Nil(t, cache.Put(ctx, signature, execPaths))
+		return signature, execPaths
+	}
+
+	first, firstPaths := putEntry("first")
+	second, _ := putEntry("second")
+
+	found, entry, err := cache.Get(ctx, first)
+	require.Nil(t, err)
+	require.True(t, found)
+	require.True(t, isEqual(firstPaths, &entry))
+
+	// The second entry is now the least recently used one, so it is evicted.
+	third, _ := putEntry("third")
+
+	allFound, entries, err := cache.GetMulti(ctx, []uuid.UUID{first, second, third})
+	require.Nil(t, err)
+	require.False(t, allFound)
+	require.Len(t, entries, 2)
+	require.False(t, store.Exists(ctx, "second_content"))
+	require.True(t, store.Exists(ctx, "first_content"))
+
+	// A new cache manager sees the same entries, like after a server restart.
+	cache = NewPersistentPreviewCacheManager(storageConfig, 25, repo, nil /* DB */)
+	allFound, _, err = cache.GetMulti(ctx, []uuid.UUID{first, third})
+	require.Nil(t, err)
+	require.True(t, allFound)
+
+	// Entries whose data is gone are dropped.
+	require.Nil(t, store.Delete(ctx, "third_content"))
+	found, _, err = cache.Get(ctx, third)
+	require.Nil(t, err)
+	require.False(t, found)
+
+	stats, err := cache.Stats(ctx)
+	require.Nil(t, err)
+	require.Equal(t, Stats{
+		Hits:         2,
+		Misses:       1,
+		Evictions:    0,
+		NumEntries:   1,
+		SizeBytes:    10,
+		MaxSizeBytes: 25,
+	}, *stats)
+}
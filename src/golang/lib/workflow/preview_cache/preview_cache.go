@@ -2,6 +2,7 @@ package preview_cache
 
 import (
 	"context"
+	"sync/atomic"
 
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
@@ -30,12 +31,40 @@ type CacheManager interface {
 	// Writes the given entries into the cache. If entries already exist with the same artifact ID,
 	// they will be deleted before the write takes place.
 	Put(ctx context.Context, artifactSignature uuid.UUID, execPaths *utils.ExecPaths) error
+
+	// List returns all entries in the cache.
+	List(ctx context.Context) ([]Entry, error)
+
+	// Stats returns how effective the cache has been since it was created, along with its current size.
+	Stats(ctx context.Context) (*Stats, error)
+}
+
+// Stats describes the usage of a preview cache. Hits, misses and evictions are counted
+// since the cache manager was created, so they are reset when the server restarts.
+type Stats struct {
+	// The number of artifact signatures that were looked up and found.
+	Hits int64 `json:"hits"`
+	// The number of artifact signatures that were looked up and not found.
+	Misses int64 `json:"misses"`
+	// The number of entries that were removed to make room for new ones.
+	Evictions int64 `json:"evictions"`
+
+	NumEntries int `json:"num_entries"`
+	// The total size of the cached data in bytes. It is 0 if the cache does not track sizes.
+	SizeBytes int64 `json:"size_bytes"`
+	// The byte budget of the cache. It is 0 if the cache is not bounded by size.
+	MaxSizeBytes int64 `json:"max_size_bytes"`
 }
 
 type inMemoryPreviewCacheManagerImpl struct {
 	cache *lru.Cache
 
 	storageConfig *shared.StorageConfig
+
+	// Updated atomically, since the cache can be used by multiple workflow runs at once.
+	hits      int64
+	misses    int64
+	evictions int64
 }
 
 func deleteDataForEntry(ctx context.Context, storageConfig *shared.StorageConfig, entry Entry) {
@@ -62,6 +91,9 @@ func (c *inMemoryPreviewCacheManagerImpl) GetMulti(_ context.Context, artifactSi
 		entry, exists := c.cache.Get(signature)
 		if exists {
 			cachedEntries[signature] = entry.(Entry)
+			atomic.AddInt64(&c.hits, 1)
+		} else {
+			atomic.AddInt64(&c.misses, 1)
 		}
 	}
 
@@ -77,6 +109,35 @@ func (c *inMemoryPreviewCacheManagerImpl) Put(_ context.Context, artifactSignatu
 	return c.putMulti([]uuid.UUID{artifactSignature}, []*utils.ExecPaths{execPaths})
 }
 
+func (c *inMemoryPreviewCacheManagerImpl) List(_ context.Context) ([]Entry, error) {
+	keys := c.cache.Keys()
+	entries := make([]Entry, 0, len(keys))
+	for _, key := range keys {
+		// Peek does not update how recently the entry was used.
+		val, exists := c.cache.Peek(key)
+		if !exists {
+			// The entry was evicted since the keys were read.
+			continue
+		}
+
+		entry := castCachedValueToEntry(val)
+		if entry == nil {
+			return nil, errors.New("Preview Artifact Cache is storing an unexpected data structure.")
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, nil
+}
+
+func (c *inMemoryPreviewCacheManagerImpl) Stats(_ context.Context) (*Stats, error) {
+	return &Stats{
+		Hits:       atomic.LoadInt64(&c.hits),
+		Misses:     atomic.LoadInt64(&c.misses),
+		Evictions:  atomic.LoadInt64(&c.evictions),
+		NumEntries: c.cache.Len(),
+	}, nil
+}
+
 func castCachedValueToEntry(val interface{}) *Entry {
 	entry, ok := val.(Entry)
 	if !ok {
@@ -126,9 +187,14 @@ func NewInMemoryPreviewCacheManager(
 	storageConfig *shared.StorageConfig,
 	numEntries int,
 ) (CacheManager, error) {
+	c := &inMemoryPreviewCacheManagerImpl{
+		storageConfig: storageConfig,
+	}
+
 	// Cleanup storage paths on eviction.
 	cache, err := lru.NewWithEvict(numEntries, func(key interface{}, val interface{}) {
 		ctx := context.Background()
+		atomic.AddInt64(&c.evictions, 1)
 
 		entry := castCachedValueToEntry(val)
 		if entry != nil {
@@ -141,8 +207,6 @@ func NewInMemoryPreviewCacheManager(
 		return nil, err
 	}
 
-	return &inMemoryPreviewCacheManagerImpl{
-		cache:         cache,
-		storageConfig: storageConfig,
-	}, nil
+	c.cache = cache
+	return c, nil
 }
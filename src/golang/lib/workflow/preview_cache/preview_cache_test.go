@@ -36,17 +36,15 @@ func requirePathsDoExist(t *testing.T, execPaths *utils.ExecPaths, errMsgTemplat
 	}
 }
 
+// writePathsToFilesystem writes one byte to each path, so that each entry takes 3 bytes of the cache.
 func writePathsToFilesystem(t *testing.T, execPaths *utils.ExecPaths) {
-	f, err := os.Create(execPaths.OpMetadataPath)
-	_ = f.Close()
+	err := os.WriteFile(execPaths.OpMetadataPath, []byte("-"), 0o600)
 	require.Nil(t, err)
 
-	f, err = os.Create(execPaths.ArtifactContentPath)
-	_ = f.Close()
+	err = os.WriteFile(execPaths.ArtifactContentPath, []byte("-"), 0o600)
 	require.Nil(t, err)
 
-	f, err = os.Create(execPaths.ArtifactMetadataPath)
-	_ = f.Close()
+	err = os.WriteFile(execPaths.ArtifactMetadataPath, []byte("-"), 0o600)
 	require.Nil(t, err)
 }
 
@@ -73,7 +71,35 @@ func storageConfigForCurrentDirectory(t *testing.T) *shared.StorageConfig {
 	}
 }
 
+// newTestCacheManagers returns a constructor for each CacheManager implementation. Each cache only fits
+// one entry of the size written by writePathsToFilesystem.
+func newTestCacheManagers(t *testing.T) map[string]func() CacheManager {
+	return map[string]func() CacheManager{
+		"InMemory": func() CacheManager {
+			cache, err := NewInMemoryPreviewCacheManager(storageConfigForCurrentDirectory(t), 1 /* numEntries */)
+			require.Nil(t, err)
+			return cache
+		},
+		"Persistent": func() CacheManager {
+			return NewPersistentPreviewCacheManager(
+				storageConfigForCurrentDirectory(t),
+				3, /* maxBytes */
+				&fakePreviewCacheEntryRepo{},
+				nil, /* DB */
+			)
+		},
+	}
+}
+
 func TestPreviewCacheCollision(t *testing.T) {
+	for name, newCache := range newTestCacheManagers(t) {
+		t.Run(name, func(t *testing.T) {
+			testPreviewCacheCollision(t, newCache())
+		})
+	}
+}
+
+func testPreviewCacheCollision(t *testing.T, cache CacheManager) {
 	ctx := context.Background()
 
 	key := uuid.New()
@@ -87,13 +113,7 @@ func TestPreviewCacheCollision(t *testing.T) {
 	// Create the data in the same directory as this test, to be overwritten.
 	writePathsToFilesystem(t, execPaths)
 
-	cache, err := NewInMemoryPreviewCacheManager(
-		storageConfigForCurrentDirectory(t),
-		5,
-	)
-	require.Nil(t, err)
-
-	err = cache.Put(ctx, key, execPaths)
+	err := cache.Put(ctx, key, execPaths)
 	require.Nil(t, err)
 
 	found, entry, err := cache.Get(ctx, key)
@@ -121,6 +141,14 @@ func TestPreviewCacheCollision(t *testing.T) {
 }
 
 func TestPreviewCacheEviction(t *testing.T) {
+	for name, newCache := range newTestCacheManagers(t) {
+		t.Run(name, func(t *testing.T) {
+			testPreviewCacheEviction(t, newCache())
+		})
+	}
+}
+
+func testPreviewCacheEviction(t *testing.T, cache CacheManager) {
 	ctx := context.Background()
 
 	execPaths := &utils.ExecPaths{
@@ -133,24 +161,25 @@ func TestPreviewCacheEviction(t *testing.T) {
 	// Create the data to be deleted when the entry is evicted.
 	writePathsToFilesystem(t, execPaths)
 
-	cache, err := NewInMemoryPreviewCacheManager(
-		storageConfigForCurrentDirectory(t),
-		1,
-	)
+	err := cache.Put(ctx, uuid.New(), execPaths)
 	require.Nil(t, err)
 
-	err = cache.Put(ctx, uuid.New(), execPaths)
-	require.Nil(t, err)
-
-	// Add a new entry with a different id. Because the cache size is 1, the previous entry will be forcably
-	// evicted, and its filesystem data deleted.
+	// Add a new entry with a different id. Because the cache only fits one entry, the previous entry will be
+	// forcably evicted, and its filesystem data deleted.
 	newExecPaths := &utils.ExecPaths{
 		"op_metadata_path2",
 		"artifact_content_path2",
 		"artifact_metadata_path2",
 	}
+	requirePathsDoNotExist(t, newExecPaths, "%s already exists! You should remove this and retry.")
+	writePathsToFilesystem(t, newExecPaths)
+
 	err = cache.Put(ctx, uuid.New(), newExecPaths)
 	require.Nil(t, err)
 
 	requirePathsDoNotExist(t, execPaths, "%s should not exist after eviction.")
+
+	requirePathsDoExist(t, newExecPaths, "%s should continue to exist.")
+	removePathsToFilesystem(t, newExecPaths)
+	requirePathsDoNotExist(t, newExecPaths, "%s should have been removed.")
 }
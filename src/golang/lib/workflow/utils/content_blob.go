@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/dropbox/godropbox/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ReleaseContent removes a reference from the content stored at each content-addressed path in
+// contentPaths. It returns the paths that are not content-addressed, since they are owned by a
+// single artifact result and can be cleaned up directly by the caller, followed by the
+// content-addressed paths that are no longer referenced by any artifact result.
+//
+// Nothing is deleted from storage, so that the content is still there if DB is rolled back.
+// Callers delete the unreferenced content with DeleteReleasedContent once DB is committed.
+func ReleaseContent(
+	ctx context.Context,
+	contentPaths []string,
+	contentBlobRepo repos.ContentBlob,
+	DB database.Database,
+) ([]string, []string, error) {
+	otherPaths := make([]string, 0, len(contentPaths))
+	releasedPaths := []string{}
+	for _, contentPath := range contentPaths {
+		hash, ok := storage.ParseContentAddressedPath(contentPath)
+		if !ok {
+			otherPaths = append(otherPaths, contentPath)
+			continue
+		}
+
+		contentBlob, err := contentBlobRepo.DecrementRefCount(ctx, hash, DB)
+		if err != nil {
+			if aq_errors.Is(err, database.ErrNoRows()) {
+				// The content was already released.
+				continue
+			}
+			return nil, nil, errors.Wrapf(err, "Unable to release content %s.", hash)
+		}
+
+		if contentBlob.RefCount > 0 {
+			continue
+		}
+
+		if err := contentBlobRepo.Delete(ctx, hash, DB); err != nil {
+			return nil, nil, errors.Wrapf(err, "Unable to release content %s.", hash)
+		}
+		releasedPaths = append(releasedPaths, contentPath)
+	}
+	return otherPaths, releasedPaths, nil
+}
+
+// DeleteReleasedContent deletes the content at the paths returned by ReleaseContent, unless an
+// artifact result has referenced it again since. An artifact result that stores the same content
+// concurrently writes it again if it is deleted anyway, see `updateArtifactResultWithContentBlob`.
+// Deletion is best-effort, since unreferenced content is also removed by storage garbage collection.
+func DeleteReleasedContent(
+	ctx context.Context,
+	storageConfig *shared.StorageConfig,
+	releasedPaths []string,
+	contentBlobRepo repos.ContentBlob,
+	DB database.Database,
+) {
+	store := storage.NewStorage(storageConfig)
+	for _, contentPath := range releasedPaths {
+		hash, ok := storage.ParseContentAddressedPath(contentPath)
+		if !ok {
+			continue
+		}
+
+		_, err := contentBlobRepo.Get(ctx, hash, DB)
+		if err == nil {
+			continue
+		}
+		if !aq_errors.Is(err, database.ErrNoRows()) {
+			log.Errorf("Unable to check references to content %s: %v", hash, err)
+			continue
+		}
+
+		if !store.Exists(ctx, contentPath) {
+			continue
+		}
+
+		if err := store.Delete(ctx, contentPath); err != nil {
+			log.Errorf("Unable to delete content %s: %v", hash, err)
+		}
+	}
+}
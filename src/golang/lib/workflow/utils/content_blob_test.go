@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeContentBlobRepo struct {
+	repos.ContentBlob
+	refCounts map[string]int
+}
+
+func (r *fakeContentBlobRepo) Get(ctx context.Context, hash string, DB database.Database) (*models.ContentBlob, error) {
+	refCount, ok := r.refCounts[hash]
+	if !ok {
+		return nil, database.ErrNoRows()
+	}
+	return &models.ContentBlob{Hash: hash, RefCount: refCount}, nil
+}
+
+func (r *fakeContentBlobRepo) IncrementRefCount(ctx context.Context, hash string, DB database.Database) (*models.ContentBlob, error) {
+	r.refCounts[hash] += 1
+	return r.Get(ctx, hash, DB)
+}
+
+func (r *fakeContentBlobRepo) DecrementRefCount(ctx context.Context, hash string, DB database.Database) (*models.ContentBlob, error) {
+	if _, ok := r.refCounts[hash]; !ok {
+		return nil, database.ErrNoRows()
+	}
+	r.refCounts[hash] -= 1
+	return r.Get(ctx, hash, DB)
+}
+
+func (r *fakeContentBlobRepo) Delete(ctx context.Context, hash string, DB database.Database) error {
+	delete(r.refCounts, hash)
+	return nil
+}
+
+func TestReleaseContent(t *testing.T) {
+	ctx := context.Background()
+
+	storageConfig := &shared.StorageConfig{
+		Type:       shared.FileStorageType,
+		FileConfig: &shared.FileConfig{Directory: t.TempDir()},
+	}
+	store := storage.NewStorage(storageConfig)
+
+	sharedHash := strings.Repeat("a", 64)
+	releasedHash := strings.Repeat("b", 64)
+	rereferencedHash := strings.Repeat("c", 64)
+	paths := []string{
+		"artifact-content",
+		storage.ContentAddressedPath(sharedHash),
+		storage.ContentAddressedPath(releasedHash),
+		storage.ContentAddressedPath(rereferencedHash),
+	}
+	for _, path := range paths {
+		require.Nil(t, store.Put(ctx, path, []byte(path)))
+	}
+
+	contentBlobRepo := &fakeContentBlobRepo{refCounts: map[string]int{
+		sharedHash:       2,
+		releasedHash:     1,
+		rereferencedHash: 1,
+	}}
+
+	otherPaths, releasedPaths, err := ReleaseContent(ctx, paths, contentBlobRepo, nil /* DB */)
+	require.Nil(t, err)
+	require.Equal(t, []string{"artifact-content"}, otherPaths)
+	require.ElementsMatch(t, paths[2:], releasedPaths)
+	require.Equal(t, map[string]int{sharedHash: 1}, contentBlobRepo.refCounts)
+
+	// Nothing is deleted until the caller's transaction is committed.
+	for _, path := range paths {
+		require.True(t, store.Exists(ctx, path))
+	}
+
+	// Content that is referenced again in the meantime is kept.
+	_, err = contentBlobRepo.IncrementRefCount(ctx, rereferencedHash, nil /* DB */)
+	require.Nil(t, err)
+
+	DeleteReleasedContent(ctx, storageConfig, releasedPaths, contentBlobRepo, nil /* DB */)
+	require.True(t, store.Exists(ctx, paths[0]))
+	require.True(t, store.Exists(ctx, paths[1]))
+	require.False(t, store.Exists(ctx, paths[2]))
+	require.True(t, store.Exists(ctx, paths[3]))
+}
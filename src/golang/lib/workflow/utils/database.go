@@ -42,6 +42,7 @@ func WriteDAGToDatabase(
 			&dag.Metadata.Schedule,
 			&dag.Metadata.RetentionPolicy,
 			&dag.Metadata.NotificationSettings,
+			dag.Metadata.MaxConcurrentOperators,
 			DB,
 		)
 		if err != nil {
@@ -52,11 +53,17 @@ func WriteDAGToDatabase(
 		dag.WorkflowID = workflow.ID
 	}
 
+	var storageIntegrationID *uuid.UUID
+	if !dag.StorageIntegrationID.IsNull {
+		storageIntegrationID = &dag.StorageIntegrationID.UUID
+	}
+
 	newDAG, err := dagRepo.Create(
 		ctx,
 		dag.WorkflowID,
 		&dag.StorageConfig,
 		&dag.EngineConfig,
+		storageIntegrationID,
 		DB,
 	)
 	if err != nil {
@@ -451,7 +458,8 @@ func createDAGResultNotification(
 ) error {
 	status := dagResult.Status
 	if status != shared.SucceededExecutionStatus &&
-		status != shared.FailedExecutionStatus {
+		status != shared.FailedExecutionStatus &&
+		status != shared.CanceledExecutionStatus {
 		// Do not create notifications for DAGResults still in progress
 		return nil
 	}
@@ -476,6 +484,12 @@ func createDAGResultNotification(
 			"Workflow %s has failed.",
 			workflow.Name,
 		)
+	} else if status == shared.CanceledExecutionStatus {
+		notificationLevel = shared.WarningNotificationLevel
+		notificationContent = fmt.Sprintf(
+			"Workflow %s was canceled.",
+			workflow.Name,
+		)
 	}
 
 	notificationAssociation := &shared.NotificationAssociation{
@@ -6,9 +6,9 @@ import (
 	"github.com/google/uuid"
 )
 
-// The subdirectory within the storage directory containing all the outputs of previewed operators.
-// The contents of this directory will be dropped on every server start.
-const previewDir = "preview"
+// PreviewDir is the subdirectory within the storage directory containing all the outputs of previewed operators.
+// They are only referenced by the preview cache, which lives in the memory of the server.
+const PreviewDir = "preview"
 
 // ExecPaths packages together all the storage paths that are written to by a python operator.
 type ExecPaths struct {
@@ -28,7 +28,7 @@ func InitializeExecOutputPaths(isPreview bool, opMetadataPath string) *ExecPaths
 func InitializePath(isPreview bool) string {
 	var pathPrefix string
 	if isPreview {
-		pathPrefix = previewDir
+		pathPrefix = PreviewDir
 	}
 	return filepath.Join(pathPrefix, uuid.New().String())
 }
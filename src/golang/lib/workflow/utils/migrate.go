@@ -4,8 +4,11 @@ import (
 	"context"
 
 	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage"
 	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/dropbox/godropbox/errors"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -54,3 +57,94 @@ func MigrateVault(
 
 	return keys, nil
 }
+
+// ReencryptVault re-encrypts all vault content with the current key of the vault's keyring.
+// Entries that are already encrypted with the current key are skipped, so this can be
+// safely retried if it was interrupted. It returns the number of entries that were re-encrypted.
+func ReencryptVault(
+	ctx context.Context,
+	vaultObj vault.Vault,
+	orgID string,
+	integrationRepo repos.Integration,
+	DB database.Database,
+) (int, error) {
+	integrations, err := integrationRepo.GetByOrg(ctx, orgID, DB)
+	if err != nil {
+		return 0, err
+	}
+
+	numReencrypted := 0
+	for _, integrationDB := range integrations {
+		// The vault key for the credentials is the integration record's ID
+		key := integrationDB.ID.String()
+
+		reencrypted, err := vaultObj.Reencrypt(ctx, key)
+		if err != nil {
+			log.Errorf("Unable to re-encrypt integration credentials %v at path %s: %v", integrationDB.ID, key, err)
+			return numReencrypted, err
+		}
+
+		if reencrypted {
+			numReencrypted++
+		}
+	}
+
+	return numReencrypted, nil
+}
+
+// ReencryptStorage re-encrypts the objects that are encrypted with the key with keyID, both in the
+// server's storage layer `storageConfig` and in each distinct storage layer that workflows store their
+// content in. It stops at the first storage layer that cannot be re-encrypted, so that the key is never
+// retired while objects are still encrypted with it. Since objects that are not encrypted with the key
+// are skipped, this can be safely retried. It returns the number of objects that were re-encrypted.
+func ReencryptStorage(
+	ctx context.Context,
+	storageConfig *shared.StorageConfig,
+	keyID string,
+	dagRepo repos.DAG,
+	DB database.Database,
+) (int, error) {
+	numReencrypted, err := storage.Reencrypt(ctx, storageConfig, keyID)
+	if err != nil {
+		return numReencrypted, errors.Wrap(err, "Unable to re-encrypt the server's storage layer.")
+	}
+
+	dags, err := dagRepo.List(ctx, DB)
+	if err != nil {
+		return numReencrypted, errors.Wrap(err, "Unable to retrieve workflow dags.")
+	}
+
+	// Workflows that share a storage layer, including the server's, are only re-encrypted once.
+	reencrypted := []*shared.StorageConfig{storageConfig}
+	for i := range dags {
+		dag := &dags[i]
+		if dag.StorageIntegrationID.IsNull {
+			continue
+		}
+
+		done := false
+		for _, layerConfig := range reencrypted {
+			if storage.SameLocation(&dag.StorageConfig, layerConfig) {
+				done = true
+				break
+			}
+		}
+		if done {
+			continue
+		}
+
+		n, err := storage.Reencrypt(ctx, &dag.StorageConfig, keyID)
+		numReencrypted += n
+		if err != nil {
+			return numReencrypted, errors.Wrapf(
+				err,
+				"Unable to re-encrypt the storage layer of integration %s.",
+				dag.StorageIntegrationID.UUID,
+			)
+		}
+
+		reencrypted = append(reencrypted, &dag.StorageConfig)
+	}
+
+	return numReencrypted, nil
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/utils"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDAGRepo struct {
+	repos.DAG
+	dags []models.DAG
+}
+
+func (r *fakeDAGRepo) List(ctx context.Context, DB database.Database) ([]models.DAG, error) {
+	return r.dags, nil
+}
+
+func TestReencryptStorage(t *testing.T) {
+	ctx := context.Background()
+
+	keys, err := storage.DeriveKeys(config.Keyring{
+		CurrentKeyID: "",
+		Keys: map[string]string{
+			"":    "0123456789abcdef0123456789abcdef",
+			"new": "fedcba9876543210fedcba9876543210",
+		},
+	})
+	require.Nil(t, err)
+	rotated := &shared.StorageKeys{CurrentKeyID: "new", Keys: keys.Keys}
+
+	storageConfig := func(dir string, keys *shared.StorageKeys) shared.StorageConfig {
+		return shared.StorageConfig{
+			Type:           shared.FileStorageType,
+			FileConfig:     &shared.FileConfig{Directory: dir},
+			Encrypt:        true,
+			EncryptionKeys: keys,
+		}
+	}
+
+	serverDir, workflowDir := t.TempDir(), t.TempDir()
+	for _, dir := range []string{serverDir, workflowDir} {
+		config := storageConfig(dir, keys)
+		require.Nil(t, storage.NewStorage(&config).Put(ctx, "content", []byte("content")))
+	}
+
+	// Both workflows store their content in the same storage layer.
+	dag := func(dir string) models.DAG {
+		return models.DAG{
+			StorageConfig:        storageConfig(dir, rotated),
+			StorageIntegrationID: utils.NullUUID{UUID: uuid.New()},
+		}
+	}
+	dagRepo := &fakeDAGRepo{dags: []models.DAG{
+		dag(workflowDir),
+		dag(workflowDir),
+		{StorageIntegrationID: utils.NullUUID{IsNull: true}},
+	}}
+
+	serverConfig := storageConfig(serverDir, rotated)
+	numReencrypted, err := ReencryptStorage(ctx, &serverConfig, "" /* keyID */, dagRepo, nil /* DB */)
+	require.Nil(t, err)
+	require.Equal(t, 2, numReencrypted)
+
+	// A storage layer that cannot be re-encrypted fails the re-encryption.
+	brokenDir := t.TempDir()
+	brokenConfig := storageConfig(brokenDir, keys)
+	require.Nil(t, storage.NewStorage(&brokenConfig).Put(ctx, "content", []byte("content")))
+	stored, err := os.ReadFile(filepath.Join(brokenDir, "content"))
+	require.Nil(t, err)
+	require.Nil(t, os.WriteFile(filepath.Join(brokenDir, "content"), stored[:len(stored)-1], 0o644))
+
+	dagRepo.dags = append(dagRepo.dags, dag(brokenDir))
+	_, err = ReencryptStorage(ctx, &serverConfig, "" /* keyID */, dagRepo, nil /* DB */)
+	require.NotNil(t, err)
+}
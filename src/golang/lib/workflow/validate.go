@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/aqueducthq/aqueduct/config"
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/graph"
 	"github.com/aqueducthq/aqueduct/lib/models"
@@ -28,6 +29,11 @@ const (
 // since Aqueduct cannot trigger Workflow runs at the end of execution on an
 // engine that is not self-orchestrated.
 // 2. Having a CascadingUpdateTrigger that creates a cycle amongst the cascading workflows.
+// 3. Having an unknown ConcurrencyPolicy.
+// 4. Having a Timezone that is not a known IANA time zone name.
+// 5. Having a negative JitterSeconds.
+// 6. Having a JitterSeconds when workflow schedules are deployed as k8s cron jobs, which
+// the cluster triggers exactly on schedule.
 // It returns an HTTP status code and a client-friendly error, if any.
 func ValidateSchedule(
 	ctx context.Context,
@@ -42,6 +48,22 @@ func ValidateSchedule(
 	workflowRepo repos.Workflow,
 	DB database.Database,
 ) (int, error) {
+	if !schedule.ConcurrencyPolicy.Valid() {
+		return http.StatusBadRequest, errors.Newf("Unsupported concurrency policy %s.", schedule.ConcurrencyPolicy)
+	}
+
+	if _, err := schedule.Location(); err != nil {
+		return http.StatusBadRequest, errors.Newf("Unknown schedule timezone %s.", schedule.Timezone)
+	}
+
+	if schedule.JitterSeconds < 0 {
+		return http.StatusBadRequest, errors.New("Schedule jitter cannot be negative.")
+	}
+
+	if schedule.JitterSeconds > 0 && config.K8sScheduler() != nil {
+		return http.StatusBadRequest, errors.New("Schedule jitter is not supported when workflow schedules are deployed to k8s.")
+	}
+
 	if schedule.Trigger != shared.CascadingUpdateTrigger {
 		// Only CascadingUpdateTriggers require validation
 		return http.StatusOK, nil
@@ -58,6 +58,9 @@ def compile(spec: spec.CompileAirflowSpec) -> bytes:
     if spec.cron_schedule:
         schedule = spec.cron_schedule
 
+    # Airflow evaluates the cron schedule in the time zone of the DAG's start date.
+    timezone = spec.timezone or "UTC"
+
     # Init Airflow tasks
     tasks = []
     task_to_alias = {}
@@ -81,6 +84,8 @@ def compile(spec: spec.CompileAirflowSpec) -> bytes:
         workflow_dag_id=spec.workflow_dag_id,
         dag_id=spec.dag_id,
         schedule=schedule,
+        timezone=timezone,
+        jitter_seconds=spec.jitter_seconds,
         tasks=tasks,
         edges=edges,
         task_to_alias=task_to_alias,
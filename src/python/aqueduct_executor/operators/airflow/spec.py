@@ -23,6 +23,8 @@ class CompileAirflowSpec(BaseModel):
     output_content_path: str
     dag_id: str
     cron_schedule: str
+    timezone: str
+    jitter_seconds: int
     task_specs: Dict[str, OperatorSpec]
     task_edges: Dict[str, List[str]]
 
@@ -61,6 +61,12 @@ class S3Config(models.BaseConfig):
 
     use_as_storage: str = ""
 
+    # The following fields are only needed for S3-compatible services other than AWS.
+    endpoint: str = ""
+    force_path_style: str = ""
+    # The content of a PEM file of additional certificate authorities to trust.
+    ca_certificate: str = ""
+
 
 class AthenaConfig(models.BaseConfig):
     # default type to ACCESS_KEY mainly for backward compatibility
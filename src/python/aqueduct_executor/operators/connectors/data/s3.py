@@ -13,7 +13,10 @@ from aqueduct_executor.operators.connectors.data.s3_serialization import (
     artifact_type_to_s3_serialization_type,
     serialize_val_for_s3,
 )
-from aqueduct_executor.operators.connectors.data.utils import construct_boto_session
+from aqueduct_executor.operators.connectors.data.utils import (
+    construct_boto_session,
+    s3_resource_kwargs,
+)
 from aqueduct_executor.operators.utils.enums import ArtifactType
 from aqueduct_executor.operators.utils.saved_object_delete import SavedObjectDelete
 from aqueduct_executor.operators.utils.utils import delete_object
@@ -23,7 +26,7 @@ from botocore.client import ClientError
 class S3Connector(connector.DataConnector):
     def __init__(self, config: S3Config):
         session = construct_boto_session(config)
-        self.s3 = session.resource("s3")
+        self.s3 = session.resource("s3", **s3_resource_kwargs(config))
         self.bucket = config.bucket
         self.root_dir = config.root_dir
 
@@ -1,9 +1,11 @@
 import os
+import tempfile
 import urllib.parse
 import uuid
-from typing import Union
+from typing import Any, Dict, Union
 
 import boto3
+from botocore.config import Config as BotoConfig
 from aqueduct_executor.operators.connectors.data.config import (
     AthenaConfig,
     AWSCredentialType,
@@ -72,5 +74,23 @@ def construct_boto_session(config: Union[S3Config, AthenaConfig]) -> boto3.sessi
         raise Exception("Unsupported integration config type: %s" % config.type)
 
 
+def s3_resource_kwargs(config: S3Config) -> Dict[str, Any]:
+    """
+    returns the arguments for creating a boto S3 resource that connects to the
+    endpoint of an S3-compatible service, if one is configured.
+    """
+    kwargs: Dict[str, Any] = {}
+    if config.endpoint:
+        kwargs["endpoint_url"] = config.endpoint
+    if config.force_path_style == "true":
+        kwargs["config"] = BotoConfig(s3={"addressing_style": "path"})
+    if config.ca_certificate:
+        # Boto expects the certificate authorities as a filepath, which needs to outlive the resource.
+        with tempfile.NamedTemporaryFile("w", suffix=".pem", delete=False) as f:
+            f.write(config.ca_certificate)
+        kwargs["verify"] = f.name
+    return kwargs
+
+
 def url_encode(value: str) -> str:
     return urllib.parse.quote_plus(value)
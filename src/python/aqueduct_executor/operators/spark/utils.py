@@ -28,10 +28,13 @@ from aqueduct_executor.operators.utils.saved_object_delete import SavedObjectDel
 from aqueduct_executor.operators.utils.storage.storage import Storage
 from aqueduct_executor.operators.utils.utils import (
     _METADATA_ARTIFACT_TYPE_KEY,
+    _METADATA_CHECKSUM_KEY,
     _METADATA_PYTHON_TYPE_KEY,
     _METADATA_SCHEMA_KEY,
     _METADATA_SERIALIZATION_TYPE_KEY,
     _METADATA_SYSTEM_METADATA_KEY,
+    compute_checksum,
+    read_verified_content,
     serialize_val_wrapper,
 )
 from pyspark.sql import SparkSession
@@ -67,7 +70,8 @@ def read_artifacts_spark(
         # Check if artifact is of type TABLE. If it is, attempt to read from a temporary view with
         # name of the input_path.
         if artifact_type != ArtifactType.TABLE:
-            inputs.append(deserialize(serialization_type, artifact_type, storage.get(input_path)))
+            content = read_verified_content(storage, input_path, artifact_metadata)
+            inputs.append(deserialize(serialization_type, artifact_type, content))
         else:
             # read from temp view
             try:
@@ -126,6 +130,8 @@ def write_artifact_spark(
             serialized_val = serialize_val_wrapper(content, serialization_type, derived_from_bson)
             storage.put(output_path, serialized_val)
 
+        output_metadata[_METADATA_CHECKSUM_KEY] = compute_checksum(serialized_val)
+
     output_metadata[_METADATA_SERIALIZATION_TYPE_KEY] = serialization_type
     output_metadata[_METADATA_PYTHON_TYPE_KEY] = type(content).__name__
     storage.put(output_metadata_path, json.dumps(output_metadata).encode(DEFAULT_ENCODING))
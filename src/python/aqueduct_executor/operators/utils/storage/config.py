@@ -1,5 +1,5 @@
 from enum import Enum
-from typing import Optional
+from typing import Dict, Optional
 
 from aqueduct_executor.operators.utils.enums import MetaEnum
 from pydantic import BaseModel
@@ -11,6 +11,11 @@ class StorageType(str, Enum, metaclass=MetaEnum):
     GCS = "gcs"
 
 
+class CompressionType(str, Enum, metaclass=MetaEnum):
+    GZIP = "gzip"
+    ZSTD = "zstd"
+
+
 class FileStorageConfig(BaseModel):
     directory: str
 
@@ -27,14 +32,34 @@ class S3StorageConfig(BaseModel):
     aws_access_key_id: str = ""
     aws_secret_access_key: str = ""
 
+    # The following fields are only set for S3-compatible services other than AWS.
+    endpoint: str = ""
+    force_path_style: bool = False
+    ca_bundle_path: str = ""
+
 
 class GCSStorageConfig(BaseModel):
     bucket: str
     service_account_credentials: str
 
 
+class StorageKeys(BaseModel):
+    # The keys are base64-encoded, by the ID of the server key that they are derived from.
+    current_key_id: str = ""
+    keys: Dict[str, str] = {}
+
+
 class StorageConfig(BaseModel):
     type: StorageType
     file_config: Optional[FileStorageConfig] = None
     s3_config: Optional[S3StorageConfig] = None
     gcs_config: Optional[GCSStorageConfig] = None
+
+    # Artifact content is moved to the path of its hash by the engine, after it is written.
+    content_addressed: bool = False
+
+    # How stored objects are compressed and encrypted. The encryption keys are only set if
+    # objects are encrypted, and the job is not passed them through the environment instead.
+    compression: Optional[CompressionType] = None
+    encrypt: bool = False
+    encryption_keys: Optional[StorageKeys] = None
@@ -0,0 +1,201 @@
+import base64
+import gzip
+import os
+import struct
+from typing import Optional
+
+from aqueduct_executor.operators.utils.storage.config import (
+    CompressionType,
+    StorageConfig,
+    StorageKeys,
+)
+from aqueduct_executor.operators.utils.storage.storage import Storage
+
+# Objects that are compressed or encrypted start with a header that records how they were encoded.
+# Objects without the header are stored as is. This must be kept in sync with the format that is
+# implemented by the Go storage layer, in `lib/storage/encoding.go`.
+_MAGIC = b"\x89AQS"
+_VERSION = 2
+# Objects of this version are encrypted with the server's key itself, so only the server can read them.
+_LEGACY_VERSION = 1
+# The size of the part of the header that every version has.
+_HEADER_SIZE = 7
+
+# Jobs are passed the storage keys through this environment variable, instead of their spec.
+_STORAGE_KEYS_ENV_VAR = "AQUEDUCT_STORAGE_KEYS"
+
+_NO_COMPRESSION = 0
+_GZIP_COMPRESSION = 1
+_ZSTD_COMPRESSION = 2
+
+_NO_ENCRYPTION = 0
+_AES_GCM_ENCRYPTION = 1
+
+_ENCRYPTED_CHUNK_SIZE = 64 * 1024
+_NONCE_SIZE = 12
+
+_COMPRESSION_IDS = {
+    None: _NO_COMPRESSION,
+    CompressionType.GZIP: _GZIP_COMPRESSION,
+    CompressionType.ZSTD: _ZSTD_COMPRESSION,
+}
+
+
+class EncodedStorage(Storage):
+    """Compresses and encrypts objects before they are written to the underlying storage,
+    according to the storage config, and decodes them when they are read."""
+
+    _storage: Storage
+    _compression: Optional[CompressionType]
+    _encrypt: bool
+    _encryption_keys: Optional[StorageKeys]
+
+    def __init__(self, storage: Storage, config: StorageConfig):
+        self._storage = storage
+        self._compression = config.compression
+        self._encrypt = config.encrypt
+        self._encryption_keys = config.encryption_keys
+        if self._encryption_keys is None and _STORAGE_KEYS_ENV_VAR in os.environ:
+            self._encryption_keys = StorageKeys.parse_raw(os.environ[_STORAGE_KEYS_ENV_VAR])
+
+    def put(self, key: str, value: bytes) -> None:
+        if self._compression is None and not self._encrypt:
+            self._storage.put(key, value)
+            return
+
+        key_id = b""
+        if self._encrypt:
+            key_id = self._keys().current_key_id.encode()
+
+        header = (
+            _MAGIC
+            + bytes(
+                [
+                    _VERSION,
+                    _COMPRESSION_IDS[self._compression],
+                    _AES_GCM_ENCRYPTION if self._encrypt else _NO_ENCRYPTION,
+                    len(key_id),
+                ]
+            )
+            + key_id
+        )
+
+        if self._compression == CompressionType.GZIP:
+            value = gzip.compress(value)
+        elif self._compression == CompressionType.ZSTD:
+            import zstandard
+
+            value = zstandard.ZstdCompressor().compress(value)
+
+        if self._encrypt:
+            value = _encrypt(value, header, self._key(key_id.decode()))
+
+        self._storage.put(key, header + value)
+
+    def get(self, key: str) -> bytes:
+        value = self._storage.get(key)
+        if len(value) < _HEADER_SIZE or value[: len(_MAGIC)] != _MAGIC:
+            return value
+
+        header, value = value[:_HEADER_SIZE], value[_HEADER_SIZE:]
+        key_id = ""
+        if header[4] == _VERSION:
+            key_id_len = value[0]
+            header += value[: 1 + key_id_len]
+            key_id = value[1 : 1 + key_id_len].decode()
+            value = value[1 + key_id_len :]
+        elif header[4] != _LEGACY_VERSION:
+            raise Exception("Unsupported encoding version %d of object %s." % (header[4], key))
+
+        if header[6] == _AES_GCM_ENCRYPTION:
+            if header[4] == _LEGACY_VERSION:
+                raise Exception(
+                    "Object %s is of encoding version 1, which can only be decrypted by the server."
+                    % key
+                )
+            value = _decrypt(value, header, self._key(key_id))
+        elif header[6] != _NO_ENCRYPTION:
+            raise Exception("Unsupported encryption algorithm %d of object %s." % (header[6], key))
+
+        if header[5] == _GZIP_COMPRESSION:
+            return gzip.decompress(value)
+        elif header[5] == _ZSTD_COMPRESSION:
+            import zstandard
+
+            # The content size is not recorded by streaming compressors, such as the Go one.
+            return zstandard.ZstdDecompressor().decompressobj().decompress(value)
+        elif header[5] != _NO_COMPRESSION:
+            raise Exception("Unsupported compression algorithm %d of object %s." % (header[5], key))
+        return value
+
+    def exists(self, key: str) -> bool:
+        return self._storage.exists(key)
+
+    def _keys(self) -> StorageKeys:
+        if self._encryption_keys is None:
+            raise Exception("No encryption key is configured for encrypted storage.")
+        return self._encryption_keys
+
+    def _key(self, key_id: str) -> bytes:
+        keys = self._keys()
+        if key_id not in keys.keys:
+            raise Exception(
+                "Storage key %s is not available. The key it is derived from may have been removed from the keyring."
+                % key_id
+            )
+        return base64.b64decode(keys.keys[key_id])
+
+
+def _additional_data(header: bytes, index: int, final: bool) -> bytes:
+    return header + struct.pack(">QB", index, 1 if final else 0)
+
+
+def _encrypt(value: bytes, header: bytes, key: bytes) -> bytes:
+    from cryptography.hazmat.primitives.ciphers.aead import AESGCM
+
+    aesgcm = AESGCM(key)
+    chunks = [
+        value[i : i + _ENCRYPTED_CHUNK_SIZE] for i in range(0, len(value), _ENCRYPTED_CHUNK_SIZE)
+    ]
+    # The final chunk is always written, even if it is empty.
+    if len(chunks) == 0:
+        chunks = [b""]
+
+    encrypted = []
+    for index, chunk in enumerate(chunks):
+        final = index == len(chunks) - 1
+        nonce = os.urandom(_NONCE_SIZE)
+        sealed = aesgcm.encrypt(nonce, chunk, _additional_data(header, index, final))
+        encrypted.append(struct.pack(">BI", 1 if final else 0, len(sealed)) + nonce + sealed)
+    return b"".join(encrypted)
+
+
+def _decrypt(value: bytes, header: bytes, key: bytes) -> bytes:
+    from cryptography.hazmat.primitives.ciphers.aead import AESGCM
+
+    aesgcm = AESGCM(key)
+    decrypted = []
+    offset = 0
+    index = 0
+    while True:
+        if offset + 5 + _NONCE_SIZE > len(value):
+            raise Exception("Encrypted object is truncated.")
+
+        final, sealed_size = struct.unpack(">BI", value[offset : offset + 5])
+        offset += 5
+        nonce = value[offset : offset + _NONCE_SIZE]
+        offset += _NONCE_SIZE
+        sealed = value[offset : offset + sealed_size]
+        if len(sealed) != sealed_size:
+            raise Exception("Encrypted object is truncated.")
+        offset += sealed_size
+
+        decrypted.append(
+            aesgcm.decrypt(nonce, sealed, _additional_data(header, index, final == 1))
+        )
+        index += 1
+
+        if final == 1:
+            if offset != len(value):
+                raise Exception("Unexpected data after the final encrypted chunk.")
+            return b"".join(decrypted)
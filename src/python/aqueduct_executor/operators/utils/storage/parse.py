@@ -1,4 +1,5 @@
 from aqueduct_executor.operators.utils.storage.config import StorageConfig
+from aqueduct_executor.operators.utils.storage.encoding import EncodedStorage
 from aqueduct_executor.operators.utils.storage.file import FileStorage
 from aqueduct_executor.operators.utils.storage.gcs import GCSStorage
 from aqueduct_executor.operators.utils.storage.s3 import S3Storage
@@ -6,6 +7,12 @@ from aqueduct_executor.operators.utils.storage.storage import Storage
 
 
 def parse_storage(storage_config: StorageConfig) -> Storage:
+    # Objects are always decoded when they are read, even if the storage layer
+    # is no longer configured to encode them.
+    return EncodedStorage(_parse_base_storage(storage_config), storage_config)
+
+
+def _parse_base_storage(storage_config: StorageConfig) -> Storage:
     if storage_config.s3_config:
         return S3Storage(storage_config.s3_config)
     if storage_config.file_config:
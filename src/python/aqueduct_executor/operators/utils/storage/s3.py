@@ -1,5 +1,5 @@
 import os
-from typing import Any, Tuple
+from typing import Any, Dict, Tuple
 
 import boto3
 from aqueduct_executor.operators.utils.storage.config import S3StorageConfig
@@ -13,12 +13,14 @@ class S3Storage(Storage):
     _config: S3StorageConfig
 
     def __init__(self, config: S3StorageConfig):
+        client_kwargs = _client_kwargs(config)
         if config.aws_access_key_id and config.aws_secret_access_key:
             # The AWS keys are passed in as part of the storage spec for AWS Lambda engines
             self._client = boto3.client(
                 "s3",
                 aws_access_key_id=config.aws_access_key_id,
                 aws_secret_access_key=config.aws_secret_access_key,
+                **client_kwargs,
             )
         elif "AWS_ACCESS_KEY_ID" in os.environ and "AWS_SECRET_ACCESS_KEY" in os.environ:
             # The AWS keys are passed in as environment variables for k8s engines
@@ -26,12 +28,13 @@ class S3Storage(Storage):
                 "s3",
                 aws_access_key_id=os.environ["AWS_ACCESS_KEY_ID"],
                 aws_secret_access_key=os.environ["AWS_SECRET_ACCESS_KEY"],
+                **client_kwargs,
             )
         else:
             # Boto3 uses an environment variable to determine the credentials filepath and profile
             os.environ["AWS_SHARED_CREDENTIALS_FILE"] = config.credentials_path
             os.environ["AWS_PROFILE"] = config.credentials_profile
-            self._client = boto3.client("s3", config=BotoConfig(region_name=config.region))
+            self._client = boto3.client("s3", **client_kwargs)
 
         self._config = config
 
@@ -75,6 +78,21 @@ class S3Storage(Storage):
         return self._key_prefix + key
 
 
+def _client_kwargs(config: S3StorageConfig) -> Dict[str, Any]:
+    """Returns the arguments for creating a boto3 client that connects to the configured endpoint."""
+    kwargs: Dict[str, Any] = {
+        "config": BotoConfig(
+            region_name=config.region or None,
+            s3={"addressing_style": "path" if config.force_path_style else "auto"},
+        ),
+    }
+    if config.endpoint:
+        kwargs["endpoint_url"] = config.endpoint
+    if config.ca_bundle_path:
+        kwargs["verify"] = config.ca_bundle_path
+    return kwargs
+
+
 def _sanitize_path(path: str) -> str:
     """Sanitize the given path to be in the format `path/to/dir/` (no leading slash but one trailing slash)."""
     if path == "":
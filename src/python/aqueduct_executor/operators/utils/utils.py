@@ -1,3 +1,4 @@
+import hashlib
 import io
 import json
 import time
@@ -36,6 +37,7 @@ _METADATA_SYSTEM_METADATA_KEY = "system_metadata"
 _METADATA_ARTIFACT_TYPE_KEY = "artifact_type"
 _METADATA_SERIALIZATION_TYPE_KEY = "serialization_type"
 _METADATA_PYTHON_TYPE_KEY = "python_type"
+_METADATA_CHECKSUM_KEY = "checksum"
 
 # The temporary file name that a Tensorflow keras model will be dumped into before we read/write it from storage.
 # This will be cleaned up within the serialization logic.
@@ -86,11 +88,37 @@ def read_artifacts(
         serialization_type = artifact_metadata[_METADATA_SERIALIZATION_TYPE_KEY]
         serialization_types.append(serialization_type)
 
-        inputs.append(deserialize(serialization_type, artifact_type, storage.get(input_path)))
+        content = read_verified_content(storage, input_path, artifact_metadata)
+        inputs.append(deserialize(serialization_type, artifact_type, content))
 
     return inputs, artifact_types, serialization_types
 
 
+def compute_checksum(content: bytes) -> str:
+    """Returns the hex-encoded SHA-256 checksum that is recorded in the artifact metadata."""
+    return hashlib.sha256(content).hexdigest()
+
+
+def read_verified_content(storage: Storage, path: str, artifact_metadata: Dict[str, Any]) -> bytes:
+    """Reads the artifact content at `path`, and checks it against the checksum in its metadata.
+
+    Content written before checksums were recorded is not verified.
+    """
+    content = storage.get(path)
+
+    checksum = artifact_metadata.get(_METADATA_CHECKSUM_KEY)
+    if checksum:
+        actual = compute_checksum(content)
+        if actual != checksum:
+            raise ExecFailureException(
+                failure_type=FailureType.SYSTEM,
+                tip="The content of an input artifact at %s is corrupted. It has checksum %s, "
+                "but checksum %s was recorded when it was written." % (path, actual, checksum),
+            )
+
+    return content
+
+
 def read_system_metadata(
     storage: Storage,
     input_metadata_paths: List[str],
@@ -157,6 +185,7 @@ def write_artifact(
     if output_path is not None:
         serialized_val = serialize_val_wrapper(content, serialization_type, derived_from_bson)
         storage.put(output_path, serialized_val)
+        output_metadata[_METADATA_CHECKSUM_KEY] = compute_checksum(serialized_val)
 
     output_metadata[_METADATA_SERIALIZATION_TYPE_KEY] = serialization_type
     output_metadata[_METADATA_PYTHON_TYPE_KEY] = type(content).__name__
@@ -27,6 +27,10 @@ const ExecutionChip: React.FC<Props> = ({ status }) => {
     return <Chip label="Canceled" color="default" size="small" />;
   }
 
+  if (status === ExecutionStatus.Skipped) {
+    return <Chip label="Skipped" color="default" size="small" />;
+  }
+
   if (status === ExecutionStatus.Registered) {
     return <Chip label="Pending" color="info" size="small" />;
   }
@@ -25,6 +25,7 @@ const Placeholders: S3Config = {
   config_file_content: '',
   config_file_profile: '',
   use_as_storage: '',
+  endpoint: 'https://minio.example.com:9000',
 };
 
 type Props = {
@@ -41,12 +42,26 @@ export const S3Dialog: React.FC<Props> = ({
   setMigrateStorage,
 }) => {
   const [fileName, setFileName] = useState<string>(null);
+  const [caFileName, setCAFileName] = useState<string>(null);
 
   const setFile = (fileData: FileData | null) => {
     setFileName(fileData?.name ?? null);
     onUpdateField('config_file_content', fileData?.data);
   };
 
+  const setCAFile = (fileData: FileData | null) => {
+    setCAFileName(fileData?.name ?? null);
+    onUpdateField('ca_certificate', fileData?.data);
+  };
+
+  const caFileData =
+    caFileName && !!value?.ca_certificate
+      ? {
+          name: caFileName,
+          data: value.ca_certificate,
+        }
+      : null;
+
   const fileData =
     fileName && !!value?.config_file_content
       ? {
@@ -207,6 +222,57 @@ export const S3Dialog: React.FC<Props> = ({
         disableReason={editMode ? readOnlyFieldDisableReason : undefined}
       />
 
+      <IntegrationTextInputField
+        spellCheck={false}
+        required={false}
+        label="Endpoint"
+        description="Only applicable to S3-compatible services other than AWS, such as MinIO, Ceph or R2. The URL of the service's S3 API. Defaults to AWS."
+        placeholder={Placeholders.endpoint}
+        onChange={(event) => onUpdateField('endpoint', event.target.value)}
+        value={value?.endpoint ?? ''}
+        disabled={editMode}
+        warning={editMode ? undefined : readOnlyFieldWarning}
+        disableReason={editMode ? readOnlyFieldDisableReason : undefined}
+      />
+
+      {!!value?.endpoint && (
+        <Box>
+          <FormControlLabel
+            label="Address the bucket as part of the URL path, which most S3-compatible services require."
+            control={
+              <Checkbox
+                checked={value?.force_path_style === 'true'}
+                onChange={(event) =>
+                  onUpdateField(
+                    'force_path_style',
+                    event.target.checked ? 'true' : 'false'
+                  )
+                }
+                disabled={editMode}
+              />
+            }
+          />
+
+          <IntegrationFileUploadField
+            label={'CA Certificate'}
+            description={
+              'Only needed if the endpoint uses a self-signed certificate. Upload the PEM file of the certificate authorities to trust.'
+            }
+            required={false}
+            file={caFileData}
+            placeholder={''}
+            onFiles={(files) => {
+              const file = files[0];
+              readCredentialsFile(file, setCAFile);
+            }}
+            displayFile={null}
+            onReset={() => {
+              setCAFile(null);
+            }}
+          />
+        </Box>
+      )}
+
       <Box sx={{ borderBottom: 1, borderColor: 'divider', mb: 2 }}>
         <Tabs
           value={value?.type ?? 'access_key'}
@@ -101,7 +101,9 @@ const ArtifactDetailsPage: React.FC<ArtifactDetailsPageProps> = ({
 
   const artifactStatus = artifact?.result?.exec_state?.status;
   const previewAvailable =
-    artifactStatus && artifactStatus !== ExecutionStatus.Canceled;
+    artifactStatus &&
+    artifactStatus !== ExecutionStatus.Canceled &&
+    artifactStatus !== ExecutionStatus.Skipped;
 
   return (
     <Layout breadcrumbs={breadcrumbs} user={user}>
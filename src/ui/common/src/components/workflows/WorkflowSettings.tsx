@@ -534,6 +534,10 @@ const WorkflowSettings: React.FC<WorkflowSettingsProps> = ({
           triggerType === WorkflowUpdateTrigger.Cascade
             ? sourceId
             : '00000000-0000-0000-0000-000000000000',
+        // The schedule is replaced as a whole, so the settings that cannot be edited here are carried over.
+        concurrency_policy: workflowDag.metadata?.schedule?.concurrency_policy,
+        timezone: workflowDag.metadata?.schedule?.timezone,
+        jitter_seconds: workflowDag.metadata?.schedule?.jitter_seconds,
       },
       retention_policy: retentionPolicyUpdated ? retentionPolicy : undefined,
       notification_settings: isNotificationSettingsUpdated
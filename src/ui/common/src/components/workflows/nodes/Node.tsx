@@ -110,6 +110,7 @@ export const Node: React.FC<Props> = ({ data, isConnectable }) => {
       backgroundColor = theme.palette.red[100];
       break;
     case ExecutionStatus.Canceled:
+    case ExecutionStatus.Skipped:
     case ExecutionStatus.Pending:
     default:
       backgroundColor = theme.palette.gray[400];
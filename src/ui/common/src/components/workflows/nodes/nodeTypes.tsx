@@ -67,6 +67,7 @@ export const artifactNodeStatusLabels = {
   [ExecutionStatus.Failed]: 'Failed',
   [ExecutionStatus.Pending]: 'Pending',
   [ExecutionStatus.Canceled]: 'Canceled',
+  [ExecutionStatus.Skipped]: 'Skipped',
   [ExecutionStatus.Registered]: 'Registered',
   [ExecutionStatus.Running]: 'Running',
   [ExecutionStatus.Warning]: 'Warning',
@@ -78,6 +79,7 @@ export const operatorNodeStatusLabels = {
   [ExecutionStatus.Failed]: 'Errored',
   [ExecutionStatus.Pending]: 'Pending',
   [ExecutionStatus.Canceled]: 'Canceled',
+  [ExecutionStatus.Skipped]: 'Skipped',
   [ExecutionStatus.Registered]: 'Registered',
   [ExecutionStatus.Running]: 'Running',
   [ExecutionStatus.Warning]: 'Warning',
@@ -91,6 +93,7 @@ export const checkNodeStatusLabels = {
   [ExecutionStatus.Failed]: 'Failed',
   [ExecutionStatus.Pending]: 'Pending',
   [ExecutionStatus.Canceled]: 'Canceled',
+  [ExecutionStatus.Skipped]: 'Skipped',
   [ExecutionStatus.Registered]: 'Registered',
   [ExecutionStatus.Running]: 'Running',
   [ExecutionStatus.Warning]: 'Warning',
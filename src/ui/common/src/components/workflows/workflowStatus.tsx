@@ -2,6 +2,7 @@ import {
   faCircleCheck,
   faCircleExclamation,
   faCircleQuestion,
+  faForward,
   faListOl,
   faSpinner,
   faTriangleExclamation,
@@ -19,6 +20,8 @@ export const getExecutionStatusColor = (status: ExecutionStatus): string => {
   switch (status) {
     case ExecutionStatus.Canceled:
       return theme.palette.Default;
+    case ExecutionStatus.Skipped:
+      return theme.palette.Default;
     case ExecutionStatus.Failed:
       return theme.palette.Error;
     case ExecutionStatus.Pending:
@@ -41,6 +44,8 @@ export const getExecutionStatusLabel = (status: ExecutionStatus): string => {
   switch (status) {
     case ExecutionStatus.Canceled:
       return 'Canceled';
+    case ExecutionStatus.Skipped:
+      return 'Skipped';
     case ExecutionStatus.Failed:
       return 'Failed';
     case ExecutionStatus.Pending:
@@ -105,6 +110,10 @@ export const StatusIndicator: React.FC<IndicatorProps> = ({
       icon = faX;
       break;
 
+    case ExecutionStatus.Skipped:
+      icon = faForward;
+      break;
+
     case ExecutionStatus.Pending:
       icon = faSpinner;
       spin = true;
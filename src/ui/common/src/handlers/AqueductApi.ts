@@ -2,12 +2,32 @@ import * as rtkQueryRaw from '@reduxjs/toolkit/dist/query/react/index.js';
 import { FetchBaseQueryError } from '@reduxjs/toolkit/query/react';
 
 import { apiAddress } from '../components/hooks/useAqueductConsts';
+import {
+  backfillCreateQuery,
+  BackfillCreateRequest,
+  BackfillCreateResponse,
+} from './v2/BackfillCreate';
+import {
+  backfillGetQuery,
+  BackfillGetRequest,
+  BackfillGetResponse,
+} from './v2/BackfillGet';
 import { dagGetQuery, DagGetRequest, DagGetResponse } from './v2/DagGet';
+import {
+  dagResultCancelQuery,
+  DagResultCancelRequest,
+  DagResultCancelResponse,
+} from './v2/DagResultCancel';
 import {
   dagResultGetQuery,
   DagResultGetRequest,
   DagResultGetResponse,
 } from './v2/DagResultGet';
+import {
+  dagResultResumeQuery,
+  DagResultResumeRequest,
+  DagResultResumeResponse,
+} from './v2/DagResultResume';
 import {
   dagResultsGetQuery,
   DagResultsGetRequest,
@@ -71,14 +91,39 @@ export const aqueductApi = createApi({
   baseQuery: fetchBaseQuery({ baseUrl: `${apiAddress}/api/v2/` }),
   keepUnusedDataFor: 60,
   endpoints: (builder) => ({
+    backfillCreate: builder.mutation<
+      BackfillCreateResponse,
+      BackfillCreateRequest
+    >({
+      query: (req) => backfillCreateQuery(req),
+      transformErrorResponse,
+    }),
+    backfillGet: builder.query<BackfillGetResponse, BackfillGetRequest>({
+      query: (req) => backfillGetQuery(req),
+      transformErrorResponse,
+    }),
     dagGet: builder.query<DagGetResponse, DagGetRequest>({
       query: (req) => dagGetQuery(req),
       transformErrorResponse,
     }),
+    dagResultCancel: builder.mutation<
+      DagResultCancelResponse,
+      DagResultCancelRequest
+    >({
+      query: (req) => dagResultCancelQuery(req),
+      transformErrorResponse,
+    }),
     dagResultGet: builder.query<DagResultGetResponse, DagResultGetRequest>({
       query: (req) => dagResultGetQuery(req),
       transformErrorResponse,
     }),
+    dagResultResume: builder.mutation<
+      DagResultResumeResponse,
+      DagResultResumeRequest
+    >({
+      query: (req) => dagResultResumeQuery(req),
+      transformErrorResponse,
+    }),
     dagResultsGet: builder.query<DagResultsGetResponse, DagResultsGetRequest>({
       query: (req) => dagResultsGetQuery(req),
       transformErrorResponse,
@@ -148,8 +193,12 @@ export const aqueductApi = createApi({
 });
 
 export const {
+  useBackfillCreateMutation,
+  useBackfillGetQuery,
   useDagGetQuery,
+  useDagResultCancelMutation,
   useDagResultGetQuery,
+  useDagResultResumeMutation,
   useDagResultsGetQuery,
   useStorageMigrationListQuery,
   useNodeArtifactGetQuery,
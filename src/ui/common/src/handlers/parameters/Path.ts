@@ -1,3 +1,7 @@
+export type BackfillIdParameter = {
+  backfillId: string;
+};
+
 export type DagIdParameter = {
   dagId: string;
 };
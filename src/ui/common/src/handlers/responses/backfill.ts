@@ -0,0 +1,23 @@
+// This file should map exactly to
+// `src/golang/lib/response/backfill.go`
+
+import { ExecState, ExecutionStatus } from '../../utils/shared';
+
+export type BackfillRunResponse = {
+  execution_time: string;
+  dag_result_id?: string;
+  status: ExecutionStatus;
+};
+
+export type BackfillResponse = {
+  id: string;
+  workflow_id: string;
+  cron_schedule: string;
+  timezone: string;
+  start_time: string;
+  end_time: string;
+  max_concurrency: number;
+  exec_state: ExecState;
+  runs_by_status: { [status: string]: number };
+  runs: BackfillRunResponse[];
+};
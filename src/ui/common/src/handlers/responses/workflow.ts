@@ -19,6 +19,7 @@ export type WorkflowResponse = {
   created_at: string;
   retention_policy: RetentionPolicy;
   notification_settings: NotificationSettings;
+  max_concurrent_operators: number;
 };
 
 export type DagResponse = {
@@ -33,4 +34,5 @@ export type DagResultResponse = {
   id: string;
   dag_id: string;
   exec_state: ExecState;
+  source_dag_result_id?: string;
 };
@@ -0,0 +1,26 @@
+// This file should map exactly to
+// src/golang/cmd/server/handler/v2/backfill_create.go
+
+import { APIKeyParameter } from '../parameters/Header';
+import { WorkflowIdParameter } from '../parameters/Path';
+import { BackfillResponse } from '../responses/backfill';
+
+export type BackfillCreateRequest = APIKeyParameter &
+  WorkflowIdParameter & {
+    startTime: string;
+    endTime: string;
+    maxConcurrency: number;
+  };
+
+export type BackfillCreateResponse = BackfillResponse;
+
+export const backfillCreateQuery = (req: BackfillCreateRequest) => ({
+  url: `workflow/${req.workflowId}/backfill`,
+  method: 'POST',
+  headers: { 'api-key': req.apiKey },
+  body: {
+    start_time: req.startTime,
+    end_time: req.endTime,
+    max_concurrency: req.maxConcurrency,
+  },
+});
@@ -0,0 +1,17 @@
+// This file should map exactly to
+// src/golang/cmd/server/handler/v2/backfill_get.go
+
+import { APIKeyParameter } from '../parameters/Header';
+import { BackfillIdParameter, WorkflowIdParameter } from '../parameters/Path';
+import { BackfillResponse } from '../responses/backfill';
+
+export type BackfillGetRequest = APIKeyParameter &
+  BackfillIdParameter &
+  WorkflowIdParameter;
+
+export type BackfillGetResponse = BackfillResponse;
+
+export const backfillGetQuery = (req: BackfillGetRequest) => ({
+  url: `workflow/${req.workflowId}/backfill/${req.backfillId}`,
+  headers: { 'api-key': req.apiKey },
+});
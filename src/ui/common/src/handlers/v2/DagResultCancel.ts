@@ -0,0 +1,17 @@
+// This file should map exactly to
+// src/golang/cmd/server/handler/v2/dag_result_cancel.go
+
+import { APIKeyParameter } from '../parameters/Header';
+import { DagResultIdParameter, WorkflowIdParameter } from '../parameters/Path';
+
+export type DagResultCancelRequest = APIKeyParameter &
+  DagResultIdParameter &
+  WorkflowIdParameter;
+
+export type DagResultCancelResponse = Record<string, never>;
+
+export const dagResultCancelQuery = (req: DagResultCancelRequest) => ({
+  url: `workflow/${req.workflowId}/result/${req.dagResultId}/cancel`,
+  method: 'POST',
+  headers: { 'api-key': req.apiKey },
+});
@@ -0,0 +1,17 @@
+// This file should map exactly to
+// src/golang/cmd/server/handler/v2/dag_result_resume.go
+
+import { APIKeyParameter } from '../parameters/Header';
+import { DagResultIdParameter, WorkflowIdParameter } from '../parameters/Path';
+
+export type DagResultResumeRequest = APIKeyParameter &
+  DagResultIdParameter &
+  WorkflowIdParameter;
+
+export type DagResultResumeResponse = Record<string, never>;
+
+export const dagResultResumeQuery = (req: DagResultResumeRequest) => ({
+  url: `workflow/${req.workflowId}/result/${req.dagResultId}/resume`,
+  method: 'POST',
+  headers: { 'api-key': req.apiKey },
+});
@@ -119,6 +119,11 @@ export type S3Config = {
   config_file_content: string;
   config_file_profile: string;
   use_as_storage: string;
+
+  // Only needed for S3-compatible services other than AWS, e.g. MinIO.
+  endpoint?: string;
+  force_path_style?: string;
+  ca_certificate?: string;
 };
 
 export type AthenaConfig = {
@@ -157,6 +162,9 @@ export type KubernetesConfig = {
   kubeconfig_path: string;
   cluster_name: string;
   use_same_cluster: string;
+  // Comma-separated namespaces and service accounts that operators may override theirs with.
+  allowed_namespaces?: string;
+  allowed_service_accounts?: string;
 };
 
 export type LambdaConfig = {
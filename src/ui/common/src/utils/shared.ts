@@ -36,6 +36,8 @@ export enum ExecutionStatus {
   Failed = 'failed',
   Pending = 'pending',
   Canceled = 'canceled',
+  // Operators whose condition was not satisfied, and their downstream operators, are skipped.
+  Skipped = 'skipped',
   Registered = 'registered',
   Running = 'running',
   // Checks can have a warning status.
@@ -87,6 +89,9 @@ export const stringToExecutionStatus = (status: string): ExecutionStatus => {
     case 'canceled':
       executionStatus = ExecutionStatus.Canceled;
       break;
+    case 'skipped':
+      executionStatus = ExecutionStatus.Skipped;
+      break;
     case 'registered':
       executionStatus = ExecutionStatus.Registered;
       break;
@@ -113,6 +118,8 @@ export type ExecutionTimestamps = {
 
 export type ExecState = {
   status: ExecutionStatus;
+  // Set to 'queued' if the operator is pending because it is waiting for other operators to finish.
+  reason?: string;
   failure_type?: FailureType;
   error?: Error;
   user_logs?: Logs;
@@ -144,6 +151,8 @@ export type Logs = {
 export type Error = {
   context?: string;
   tip?: string;
+  // Set to 'timeout' if the operator was stopped for exceeding its timeout.
+  code?: string;
 };
 
 export const GithubIssueLink = `https://github.com/aqueducthq/aqueduct/issues/new?assignees=&labels=bug&template=bug_report.md&title=%5BBUG%5D`;
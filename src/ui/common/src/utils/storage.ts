@@ -19,6 +19,9 @@ export type S3Config = {
   credentials_profile?: string;
   aws_access_key_id?: string;
   aws_secret_access_key?: string;
+  endpoint?: string;
+  force_path_style?: boolean;
+  ca_bundle_path?: string;
 };
 
 export type FileConfig = {
@@ -30,11 +33,15 @@ export type GCSConfig = {
   service_account_credentials?: string;
 };
 
+export type CompressionType = 'gzip' | 'zstd';
+
 export type StorageConfig = {
   type: StorageType;
   s3_config?: S3Config;
   file_config?: FileConfig;
   gcs_config?: GCSConfig;
+  compression?: CompressionType;
+  encrypt?: boolean;
 };
 
 export type MetadataStorageConfig = {
@@ -42,6 +49,8 @@ export type MetadataStorageConfig = {
   s3Config?: S3Config;
   fileConfig?: FileConfig;
   gcsConfig?: GCSConfig;
+  compression?: CompressionType;
+  encrypt?: boolean;
 };
 
 export type ServerConfig = {
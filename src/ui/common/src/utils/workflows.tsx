@@ -28,12 +28,24 @@ export enum WorkflowUpdateTrigger {
   Cascade = 'cascade',
 }
 
+// Specifies what happens when a workflow is triggered while a previous run is still in progress.
+export enum ConcurrencyPolicy {
+  Allow = 'allow',
+  SkipIfRunning = 'skip_if_running',
+  Queue = 'queue',
+  CancelPrevious = 'cancel_previous',
+}
+
 export type WorkflowSchedule = {
   trigger: WorkflowUpdateTrigger;
   cron_schedule: string;
   disable_manual_trigger: boolean;
   paused: boolean;
   source_id: string;
+  concurrency_policy?: ConcurrencyPolicy;
+  // IANA time zone name in which the cron schedule is interpreted. Defaults to UTC.
+  timezone?: string;
+  jitter_seconds?: number;
 };
 
 export type RetentionPolicy = {
@@ -118,6 +130,7 @@ export type Workflow = {
   created_at: number;
   retention_policy?: RetentionPolicy;
   notification_settings?: NotificationSettings;
+  max_concurrent_operators?: number;
 };
 
 export type WorkflowDag = {